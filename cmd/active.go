@@ -0,0 +1,149 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"golang.org/x/term"
+
+	"github.com/mhersson/gojira/pkg/jira"
+	"github.com/mhersson/gojira/pkg/util"
+	"github.com/mhersson/gojira/pkg/util/format"
+)
+
+// Max number of issues to pick from when the active issue is stale.
+const staleCandidates = 10
+
+// activeIssue returns the active issue, after checking it is not stale
+// by the activeIssue config, and marks it as used.
+func activeIssue(issueFile string) string {
+	file := util.ActiveIssueFile(issueFile)
+	key, err := util.GetActiveIssue(issueFile)
+	exitIfNotSet(err)
+
+	if reason := staleReason(file, key); reason != "" {
+		key = replaceStaleIssue(file, key, reason)
+	}
+
+	now := time.Now()
+	_ = os.Chtimes(file, now, now)
+
+	return key
+}
+
+// activeBoard returns the active board of the board type.
+func activeBoard(boardType string) string {
+	board, err := util.GetActiveSprintOrKanban(BoardFile, boardType)
+	exitIfNotSet(err)
+
+	return board
+}
+
+// exitIfNotSet exits with exitUsage when there is no active issue or
+// board, and with the code of any other error.
+func exitIfNotSet(err error) {
+	var notSet *util.NotSetError
+	if errors.As(err, &notSet) {
+		fail(jsonError{Code: "not_set", Message: notSet.Message}, exitUsage)
+	}
+
+	exitOnError(err)
+}
+
+// staleReason returns why the active issue is stale,
+// or an empty string if it is not.
+func staleReason(file, key string) string {
+	if days := Cfg.ActiveIssue.MaxDays; days > 0 {
+		if info, err := os.Stat(file); err == nil && time.Since(info.ModTime()) > time.Duration(days)*24*time.Hour {
+			return fmt.Sprintf("has not been used in %d days", int(time.Since(info.ModTime()).Hours()/24))
+		}
+	}
+
+	if Cfg.ActiveIssue.Resolved {
+		issues := must(JiraClient.GetIssuesSelecting(ctx, "key = "+key, jira.OrderByPriority, jira.BriefFields))
+		if len(issues) == 1 && issues[0].Fields.Resolution.Name != "" {
+			return "is resolved as " + issues[0].Fields.Resolution.Name
+		}
+	}
+
+	return ""
+}
+
+// replaceStaleIssue clears the stale issue, or asks to keep it or pick
+// another one, and returns the issue to use. --yes keeps it.
+func replaceStaleIssue(file, key, reason string) string {
+	if Cfg.ActiveIssue.WhenStale == "clear" {
+		unsetActive(file)
+		fmt.Printf("The active issue %s %s, and is cleared\n", key, reason)
+		os.Exit(exitFailure)
+	}
+
+	if AssumeYes {
+		return key
+	}
+
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		fmt.Printf("The active issue %s %s, give the issue key or set another issue active\n", key, reason)
+		os.Exit(exitFailure)
+	}
+
+	fmt.Printf("%sThe active issue %s %s%s\n", format.Color.Yellow, key, reason, format.Color.Nocolor)
+
+	issues := must(JiraClient.GetIssuesSelecting(ctx,
+		"assignee = currentUser() AND resolution = unresolved AND key != "+key, "updated desc", jira.BriefFields))
+
+	if len(issues) > staleCandidates {
+		issues = issues[:staleCandidates]
+	}
+
+	for i, issue := range issues {
+		fmt.Printf("%d. %-15s%s\n", i, issue.Key, issue.Fields.Summary)
+	}
+
+	answer := util.GetUserInput(
+		fmt.Sprintf("Pick an issue, k to keep %s or c to clear it: ", key), `^([0-9]+|k|c)$`)
+
+	switch answer {
+	case "k":
+		return key
+	case "c":
+		unsetActive(file)
+		fmt.Println("Active issue cleared")
+		os.Exit(0)
+	}
+
+	i, _ := strconv.Atoi(answer)
+	if i >= len(issues) {
+		fmt.Println("Invalid choice")
+		os.Exit(exitUsage)
+	}
+
+	setActiveIssue(issues[i].Key)
+	fmt.Printf("Issue %s is active\n", issues[i].Key)
+
+	return issues[i].Key
+}
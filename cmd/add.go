@@ -23,17 +23,22 @@ package cmd
 
 import (
 	"fmt"
+	"net/url"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
 
-	"github.com/mhersson/gojira/pkg/jira"
+	"github.com/mhersson/gojira/pkg/types"
+	"github.com/mhersson/gojira/pkg/util"
 	"github.com/mhersson/gojira/pkg/util/format"
+	"github.com/mhersson/gojira/pkg/util/markup"
 	"github.com/mhersson/gojira/pkg/util/validate"
 	"github.com/spf13/cobra"
 )
 
+// The variables are quoted, as the usage is a template itself.
 const addCommentUsage string = `This command will add a new comment to an issue.
 The input supports multiline text, and will open in $EDITOR, defaults to vim.
 Writing JIRA notation, with {noformat} and {code}, is supported, but for
@@ -42,6 +47,16 @@ easier writing three backticks will be converted to {noformat}.
 By default the comment is added to the active issue,
 but this can be changed by adding the issue key as argument.
 
+The same comment can be added to all issues matching a jql filter.
+The comment is then shown for the first issue, and must be confirmed
+before it is added to the rest.
+
+Instead of writing the comment in the editor it can be read from a
+template in ~/.config/gojira/templates, e.g. release-delay.tmpl is used
+with --template release-delay. Templates, and comments written in the editor
+for a filter, can use the variables {{"{{.Key}}"}}, {{"{{.Summary}}"}}, {{"{{.Type}}"}}, {{"{{.Status}}"}},
+{{"{{.Priority}}"}} and {{"{{.Assignee}}"}}, which are replaced by the values of each issue.
+
 Usage:
   gojira add comment [ISSUE KEY] [flags]
 
@@ -49,7 +64,12 @@ Aliases:
   comment, c
 
 Flags:
+  -f, --filter [JQL FILTER]    add the comment to all issues matching the filter
   -h, --help                   help for comment
+  -t, --template [NAME]        read the comment from a template
+
+Example:
+  gojira add comment --filter "fixVersion = 2.4.0" --template release-delay
 `
 
 const addWorkUsage string = `This command will add work to an issue worklog.
@@ -71,10 +91,17 @@ Usage:
 Aliases:
   work, w
 
+The work can be given the attributes billable, overtime and account,
+which are stored as a property on the worklog. The attributes are shown
+by get worklog, and the time spent is summed up per attribute.
+
 Flags:
+  -a, --account [ACCOUNT]      book the work on an account
+  -b, --billable               mark the work as billable
   -c. --comment                add a comment with the worklog
   -d, --date                   set the date
   -h, --help                   help for work
+      --overtime               mark the work as overtime
   -t, --time                   set the time
 
 Example:
@@ -92,11 +119,61 @@ Example specifying the issue and adding a comment:
 
 Example same as above but using alias (requires g1 set to GOJIRA-1 in config)
   # gojira add work g1 2h --comment "Helping out customer X"
+
+Example adding billable work on the account of customer X:
+  # gojira add work GOJIRA-1 2h --billable --account CUSTX
+`
+
+const addLinkUsage string = `This command links an issue to another issue.
+The link is given by the description of the link type as it reads from
+the issue, e.g. blocks or is blocked by, or by the name of the link type.
+Run "get linktypes" to list the link types.
+
+By default the active issue is linked, but this can be changed by adding
+the issue key as argument. When this is the case the argument order is
+important, and the issue key must always come first.
+
+Usage:
+  gojira add link [ISSUE KEY] <LINK> <ISSUE KEY> [flags]
+
+Aliases:
+  link, l
+
+Flags:
+  -h, --help                   help for link
+
+Example:
+  # Mark GOJIRA-1 as blocking GOJIRA-2
+  gojira add link GOJIRA-1 blocks GOJIRA-2
+
+  # Mark the active issue as blocked by GOJIRA-3
+  gojira add link "is blocked by" GOJIRA-3
+`
+
+const addWebLinkUsage string = `This command links an issue to a web page, e.g. a merge
+request or a Confluence page. The link is shown with the title,
+or with the url if no title is given.
+
+By default the link is added to the active issue, but this can be
+changed by adding the issue key as argument. When this is the case the
+argument order is important, and the issue key must always come first.
+
+Usage:
+  gojira add weblink [ISSUE KEY] <URL> [TITLE] [flags]
+
+Aliases:
+  weblink, wl
+
+Flags:
+  -h, --help                   help for weblink
+
+Example:
+  gojira add weblink GOJIRA-1 https://gitlab.com/gojira/-/merge_requests/42 "Fix the timer"
 `
 
 var addCmd = &cobra.Command{
 	Use:     "add",
-	Short:   "Add a comment or register time",
+	Short:   "Add a comment, a link or register time",
 	Args:    cobra.NoArgs,
 	Aliases: []string{"a"},
 }
@@ -107,6 +184,8 @@ var addWorkCmd = &cobra.Command{
 	Aliases: []string{"w"},
 	Args:    cobra.RangeArgs(1, 2),
 	Run: func(cmd *cobra.Command, args []string) {
+		exitIfReadOnly()
+
 		var work string
 
 		if len(args) == 1 {
@@ -121,30 +200,45 @@ var addWorkCmd = &cobra.Command{
 			IssueKey = strings.ToUpper(aliasValue)
 		}
 
-		jira.CheckIssueKey(&IssueKey, IssueFile)
+		checkIssueKey(&IssueKey, IssueFile)
+		checkPermission(IssueKey, "log work", permWorkOnIssues)
+
 		if WorkDate != "" && !validate.Date(WorkDate) {
 			fmt.Println("Invalid date. Date must be on the format yyyy-mm-dd")
-			os.Exit(1)
+			os.Exit(exitUsage)
 		}
 
 		if WorkTime != "" && !validate.Time(WorkTime) {
 			fmt.Println("Invalid time. Time must be on the format hh:mm")
-			os.Exit(1)
+			os.Exit(exitUsage)
 		}
 
 		duration, err := time.ParseDuration(work)
 		if err != nil {
 			fmt.Printf("Failed to add worklog - %s", err.Error())
-			os.Exit(1)
+			os.Exit(exitCode(err))
+		}
+
+		if !confirmWorklog(IssueKey, duration) {
+			os.Exit(exitCancelled)
+		}
+
+		seconds := strconv.FormatFloat(duration.Seconds(), 'f', 0, 64)
+
+		if WorkBillable || WorkOvertime || WorkAccount != "" {
+			attrs := types.WorklogAttributes{Billable: WorkBillable, Account: WorkAccount, Overtime: WorkOvertime}
+			err = JiraClient.AddWorklogWithAttributes(ctx, WorkDate, WorkTime, IssueKey, seconds, WorkComment, attrs)
+		} else {
+			err = JiraClient.AddWorklog(ctx, WorkDate, WorkTime, IssueKey, seconds, WorkComment)
 		}
 
-		err = jira.AddWorklog(WorkDate, WorkTime, IssueKey, strconv.FormatFloat(duration.Seconds(), 'f', 0, 64), WorkComment)
 		if err != nil {
 			fmt.Printf("Failed to add worklog - %s", err.Error())
-			os.Exit(1)
+			os.Exit(exitCode(err))
 		}
 
-		fmt.Printf("%sSuccessfully added new worklog.%s\n", format.Color.Green, format.Color.Nocolor)
+		success("Successfully added new worklog.")
+		checkBudget(IssueKey)
 	},
 }
 
@@ -154,35 +248,276 @@ var addCommentCmd = &cobra.Command{
 	Aliases: []string{"c"},
 	Args:    cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
+		exitIfReadOnly()
+
+		if JQLFilter != "" {
+			if len(args) == 1 {
+				fmt.Println("Can not use both an issue key and a filter")
+				os.Exit(exitUsage)
+			}
+
+			addCommentToIssues(must(JiraClient.GetIssues(ctx, JQLFilter)))
+
+			return
+		}
+
 		if len(args) == 1 {
 			IssueKey = strings.ToUpper(args[0])
 		}
 
-		jira.CheckIssueKey(&IssueKey, IssueFile)
+		checkIssueKey(&IssueKey, IssueFile)
+		checkPermission(IssueKey, "add comments", permAddComments)
 
-		comment, err := captureInputFromEditor("", "comment*")
-		if err != nil {
-			fmt.Println("Failed to add comment")
+		var comment []byte
+
+		if CommentTemplate != "" {
+			issues := must(JiraClient.GetIssues(ctx, "key = "+IssueKey))
+			if len(issues) == 0 {
+				fmt.Printf("Failed to get issue %s\n", IssueKey)
+				os.Exit(exitNotFound)
+			}
+
+			comment = renderComment(readCommentTemplate(), false, issues[0])
+		} else {
+			comment = editComment()
 		}
 
-		err = jira.AddComment(IssueKey, comment)
+		exitIfEmptyComment(string(comment))
+
+		err := JiraClient.AddComment(ctx, IssueKey, comment)
 		if err != nil {
 			fmt.Printf("Failed to add comment - %s\n", err.Error())
-			os.Exit(1)
+			os.Exit(exitCode(err))
 		}
 
 		fmt.Println("Successfully added comment")
 	},
 }
 
+// commentTemplate returns the comment template from --template, or
+// else the comment written in the editor, and if it is markdown. The
+// markdown is converted after the variables are replaced, as inline code
+// in wiki markup looks like a template action.
+func commentTemplate() (string, bool) {
+	if CommentTemplate != "" {
+		return readCommentTemplate(), false
+	}
+
+	if !editsMarkdown() {
+		return string(editComment()), false
+	}
+
+	content, err := captureInputFromEditor("", "comment*.md")
+	if err != nil {
+		fmt.Printf("Failed to add comment - %s\n", err.Error())
+		os.Exit(exitFailure)
+	}
+
+	return string(content), true
+}
+
+// editComment returns the comment written in the editor.
+func editComment() []byte {
+	content, err := captureTextFromEditor("", "comment*")
+	if err != nil {
+		fmt.Printf("Failed to add comment - %s\n", err.Error())
+		os.Exit(exitFailure)
+	}
+
+	return content
+}
+
+// exitIfEmptyComment stops when nothing was written in the editor,
+// instead of adding an empty comment.
+func exitIfEmptyComment(comment string) {
+	if strings.TrimSpace(comment) == "" {
+		fmt.Println("The comment is empty, no changes made")
+		os.Exit(0)
+	}
+}
+
+// commentVars are the variables available in comment templates.
+type commentVars struct {
+	Key      string
+	Summary  string
+	Type     string
+	Status   string
+	Priority string
+	Assignee string
+}
+
+// addCommentToIssues adds the same comment, with the variables
+// replaced per issue, to all the issues after a preview and confirmation.
+func addCommentToIssues(issues []types.Issue) {
+	if len(issues) == 0 {
+		fmt.Println("No issues matched the filter")
+
+		return
+	}
+
+	tmpl, markdown := commentTemplate()
+	exitIfEmptyComment(tmpl)
+
+	fmt.Printf("%sComment for %s:%s\n%s\n\n",
+		format.Color.Ul, issues[0].Key, format.Color.Nocolor, renderComment(tmpl, markdown, issues[0]))
+	printIssues(issues, false, false)
+
+	fmt.Println()
+
+	if !confirm(Cfg.Confirm.Bulk, fmt.Sprintf("Add the comment to these %d issues", len(issues))) {
+		os.Exit(exitCancelled)
+	}
+
+	failed := 0
+
+	for _, issue := range issues {
+		if err := JiraClient.AddComment(ctx, issue.Key, renderComment(tmpl, markdown, issue)); err != nil {
+			fmt.Printf("%sFailed to add comment to %s - %s%s\n",
+				format.Color.Red, issue.Key, err.Error(), format.Color.Nocolor)
+
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		fmt.Printf("Added comment to %d of %d issues\n", len(issues)-failed, len(issues))
+		os.Exit(exitFailure)
+	}
+
+	success("Successfully added comment to %d issues", len(issues))
+}
+
+func readCommentTemplate() string {
+	content, err := os.ReadFile(filepath.Join(TemplateFolder, CommentTemplate+".tmpl"))
+	if err != nil {
+		fmt.Printf("Failed to read template %s - %s\n", CommentTemplate, err.Error())
+		os.Exit(exitCode(err))
+	}
+
+	return string(content)
+}
+
+// renderComment replaces the variables in the comment template with the
+// values of the issue, and converts markdown to wiki markup.
+func renderComment(tmpl string, markdown bool, issue types.Issue) []byte {
+	vars := commentVars{
+		Key:      issue.Key,
+		Summary:  issue.Fields.Summary,
+		Type:     issue.Fields.IssueType.Name,
+		Status:   issue.Fields.Status.Name,
+		Priority: issue.Fields.Priority.Name,
+		Assignee: issue.Fields.Assignee.DisplayName,
+	}
+
+	comment, err := util.ExecuteTemplateText(tmpl, vars)
+	if err != nil {
+		fmt.Printf("Failed to render comment for %s - %s\n", issue.Key, err.Error())
+		os.Exit(exitCode(err))
+	}
+
+	if markdown {
+		return []byte(markup.FromMarkdown(string(comment)))
+	}
+
+	return comment
+}
+
+var addLinkCmd = &cobra.Command{
+	Use:     "link",
+	Short:   "Link to another issue",
+	Aliases: []string{"l"},
+	Args:    cobra.RangeArgs(2, 3),
+	Run: func(cmd *cobra.Command, args []string) {
+		exitIfReadOnly()
+
+		if len(args) == 3 {
+			IssueKey = strings.ToUpper(args[0])
+			args = args[1:]
+		}
+
+		description, linkedKey := args[0], strings.ToUpper(args[1])
+		checkIssueKeys(IssueFile, &IssueKey, &linkedKey)
+
+		if IssueKey == linkedKey {
+			fmt.Println("An issue can not be linked to itself")
+			os.Exit(exitUsage)
+		}
+
+		checkPermission(IssueKey, "link issues", permLinkIssues)
+
+		link := findLinkType(must(JiraClient.GetIssueLinkTypes(ctx)), description)
+		if link.Type == "" {
+			fmt.Printf("There is no link type %s, run \"gojira get linktypes\" to list them\n", description)
+			os.Exit(exitUsage)
+		}
+
+		from, to := IssueKey, linkedKey
+		if link.Inward {
+			from, to = to, from
+		}
+
+		if err := JiraClient.LinkIssues(ctx, link.Type, from, to); err != nil {
+			fmt.Printf("Failed to link %s to %s - %s\n", IssueKey, linkedKey, err.Error())
+			os.Exit(exitCode(err))
+		}
+
+		success("Successfully linked %s to %s (%s)", IssueKey, linkedKey, link.Type)
+	},
+}
+
+var addWebLinkCmd = &cobra.Command{
+	Use:     "weblink",
+	Short:   "Link to a web page",
+	Aliases: []string{"wl"},
+	Args:    cobra.RangeArgs(1, 3),
+	Run: func(cmd *cobra.Command, args []string) {
+		exitIfReadOnly()
+
+		if key := strings.ToUpper(args[0]); len(args) > 1 && validate.IssueKey(&key) {
+			IssueKey = key
+			args = args[1:]
+		} else if len(args) == 3 {
+			fmt.Println("Invalid key " + args[0])
+			os.Exit(exitUsage)
+		}
+
+		link := args[0]
+		if u, err := url.ParseRequestURI(link); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			fmt.Printf("Invalid url %s - must start with http:// or https://\n", link)
+			os.Exit(exitUsage)
+		}
+
+		title := link
+		if len(args) == 2 {
+			title = args[1]
+		}
+
+		checkIssueKey(&IssueKey, IssueFile)
+		checkPermission(IssueKey, "link issues", permLinkIssues)
+
+		if err := JiraClient.AddRemoteLink(ctx, IssueKey, link, title); err != nil {
+			fmt.Printf("Failed to link %s to %s - %s\n", IssueKey, link, err.Error())
+			os.Exit(exitCode(err))
+		}
+
+		success("Successfully linked %s to %s", IssueKey, title)
+	},
+}
+
 func init() {
 	rootCmd.AddCommand(addCmd)
 
 	addCmd.AddCommand(addCommentCmd)
 	addCmd.AddCommand(addWorkCmd)
+	addCmd.AddCommand(addLinkCmd)
+	addCmd.AddCommand(addWebLinkCmd)
 
 	addCommentCmd.SetUsageTemplate(addCommentUsage)
+	addCommentCmd.Flags().StringVarP(&JQLFilter, "filter", "f", "", "add the comment to all issues matching the jql filter")
+	addCommentCmd.Flags().StringVarP(&CommentTemplate, "template", "t", "", "read the comment from a template")
 	addWorkCmd.SetUsageTemplate(addWorkUsage)
+	addLinkCmd.SetUsageTemplate(addLinkUsage)
+	addWebLinkCmd.SetUsageTemplate(addWebLinkUsage)
 
 	addWorkCmd.PersistentFlags().StringVarP(&WorkDate,
 		"date", "d", "", "date, overrides the default date (today)")
@@ -190,4 +525,7 @@ func init() {
 		"time", "t", "", "time, overrides the default time (now)")
 	addWorkCmd.PersistentFlags().StringVarP(&WorkComment,
 		"comment", "c", "", "add a comment to you worklog")
+	addWorkCmd.Flags().BoolVarP(&WorkBillable, "billable", "b", false, "mark the work as billable")
+	addWorkCmd.Flags().StringVarP(&WorkAccount, "account", "a", "", "book the work on an account")
+	addWorkCmd.Flags().BoolVar(&WorkOvertime, "overtime", false, "mark the work as overtime")
 }
@@ -0,0 +1,323 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mhersson/gojira/pkg/types"
+	"github.com/mhersson/gojira/pkg/util"
+	"github.com/mhersson/gojira/pkg/util/format"
+	"github.com/mhersson/gojira/pkg/util/validate"
+)
+
+const adminComponentUsage string = `Manage the components of a project.
+Creating components requires project admin rights.
+
+Usage:
+  gojira admin component list <PROJECT KEY>
+  gojira admin component create <PROJECT KEY> --name <NAME> [flags]
+
+Available Commands:
+  create      Create a new component
+  list        List all components
+
+Flags:
+  -d, --description            description of the component (create)
+  -h, --help                   help for component
+  -l, --lead                   username of the component lead (create)
+  -n, --name                   name of the component (create)
+
+Example:
+  gojira admin component create OSE --name backend --lead bob
+`
+
+const adminVersionUsage string = `Manage the versions of a project.
+Creating, releasing and archiving versions requires project admin rights.
+
+Valid date format is yyyy-mm-dd
+
+Usage:
+  gojira admin version list <PROJECT KEY>
+  gojira admin version create <PROJECT KEY> <VERSION> [flags]
+  gojira admin version release <PROJECT KEY> <VERSION> [flags]
+  gojira admin version archive <PROJECT KEY> <VERSION>
+
+Available Commands:
+  archive     Archive a version
+  create      Create a new version
+  list        List all versions
+  release     Release a version
+
+Flags:
+  -d, --description            description of the version (create)
+  -h, --help                   help for version
+  -r, --release-date           the release date (create and release)
+
+Example:
+  gojira admin version create OSE 2.5.0 --release-date 2024-06-01
+  gojira admin version release OSE 2.5.0
+`
+
+// Used by `admin component` and `admin version`.
+var (
+	AdminName        string
+	AdminLead        string
+	AdminDescription string
+	AdminReleaseDate string
+)
+
+var adminCmd = &cobra.Command{
+	Use:   "admin",
+	Short: "Administrate project components and versions",
+	Args:  cobra.NoArgs,
+}
+
+var adminComponentCmd = &cobra.Command{
+	Use:     "component",
+	Short:   "Manage project components",
+	Args:    cobra.NoArgs,
+	Aliases: []string{"c"},
+}
+
+var adminComponentListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List all components",
+	Args:    cobra.ExactArgs(1),
+	Aliases: []string{"l"},
+	Run: func(cmd *cobra.Command, args []string) {
+		project := getProject(args[0])
+		printComponents(must(JiraClient.GetComponents(ctx, project.Key)))
+	},
+}
+
+var adminComponentCreateCmd = &cobra.Command{
+	Use:     "create",
+	Short:   "Create a new component",
+	Args:    cobra.ExactArgs(1),
+	Aliases: []string{"c"},
+	Run: func(cmd *cobra.Command, args []string) {
+		exitIfReadOnly()
+
+		project := getProject(args[0])
+
+		if AdminLead != "" && !must(JiraClient.UserExists(ctx, AdminLead)) {
+			fmt.Printf("User %s does not exist.\n", AdminLead)
+			os.Exit(exitNotFound)
+		}
+
+		err := JiraClient.CreateComponent(ctx, project.Key, AdminName, AdminLead, AdminDescription)
+		if err != nil {
+			fmt.Printf("Failed to create component - %s\n", err.Error())
+			os.Exit(exitCode(err))
+		}
+
+		success("Successfully created component %s in %s", AdminName, project.Key)
+	},
+}
+
+var adminVersionCmd = &cobra.Command{
+	Use:     "version",
+	Short:   "Manage project versions",
+	Args:    cobra.NoArgs,
+	Aliases: []string{"v"},
+}
+
+var adminVersionListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List all versions",
+	Args:    cobra.ExactArgs(1),
+	Aliases: []string{"l"},
+	Run: func(cmd *cobra.Command, args []string) {
+		project := getProject(args[0])
+		printVersions(must(JiraClient.GetVersions(ctx, project.Key)))
+	},
+}
+
+var adminVersionCreateCmd = &cobra.Command{
+	Use:     "create",
+	Short:   "Create a new version",
+	Args:    cobra.ExactArgs(2),
+	Aliases: []string{"c"},
+	Run: func(cmd *cobra.Command, args []string) {
+		exitIfReadOnly()
+		checkReleaseDate()
+
+		project := getProject(args[0])
+
+		err := JiraClient.CreateVersion(ctx, project.Key, args[1], AdminReleaseDate, AdminDescription)
+		if err != nil {
+			fmt.Printf("Failed to create version - %s\n", err.Error())
+			os.Exit(exitCode(err))
+		}
+
+		success("Successfully created version %s in %s", args[1], project.Key)
+	},
+}
+
+var adminVersionReleaseCmd = &cobra.Command{
+	Use:     "release",
+	Short:   "Release a version",
+	Args:    cobra.ExactArgs(2),
+	Aliases: []string{"r"},
+	Run: func(cmd *cobra.Command, args []string) {
+		exitIfReadOnly()
+		checkReleaseDate()
+
+		version := getVersion(args[0], args[1])
+		if version.Released {
+			fmt.Printf("Version %s is already released\n", version.Name)
+			os.Exit(exitUsage)
+		}
+
+		if AdminReleaseDate == "" && version.ReleaseDate == "" {
+			AdminReleaseDate = util.Today()
+		}
+
+		err := JiraClient.ReleaseVersion(ctx, version.ID, AdminReleaseDate)
+		if err != nil {
+			fmt.Printf("Failed to release version - %s\n", err.Error())
+			os.Exit(exitCode(err))
+		}
+
+		success("Successfully released version %s", version.Name)
+	},
+}
+
+var adminVersionArchiveCmd = &cobra.Command{
+	Use:     "archive",
+	Short:   "Archive a version",
+	Args:    cobra.ExactArgs(2),
+	Aliases: []string{"a"},
+	Run: func(cmd *cobra.Command, args []string) {
+		exitIfReadOnly()
+
+		version := getVersion(args[0], args[1])
+
+		err := JiraClient.ArchiveVersion(ctx, version.ID)
+		if err != nil {
+			fmt.Printf("Failed to archive version - %s\n", err.Error())
+			os.Exit(exitCode(err))
+		}
+
+		success("Successfully archived version %s", version.Name)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(adminCmd)
+
+	adminCmd.AddCommand(adminComponentCmd)
+	adminCmd.AddCommand(adminVersionCmd)
+
+	adminComponentCmd.SetUsageTemplate(adminComponentUsage)
+	adminComponentCmd.AddCommand(adminComponentListCmd)
+	adminComponentCmd.AddCommand(adminComponentCreateCmd)
+
+	adminComponentCreateCmd.SetUsageTemplate(adminComponentUsage)
+	adminComponentCreateCmd.Flags().StringVarP(&AdminName, "name", "n", "", "name of the component")
+	adminComponentCreateCmd.Flags().StringVarP(&AdminLead, "lead", "l", "", "username of the component lead")
+	adminComponentCreateCmd.Flags().StringVarP(&AdminDescription, "description", "d", "", "description of the component")
+	_ = adminComponentCreateCmd.MarkFlagRequired("name")
+
+	adminVersionCmd.SetUsageTemplate(adminVersionUsage)
+	adminVersionCmd.AddCommand(adminVersionListCmd)
+	adminVersionCmd.AddCommand(adminVersionCreateCmd)
+	adminVersionCmd.AddCommand(adminVersionReleaseCmd)
+	adminVersionCmd.AddCommand(adminVersionArchiveCmd)
+
+	adminVersionCreateCmd.SetUsageTemplate(adminVersionUsage)
+	adminVersionCreateCmd.Flags().StringVarP(&AdminReleaseDate, "release-date", "r", "", "the release date")
+	adminVersionCreateCmd.Flags().StringVarP(&AdminDescription, "description", "d", "", "description of the version")
+
+	adminVersionReleaseCmd.SetUsageTemplate(adminVersionUsage)
+	adminVersionReleaseCmd.Flags().StringVarP(&AdminReleaseDate, "release-date", "r", "",
+		"the release date, defaults to today if not already set")
+
+	adminVersionArchiveCmd.SetUsageTemplate(adminVersionUsage)
+}
+
+func getProject(key string) types.Project {
+	key = strings.ToUpper(key)
+
+	project := validate.ProjectKey(key, must(JiraClient.GetValidProjects(ctx)))
+	if project.ID == "" {
+		fmt.Printf("%s is not a valid project key\n", key)
+		os.Exit(exitUsage)
+	}
+
+	return project
+}
+
+func getVersion(projectKey, name string) types.Version {
+	project := getProject(projectKey)
+
+	for _, v := range must(JiraClient.GetVersions(ctx, project.Key)) {
+		if v.Name == name {
+			return v
+		}
+	}
+
+	fmt.Printf("Version %s does not exist in %s\n", name, project.Key)
+	os.Exit(exitNotFound)
+
+	return types.Version{}
+}
+
+func checkReleaseDate() {
+	if AdminReleaseDate != "" && !validate.Date(AdminReleaseDate) {
+		fmt.Println("Invalid date. Date must be on the format yyyy-mm-dd")
+		os.Exit(exitUsage)
+	}
+}
+
+func printComponents(components []types.Component) {
+	fmt.Printf("%s%s\n%-10s%-30s%-25s%s%s\n", format.Color.Ul, format.Color.Yellow,
+		"ID", "Name", "Lead", "Description", format.Color.Nocolor)
+
+	for _, c := range components {
+		fmt.Printf("%-10s%-30s%-25s%s\n", c.ID, c.Name, c.Lead.DisplayName, c.Description)
+	}
+}
+
+func printVersions(versions []types.Version) {
+	fmt.Printf("%s%s\n%-10s%-20s%-14s%-10s%-10s%s%s\n", format.Color.Ul, format.Color.Yellow,
+		"ID", "Name", "Release date", "Released", "Archived", "Description", format.Color.Nocolor)
+
+	for _, v := range versions {
+		fmt.Printf("%-10s%-20s%-14s%-10s%-10s%s\n", v.ID, v.Name, v.ReleaseDate,
+			yesNo(v.Released), yesNo(v.Archived), v.Description)
+	}
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "Yes"
+	}
+
+	return "No"
+}
@@ -0,0 +1,208 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/zalando/go-keyring"
+	"golang.org/x/term"
+
+	"github.com/mhersson/gojira/pkg/types"
+	"github.com/mhersson/gojira/pkg/util/agent"
+)
+
+const authUsage string = `Manage the password stored in the keyring of the operating system,
+i.e. the Secret Service on Linux, the macOS Keychain or the Windows
+Credential Manager, for passwordtype keyring.
+
+The name defaults to the password in the config file when the
+passwordtype is keyring. The password is read without echo, or from
+stdin if it is not a terminal.
+
+When there is no password in the config file, gojira asks for it.
+Set passwordCache, e.g. to 30m, to have an agent running in the
+background remember it for that long. Use forget to stop the agent
+right away, e.g. after typing the wrong password.
+
+Usage:
+  gojira auth set [NAME]
+  gojira auth delete [NAME]
+  gojira auth forget
+
+Available Commands:
+  delete      Delete the password from the keyring
+  forget      Forget the password cached by the agent
+  set         Store the password in the keyring
+
+Flags:
+  -h, --help                   help for auth
+
+Example:
+  gojira auth set jira
+  pass show jira | gojira auth set jira
+`
+
+// Used by `auth agent`.
+var AgentTimeout time.Duration
+
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Manage the password in the keyring",
+	Args:  cobra.NoArgs,
+}
+
+var authSetCmd = &cobra.Command{
+	Use:     "set",
+	Short:   "Store the password in the keyring",
+	Args:    cobra.MaximumNArgs(1),
+	Aliases: []string{"s"},
+	Run: func(cmd *cobra.Command, args []string) {
+		name := keyringName(args)
+
+		secret := readSecret(fmt.Sprintf("Password for %s: ", name))
+		if secret == "" {
+			fmt.Println("The password can not be empty")
+			os.Exit(exitUsage)
+		}
+
+		if err := keyring.Set(types.KeyringService, name, secret); err != nil {
+			fmt.Printf("Failed to store the password - %s\n", err.Error())
+			os.Exit(exitCode(err))
+		}
+
+		success("Successfully stored %s in the keyring", name)
+	},
+}
+
+var authDeleteCmd = &cobra.Command{
+	Use:     "delete",
+	Short:   "Delete the password from the keyring",
+	Args:    cobra.MaximumNArgs(1),
+	Aliases: []string{"d"},
+	Run: func(cmd *cobra.Command, args []string) {
+		name := keyringName(args)
+
+		err := keyring.Delete(types.KeyringService, name)
+		if errors.Is(err, keyring.ErrNotFound) {
+			fmt.Printf("%s is not in the keyring\n", name)
+			os.Exit(exitNotFound)
+		}
+
+		if err != nil {
+			fmt.Printf("Failed to delete the password - %s\n", err.Error())
+			os.Exit(exitCode(err))
+		}
+
+		success("Successfully deleted %s from the keyring", name)
+	},
+}
+
+var authForgetCmd = &cobra.Command{
+	Use:   "forget",
+	Short: "Forget the password cached by the agent",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := agent.Stop(agent.Socket()); err != nil {
+			fmt.Println("There is no cached password")
+
+			return
+		}
+
+		success("Successfully forgot the cached password")
+	},
+}
+
+// authAgentCmd is started in the background by gojira itself,
+// when a password typed at the prompt is cached.
+var authAgentCmd = &cobra.Command{
+	Use:    "agent",
+	Short:  "Cache the password typed at the prompt",
+	Args:   cobra.NoArgs,
+	Hidden: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := agent.Serve(agent.Socket(), AgentTimeout); err != nil {
+			fmt.Printf("Failed to run the agent - %s\n", err.Error())
+			os.Exit(exitCode(err))
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(authCmd)
+
+	authCmd.SetUsageTemplate(authUsage)
+	authCmd.AddCommand(authSetCmd)
+	authCmd.AddCommand(authDeleteCmd)
+	authCmd.AddCommand(authForgetCmd)
+	authCmd.AddCommand(authAgentCmd)
+
+	authSetCmd.SetUsageTemplate(authUsage)
+	authDeleteCmd.SetUsageTemplate(authUsage)
+	authForgetCmd.SetUsageTemplate(authUsage)
+
+	authAgentCmd.Flags().DurationVar(&AgentTimeout, "timeout", 30*time.Minute, "how long to cache the password")
+}
+
+// keyringName returns the name of the password in the keyring, from
+// the argument, or else the password in the config if it is in the keyring.
+func keyringName(args []string) string {
+	if len(args) == 1 {
+		return args[0]
+	}
+
+	if Cfg.PasswordType != "keyring" || Cfg.Password == "" {
+		fmt.Println("Give the name of the password, or set passwordtype to keyring and password to the name")
+		os.Exit(exitUsage)
+	}
+
+	return Cfg.Password
+}
+
+// readSecret reads a line without echo from the terminal,
+// or else the first line of stdin.
+func readSecret(prompt string) string {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+
+		return strings.TrimSpace(line)
+	}
+
+	fmt.Print(prompt)
+
+	secret, err := term.ReadPassword(int(os.Stdin.Fd()))
+
+	fmt.Println()
+
+	if err != nil {
+		fmt.Printf("Failed to read the password - %s\n", err.Error())
+		os.Exit(exitCode(err))
+	}
+
+	return strings.TrimSpace(string(secret))
+}
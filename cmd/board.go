@@ -0,0 +1,237 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mhersson/gojira/pkg/types"
+	"github.com/mhersson/gojira/pkg/util/format"
+)
+
+const boardUsage string = `Manage your favourite boards. The favourite boards are displayed
+one after another by get sprint and get kanban with --all-boards,
+for those working with more than one team.
+
+Usage:
+  gojira board [command]
+
+Available Commands:
+  add         Add boards to your favourites
+  list        List your favourite boards
+  remove      Remove boards from your favourites
+
+Flags:
+  -h, --help                   help for board
+
+Example:
+  gojira board add "Team Rocket" "Team Magma"
+  gojira get sprint --all-boards
+`
+
+var boardCmd = &cobra.Command{
+	Use:     "board",
+	Short:   "Manage your favourite boards",
+	Aliases: []string{"b"},
+	Args:    cobra.NoArgs,
+}
+
+var boardAddCmd = &cobra.Command{
+	Use:     "add",
+	Short:   "Add boards to your favourites",
+	Aliases: []string{"a"},
+	Args:    cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		views := must(JiraClient.GetRapidViews(ctx))
+		boards := loadFavouriteBoards()
+
+		for _, name := range args {
+			if findRapidView(views, name) == nil {
+				fmt.Printf("Board %s does not exist\n", name)
+				os.Exit(exitNotFound)
+			}
+		}
+
+		for _, name := range args {
+			view := findRapidView(views, name)
+
+			if containsBoard(boards, view.Name) {
+				fmt.Printf("%s is already a favourite\n", view.Name)
+
+				continue
+			}
+
+			boards = append(boards, view.Name)
+			success("%s added to your favourites", view.Name)
+		}
+
+		saveFavouriteBoards(boards)
+	},
+}
+
+var boardRemoveCmd = &cobra.Command{
+	Use:     "remove",
+	Short:   "Remove boards from your favourites",
+	Aliases: []string{"rm"},
+	Args:    cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		boards := loadFavouriteBoards()
+
+		for _, name := range args {
+			if !containsBoard(boards, name) {
+				fmt.Printf("%s is not a favourite\n", name)
+
+				continue
+			}
+
+			boards = slices.DeleteFunc(boards, func(b string) bool { return strings.EqualFold(b, name) })
+			fmt.Printf("%s removed from your favourites\n", name)
+		}
+
+		saveFavouriteBoards(boards)
+	},
+}
+
+var boardListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List your favourite boards",
+	Aliases: []string{"ls", "l"},
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		boards := loadFavouriteBoards()
+		if len(boards) == 0 {
+			fmt.Println("You have no favourite boards")
+
+			return
+		}
+
+		views := must(JiraClient.GetRapidViews(ctx))
+
+		fmt.Printf("%s%s%-8s%-40s%s%s\n", format.Color.Ul, format.Color.Yellow,
+			"ID", "Name", "Type", format.Color.Nocolor)
+
+		for _, b := range boards {
+			view := findRapidView(views, b)
+			if view == nil {
+				fmt.Printf("%-8s%-40s%s\n", "-", b, "does not exist")
+
+				continue
+			}
+
+			boardType := "kanban"
+			if view.SprintSupportEnabled {
+				boardType = "sprint"
+			}
+
+			fmt.Printf("%-8d%-40s%s\n", view.ID, view.Name, boardType)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(boardCmd)
+
+	boardCmd.SetUsageTemplate(boardUsage)
+	boardCmd.AddCommand(boardAddCmd)
+	boardCmd.AddCommand(boardRemoveCmd)
+	boardCmd.AddCommand(boardListCmd)
+}
+
+func findRapidView(views []types.RapidView, name string) *types.RapidView {
+	for i := range views {
+		if strings.EqualFold(views[i].Name, name) {
+			return &views[i]
+		}
+	}
+
+	return nil
+}
+
+func containsBoard(boards []string, name string) bool {
+	return slices.ContainsFunc(boards, func(b string) bool { return strings.EqualFold(b, name) })
+}
+
+// loadFavouriteBoards returns the favourite boards,
+// stored with one board name per line.
+func loadFavouriteBoards() []string {
+	boards := []string{}
+
+	content, err := os.ReadFile(BoardsFile)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			fmt.Printf("Failed to read the favourite boards - %s\n", err.Error())
+			os.Exit(exitCode(err))
+		}
+
+		return boards
+	}
+
+	for _, line := range strings.Split(string(content), "\n") {
+		if b := strings.TrimSpace(line); b != "" {
+			boards = append(boards, b)
+		}
+	}
+
+	return boards
+}
+
+func saveFavouriteBoards(boards []string) {
+	createStateFolder()
+
+	content := strings.Join(boards, "\n")
+	if len(boards) > 0 {
+		content += "\n"
+	}
+
+	if err := os.WriteFile(BoardsFile, []byte(content), 0o600); err != nil {
+		fmt.Printf("Failed to save the favourite boards - %s\n", err.Error())
+		os.Exit(exitCode(err))
+	}
+}
+
+// boardsToShow returns the board given as argument, the favourite
+// boards with --all-boards, or else the active board of the type.
+func boardsToShow(args []string, boardType string) []string {
+	switch {
+	case AllBoards && len(args) > 0:
+		fmt.Println("Can not use both a board name and --all-boards")
+		os.Exit(exitUsage)
+	case AllBoards:
+		boards := loadFavouriteBoards()
+		if len(boards) == 0 {
+			fmt.Println("You have no favourite boards, add them with gojira board add")
+			os.Exit(0)
+		}
+
+		return boards
+	case len(args) >= 1:
+		return args[:1]
+	}
+
+	return []string{activeBoard(boardType)}
+}
@@ -0,0 +1,219 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mhersson/gojira/pkg/util/convert"
+	"github.com/mhersson/gojira/pkg/util/format"
+	"github.com/mhersson/gojira/pkg/util/validate"
+)
+
+const budgetUsage string = `Set a personal time budget on an issue, e.g. for keeping fixed-bid
+work in check. The budgets are stored locally, and you are warned
+whenever new work logged by gojira pushes the total time logged on
+the issue past its budget.
+
+By default the budget of the active issue is set or shown,
+but this can be changed by adding the issue key as the first argument.
+Without any arguments all budgets are listed.
+
+Usage:
+  gojira budget [ISSUE KEY] [DURATION] [flags]
+
+Flags:
+  -h, --help                   help for budget
+  -r, --remove                 remove the budget
+
+Examples:
+  # Set a budget of 10 hours on OSE-1
+  gojira budget OSE-1 10h
+
+  # Show the budget of the active issue
+  gojira budget
+
+  # Remove the budget of OSE-1
+  gojira budget OSE-1 --remove
+`
+
+var RemoveBudget bool // Used by `budget`
+
+var budgetCmd = &cobra.Command{
+	Use:   "budget",
+	Short: "Set a personal time budget on an issue",
+	Args:  cobra.MaximumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		budgets := loadBudgets()
+
+		if len(args) == 0 && !RemoveBudget {
+			printBudgets(budgets)
+
+			return
+		}
+
+		if len(args) > 0 {
+			if key := strings.ToUpper(args[0]); validate.IssueKey(&key) {
+				IssueKey = key
+				args = args[1:]
+			}
+		}
+
+		checkIssueKey(&IssueKey, IssueFile)
+
+		switch {
+		case RemoveBudget:
+			if _, ok := budgets[IssueKey]; !ok {
+				fmt.Printf("%s does not have a budget\n", IssueKey)
+				os.Exit(exitNotFound)
+			}
+
+			delete(budgets, IssueKey)
+			saveBudgets(budgets)
+			fmt.Printf("Removed the budget of %s\n", IssueKey)
+		case len(args) == 1:
+			seconds, err := convert.DurationStringToSeconds(args[0])
+			if err == nil && seconds == "0" {
+				err = fmt.Errorf("the budget must be more than 0m")
+			}
+
+			if err != nil {
+				fmt.Printf("Invalid budget %s - %v\n", args[0], err)
+				os.Exit(exitUsage)
+			}
+
+			budgets[IssueKey], _ = strconv.Atoi(seconds)
+			saveBudgets(budgets)
+			success("Budget of %s set to %s", IssueKey, convert.SecondsToHoursAndMinutes(budgets[IssueKey], false))
+			checkBudget(IssueKey)
+		default:
+			if _, ok := budgets[IssueKey]; !ok {
+				fmt.Printf("%s does not have a budget\n", IssueKey)
+
+				return
+			}
+
+			printBudgets(map[string]int{IssueKey: budgets[IssueKey]})
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(budgetCmd)
+
+	budgetCmd.SetUsageTemplate(budgetUsage)
+	budgetCmd.Flags().BoolVarP(&RemoveBudget, "remove", "r", false, "remove the budget")
+}
+
+// loadBudgets returns the budgets in seconds keyed by issue key.
+func loadBudgets() map[string]int {
+	budgets := map[string]int{}
+
+	content, err := os.ReadFile(BudgetFile)
+	if err != nil {
+		return budgets
+	}
+
+	if err := json.Unmarshal(content, &budgets); err != nil {
+		fmt.Printf("Failed to read budgets - %s\n", err.Error())
+		os.Exit(exitCode(err))
+	}
+
+	return budgets
+}
+
+func saveBudgets(budgets map[string]int) {
+	createStateFolder()
+
+	content, _ := json.MarshalIndent(budgets, "", "  ")
+
+	if err := os.WriteFile(BudgetFile, content, 0o600); err != nil {
+		fmt.Printf("Failed to save budgets - %s\n", err.Error())
+		os.Exit(exitCode(err))
+	}
+}
+
+func printBudgets(budgets map[string]int) {
+	if len(budgets) == 0 {
+		fmt.Println("You have not set any budgets")
+
+		return
+	}
+
+	keys := []string{}
+	for k := range budgets {
+		keys = append(keys, k)
+	}
+
+	slices.Sort(keys)
+
+	fmt.Printf("%s%s%-15s%-15s%-15s%s%s%s\n", format.Color.Ul, format.Color.Yellow,
+		"Key", "Budget", "Logged", "Remaining", format.Color.Nocolor, format.Color.Nocolor)
+
+	for _, k := range keys {
+		logged := must(JiraClient.GetTimeSpent(ctx, k))
+		remaining := budgets[k] - logged
+
+		color := format.Color.Green
+		if remaining < 0 {
+			color = format.Color.Red
+		}
+
+		fmt.Printf("%-15s%-15s%-15s%s%s%s\n", k,
+			convert.SecondsToHoursAndMinutes(budgets[k], false),
+			convert.SecondsToHoursAndMinutes(logged, false),
+			color, budgetRemaining(remaining), format.Color.Nocolor)
+	}
+}
+
+func budgetRemaining(seconds int) string {
+	if seconds < 0 {
+		return convert.SecondsToHoursAndMinutes(-seconds, false) + " over"
+	}
+
+	return convert.SecondsToHoursAndMinutes(seconds, false)
+}
+
+// checkBudget warns if the time logged on the issue is past its budget.
+// Called after adding work to the issue.
+func checkBudget(key string) {
+	budget, ok := loadBudgets()[key]
+	if !ok {
+		return
+	}
+
+	logged := must(JiraClient.GetTimeSpent(ctx, key))
+	if logged <= budget {
+		return
+	}
+
+	fmt.Printf("%sWarning: %s is over budget, logged %s of %s (%s)%s\n", format.Color.Red, key,
+		convert.SecondsToHoursAndMinutes(logged, false), convert.SecondsToHoursAndMinutes(budget, false),
+		budgetRemaining(budget-logged), format.Color.Nocolor)
+}
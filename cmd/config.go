@@ -0,0 +1,710 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cast"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/zalando/go-keyring"
+	"golang.org/x/term"
+	"gopkg.in/yaml.v3"
+
+	"github.com/mhersson/gojira/pkg/jira"
+	"github.com/mhersson/gojira/pkg/types"
+	"github.com/mhersson/gojira/pkg/util"
+	"github.com/mhersson/gojira/pkg/util/calendar"
+	"github.com/mhersson/gojira/pkg/util/format"
+)
+
+const configUsage string = `Edit or query the config file. The config file is validated
+before it is saved, and comments in the file are preserved.
+
+Keys are case insensitive, and nested keys, like aliases, are
+separated by a dot. Only the keys gojira knows can be set, and the
+values of lists, like insightFields, are separated by commas.
+
+Use init to write a new config file, answering a few questions about
+the Jira server, how the password is kept and your working hours. The
+login is checked before the config file is written.
+
+Usage:
+  gojira config init
+  gojira config edit
+  gojira config get [KEY]
+  gojira config set <KEY> <VALUE>
+
+Available Commands:
+  edit        Open the config file in $EDITOR
+  get         Display the value of a key, or the whole config
+  init        Write a new config file, asking for the settings
+  set         Set the value of a key
+
+Flags:
+  -h, --help                   help for config
+
+Example:
+  gojira config set sprintFilter "Team A.*"
+  gojira config set aliases.g1 GOJIRA-1
+  gojira config set insightFields customfield_10100,customfield_10101
+  gojira config get aliases
+`
+
+// Value types of the config keys, used to validate the config.
+var (
+	configRequired = []string{"JiraURL", "username"}
+	configBools    = []string{
+		"useTimesheetPlugin", "checkForUpdates", "readOnly", "fullSummary", "activeIssue.resolved", "insecureSkipVerify",
+		"rawMarkup", "editMarkdown",
+	}
+	configInts      = []string{"numberOfWorkingDays", "truncateSummary", "maxAttempts", "activeIssue.maxDays"}
+	configFloats    = []string{"numberOfWorkingHoursPerDay", "numberOfWorkingHoursPerWeek", "confirm.worklogHours"}
+	configDurations = []string{"timerMax", "passwordCache", "existsCacheTTL"}
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Edit or query the config file",
+	Args:  cobra.NoArgs,
+}
+
+var configInitCmd = &cobra.Command{
+	Use:     "init",
+	Short:   "Write a new config file, asking for the settings",
+	Args:    cobra.NoArgs,
+	Aliases: []string{"i"},
+	Run: func(cmd *cobra.Command, args []string) {
+		filename := configFile()
+		in := bufio.NewReader(os.Stdin)
+
+		if _, err := os.Stat(filename); err == nil && !AssumeYes && !askYes(in, filename+" exists, overwrite it", false) {
+			fmt.Println("Cancelled by user")
+
+			return
+		}
+
+		answers := askConfig(in)
+
+		content, err := yaml.Marshal(answers)
+		if err != nil {
+			fmt.Printf("Failed to encode config - %s\n", err.Error())
+			os.Exit(exitCode(err))
+		}
+
+		if problems := validateConfig(content); len(problems) > 0 {
+			printConfigProblems(problems)
+			os.Exit(exitFailure)
+		}
+
+		if !checkLogin(answers) && !askYes(in, "Write the config anyway", false) {
+			fmt.Println("Cancelled by user")
+			os.Exit(exitCancelled)
+		}
+
+		writeConfig(filename, append([]byte(configInitHeader), content...))
+	},
+}
+
+var configEditCmd = &cobra.Command{
+	Use:     "edit",
+	Short:   "Open the config file in $EDITOR",
+	Args:    cobra.NoArgs,
+	Aliases: []string{"e"},
+	Run: func(cmd *cobra.Command, args []string) {
+		filename := configFile()
+
+		content, err := os.ReadFile(filename)
+		if err != nil && !os.IsNotExist(err) {
+			fmt.Printf("Failed to read %s - %s\n", filename, err.Error())
+			os.Exit(exitCode(err))
+		}
+
+		for {
+			edited, err := captureInputFromEditor(string(content), "config*.yaml")
+			if err != nil {
+				fmt.Printf("Failed to edit config - %s\n", err.Error())
+				os.Exit(exitCode(err))
+			}
+
+			if len(edited) == 0 {
+				fmt.Println("No changes made")
+
+				return
+			}
+
+			problems := validateConfig(edited)
+			if len(problems) == 0 {
+				writeConfig(filename, edited)
+
+				return
+			}
+
+			printConfigProblems(problems)
+
+			if util.GetUserInput("Edit again [y/N]: ", "[y|n]") != "y" {
+				fmt.Println("Changes discarded")
+
+				return
+			}
+
+			content = edited
+		}
+	},
+}
+
+var configGetCmd = &cobra.Command{
+	Use:     "get",
+	Short:   "Display the value of a key, or the whole config",
+	Args:    cobra.MaximumNArgs(1),
+	Aliases: []string{"g"},
+	Run: func(cmd *cobra.Command, args []string) {
+		doc := readConfigNode(configFile())
+
+		if len(args) == 0 {
+			enc := yaml.NewEncoder(os.Stdout)
+			enc.SetIndent(2)
+			_ = enc.Encode(doc)
+
+			return
+		}
+
+		if configKeyType(args[0]) == nil {
+			fmt.Printf("%s is not a known key, see config-example.yaml for the keys\n", args[0])
+			os.Exit(exitUsage)
+		}
+
+		node := findConfigKey(doc, args[0], false)
+		if node == nil {
+			fmt.Printf("%s is not set\n", args[0])
+			os.Exit(exitNotFound)
+		}
+
+		if node.Kind == yaml.ScalarNode {
+			fmt.Println(node.Value)
+
+			return
+		}
+
+		out, _ := yaml.Marshal(node)
+		fmt.Print(string(out))
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:     "set",
+	Short:   "Set the value of a key",
+	Args:    cobra.ExactArgs(2),
+	Aliases: []string{"s"},
+	Run: func(cmd *cobra.Command, args []string) {
+		typ := configKeyType(args[0])
+		if typ == nil {
+			fmt.Printf("%s is not a known key, see config-example.yaml for the keys\n", args[0])
+			os.Exit(exitUsage)
+		}
+
+		value := yaml.Node{Kind: yaml.ScalarNode, Value: args[1]}
+
+		switch typ.Kind() { //nolint:exhaustive
+		case reflect.Struct, reflect.Map:
+			fmt.Printf("Can not set %s, set the keys in it instead\n", args[0])
+			os.Exit(exitUsage)
+		case reflect.Slice:
+			value = yaml.Node{Kind: yaml.SequenceNode}
+
+			for _, v := range strings.Split(args[1], ",") {
+				if v = strings.TrimSpace(v); v != "" {
+					value.Content = append(value.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: v})
+				}
+			}
+		}
+
+		filename := configFile()
+		doc := readConfigNode(filename)
+
+		node := findConfigKey(doc, args[0], true)
+		if node == nil {
+			fmt.Printf("Can not set %s, the parent is not a map\n", args[0])
+			os.Exit(exitUsage)
+		}
+
+		value.LineComment = node.LineComment
+		*node = value
+
+		var buf bytes.Buffer
+
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+
+		if err := enc.Encode(doc); err != nil {
+			fmt.Printf("Failed to encode config - %s\n", err.Error())
+			os.Exit(exitCode(err))
+		}
+
+		if problems := validateConfig(buf.Bytes()); len(problems) > 0 {
+			printConfigProblems(problems)
+			os.Exit(exitFailure)
+		}
+
+		writeConfig(filename, buf.Bytes())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+
+	configCmd.SetUsageTemplate(configUsage)
+	configCmd.AddCommand(configInitCmd)
+	configCmd.AddCommand(configEditCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+
+	configInitCmd.SetUsageTemplate(configUsage)
+	configEditCmd.SetUsageTemplate(configUsage)
+	configGetCmd.SetUsageTemplate(configUsage)
+	configSetCmd.SetUsageTemplate(configUsage)
+}
+
+// configFile returns the config file in use, or the
+// default location if there is no config file yet.
+func configFile() string {
+	if f := viper.ConfigFileUsed(); f != "" {
+		return f
+	}
+
+	return filepath.Join(ConfigFolder, "config.yaml")
+}
+
+// configKeyType returns the type of the value of the dotted key, matching
+// the keys case insensitively, or nil if gojira does not know the key.
+// The keys in the maps, like the aliases, can be anything.
+func configKeyType(key string) reflect.Type {
+	t := reflect.TypeOf(types.Config{})
+
+	for _, part := range strings.Split(key, ".") {
+		switch t.Kind() { //nolint:exhaustive
+		case reflect.Struct:
+			field, ok := configField(t, part)
+			if !ok {
+				return nil
+			}
+
+			t = field.Type
+		case reflect.Map:
+			t = t.Elem()
+		default:
+			return nil
+		}
+	}
+
+	return t
+}
+
+// configField returns the field of the struct with the yaml name.
+func configField(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+
+		if tag, _, _ := strings.Cut(field.Tag.Get("yaml"), ","); strings.EqualFold(tag, name) {
+			return field, true
+		}
+	}
+
+	return reflect.StructField{}, false
+}
+
+func readConfigNode(filename string) *yaml.Node {
+	doc := &yaml.Node{Kind: yaml.DocumentNode}
+
+	content, err := os.ReadFile(filename)
+	if err != nil && !os.IsNotExist(err) {
+		fmt.Printf("Failed to read %s - %s\n", filename, err.Error())
+		os.Exit(exitCode(err))
+	}
+
+	if err := yaml.Unmarshal(content, doc); err != nil {
+		fmt.Printf("Failed to parse %s - %s\n", filename, err.Error())
+		os.Exit(exitCode(err))
+	}
+
+	if len(doc.Content) == 0 {
+		doc.Kind = yaml.DocumentNode
+		doc.Content = []*yaml.Node{{Kind: yaml.MappingNode}}
+	}
+
+	return doc
+}
+
+// findConfigKey returns the value node of the dotted key, matching
+// the keys case insensitively like viper does. If create is true
+// missing keys are added. Returns nil if the key does not exist.
+func findConfigKey(doc *yaml.Node, key string, create bool) *yaml.Node {
+	node := doc.Content[0]
+
+	for _, part := range strings.Split(key, ".") {
+		if node.Kind != yaml.MappingNode {
+			return nil
+		}
+
+		var value *yaml.Node
+
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if strings.EqualFold(node.Content[i].Value, part) {
+				value = node.Content[i+1]
+
+				break
+			}
+		}
+
+		if value == nil {
+			if !create {
+				return nil
+			}
+
+			value = &yaml.Node{Kind: yaml.MappingNode}
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: part}, value)
+		}
+
+		node = value
+	}
+
+	return node
+}
+
+// validateConfig returns the problems found in the config, with the
+// options set in the environment overriding the content.
+func validateConfig(content []byte) []string {
+	v := viper.New()
+	v.SetConfigType("yaml")
+	readEnv(v)
+
+	if err := v.ReadConfig(bytes.NewReader(content)); err != nil {
+		return []string{err.Error()}
+	}
+
+	problems := []string{}
+
+	for _, key := range configRequired {
+		if v.GetString(key) == "" {
+			problems = append(problems, key+" is not set")
+		}
+	}
+
+	passwordTypes := []string{"pass", "gpg", "keyring", "op", "plain", "apitoken", "pat"}
+	if pt := v.GetString("passwordtype"); pt != "" && !slices.Contains(passwordTypes, pt) {
+		problems = append(problems, "passwordtype must be one of pass, gpg, keyring, op, plain, apitoken or pat")
+	}
+
+	if v.GetString("passwordtype") == "op" && !strings.HasPrefix(v.GetString("password"), "op://") {
+		problems = append(problems, "password must be a 1Password secret reference, e.g. op://vault/jira/password")
+	}
+
+	deployment := v.GetString("deployment")
+	if deployment == "" {
+		deployment = "auto"
+	}
+
+	cloud := jira.IsCloud(types.Config{JiraURL: v.GetString("JiraURL"), Deployment: deployment})
+
+	switch v.GetString("passwordtype") {
+	case "apitoken":
+		problems = append(problems, types.APITokenProblems(v.GetString("username"), v.GetString("password"))...)
+
+		if !cloud {
+			problems = append(problems, "API tokens only work with Jira Cloud, set deployment to cloud")
+		}
+	case "pat":
+		if cloud {
+			problems = append(problems, "personal access tokens only work with Jira Data Center, use apitoken on Jira Cloud")
+		}
+	}
+
+	if d := v.GetString("deployment"); d != "" && !slices.Contains([]string{"server", "cloud", "auto"}, strings.ToLower(d)) {
+		problems = append(problems, "deployment must be one of server, cloud or auto")
+	}
+
+	switch strings.ToLower(v.GetString("authMode")) {
+	case "", "basic":
+	case "session":
+		if cloud {
+			problems = append(problems, "session auth only works with Jira Server and Data Center")
+		}
+
+		if pt := v.GetString("passwordtype"); pt == "apitoken" || pt == "pat" {
+			problems = append(problems, "session auth needs a password, not a token")
+		}
+	default:
+		problems = append(problems, "authMode must be basic or session")
+	}
+
+	if (v.GetString("clientCert") == "") != (v.GetString("clientKey") == "") {
+		problems = append(problems, "clientCert and clientKey must be set together")
+	}
+
+	if _, err := calendar.ParseRule(v.GetString("weekNumbering")); err != nil {
+		problems = append(problems, "weekNumbering must be iso or us")
+	}
+
+	if w := strings.ToLower(v.GetString("activeIssue.whenStale")); w != "" && w != "ask" && w != "clear" {
+		problems = append(problems, "activeIssue.whenStale must be ask or clear")
+	}
+
+	if v.IsSet("profiles." + defaultContext) {
+		problems = append(problems, "the profile name default is taken by the main config")
+	}
+
+	check := func(keys []string, typ string, convert func(interface{}) error) {
+		for _, key := range keys {
+			if v.IsSet(key) {
+				if err := convert(v.Get(key)); err != nil {
+					problems = append(problems, fmt.Sprintf("%s must be a %s", key, typ))
+				}
+			}
+		}
+	}
+
+	check(configBools, "boolean", func(i interface{}) error { _, err := cast.ToBoolE(i); return err })
+	check(configInts, "whole number", func(i interface{}) error { _, err := cast.ToIntE(i); return err })
+	check(configFloats, "number", func(i interface{}) error { _, err := cast.ToFloat64E(i); return err })
+	check(configDurations, "duration, like 4h", func(i interface{}) error { _, err := cast.ToDurationE(i); return err })
+
+	return problems
+}
+
+func printConfigProblems(problems []string) {
+	fmt.Printf("%sThe config is not valid:%s\n", format.Color.Red, format.Color.Nocolor)
+
+	for _, p := range problems {
+		fmt.Printf("  - %s\n", p)
+	}
+}
+
+func writeConfig(filename string, content []byte) {
+	createFolder(filepath.Dir(filename))
+
+	if err := os.WriteFile(filename, content, 0o600); err != nil {
+		fmt.Printf("Failed to write %s - %s\n", filename, err.Error())
+		os.Exit(exitCode(err))
+	}
+
+	success("Successfully updated %s", filename)
+}
+
+const configInitHeader = `# Written by gojira config init. See config-example.yaml in the
+# gojira repository for all the settings.
+`
+
+// initAnswers is the config written by `config init`.
+type initAnswers struct {
+	JiraURL             string  `yaml:"JiraURL"` //nolint:tagliatelle
+	Username            string  `yaml:"username"`
+	Password            string  `yaml:"password,omitempty"`
+	PasswordType        string  `yaml:"passwordtype,omitempty"`
+	PasswordCache       string  `yaml:"passwordCache,omitempty"`
+	AuthMode            string  `yaml:"authMode,omitempty"`
+	UseTimesheetPlugin  bool    `yaml:"useTimesheetPlugin"`
+	NumWorkingDays      int     `yaml:"numberOfWorkingDays"`
+	WorkingHoursPerDay  float64 `yaml:"numberOfWorkingHoursPerDay"`
+	WorkingHoursPerWeek float64 `yaml:"numberOfWorkingHoursPerWeek"`
+	CountryCode         string  `yaml:"countryCode,omitempty"`
+}
+
+// askConfig asks for the settings of a new config file. The password
+// is stored in the keyring right away, if that is where it is kept.
+func askConfig(in *bufio.Reader) initAnswers {
+	a := initAnswers{}
+
+	fmt.Println("Press enter to use the value in brackets.")
+
+	for a.JiraURL == "" {
+		a.JiraURL = strings.TrimSuffix(ask(in, "Jira URL, e.g. https://jira.yourcompany.com", ""), "/")
+	}
+
+	cloud := jira.IsCloud(types.Config{JiraURL: a.JiraURL, Deployment: "auto"})
+	token := "pat"
+
+	question := "Username"
+	if cloud {
+		question = "Email of your Atlassian account"
+		token = "apitoken"
+	}
+
+	for a.Username == "" {
+		a.Username = ask(in, question, "")
+	}
+
+	fmt.Println("\nHow is the password kept:")
+	fmt.Println("  keyring   in the keyring of the operating system")
+	fmt.Println("  pass      in passwordstore")
+	fmt.Println("  op        in 1Password")
+	fmt.Printf("  %-9s in the config file, as it is a token\n", token)
+	fmt.Println("  prompt    nowhere, ask for it when needed")
+
+	methods := []string{"keyring", "pass", "op", token, "prompt"}
+
+	a.PasswordType = ask(in, "Password", "keyring")
+	for !slices.Contains(methods, a.PasswordType) {
+		a.PasswordType = ask(in, "Password must be one of "+strings.Join(methods, ", "), "keyring")
+	}
+
+	switch a.PasswordType {
+	case "keyring":
+		a.Password = ask(in, "Name of the password in the keyring", "jira")
+
+		if err := keyring.Set(types.KeyringService, a.Password, askSecret(in, "Password: ")); err != nil {
+			fmt.Printf("Failed to store the password in the keyring - %s\n", err.Error())
+			os.Exit(exitCode(err))
+		}
+	case "pass":
+		a.Password = ask(in, "Name of the password in pass", "jira")
+	case "op":
+		a.Password = ask(in, "Secret reference, e.g. op://vault/jira/password", "")
+	case "prompt":
+		a.PasswordType = ""
+		a.PasswordCache = ask(in, "Remember the password for, e.g. 30m, or 0 to always ask", "0")
+
+		if a.PasswordCache == "0" {
+			a.PasswordCache = ""
+		}
+	default:
+		a.Password = askSecret(in, "Token: ")
+	}
+
+	if !cloud && a.PasswordType != "pat" && askYes(in, "Log in once with a session, instead of sending the password every time", false) {
+		a.AuthMode = "session"
+	}
+
+	a.UseTimesheetPlugin = askYes(in, "Use the timesheet plugin for the worklogs", false)
+
+	a.NumWorkingDays = int(askNumber(in, "Working days per week", 5))
+	a.WorkingHoursPerDay = askNumber(in, "Working hours per day", 7.5)
+	a.WorkingHoursPerWeek = askNumber(in, "Working hours per week", float64(a.NumWorkingDays)*a.WorkingHoursPerDay)
+	a.CountryCode = strings.ToUpper(ask(in, "Two letter country code for the public holidays, or none", ""))
+
+	return a
+}
+
+// checkLogin logs in to Jira with the answers, and returns if it succeeded.
+func checkLogin(a initAnswers) bool {
+	cfg := types.Config{
+		JiraURL:      a.JiraURL,
+		Username:     a.Username,
+		Password:     a.Password,
+		PasswordType: a.PasswordType,
+		AuthMode:     a.AuthMode,
+		Deployment:   "auto",
+	}
+
+	client := jira.NewClient(cfg)
+	client.SetTimeout(RequestTimeout)
+
+	user, err := client.GetMyself(ctx)
+	if err != nil {
+		fmt.Printf("%sFailed to log in to %s - %s%s\n", format.Color.Red, a.JiraURL, err.Error(), format.Color.Nocolor)
+
+		return false
+	}
+
+	success("Logged in as %s (%s)", user.DisplayName, user.Name)
+
+	return true
+}
+
+// ask returns the answer to the question, or the default if the answer is empty.
+func ask(in *bufio.Reader, question, def string) string {
+	if def != "" {
+		question += " [" + def + "]"
+	}
+
+	fmt.Print(question + ": ")
+
+	line, err := in.ReadString('\n')
+	if err != nil && line == "" {
+		fmt.Println("\nCancelled by user")
+		os.Exit(exitCancelled)
+	}
+
+	if answer := strings.TrimSpace(line); answer != "" {
+		return answer
+	}
+
+	return def
+}
+
+func askYes(in *bufio.Reader, question string, def bool) bool {
+	options := " [y/N]"
+	if def {
+		options = " [Y/n]"
+	}
+
+	for {
+		fmt.Print(question + options + ": ")
+
+		line, err := in.ReadString('\n')
+		if err != nil && line == "" {
+			fmt.Println("\nCancelled by user")
+			os.Exit(exitCancelled)
+		}
+
+		switch strings.ToLower(strings.TrimSpace(line)) {
+		case "":
+			return def
+		case "y", "yes":
+			return true
+		case "n", "no":
+			return false
+		}
+	}
+}
+
+func askNumber(in *bufio.Reader, question string, def float64) float64 {
+	for {
+		answer := ask(in, question, strconv.FormatFloat(def, 'f', -1, 64))
+
+		if n, err := strconv.ParseFloat(answer, 64); err == nil && n > 0 {
+			return n
+		}
+
+		fmt.Println("Invalid number")
+	}
+}
+
+// askSecret reads the secret without echo from the terminal,
+// or else the next line of the input.
+func askSecret(in *bufio.Reader, prompt string) string {
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		return readSecret(prompt)
+	}
+
+	line, _ := in.ReadString('\n')
+
+	return strings.TrimSpace(line)
+}
@@ -0,0 +1,56 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mhersson/gojira/pkg/util"
+	"github.com/mhersson/gojira/pkg/util/convert"
+)
+
+// confirm asks the user to confirm the change if the confirm config
+// requires it, and returns if the change can be made. --yes answers
+// yes without asking.
+func confirm(required bool, question string) bool {
+	if !required || AssumeYes {
+		return true
+	}
+
+	if util.GetUserInput(question+" [y/N]: ", "[y|n]") == "y" {
+		return true
+	}
+
+	fmt.Println("Cancelled by user")
+
+	return false
+}
+
+// confirmWorklog returns if a worklog of the duration can be added,
+// asking first if it is longer than the confirm config allows.
+func confirmWorklog(key string, duration time.Duration) bool {
+	hours := Cfg.Confirm.WorklogHours
+
+	return confirm(hours > 0 && duration.Hours() > hours,
+		fmt.Sprintf("Log %s on %s, more than %g hours", convert.DurationToDaysAndHours(duration), key, hours))
+}
@@ -0,0 +1,247 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/mhersson/gojira/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+// defaultContext is the name of the context using the main config.
+const defaultContext = "default"
+
+var (
+	ContextName string // Used by all commands to override the active context
+	ContextFile = path.Join(StateFolder, "context")
+)
+
+// currentContext is the context used by the command.
+var currentContext = defaultContext
+
+// mainProfile is the Jira server of the main config, kept when
+// another context is used so it can still be reached as a profile.
+var mainProfile types.Profile
+
+const contextUsage string = `Switch between the Jira servers in the profiles of the config. The
+main config is the context named default, and every profile is a
+context of its own, with its own active issue, board, timer and caches.
+
+Use --context with any command to use another context than the active
+one, just for that command.
+
+Usage:
+  gojira context list
+  gojira context use CONTEXT
+  gojira context show
+
+Aliases:
+  context, ctx
+
+Available Commands:
+  list        List the contexts, marking the active one
+  show        Show the server and state folder of the active context
+  use         Make the context active
+
+Flags:
+  -h, --help                   help for context
+
+Example:
+  gojira context use customer
+  gojira get all --context default
+`
+
+var contextCmd = &cobra.Command{
+	Use:     "context",
+	Aliases: []string{"ctx"},
+	Short:   "Switch between the Jira servers in the profiles",
+	Args:    cobra.NoArgs,
+}
+
+var contextListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"l"},
+	Short:   "List the contexts, marking the active one",
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		names := []string{defaultContext}
+
+		for name := range Cfg.Profiles {
+			names = append(names, name)
+		}
+
+		sort.Strings(names[1:])
+
+		for _, name := range names {
+			p, _ := lookupProfile(name)
+
+			mark := " "
+			if name == currentContext {
+				mark = "*"
+			}
+
+			fmt.Printf("%s %-20s %-40s %s\n", mark, name, p.JiraURL, p.Username)
+		}
+	},
+}
+
+var contextUseCmd = &cobra.Command{
+	Use:   "use CONTEXT",
+	Short: "Make the context active",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := strings.ToLower(args[0])
+
+		if _, ok := lookupProfile(name); !ok {
+			fmt.Printf("There is no profile %s in the config\n", args[0])
+			os.Exit(exitNotFound)
+		}
+
+		createStateFolder()
+
+		if err := os.WriteFile(ContextFile, []byte(name), 0o600); err != nil {
+			fmt.Println("Failed to set the active context")
+			os.Exit(exitFailure)
+		}
+
+		success("Switched to context %s", name)
+	},
+}
+
+var contextShowCmd = &cobra.Command{
+	Use:     "show",
+	Aliases: []string{"s"},
+	Short:   "Show the server and state folder of the active context",
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Printf("Context:    %s\n", currentContext)
+		fmt.Printf("Jira:       %s\n", Cfg.JiraURL)
+		fmt.Printf("Username:   %s\n", Cfg.Username)
+		fmt.Printf("Deployment: %s\n", Cfg.Deployment)
+		fmt.Printf("State:      %s\n", path.Dir(IssueFile))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(contextCmd)
+	contextCmd.AddCommand(contextListCmd)
+	contextCmd.AddCommand(contextUseCmd)
+	contextCmd.AddCommand(contextShowCmd)
+
+	contextCmd.SetUsageTemplate(contextUsage)
+	contextListCmd.SetUsageTemplate(contextUsage)
+	contextUseCmd.SetUsageTemplate(contextUsage)
+	contextShowCmd.SetUsageTemplate(contextUsage)
+}
+
+// activeContext returns the context given with --context, or the
+// one made active with `context use`.
+func activeContext() string {
+	if ContextName != "" {
+		return strings.ToLower(ContextName)
+	}
+
+	content, err := os.ReadFile(ContextFile)
+	if err != nil || strings.TrimSpace(string(content)) == "" {
+		return defaultContext
+	}
+
+	return strings.ToLower(strings.TrimSpace(string(content)))
+}
+
+// lookupProfile returns the profile of the context, where the default
+// context is the Jira server of the main config.
+func lookupProfile(name string) (types.Profile, bool) {
+	if name == defaultContext {
+		return mainProfile, true
+	}
+
+	p, ok := Cfg.Profiles[name]
+
+	return p, ok && p.JiraURL != ""
+}
+
+// configProfile returns the Jira server and credentials of the config
+// as a profile, the profile of the default context.
+func configProfile(cfg types.Config) types.Profile {
+	return types.Profile{
+		JiraURL:      cfg.JiraURL,
+		Username:     cfg.Username,
+		Password:     cfg.Password,
+		PasswordType: cfg.PasswordType,
+		Deployment:   cfg.Deployment,
+		AuthMode:     cfg.AuthMode,
+	}
+}
+
+// useContext applies the profile of the context to the config, and
+// keeps the state of the context in a folder of its own. The main
+// config keeps its state directly in the state folder.
+func useContext() {
+	mainProfile = configProfile(Cfg)
+
+	currentContext = defaultContext
+
+	name := activeContext()
+	if name == defaultContext {
+		return
+	}
+
+	p, ok := lookupProfile(name)
+	if !ok {
+		if ContextName != "" {
+			fmt.Printf("There is no profile %s in the config\n", ContextName)
+			os.Exit(exitNotFound)
+		}
+
+		// Do not lock the user out with a profile removed from the config
+		fmt.Fprintf(os.Stderr, "There is no profile %s in the config, using the default context\n", name)
+
+		return
+	}
+
+	currentContext = name
+	Cfg = withProfile(Cfg, p)
+
+	folder := path.Join(StateFolder, "contexts", name)
+	if err := os.MkdirAll(folder, 0o755); err != nil {
+		fmt.Printf("Failed to create %s - %s\n", folder, err.Error())
+		os.Exit(exitCode(err))
+	}
+
+	IssueFile = path.Join(folder, "issue")
+	IssueTypeFile = path.Join(folder, "issuetype")
+	BoardFile = path.Join(folder, "board")
+	BoardsFile = path.Join(folder, "boards")
+	TimerFile = path.Join(folder, "timer")
+	BudgetFile = path.Join(folder, "budgets.json")
+	CacheFolder = path.Join(folder, "cache")
+	IssueCacheFile = path.Join(CacheFolder, "issues.json")
+	ExistsCacheFile = path.Join(CacheFolder, "exists.json")
+	NoTimesheetFile = path.Join(CacheFolder, "no-timesheet-plugin")
+	SnapshotFolder = path.Join(folder, "snapshots")
+}
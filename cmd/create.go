@@ -31,7 +31,6 @@ import (
 
 	"github.com/spf13/cobra"
 
-	"github.com/mhersson/gojira/pkg/jira"
 	"github.com/mhersson/gojira/pkg/types"
 	"github.com/mhersson/gojira/pkg/util"
 	"github.com/mhersson/gojira/pkg/util/format"
@@ -50,50 +49,93 @@ three backticks will be converted to {noformat}.
 After all data is collected they must be verified and confirmed
 by the user, and only then will the request be sent to JIRA.
 
+Files can be attached, and the issue linked to other issues, right
+after it is created. The link is given as the link description and
+the issue key, e.g. "blocks:OSE-100" or "is blocked by:OSE-100".
+The files and links are checked before the issue is created, but if
+attaching or linking fails anyway, the issue is kept, and the steps
+that failed are listed.
+
 Usage:
   gojira create [PROJECT_KEY] [flags]
 
 Flags:
-  -h, --help   help for create
+  -a, --attach [FILE]          attach the file, can be repeated
+  -h, --help                   help for create
+  -l, --link [TYPE:ISSUE KEY]  link to the issue, can be repeated
+
+Example:
+  gojira create OSE --attach trace.log --attach screenshot.png --link "blocks:OSE-100"
 `
 
+// Used by `create`.
+var (
+	CreateAttachments []string
+	CreateLinks       []string
+)
+
+// issueLink is a link from the issue being created,
+// or to it when Inward is true.
+type issueLink struct {
+	Type   string
+	Key    string
+	Inward bool
+}
+
 // createCmd represents the create command.
 var createCmd = &cobra.Command{
 	Use:   "create",
 	Short: "Create new issue",
 	Args:  cobra.MatchAll(cobra.ExactArgs(1), cobra.ArbitraryArgs),
 	Run: func(cmd *cobra.Command, args []string) {
+		exitIfReadOnly()
+
 		key := strings.ToUpper(args[0])
-		validProjects := jira.GetValidProjects()
+		validProjects := must(JiraClient.GetValidProjects(ctx))
 		project := validate.ProjectKey(key, validProjects)
 		if project.ID == "" {
 			fmt.Printf("%s is not a valid project key\n", key)
-			os.Exit(1)
+			os.Exit(exitUsage)
 		}
+		checkAttachments(CreateAttachments)
+		links := parseIssueLinks(CreateLinks)
+
 		fmt.Printf("Creating new %s issue\n", project.Key)
 		summary, rawSummary := getUserInputSummary()
 		issueTypeID, issueTypeName := getUserInputIssueType(project)
 		priorityID, priorityName := getUserInputPriority()
 		desc, rawDesc := getUserInputDescription()
 
-		getUserInputConfirmOk(project, issueTypeName, priorityName, rawSummary, rawDesc)
+		if !getUserInputConfirmOk(project, issueTypeName, priorityName, rawSummary, rawDesc) {
+			os.Exit(exitCancelled)
+		}
 
-		newKey, err := jira.CreateNewIssue(project, issueTypeID, priorityID, summary, desc)
+		newKey, err := JiraClient.CreateNewIssue(ctx, project, issueTypeID, priorityID, summary, desc)
 		if err != nil {
 			fmt.Printf("Failed to create issue - %s\n", err.Error())
 			fmt.Println(newKey)
-			os.Exit(1)
+			os.Exit(exitCode(err))
 		}
 
 		fmt.Printf("%sNew issue has got key %s%s\n", format.Color.Blue, newKey, format.Color.Nocolor)
 
+		failed := attachAndLink(newKey, CreateAttachments, links)
+		for _, f := range failed {
+			fmt.Printf("%sFailed to %s%s\n", format.Color.Red, f, format.Color.Nocolor)
+		}
+
 		ans := util.GetUserInput("Do you want to set the new issue active [y/N]: ", "[y|n]")
 		if ans == "y" {
 			setActiveIssue(newKey)
 		}
 
-		fmt.Printf("\n%sSuccessfully created new issue - run describe to see the details%s\n\n",
-			format.Color.Green, format.Color.Nocolor)
+		if len(failed) > 0 {
+			fmt.Printf("\n%s%s was created, but %d of %d attachments and links failed%s\n\n",
+				format.Color.Red, newKey, len(failed), len(CreateAttachments)+len(links), format.Color.Nocolor)
+			os.Exit(exitFailure)
+		}
+
+		success("\nSuccessfully created new issue - run describe to see the details\n")
 	},
 }
 
@@ -101,10 +143,118 @@ func init() {
 	rootCmd.AddCommand(createCmd)
 
 	createCmd.SetUsageTemplate(createUsage)
+	createCmd.Flags().StringArrayVarP(&CreateAttachments, "attach", "a", nil, "attach the file, can be repeated")
+	createCmd.Flags().StringArrayVarP(&CreateLinks, "link", "l", nil, "link to the issue, e.g. blocks:OSE-100")
+}
+
+// checkAttachments exits before the issue is created
+// if one of the files can not be attached.
+func checkAttachments(files []string) {
+	for _, f := range files {
+		info, err := os.Stat(f)
+		if err != nil {
+			fmt.Printf("Can not attach %s - %s\n", f, err.Error())
+			os.Exit(exitUsage)
+		}
+
+		if info.IsDir() {
+			fmt.Printf("Can not attach %s - it is a directory\n", f)
+			os.Exit(exitUsage)
+		}
+	}
+}
+
+// parseIssueLinks parses links on the format "description:ISSUE KEY",
+// where the description is the name, or the inward or outward
+// description of a link type. It exits if a link is invalid.
+func parseIssueLinks(specs []string) []issueLink {
+	links := []issueLink{}
+
+	if len(specs) == 0 {
+		return links
+	}
+
+	linkTypes := must(JiraClient.GetIssueLinkTypes(ctx))
+
+	for _, spec := range specs {
+		i := strings.LastIndex(spec, ":")
+		if i < 1 || i == len(spec)-1 {
+			fmt.Printf("Invalid link %s - must be on the format description:ISSUE KEY\n", spec)
+			os.Exit(exitUsage)
+		}
+
+		description, key := strings.TrimSpace(spec[:i]), strings.ToUpper(strings.TrimSpace(spec[i+1:]))
+
+		link := findLinkType(linkTypes, description)
+		link.Key = key
+
+		if link.Type == "" {
+			fmt.Printf("Invalid link %s - there is no link type %s\n", spec, description)
+			os.Exit(exitUsage)
+		}
+
+		if len(must(JiraClient.GetIssues(ctx, "key = "+key))) != 1 {
+			fmt.Printf("Invalid link %s - issue %s does not exist\n", spec, key)
+			os.Exit(exitNotFound)
+		}
+
+		links = append(links, link)
+	}
+
+	return links
+}
+
+// findLinkType returns the link type with the description as its name or
+// outward description, or as its inward description with Inward set.
+// The type is empty if there is no such link type.
+func findLinkType(linkTypes []types.IssueLinkType, description string) issueLink {
+	for _, t := range linkTypes {
+		switch {
+		case strings.EqualFold(description, t.Outward), strings.EqualFold(description, t.Name):
+			return issueLink{Type: t.Name}
+		case strings.EqualFold(description, t.Inward):
+			return issueLink{Type: t.Name, Inward: true}
+		}
+	}
+
+	return issueLink{}
+}
+
+// attachAndLink attaches the files and adds the links to the new issue,
+// and returns a description of each step that failed.
+func attachAndLink(key string, files []string, links []issueLink) []string {
+	failed := []string{}
+
+	for _, f := range files {
+		if err := JiraClient.AddAttachment(ctx, key, f); err != nil {
+			failed = append(failed, fmt.Sprintf("attach %s - %s", f, err.Error()))
+
+			continue
+		}
+
+		fmt.Printf("Attached %s\n", f)
+	}
+
+	for _, l := range links {
+		from, to := key, l.Key
+		if l.Inward {
+			from, to = to, from
+		}
+
+		if err := JiraClient.LinkIssues(ctx, l.Type, from, to); err != nil {
+			failed = append(failed, fmt.Sprintf("link %s to %s - %s", l.Type, l.Key, err.Error()))
+
+			continue
+		}
+
+		fmt.Printf("Linked %s to %s (%s)\n", key, l.Key, l.Type)
+	}
+
+	return failed
 }
 
 func getUserInputPriority() (string, string) {
-	priorities := jira.GetPriorities()
+	priorities := must(JiraClient.GetPriorities(ctx))
 
 	fmt.Println("Choose issue priority:")
 
@@ -127,7 +277,7 @@ func getUserInputPriority() (string, string) {
 }
 
 func getUserInputIssueType(project types.Project) (string, string) {
-	issueTypes := jira.GetProjectIssueTypes(project.Key)
+	issueTypes := must(JiraClient.GetProjectIssueTypes(ctx, project.Key))
 
 	fmt.Println("Choose issue type:")
 
@@ -161,7 +311,7 @@ func getUserInputSummary() (string, string) {
 	input, _ := reader.ReadBytes('\n')
 
 	if input[0] == '\n' {
-		os.Exit(0)
+		os.Exit(exitCancelled)
 	}
 
 	st := strings.TrimSpace(string(input))
@@ -169,7 +319,7 @@ func getUserInputSummary() (string, string) {
 	summary, err := json.Marshal(st)
 	if err != nil {
 		fmt.Println("Failed to parse comment")
-		os.Exit(1)
+		os.Exit(exitFailure)
 	}
 
 	// Remove the {} around the comment
@@ -179,10 +329,10 @@ func getUserInputSummary() (string, string) {
 }
 
 func getUserInputDescription() (string, string) {
-	desc, err := captureInputFromEditor("", "description*")
+	desc, err := captureTextFromEditor("", "description*")
 	if err != nil {
 		fmt.Println("Failed to read user input")
-		os.Exit(1)
+		os.Exit(exitFailure)
 	}
 
 	escaped := util.MakeStringJSONSafe(string(desc))
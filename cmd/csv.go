@@ -0,0 +1,240 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mhersson/gojira/pkg/types"
+	"github.com/mhersson/gojira/pkg/util/convert"
+)
+
+var (
+	sprintIssuesCSVHeader = []string{
+		"Sprint", "Key", "Type", "Priority", "Summary", "Estimate", "Epic", "Done", "Assignee",
+	}
+	sprintChangesCSVHeader = []string{"Sprint", "Time", "Change", "Key", "Summary", "By"}
+)
+
+func printIssuesCSV(issues []types.Issue, printClosed bool) {
+	rows := [][]string{}
+	header := []string{"Key", "Type", "Priority", "Summary", "Status", "Assignee"}
+
+	if IssueOrder == "rank" {
+		header = append([]string{"Position"}, header...)
+	}
+
+	for i, v := range issues {
+		if !printClosed && slices.Contains([]string{"Closed", "Resolved", "Verified"}, v.Fields.Status.Name) {
+			continue
+		}
+
+		row := []string{
+			v.Key, v.Fields.IssueType.Name, v.Fields.Priority.Name,
+			v.Fields.Summary, v.Fields.Status.Name, v.Fields.Assignee.DisplayName,
+		}
+
+		if IssueOrder == "rank" {
+			row = append([]string{strconv.Itoa(i + 1)}, row...)
+		}
+
+		rows = append(rows, row)
+	}
+
+	printCSV(header, rows)
+}
+
+func printColumnsCSV(issues []types.RawIssue, columns []column) {
+	header := []string{}
+	for _, c := range columns {
+		header = append(header, c.Header)
+	}
+
+	printCSV(header, columnRows(issues, columns))
+}
+
+func sprintIssuesCSV(
+	sprint *types.Sprint, issues []types.SprintIssue, issueTypes []types.IssueType, priorities []types.Priority,
+) [][]string {
+	rows := [][]string{}
+
+	for _, id := range sprint.IssuesIDs {
+		for _, v := range issues {
+			if v.ID != id {
+				continue
+			}
+
+			rows = append(rows, []string{
+				sprint.Name, v.Key,
+				getIssueTypeNameByID(issueTypes, v.TypeID),
+				getPriorityNameByID(priorities, v.PriorityID),
+				v.Summary,
+				convert.SecondsToHoursAndMinutes(int(v.EstimateStatistic.StatFieldValue.Value), false),
+				v.Epic, strconv.FormatBool(v.Done), v.AssigneeName,
+			})
+
+			break
+		}
+	}
+
+	return rows
+}
+
+func sprintChangesCSV(sprint *types.Sprint, changes []sprintChange) [][]string {
+	rows := [][]string{}
+
+	for _, c := range changes {
+		t := ""
+		if !c.Time.IsZero() {
+			t = c.Time.Local().Format("2006-01-02 15:04")
+		}
+
+		rows = append(rows, []string{sprint.Name, t, c.Change, c.Key, c.Summary, c.Author})
+	}
+
+	return rows
+}
+
+func printEpicsCSV(progress []epicProgress) {
+	rows := [][]string{}
+
+	for _, p := range progress {
+		rows = append(rows, []string{
+			p.Epic.Key, p.Epic.Fields.Summary, p.Epic.Fields.Status.Name,
+			strconv.Itoa(p.Issues), strconv.Itoa(p.Done), strconv.Itoa(p.Percent()),
+		})
+	}
+
+	printCSV([]string{"Key", "Summary", "Status", "Issues", "Done", "Percent Done"}, rows)
+}
+
+func printLabelStatsCSV(stats []labelStats) {
+	rows := [][]string{}
+
+	for _, l := range stats {
+		rows = append(rows, []string{
+			l.Label, strconv.Itoa(l.Issues), strconv.Itoa(l.TimeSpent), strconv.FormatFloat(l.Share, 'f', 4, 64),
+		})
+	}
+
+	printCSV([]string{"Label", "Issues", "Spent", "Share"}, rows)
+}
+
+func printVersionBudgetCSV(budgets []epicBudget) {
+	rows := [][]string{}
+
+	for _, b := range budgets {
+		rows = append(rows, []string{
+			b.Key, b.Summary, strconv.Itoa(b.Issues), strconv.Itoa(b.OriginalEstimate),
+			strconv.Itoa(b.TimeSpent), strconv.Itoa(b.Remaining), strconv.Itoa(b.Variance()),
+		})
+	}
+
+	printCSV([]string{"Epic", "Summary", "Issues", "Estimate", "Spent", "Remaining", "Variance"}, rows)
+}
+
+func printWorklogsCSV(worklogs []types.Worklog) {
+	rows := [][]string{}
+
+	for _, v := range worklogs {
+		attrs, _ := v.Attributes()
+
+		rows = append(rows, []string{
+			v.Started[:16], v.ID, v.Author.DisplayName,
+			v.TimeSpent, strconv.Itoa(v.TimeSpentSeconds),
+			strconv.FormatBool(attrs.Billable), strconv.FormatBool(attrs.Overtime), attrs.Account,
+			v.Comment,
+		})
+	}
+
+	printCSV([]string{
+		"Started", "ID", "Author", "Time Spent", "Seconds",
+		"Billable", "Overtime", "Account", "Comment",
+	}, rows)
+}
+
+func printMyWorklogCSV(ti []types.TimeSpentUserIssue) {
+	rows := [][]string{}
+
+	for _, v := range ti {
+		rows = append(rows, []string{
+			v.Date, v.Key, v.Summary, v.TimeSpent, strconv.Itoa(v.TimeSpentSeconds),
+		})
+	}
+
+	printCSV([]string{"Date", "Key", "Summary", "Time Spent", "Seconds"}, rows)
+}
+
+func printTimesheetCSV(worklogs []types.SimplifiedTimesheet) {
+	rows := [][]string{}
+
+	for _, w := range worklogs {
+		rows = append(rows, []string{
+			w.StartDate, w.Key, w.Summary, w.Comment,
+			convert.SecondsToHoursAndMinutes(w.TimeSpent, false), strconv.Itoa(w.TimeSpent),
+		})
+	}
+
+	printCSV([]string{"Started", "Key", "Summary", "Comment", "Time Spent", "Seconds"}, rows)
+}
+
+func printStatisticsCSV(weeks []types.Week) {
+	rows := [][]string{}
+
+	for _, week := range weeks {
+		rows = append(rows, []string{
+			strconv.Itoa(week.Number()),
+			week.StartDate.Format("2006-01-02"),
+			week.EndDate.Format("2006-01-02"),
+			strconv.Itoa(week.WorkDays()),
+			strconv.Itoa(week.PublicHolidays),
+			strconv.FormatFloat(week.Average(), 'f', 2, 64),
+			strconv.FormatFloat(week.TotalTime(), 'f', 2, 64),
+		})
+	}
+
+	printCSV([]string{"Week", "Start", "End", "Workdays", "Holidays", "Average", "Total"}, rows)
+}
+
+func printWorkloadsCSV(workloads []workload) {
+	rows := [][]string{}
+
+	for _, w := range workloads {
+		rows = append(rows, []string{
+			w.Assignee, strconv.Itoa(w.Issues), strconv.Itoa(w.Remaining), strconv.Itoa(w.Overdue),
+		})
+	}
+
+	printCSV([]string{"Assignee", "Issues", "Remaining Seconds", "Overdue"}, rows)
+}
+
+func printMigrationsCSV(migrations []migration) {
+	rows := [][]string{}
+
+	for _, m := range migrations {
+		rows = append(rows, []string{m.From, m.To, m.Summary, strings.Join(m.Problems, "; ")})
+	}
+
+	printCSV([]string{"From", "To", "Summary", "Problems"}, rows)
+}
@@ -0,0 +1,218 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mhersson/gojira/pkg/types"
+	"github.com/mhersson/gojira/pkg/util/validate"
+)
+
+const deleteWorklogUsage string = `This command deletes a worklog entry.
+
+By default the worklog is deleted from the active issue, but this can be
+changed by adding the issue key as argument. When this is the case the
+argument order is important, and the issue key must always come first.
+
+The worklog id can be found by running "get worklog". Set deletes under
+confirm in the config file to be asked before the worklog is deleted.
+
+Usage:
+  gojira delete worklog [ISSUE KEY] <WORKLOG ID> [flags]
+
+Aliases:
+  worklog, w
+
+Flags:
+  -h, --help                   help for worklog
+
+Example:
+  # Delete worklog 123456 from GOJIRA-1
+  gojira delete worklog GOJIRA-1 123456
+`
+
+const deleteLinkUsage string = `This command deletes the links between two issues.
+
+By default the links of the active issue are deleted, but this can be
+changed by adding the issue key as argument. When this is the case the
+argument order is important, and the issue key must always come first.
+
+All links between the issues are deleted, unless the link is given by
+the description of the link type as it reads from the issue, e.g. blocks,
+or by the name of the link type. Set deletes under confirm in the config
+file to be asked before the links are deleted.
+
+Usage:
+  gojira delete link [ISSUE KEY] [LINK] <ISSUE KEY> [flags]
+
+Aliases:
+  link, l
+
+Flags:
+  -h, --help                   help for link
+
+Example:
+  # GOJIRA-1 no longer blocks GOJIRA-2
+  gojira delete link GOJIRA-1 blocks GOJIRA-2
+`
+
+var deleteCmd = &cobra.Command{
+	Use:     "delete",
+	Short:   "Delete a worklog or a link",
+	Args:    cobra.NoArgs,
+	Aliases: []string{"rm"},
+}
+
+var deleteWorklogCmd = &cobra.Command{
+	Use:     "worklog",
+	Short:   "Delete a worklog",
+	Aliases: []string{"w"},
+	Args:    cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		exitIfReadOnly()
+
+		worklogID := args[0]
+		if len(args) == 2 {
+			IssueKey = strings.ToUpper(args[0])
+			worklogID = args[1]
+		}
+
+		if !regexp.MustCompile(`^[0-9]+$`).MatchString(worklogID) {
+			fmt.Println("Invalid worklog id")
+			os.Exit(exitUsage)
+		}
+
+		checkIssueKey(&IssueKey, IssueFile)
+		checkPermission(IssueKey, "delete worklogs", permDeleteOwnWorklogs, permDeleteAllWorklogs)
+
+		worklog := getWorklog(IssueKey, worklogID)
+		if worklog.ID == "" {
+			fmt.Printf("Worklog %s does not exist on %s\n", worklogID, IssueKey)
+			os.Exit(exitNotFound)
+		}
+
+		if !confirm(Cfg.Confirm.Deletes, fmt.Sprintf("Delete worklog %s (%s) from %s",
+			worklog.ID, worklog.TimeSpent, IssueKey)) {
+			os.Exit(exitCancelled)
+		}
+
+		if err := JiraClient.DeleteWorklog(ctx, IssueKey, worklog.ID); err != nil {
+			fmt.Printf("Failed to delete worklog %s from %s - %s\n", worklog.ID, IssueKey, err.Error())
+			os.Exit(exitCode(err))
+		}
+
+		success("Successfully deleted worklog %s (%s) from %s", worklog.ID, worklog.TimeSpent, IssueKey)
+	},
+}
+
+var deleteLinkCmd = &cobra.Command{
+	Use:     "link",
+	Short:   "Delete the links to another issue",
+	Aliases: []string{"l"},
+	Args:    cobra.RangeArgs(1, 3),
+	Run: func(cmd *cobra.Command, args []string) {
+		exitIfReadOnly()
+
+		if key := strings.ToUpper(args[0]); len(args) == 3 || (len(args) == 2 && validate.IssueKey(&key)) {
+			IssueKey = key
+			args = args[1:]
+		}
+
+		description := ""
+		if len(args) == 2 {
+			description = args[0]
+		}
+
+		linkedKey := strings.ToUpper(args[len(args)-1])
+		checkIssueKeys(IssueFile, &IssueKey, &linkedKey)
+		checkPermission(IssueKey, "link issues", permLinkIssues)
+
+		links := findIssueLinks(must(JiraClient.GetIssue(ctx, IssueKey)), linkedKey, description)
+		if len(links) == 0 {
+			fmt.Printf("%s is not linked to %s\n", IssueKey, linkedKey)
+			os.Exit(exitNotFound)
+		}
+
+		if !confirm(Cfg.Confirm.Deletes, fmt.Sprintf("Delete %d link(s) between %s and %s",
+			len(links), IssueKey, linkedKey)) {
+			os.Exit(exitCancelled)
+		}
+
+		for _, l := range links {
+			if err := JiraClient.DeleteIssueLink(ctx, l.ID); err != nil {
+				fmt.Printf("Failed to delete the link %s %s %s - %s\n", IssueKey, l.Description, linkedKey, err.Error())
+				os.Exit(exitCode(err))
+			}
+
+			success("Successfully deleted the link %s %s %s", IssueKey, l.Description, linkedKey)
+		}
+	},
+}
+
+// linkToDelete is a link from or to an issue, with the
+// description of the link type as it reads from the issue.
+type linkToDelete struct {
+	ID          string
+	Description string
+}
+
+// findIssueLinks returns the links between the issue and the linked issue,
+// only those matching the description if it is set.
+func findIssueLinks(issue types.IssueDescription, linkedKey, description string) []linkToDelete {
+	links := []linkToDelete{}
+
+	for _, l := range issue.Fields.IssueLinks {
+		link := linkToDelete{ID: l.ID}
+
+		switch linkedKey {
+		case l.OutwardIssue.Key:
+			link.Description = l.Type.Outward
+		case l.InwardIssue.Key:
+			link.Description = l.Type.Inward
+		default:
+			continue
+		}
+
+		if description == "" || strings.EqualFold(description, link.Description) ||
+			strings.EqualFold(description, l.Type.Name) {
+			links = append(links, link)
+		}
+	}
+
+	return links
+}
+
+func init() {
+	rootCmd.AddCommand(deleteCmd)
+
+	deleteCmd.AddCommand(deleteWorklogCmd)
+	deleteCmd.AddCommand(deleteLinkCmd)
+
+	deleteWorklogCmd.SetUsageTemplate(deleteWorklogUsage)
+	deleteLinkCmd.SetUsageTemplate(deleteLinkUsage)
+}
@@ -22,58 +22,145 @@ THE SOFTWARE.
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+	"slices"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/spf13/cobra"
 
-	"github.com/mhersson/gojira/pkg/jira"
 	"github.com/mhersson/gojira/pkg/types"
+	"github.com/mhersson/gojira/pkg/util"
+	"github.com/mhersson/gojira/pkg/util/convert"
 	"github.com/mhersson/gojira/pkg/util/format"
+	"github.com/mhersson/gojira/pkg/util/validate"
 )
 
 const describeUsage string = `
 By default the active issue will be described,
-but this can be changed by adding the issue key as argument.
+but this can be changed by adding one or more issue keys as arguments,
+or by selecting the issues with a jql filter. When describing more than
+one issue the issues are fetched concurrently and printed one after another.
+
+The time tracking of an epic, or an issue with subtasks, is also summed
+up with all the issues below it, like the Σ columns in Jira.
 
 Usage:
-  gojira describe [ISSUE KEY] [flags]
+  gojira describe [ISSUE KEY...] [flags]
 
 Aliases:
   describe, d
 
 Flags:
+  -f, --filter [JQL FILTER]    describe all issues matching the filter
   -h, --help                   help for describe
+  -o, --output [FORMAT]        output format, json, yaml or exec:FORMATTER
+      --template [TEMPLATE]    render the output with a go template, or @FILE
+
+Examples:
+  # Describe three issues
+  gojira describe OSE-1 OSE-2 OSE-3
+
+  # Describe all open bugs in project OSE as a json array
+  gojira describe -f "project = OSE and type = Bug and resolution = unresolved" -o json
+
+  # Pipe the json to an external formatter, which prints the output
+  gojira describe OSE-1 -o "exec:jq .fields.summary"
 `
 
+// Max number of issues fetched at the same time.
+const describeConcurrency = 5
+
+// issueDetails holds everything needed to describe an issue.
+type issueDetails struct {
+	Issue        types.IssueDescription
+	Epic         types.IssueDescription
+	Issues       []types.Issue
+	Watchers     *types.Watchers // nil if the watchers could not be fetched
+	RemoteLinks  []types.RemoteLink
+	Participants []types.User
+	Insight      []insightField
+	Rollup       *epicBudget
+}
+
+// insightField is an Insight (Assets) custom field and its objects.
+type insightField struct {
+	Name    string
+	Objects []types.InsightObject
+}
+
 // describeCmd represents the describe command.
 var describeCmd = &cobra.Command{
 	Use:     "describe",
 	Short:   "Display issue with all its gory details",
 	Aliases: []string{"d"},
-	Args:    cobra.MaximumNArgs(1),
+	Args:    cobra.ArbitraryArgs,
 	Run: func(cmd *cobra.Command, args []string) {
-		if len(args) == 1 {
-			IssueKey = strings.ToUpper(args[0])
-		}
-		jira.CheckIssueKey(&IssueKey, IssueFile)
-		issue := jira.GetIssue(IssueKey)
+		checkOutputFormat("json")
+
+		keys := []string{}
+
+		switch {
+		case JQLFilter != "":
+			for _, i := range must(JiraClient.GetIssues(ctx, JQLFilter)) {
+				keys = append(keys, i.Key)
+			}
+
+			if len(keys) == 0 {
+				fmt.Println("No issues matched the filter")
+
+				return
+			}
+		case len(args) > 0:
+			// GetIssue fails on keys that do not exist, so only the
+			// format is checked, and not with an extra request per key
+			for _, a := range args {
+				key := strings.ToUpper(a)
+				if !validate.IssueKey(&key) {
+					fail(jsonError{Code: "invalid_key", Message: "Invalid key " + key}, exitUsage)
+				}
 
-		var epic types.IssueDescription
-		if issue.Fields.Epic != "" {
-			epic = jira.GetIssue(issue.Fields.Epic)
+				keys = append(keys, key)
+			}
+		default:
+			checkIssueKey(&IssueKey, IssueFile)
+			keys = append(keys, IssueKey)
 		}
 
-		var issues []types.Issue
-		if issue.Fields.IssueType.Name == "Epic" {
-			issues = jira.GetIssuesInEpic(issue.Key)
+		details := getIssueDetails(keys)
+
+		if OutputFormat == "json" {
+			issues := []types.IssueDescription{}
+			for _, d := range details {
+				issues = append(issues, d.Issue)
+			}
+
+			if len(issues) == 1 && JQLFilter == "" {
+				printJSON(issues[0])
+			} else {
+				printJSON(issues)
+			}
+
+			return
 		}
 
-		printIssue(issue, epic)
+		startPager()
+		defer stopPager()
+
+		for i, d := range details {
+			if i > 0 {
+				fmt.Println("\n" + format.Color.Bold + strings.Repeat("=", 100) + format.Color.Nocolor)
+			}
+
+			printIssue(d)
 
-		if len(issues) > 0 {
-			fmt.Printf("\n%sIssues in Epic:%s\n", format.Color.Ul, format.Color.Nocolor)
-			printIssues(issues, false, true)
+			if len(d.Issues) > 0 {
+				fmt.Printf("\n%sIssues in Epic:%s\n", format.Color.Ul, format.Color.Nocolor)
+				printIssues(d.Issues, false, true)
+			}
 		}
 	},
 }
@@ -82,9 +169,164 @@ func init() {
 	rootCmd.AddCommand(describeCmd)
 
 	describeCmd.SetUsageTemplate(describeUsage)
+	describeCmd.Flags().StringVarP(&JQLFilter, "filter", "f", "", "describe all issues matching the jql filter")
+	addTemplateFlag(describeCmd)
+}
+
+// getIssueDetails fetches the issues concurrently, and
+// returns the details in the same order as the keys.
+func getIssueDetails(keys []string) []issueDetails {
+	details := make([]issueDetails, len(keys))
+	sem := make(chan struct{}, describeConcurrency)
+
+	var wg sync.WaitGroup
+
+	for i, key := range keys {
+		wg.Add(1)
+
+		go func(i int, key string) {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			details[i].Issue = must(JiraClient.GetIssueWithChangelog(ctx, key))
+
+			if details[i].Issue.Fields.Epic != "" {
+				details[i].Epic = must(JiraClient.GetIssue(ctx, details[i].Issue.Fields.Epic))
+			}
+
+			var rollup sync.WaitGroup
+
+			rollup.Add(1)
+
+			go func() {
+				defer rollup.Done()
+
+				details[i].Rollup = getRollup(details[i].Issue)
+			}()
+
+			if details[i].Issue.Fields.IssueType.Name == "Epic" {
+				details[i].Issues = must(JiraClient.GetIssuesInEpic(ctx, key))
+			}
+
+			// The watchers are often hidden on locked-down projects,
+			// so the issue is described without them
+			if watchers, err := JiraClient.GetWatchers(ctx, key); err == nil {
+				details[i].Watchers = &watchers
+			} else {
+				fmt.Fprintf(os.Stderr, "Leaving out the watchers of %s - %s\n", key, err.Error())
+			}
+
+			// Remote links can be turned off, or hidden, like the watchers
+			if links, err := JiraClient.GetRemoteLinks(ctx, key); err == nil {
+				details[i].RemoteLinks = links
+			} else {
+				fmt.Fprintf(os.Stderr, "Leaving out the web links of %s - %s\n", key, err.Error())
+			}
+
+			if Cfg.ParticipantsField != "" {
+				details[i].Participants = getParticipants(key)
+			}
+
+			if len(Cfg.InsightFields) > 0 {
+				details[i].Insight = getInsightFields(key)
+			}
+
+			rollup.Wait()
+		}(i, key)
+	}
+
+	wg.Wait()
+
+	return details
+}
+
+// getRollup sums up the time tracking of an epic and the issues in it,
+// or of an issue and its subtasks, like the Σ columns in Jira. It
+// returns nil for the issues without any issues below them.
+func getRollup(issue types.IssueDescription) *epicBudget {
+	key := issue.Key
+	rollup := &epicBudget{Key: key, Summary: issue.Fields.Summary, Issues: len(issue.Fields.Subtasks)}
+
+	// The aggregates of an issue include its subtasks,
+	// so the subtasks are not fetched on their own
+	filter := "key = " + key
+
+	switch {
+	case issue.Fields.IssueType.Name == "Epic":
+		filter += " OR cf[10500] = " + key
+	case len(issue.Fields.Subtasks) == 0:
+		return nil
+	}
+
+	for _, i := range must(JiraClient.GetTimeTrackingIssues(ctx, filter)) {
+		if i.Key != key {
+			rollup.Issues++
+		}
+
+		rollup.OriginalEstimate += i.Fields.AggregateOriginalEstimate
+		rollup.TimeSpent += i.Fields.AggregateTimeSpent
+		rollup.Remaining += i.Fields.AggregateRemaining
+	}
+
+	if rollup.Issues == 0 {
+		return nil
+	}
+
+	return rollup
+}
+
+func getParticipants(key string) []types.User {
+	participants := []types.User{}
+
+	raw := must(JiraClient.GetIssueField(ctx, key, Cfg.ParticipantsField))
+	if len(raw) == 0 {
+		return participants
+	}
+
+	if err := json.Unmarshal(raw, &participants); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to parse participants field %s - %v\n", Cfg.ParticipantsField, err)
+	}
+
+	return participants
+}
+
+func getInsightFields(key string) []insightField {
+	fields := []insightField{}
+
+	values, names, err := JiraClient.GetIssueFields(ctx, key, Cfg.InsightFields)
+	exitOnError(err)
+
+	for _, id := range Cfg.InsightFields {
+		objects := types.ParseInsightObjects(values[id])
+		if len(objects) == 0 {
+			continue
+		}
+
+		name := names[id]
+		if name == "" {
+			name = id
+		}
+
+		fields = append(fields, insightField{Name: name, Objects: objects})
+	}
+
+	return fields
+}
+
+func displayNames(users []types.User) string {
+	names := []string{}
+	for _, u := range users {
+		names = append(names, u.DisplayName)
+	}
+
+	return strings.Join(names, ", ")
 }
 
-func printIssue(issue, epic types.IssueDescription) {
+func printIssue(d issueDetails) {
+	issue, epic := d.Issue, d.Epic
+
 	fmt.Println()
 	fmt.Println(format.Header(issue.Fields.Project.Name, issue.Key, issue.Fields.Summary))
 	fmt.Printf("%sDetails:%s\n", format.Color.Ul, format.Color.Nocolor)
@@ -99,6 +341,15 @@ func printIssue(issue, epic types.IssueDescription) {
 	if epic.Fields.Summary != "" {
 		fmt.Printf("Epic:              %s\n", format.Epic(epic.Fields.Summary))
 	}
+
+	for _, f := range d.Insight {
+		objects := []string{}
+		for _, o := range f.Objects {
+			objects = append(objects, o.String())
+		}
+
+		fmt.Printf("%-19s%s\n", f.Name+":", strings.Join(objects, ", "))
+	}
 	// ******************************************************************
 	fmt.Printf("\n%sPeople:%s%-57s%sDates:%s\n",
 		format.Color.Ul, format.Color.Nocolor, " ", format.Color.Ul, format.Color.Nocolor)
@@ -109,17 +360,55 @@ func printIssue(issue, epic types.IssueDescription) {
 		issue.Fields.Reporter.DisplayName+" ("+issue.Fields.Reporter.Name+")",
 		issue.Fields.Updated[:16]) // Truncated at minutes
 
+	if created, err := util.ParseJiraTime(issue.Fields.Created); err == nil {
+		age := fmt.Sprintf("Age:     %s", convert.DurationToDaysAndHours(time.Since(created)))
+
+		if changed, err := util.StatusChangedAt(issue); err == nil {
+			age += fmt.Sprintf(", in current status for %s", convert.DurationToDaysAndHours(time.Since(changed)))
+		}
+
+		fmt.Printf("%64s%s\n", "", age)
+	}
+
+	if w := d.Watchers; w != nil {
+		fmt.Printf("Watchers:          %d", w.WatchCount)
+
+		if w.IsWatching {
+			fmt.Print(" (watching)")
+		} else {
+			fmt.Print(" (not watching)")
+		}
+
+		if len(w.Watchers) > 0 {
+			fmt.Printf(" - %s", displayNames(w.Watchers))
+		}
+
+		fmt.Println()
+	}
+
+	if len(d.Participants) > 0 {
+		fmt.Printf("Participants:      %s\n", displayNames(d.Participants))
+	}
+
 	// ******************************************************************
 	fmt.Printf("\n%sTime Tracking:%s\n", format.Color.Ul, format.Color.Nocolor)
 	fmt.Printf("Estimated: %-25sLogged: %-20sRemaining: %s\n",
 		format.TimeEstimate(issue.Fields.TimeTracking.Estimate),
 		issue.Fields.TimeTracking.TimeSpent, issue.Fields.TimeTracking.Remaining)
 
+	if r := d.Rollup; r != nil {
+		fmt.Printf("Σ Estimated: %-23sΣ Logged: %-18sΣ Remaining: %-12s(%d issues)\n",
+			convert.SecondsToHoursAndMinutes(r.OriginalEstimate, false),
+			convert.SecondsToHoursAndMinutes(r.TimeSpent, false),
+			convert.SecondsToHoursAndMinutes(r.Remaining, false), r.Issues)
+	}
+
 	// ******************************************************************
-	fmt.Printf("\n%sDescription:%s\n%s\n", format.Color.Ul, format.Color.Nocolor, issue.Fields.Description)
+	fmt.Printf("\n%sDescription:%s\n%s\n", format.Color.Ul, format.Color.Nocolor, renderText(issue.Fields.Description))
 
 	// ******************************************************************
 	printIssueLinks(issue)
+	printRemoteLinks(d.RemoteLinks)
 
 	// ******************************************************************
 	if len(issue.Fields.Comment.Comments) > 0 {
@@ -128,36 +417,62 @@ func printIssue(issue, epic types.IssueDescription) {
 	}
 }
 
+func printRemoteLinks(links []types.RemoteLink) {
+	if len(links) == 0 {
+		return
+	}
+
+	fmt.Printf("\n%sWEB LINKS:%s\n", format.Color.Ul, format.Color.Nocolor)
+
+	table := format.Table{HideHeader: true, Columns: []format.Column{
+		{Header: "Title", Shrink: minAssigneeLength},
+		{Header: "URL"},
+	}}
+
+	for _, l := range links {
+		title := l.Object.Title
+		if title == l.Object.URL {
+			title = ""
+		}
+
+		table.AddRow(title, l.Object.URL)
+	}
+
+	table.Render(os.Stdout, tableWidth())
+}
+
 func printIssueLinks(issue types.IssueDescription) {
 	outward := make(map[string][]string)
 	inward := make(map[string][]string)
 
-	for _, link := range issue.Fields.IssueLinks {
-		var summary string
-		if link.OutwardIssue.Key == "" {
-			summary = link.InwardIssue.Fields.Summary
-			if len(summary) > 42 {
-				summary = summary[:42] + ".."
-			}
+	links := slices.Clone(issue.Fields.IssueLinks)
+	summaries := []*string{}
 
+	for i := range links {
+		if links[i].OutwardIssue.Key == "" {
+			summaries = append(summaries, &links[i].InwardIssue.Fields.Summary)
+		} else {
+			summaries = append(summaries, &links[i].OutwardIssue.Fields.Summary)
+		}
+	}
+
+	width := truncateSummaries(summaryLength(57), summaries...)
+
+	for _, link := range links {
+		if link.OutwardIssue.Key == "" {
 			inward[link.Type.Inward] = append(inward[link.Type.Inward], fmt.Sprintf(
-				"%s%-15s%-45s%s%s\n",
+				"%s%-15s%-*s%s%s\n",
 				format.IssueType(link.InwardIssue.Fields.IssueType.Name, true),
 				link.InwardIssue.Key,
-				summary,
+				width, link.InwardIssue.Fields.Summary,
 				format.Priority(link.InwardIssue.Fields.Priority.Name, true),
 				format.Status(link.InwardIssue.Fields.Status.Name, true)))
 		} else {
-			summary = link.OutwardIssue.Fields.Summary
-			if len(summary) > 42 {
-				summary = summary[:42] + ".."
-			}
-
 			outward[link.Type.Outward] = append(outward[link.Type.Outward], fmt.Sprintf(
-				"%s%-15s%-45s%s%s\n",
+				"%s%-15s%-*s%s%s\n",
 				format.IssueType(link.OutwardIssue.Fields.IssueType.Name, true),
 				link.OutwardIssue.Key,
-				summary,
+				width, link.OutwardIssue.Fields.Summary,
 				format.Priority(link.OutwardIssue.Fields.Priority.Name, true),
 				format.Status(link.OutwardIssue.Fields.Status.Name, true)))
 		}
@@ -0,0 +1,164 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mhersson/gojira/pkg/types"
+	"github.com/mhersson/gojira/pkg/util"
+	"github.com/mhersson/gojira/pkg/util/convert"
+	"github.com/mhersson/gojira/pkg/util/format"
+)
+
+const digestUsage string = `Shows the new comments on issues you watch or are assigned to,
+grouped per issue with the author and the start of each comment.
+Your own comments are not included.
+
+The comments are fetched concurrently, but never more than five issues
+at the same time, to go easy on the Jira server.
+
+Valid values for since are today, yesterday, a number of days (3d),
+a duration (12h) or a date on the format yyyy-mm-dd.
+
+Usage:
+  gojira digest [flags]
+
+Flags:
+  -h, --help                   help for digest
+  -s, --since [SINCE]          show comments added since (default yesterday)
+
+Example:
+  # Catch up after a long weekend
+  gojira digest --since 4d
+`
+
+var DigestSince string // Used by `digest`
+
+type issueDigest struct {
+	Issue    types.Issue
+	Comments []types.Comment
+}
+
+var whitespace = regexp.MustCompile(`\s+`)
+
+var digestCmd = &cobra.Command{
+	Use:   "digest",
+	Short: "Display new comments on your issues",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		since, err := convert.SinceToTime(DigestSince, time.Now())
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(exitUsage)
+		}
+
+		issues := must(JiraClient.GetIssuesOrderedBy(ctx, "(watcher = currentUser() OR assignee = currentUser()) AND updated >= \""+
+			since.Format("2006-01-02 15:04")+"\"", "updated DESC"))
+
+		digests := getDigests(issues, since)
+		if len(digests) == 0 {
+			fmt.Printf("No new comments since %s\n", since.Format("2006-01-02 15:04"))
+
+			return
+		}
+
+		printDigests(digests)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(digestCmd)
+
+	digestCmd.SetUsageTemplate(digestUsage)
+	digestCmd.Flags().StringVarP(&DigestSince, "since", "s", "yesterday", "show comments added since")
+}
+
+// getDigests fetches the comments concurrently, and returns the issues
+// with comments from others added after since, in the same order as the issues.
+func getDigests(issues []types.Issue, since time.Time) []issueDigest {
+	digests := make([]issueDigest, len(issues))
+	sem := make(chan struct{}, describeConcurrency)
+
+	var wg sync.WaitGroup
+
+	for i, issue := range issues {
+		wg.Add(1)
+
+		go func(i int, issue types.Issue) {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			digests[i].Issue = issue
+
+			for _, c := range must(JiraClient.GetComments(ctx, issue.Key)) {
+				created, err := util.ParseJiraTime(c.Created)
+				if err != nil || created.Before(since) || c.Author.Name == Cfg.Username {
+					continue
+				}
+
+				digests[i].Comments = append(digests[i].Comments, c)
+			}
+		}(i, issue)
+	}
+
+	wg.Wait()
+
+	result := []issueDigest{}
+
+	for _, d := range digests {
+		if len(d.Comments) > 0 {
+			result = append(result, d)
+		}
+	}
+
+	return result
+}
+
+func printDigests(digests []issueDigest) {
+	for i, d := range digests {
+		if i > 0 {
+			fmt.Println()
+		}
+
+		fmt.Printf("%s%s%-15s%s%s\n", format.Color.Ul, format.Color.Yellow,
+			d.Issue.Key, d.Issue.Fields.Summary, format.Color.Nocolor)
+
+		for _, c := range d.Comments {
+			snippet := strings.TrimSpace(whitespace.ReplaceAllString(c.Body, " "))
+			author := c.Author.DisplayName + ":"
+
+			truncateSummaries(summaryLength(len(author)+20), &snippet)
+
+			fmt.Printf("  %s  %s%s%s %s\n", c.Created[:16], format.Color.Cyan, author, format.Color.Nocolor, snippet)
+		}
+	}
+}
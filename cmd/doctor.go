@@ -0,0 +1,336 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/mhersson/gojira/pkg/jira"
+	"github.com/mhersson/gojira/pkg/types"
+	"github.com/mhersson/gojira/pkg/util/format"
+)
+
+const doctorUsage string = `Checks the setup of gojira, and prints what to do about each check
+that fails. The checks are run in order, and the checks that need
+what failed are skipped:
+
+  config       the config file, or the environment, is found and valid
+  password     the password can be decrypted
+  server       the Jira server can be reached
+  api          the deployment in the config matches the server
+  login        the username and password are accepted
+  timesheet    the timesheet plugin answers, if useTimesheetPlugin is set
+  agile        the board and sprint endpoints answer
+
+Exits with 1 if any check fails.
+
+Usage:
+  gojira doctor [flags]
+
+Flags:
+  -h, --help                   help for doctor
+`
+
+// doctorCheck is the result of one of the checks of doctor.
+type doctorCheck struct {
+	name   string
+	ok     bool
+	result string
+	hint   string
+}
+
+// doctor runs the checks, and remembers if any of them failed.
+type doctor struct {
+	failed bool
+}
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the config, the password and the Jira endpoints",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		d := &doctor{}
+
+		if d.run() {
+			fmt.Printf("\n%sAll checks passed%s\n", format.Color.Green, format.Color.Nocolor)
+
+			return
+		}
+
+		os.Exit(exitFailure)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+
+	doctorCmd.SetUsageTemplate(doctorUsage)
+}
+
+// run runs the checks in order, and stops when the rest of
+// the checks can not be run. It returns false if any check failed.
+func (d *doctor) run() bool {
+	// The rest can be checked with an invalid config, but not without the url
+	if !d.print(checkConfig()) && Cfg.JiraURL == "" {
+		return false
+	}
+
+	if !d.print(checkPassword()) {
+		return false
+	}
+
+	info, check := checkServer()
+	if !d.print(check) {
+		return false
+	}
+
+	d.print(checkAPI(info.DeploymentType))
+
+	if !d.print(checkMyself()) {
+		return false
+	}
+
+	d.print(checkTimesheetPlugin())
+	d.print(checkAgile())
+
+	return !d.failed
+}
+
+// print prints the result of the check, and what to do about it if it failed.
+func (d *doctor) print(c doctorCheck) bool {
+	if c.ok {
+		fmt.Printf("%sok  %s  %-10s %s\n", format.Color.Green, format.Color.Nocolor, c.name, c.result)
+
+		return true
+	}
+
+	d.failed = true
+
+	fmt.Printf("%sFAIL%s  %-10s %s\n", format.Color.Red, format.Color.Nocolor, c.name, c.result)
+
+	if c.hint != "" {
+		fmt.Printf("%16s %s\n", "->", c.hint)
+	}
+
+	return false
+}
+
+func checkConfig() doctorCheck {
+	c := doctorCheck{name: "config"}
+
+	filename := viper.ConfigFileUsed()
+	if filename == "" && !viper.IsSet("JiraURL") {
+		c.result = "No config file found"
+		c.hint = "Write one with gojira config init, or set GOJIRA_URL and the other options in the environment"
+
+		return c
+	}
+
+	var content []byte
+
+	if filename != "" {
+		var err error
+
+		if content, err = os.ReadFile(filename); err != nil {
+			c.result = fmt.Sprintf("Failed to read %s - %s", filename, err.Error())
+			c.hint = "Check that the file can be read by you"
+
+			if errors.Is(err, os.ErrNotExist) {
+				c.hint = "Write it with gojira config init"
+			}
+
+			return c
+		}
+	} else {
+		filename = "The environment"
+	}
+
+	if problems := validateConfig(content); len(problems) > 0 {
+		c.result = filename + " is not valid: " + strings.Join(problems, ", ")
+		c.hint = "Fix it with gojira config edit, or fix the GOJIRA_ variables in the environment"
+
+		return c
+	}
+
+	c.ok = true
+	c.result = filename
+
+	return c
+}
+
+func checkPassword() doctorCheck {
+	c := doctorCheck{name: "password"}
+
+	if err := JiraClient.DecryptPassword(); err != nil {
+		c.result = err.Error()
+		c.hint = "Check password and passwordtype in the config, or store the password with gojira auth set"
+
+		return c
+	}
+
+	c.ok = true
+	c.result = "Decrypted"
+
+	if Cfg.PasswordType != "" {
+		c.result += " with " + Cfg.PasswordType
+	}
+
+	return c
+}
+
+func checkServer() (types.ServerInfo, doctorCheck) {
+	c := doctorCheck{name: "server"}
+
+	start := time.Now()
+
+	info, err := JiraClient.GetServerInfo(ctx)
+	if err != nil {
+		c.result = fmt.Sprintf("Failed to reach %s - %s", Cfg.JiraURL, err.Error())
+		c.hint = "Check jiraURL in the config, and that the server can be reached from here"
+
+		return info, c
+	}
+
+	c.ok = true
+	c.result = fmt.Sprintf("%s %s answered in %dms", info.ServerTitle, info.Version, time.Since(start).Milliseconds())
+
+	return info, c
+}
+
+func checkAPI(deploymentType string) doctorCheck {
+	c := doctorCheck{name: "api"}
+	cloud := jira.IsCloud(Cfg)
+
+	if isCloud := strings.EqualFold(deploymentType, "Cloud"); isCloud != cloud {
+		c.result = fmt.Sprintf("The server is a %s deployment, but gojira uses the %s API", deploymentType, apiVersion(cloud))
+		c.hint = fmt.Sprintf("Set deployment to %s in the config", strings.ToLower(deploymentType))
+
+		return c
+	}
+
+	c.ok = true
+	c.result = fmt.Sprintf("%s deployment, using the %s API", deploymentType, apiVersion(cloud))
+
+	return c
+}
+
+func checkMyself() doctorCheck {
+	c := doctorCheck{name: "login"}
+
+	user, err := JiraClient.GetMyself(ctx)
+	if err != nil {
+		c.result = "Failed to log in - " + err.Error()
+		c.hint = "Check username and password in the config"
+
+		if Cfg.PasswordType == "apitoken" || Cfg.PasswordType == "pat" {
+			c.hint = "Check username in the config, and that the token has not expired"
+		}
+
+		return c
+	}
+
+	c.ok = true
+	c.result = fmt.Sprintf("Logged in as %s (%s)", user.DisplayName, user.Name)
+
+	return c
+}
+
+func checkTimesheetPlugin() doctorCheck {
+	c := doctorCheck{name: "timesheet"}
+
+	if !Cfg.UseTimesheetPlugin {
+		c.ok = true
+		c.result = "Not used, useTimesheetPlugin is false"
+
+		return c
+	}
+
+	today := time.Now().Format("2006-01-02")
+
+	if _, err := JiraClient.GetTimesheet(ctx, today, today, false); err != nil {
+		c.result = err.Error()
+		c.hint = "Check that the timesheet plugin is enabled in Jira"
+
+		if errors.Is(err, jira.ErrNoTimesheetPlugin) {
+			c.hint = "Set useTimesheetPlugin to false in the config"
+		}
+
+		return c
+	}
+
+	c.ok = true
+	c.result = "The timesheet plugin answered"
+
+	return c
+}
+
+func checkAgile() doctorCheck {
+	c := doctorCheck{name: "agile", hint: "Check that Jira Software is installed, and that you can see a board"}
+
+	views, err := JiraClient.GetRapidViews(ctx)
+	if err != nil {
+		c.result = "Failed to get the boards - " + err.Error()
+
+		return c
+	}
+
+	if len(views) == 0 {
+		c.ok = true
+		c.result = "No boards found, the sprint endpoints are not checked"
+
+		return c
+	}
+
+	board := views[0]
+
+	for _, v := range views {
+		if v.SprintSupportEnabled {
+			board = v
+
+			break
+		}
+	}
+
+	if board.SprintSupportEnabled {
+		_, err = JiraClient.GetOpenSprints(ctx, board.ID)
+	} else {
+		_, err = JiraClient.GetBoardEpics(ctx, board.ID)
+	}
+
+	if err != nil {
+		c.result = fmt.Sprintf("Failed to read the board %s - %s", board.Name, err.Error())
+
+		return c
+	}
+
+	c.ok = true
+	c.result = fmt.Sprintf("%d boards, read the board %s", len(views), board.Name)
+
+	return c
+}
@@ -0,0 +1,168 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mhersson/gojira/pkg/types"
+	"github.com/mhersson/gojira/pkg/util"
+)
+
+const doneUsage string = `Finish working on an issue, the mirror of take. The timer is stopped
+and the work is logged if it is running on the issue, the issue is
+moved to Done, and the issue is no longer active.
+
+By default the active issue is finished,
+but this can be changed by adding the issue key as argument.
+
+The status to move the issue to is set with doneStatus in the config
+file, and can be overridden with --status. The resolution is set with
+doneResolution in the config file, and can be overridden with
+--resolution. The resolution can only be set if it is on the screen
+of the transition, otherwise the workflow decides the resolution.
+
+The comment is used both as the worklog comment and as the comment
+added to the issue when it is moved.
+
+Usage:
+  gojira done [ISSUE KEY] [flags]
+
+Flags:
+  -c, --comment [COMMENT]      worklog and issue comment
+  -h, --help                   help for done
+  -r, --resolution [NAME]      the resolution, e.g. Fixed
+  -s, --status [STATUS]        the status to move the issue to (default Done)
+
+Example:
+  gojira done --resolution Fixed --comment "Fixed the flaky test"
+`
+
+// Used by `done`.
+var (
+	DoneStatus     string
+	DoneResolution string
+	DoneComment    string
+)
+
+var doneCmd = &cobra.Command{
+	Use:   "done",
+	Short: "Stop the timer and move an issue to Done",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		exitIfReadOnly()
+
+		if len(args) == 1 {
+			IssueKey = strings.ToUpper(args[0])
+		}
+
+		checkIssueKey(&IssueKey, IssueFile)
+		checkPermission(IssueKey, "change the status", permTransitionIssues)
+
+		status := DoneStatus
+		if status == "" {
+			status = Cfg.DoneStatus
+		}
+
+		resolution := DoneResolution
+		if resolution == "" {
+			resolution = Cfg.DoneResolution
+		}
+
+		issue := getIssueBrief(IssueKey)
+		moved := !strings.EqualFold(issue.Fields.Status.Name, status)
+
+		// Check the transition before logging any work,
+		// so the issue is not left half done
+		var t types.Transition
+
+		if moved {
+			var ok bool
+
+			t, ok = findTransition(must(JiraClient.GetTransistions(ctx, IssueKey)), status)
+			if !ok {
+				fmt.Printf("%s can not be moved from %s to %s\n", IssueKey, issue.Fields.Status.Name, status)
+				os.Exit(exitFailure)
+			}
+
+			checkResolution(t, resolution)
+
+			if !confirm(Cfg.Confirm.Transitions,
+				fmt.Sprintf("Move %s from %s to %s", IssueKey, issue.Fields.Status.Name, t.To.Name)) {
+				os.Exit(exitCancelled)
+			}
+		}
+
+		autoStopExpiredTimer()
+
+		if timer, ok := loadTimer(); ok && timer.Running && timer.Key == IssueKey {
+			stopTimer(&timer, DoneComment, time.Now())
+		}
+
+		if moved {
+			err := JiraClient.ResolveIssue(ctx, IssueKey, t.ID, resolution, DoneComment)
+			if err != nil {
+				fmt.Printf("Update failed: %s\n", err.Error())
+				os.Exit(exitCode(err))
+			}
+
+			success("%s is moved to %s", IssueKey, t.To.Name)
+		} else {
+			fmt.Printf("%s is already %s\n", IssueKey, issue.Fields.Status.Name)
+		}
+
+		file := util.ActiveIssueFile(IssueFile)
+		if active, err := os.ReadFile(file); err == nil && string(active) == IssueKey {
+			unsetActive(file)
+			fmt.Println("Active issue cleared")
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(doneCmd)
+
+	doneCmd.SetUsageTemplate(doneUsage)
+	doneCmd.Flags().StringVarP(&DoneStatus, "status", "s", "", "the status to move the issue to")
+	doneCmd.Flags().StringVarP(&DoneResolution, "resolution", "r", "", "the resolution, e.g. Fixed")
+	doneCmd.Flags().StringVarP(&DoneComment, "comment", "c", "", "worklog and issue comment")
+}
+
+// checkResolution exits if the resolution can not be set by the
+// transition, or if the transition requires a resolution.
+func checkResolution(t types.Transition, resolution string) {
+	f, onScreen := t.Fields["resolution"]
+
+	switch {
+	case resolution != "" && !onScreen:
+		fmt.Printf("The resolution can not be set when moving the issue to %s\n", t.To.Name)
+		os.Exit(exitUsage)
+	case resolution == "" && onScreen && f.Required:
+		fmt.Printf("A resolution is required when moving the issue to %s, use --resolution\n", t.To.Name)
+		os.Exit(exitUsage)
+	}
+}
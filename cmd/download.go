@@ -0,0 +1,198 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mhersson/gojira/pkg/types"
+	"github.com/mhersson/gojira/pkg/util/convert"
+	"github.com/mhersson/gojira/pkg/util/validate"
+)
+
+const downloadUsage string = `Download the attachments of an issue.
+
+By default the attachment is downloaded from the active issue,
+but this can be changed by adding the issue key as argument.
+The attachment is given by its name or id, as displayed by
+gojira get attachments, or use --all to download all of them.
+When several attachments have the same name, only the latest
+is downloaded, unless the id of another one is given.
+
+The files are written to the current directory, or to the
+directory given with --output-dir, and existing files with the
+same name are overwritten.
+
+Usage:
+  gojira download [ISSUE KEY] [NAME|--all] [flags]
+
+Aliases:
+  download, dl
+
+Flags:
+  -a, --all                    download all the attachments
+  -h, --help                   help for download
+  -O, --output-dir [DIR]       write the files to the directory
+
+Examples:
+  # Download screenshot.png from the active issue
+  gojira download screenshot.png
+
+  # Download all attachments of OSE-1 to /tmp/OSE-1
+  gojira download OSE-1 --all -O /tmp/OSE-1
+`
+
+// Used by `download`.
+var (
+	DownloadAll bool
+	DownloadDir string
+)
+
+var downloadCmd = &cobra.Command{
+	Use:     "download",
+	Short:   "Download attachments",
+	Args:    cobra.MaximumNArgs(2),
+	Aliases: []string{"dl"},
+	Run: func(cmd *cobra.Command, args []string) {
+		name := ""
+
+		switch {
+		case len(args) == 2:
+			IssueKey = strings.ToUpper(args[0])
+			name = args[1]
+		case len(args) == 1 && (DownloadAll || validate.IssueKey(&args[0])):
+			IssueKey = strings.ToUpper(args[0])
+		case len(args) == 1:
+			name = args[0]
+		}
+
+		if (name == "") == !DownloadAll {
+			fmt.Println("Give the name of the attachment or --all, but not both")
+			os.Exit(exitUsage)
+		}
+
+		checkIssueKey(&IssueKey, IssueFile)
+		issue := must(JiraClient.GetIssue(ctx, IssueKey))
+
+		attachments := latestAttachments(issue.Fields.Attachments)
+		if !DownloadAll {
+			attachments = findAttachment(issue.Fields.Attachments, name)
+		}
+
+		if len(attachments) == 0 {
+			if DownloadAll {
+				fmt.Printf("%s has no attachments\n", IssueKey)
+			} else {
+				fmt.Printf("%s has no attachment named %s\n", IssueKey, name)
+			}
+
+			os.Exit(exitNotFound)
+		}
+
+		exitOnError(os.MkdirAll(DownloadDir, 0o755))
+
+		for _, a := range attachments {
+			file, size, err := downloadAttachment(a, DownloadDir)
+			if err != nil {
+				fmt.Printf("Failed to download %s: %s\n", a.Filename, err)
+				os.Exit(exitCode(err))
+			}
+
+			success("Downloaded %s (%s) to %s", a.Filename, convert.BytesToHumanSize(int(size)), file)
+		}
+	},
+}
+
+// findAttachment returns the attachment with the name or id, the latest
+// if there are several with the same name, or nothing if none matches.
+func findAttachment(attachments []types.Attachment, name string) []types.Attachment {
+	var found []types.Attachment
+
+	for _, a := range attachments {
+		if a.ID == name {
+			return []types.Attachment{a}
+		}
+
+		if a.Filename == name && (len(found) == 0 || a.Created > found[0].Created) {
+			found = []types.Attachment{a}
+		}
+	}
+
+	return found
+}
+
+// latestAttachments returns the attachments without the older
+// ones with the same name, which would otherwise be overwritten.
+func latestAttachments(attachments []types.Attachment) []types.Attachment {
+	var latest []types.Attachment
+
+	for _, a := range attachments {
+		if found := findAttachment(attachments, a.Filename); found[0].ID == a.ID {
+			latest = append(latest, a)
+		}
+	}
+
+	return latest
+}
+
+// downloadAttachment writes the attachment to the directory, through a
+// temporary file so a failed download does not leave half a file behind.
+func downloadAttachment(attachment types.Attachment, dir string) (string, int64, error) {
+	file := filepath.Join(dir, filepath.Base(attachment.Filename))
+
+	tmp, err := os.CreateTemp(dir, ".gojira-download-*")
+	if err != nil {
+		return "", 0, err
+	}
+	defer os.Remove(tmp.Name())
+
+	size, err := JiraClient.DownloadAttachment(ctx, attachment, tmp)
+	if err == nil {
+		err = tmp.Chmod(0o644)
+	}
+
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return "", 0, err
+	}
+
+	if err := os.Rename(tmp.Name(), file); err != nil {
+		return "", 0, err
+	}
+
+	return file, size, nil
+}
+
+func init() {
+	rootCmd.AddCommand(downloadCmd)
+	downloadCmd.SetUsageTemplate(downloadUsage)
+	downloadCmd.Flags().BoolVarP(&DownloadAll, "all", "a", false, "download all the attachments")
+	downloadCmd.Flags().StringVarP(&DownloadDir, "output-dir", "O", ".", "write the files to the directory")
+}
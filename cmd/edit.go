@@ -25,10 +25,10 @@ import (
 	"fmt"
 	"os"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 
-	"github.com/mhersson/gojira/pkg/jira"
 	"github.com/mhersson/gojira/pkg/types"
 	"github.com/mhersson/gojira/pkg/util"
 	"github.com/mhersson/gojira/pkg/util/convert"
@@ -67,9 +67,35 @@ Flags:
   -h, --help                   help for comment
 `
 
+const editFieldUsage string = `By default the active issue is edited, but this can be
+changed by adding the issue key as the first argument.
+
+The field can be given by its id or its name. Fields listed in
+insightFields in the config are Insight (Assets) fields, and their values
+are object keys. The objects are looked up before the field is updated,
+and leaving out the values clears the field. For all other fields the
+values are joined and set as text.
+
+Usage:
+  gojira edit field [ISSUE KEY] <FIELD> [VALUE...] [flags]
+
+Aliases:
+  field, f
+
+Flags:
+  -h, --help                   help for field
+
+Examples:
+  # Set the hardware of the active issue to two Insight objects
+  gojira edit field customfield_11000 ITAM-12 ITAM-31
+
+  # Clear the Insight field named Hardware on OSE-1
+  gojira edit field OSE-1 Hardware
+`
+
 var editCmd = &cobra.Command{
 	Use:     "edit",
-	Short:   "Edit comments, descriptions and your worklog",
+	Short:   "Edit comments, descriptions, fields and your worklog",
 	Args:    cobra.NoArgs,
 	Aliases: []string{"e"},
 }
@@ -80,22 +106,25 @@ var editDescrptionCmd = &cobra.Command{
 	Args:    cobra.MaximumNArgs(1),
 	Aliases: []string{"d"},
 	Run: func(cmd *cobra.Command, args []string) {
+		exitIfReadOnly()
+
 		if len(args) == 1 {
 			IssueKey = strings.ToUpper(args[0])
 		}
-		jira.CheckIssueKey(&IssueKey, IssueFile)
-		issue := jira.GetIssue(IssueKey)
+		checkIssueKey(&IssueKey, IssueFile)
+		checkPermission(IssueKey, "edit the description", permEditIssues)
+		issue := must(JiraClient.GetIssue(ctx, IssueKey))
 
-		desc, err := captureInputFromEditor(issue.Fields.Description, "description*")
+		desc, err := captureTextFromEditor(issue.Fields.Description, "description*")
 		if err != nil {
 			fmt.Println("Failed to read description")
-			os.Exit(1)
+			os.Exit(exitFailure)
 		}
 
-		err = jira.UpdateDescription(IssueKey, desc)
+		err = JiraClient.UpdateDescription(ctx, IssueKey, desc)
 		if err != nil {
 			fmt.Printf("Failed to update description, %v\n", err)
-			os.Exit(1)
+			os.Exit(exitCode(err))
 		}
 
 		fmt.Println("Successfully saved new description")
@@ -109,6 +138,8 @@ var editCommentCmd = &cobra.Command{
 	Aliases: []string{"c"},
 	Args:    cobra.MaximumNArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
+		exitIfReadOnly()
+
 		var commentID string
 
 		switch len(args) {
@@ -118,31 +149,33 @@ var editCommentCmd = &cobra.Command{
 			if validate.CommentID(args[0]) {
 				// Comment id is valid, the issuekey will be set to the active issue
 				commentID = args[0]
-				jira.CheckIssueKey(&IssueKey, IssueFile)
+				checkIssueKey(&IssueKey, IssueFile)
 			} else {
 				// The argument is not a valid comment id, check if it
 				// is a valid issue key
 				IssueKey = strings.ToUpper(args[0])
-				jira.CheckIssueKey(&IssueKey, IssueFile)
+				checkIssueKey(&IssueKey, IssueFile)
 			}
 
 		case 2:
 			// If two arguments are provided first must be the issueKey,
 			// and second must be the comment id
 			IssueKey = strings.ToUpper(args[0])
-			jira.CheckIssueKey(&IssueKey, IssueFile)
+			checkIssueKey(&IssueKey, IssueFile)
 
 			commentID = args[1]
 			if !validate.CommentID(commentID) {
 				fmt.Println("Invalid comment id")
-				os.Exit(1)
+				os.Exit(exitUsage)
 			}
 
 		default:
 			// If no argument is provided edit the last comment of the current active issue
-			jira.CheckIssueKey(&IssueKey, IssueFile)
+			checkIssueKey(&IssueKey, IssueFile)
 		}
 
+		checkPermission(IssueKey, "edit comments", permEditOwnComments, permEditAllComments)
+
 		// Get the existing comment
 		ec := getComment(IssueKey, commentID)
 		if commentID == "" {
@@ -156,18 +189,18 @@ var editCommentCmd = &cobra.Command{
 
 				fmt.Println("Issue does not have any comments. Try add comment instead")
 			}
-			os.Exit(1)
+			os.Exit(exitFailure)
 		}
 
-		comment, err := captureInputFromEditor(ec.Body, "comment*")
+		comment, err := captureTextFromEditor(ec.Body, "comment*")
 		if err != nil {
 			fmt.Println("Failed to read comment")
 		}
 
-		err = jira.UpdateComment(IssueKey, comment, commentID)
+		err = JiraClient.UpdateComment(ctx, IssueKey, comment, commentID)
 		if err != nil {
 			fmt.Printf("Failed to update comment - %s\n", err.Error())
-			os.Exit(1)
+			os.Exit(exitCode(err))
 		}
 
 		fmt.Println("Successfully saved new comment")
@@ -180,13 +213,22 @@ var editMyWorklogCmd = &cobra.Command{
 	Aliases: []string{"m"},
 	Args:    cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
+		exitIfReadOnly()
+
 		date := util.GetCurrentDate()
 		if len(args) == 1 {
 			date = args[0]
 		}
-		if Cfg.UseTimesheetPlugin {
+		if useTimesheetPlugin() {
 			if validate.Date(date) {
-				ts := jira.GetTimesheet(date, date, ShowEntireWeek)
+				ts, err := JiraClient.GetTimesheet(ctx, date, date, ShowEntireWeek)
+				if timesheetMissing(err) {
+					fmt.Println("This command is currently only supported with the timesheet plugin enabled")
+					os.Exit(exitUsage)
+				}
+
+				exitOnError(err)
+
 				if len(ts) == 0 && (AdoptUser == "" || MergeToday) {
 					fmt.Println("There is nothing to edit.")
 					os.Exit(0)
@@ -194,7 +236,7 @@ var editMyWorklogCmd = &cobra.Command{
 				worklogs := util.GetWorklogsSorted(ts, false)
 
 				// If mergetoday is set
-				if !util.DateIsToday(date) && MergeToday && !ShowEntireWeek {
+				if !must(util.DateIsToday(date)) && MergeToday && !ShowEntireWeek {
 					worklogs = mergeWorklogs(worklogs)
 				}
 
@@ -212,8 +254,15 @@ var editMyWorklogCmd = &cobra.Command{
 				}
 
 				editedWorklogs := parseEditedWorklog(date, edited)
+				deletedWorklogs := findDeletedWorklogs(worklogs, edited)
+
+				if !confirmDeletedWorklogs(deletedWorklogs) {
+					os.Exit(exitCancelled)
+				}
+
 				updateChangedWorklogs(worklogs, editedWorklogs)
 				addNewWorklogs(editedWorklogs)
+				deleteWorklogs(deletedWorklogs)
 			}
 		} else {
 			fmt.Println("This command is currently only supported with the timesheet plugin enabled")
@@ -221,15 +270,53 @@ var editMyWorklogCmd = &cobra.Command{
 	},
 }
 
+var editFieldCmd = &cobra.Command{
+	Use:     "field",
+	Short:   "Set the value of a field",
+	Aliases: []string{"f"},
+	Args:    cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		exitIfReadOnly()
+
+		if key := strings.ToUpper(args[0]); len(args) > 1 && validate.IssueKey(&key) {
+			IssueKey = key
+			args = args[1:]
+		}
+
+		checkIssueKey(&IssueKey, IssueFile)
+		checkPermission(IssueKey, "edit fields", permEditIssues)
+
+		field, name := findField(IssueKey, args[0])
+		values := args[1:]
+
+		if slices.Contains(Cfg.InsightFields, field) {
+			setInsightField(IssueKey, field, name, values)
+
+			return
+		}
+
+		value := strings.Join(values, " ")
+
+		if err := JiraClient.UpdateField(ctx, IssueKey, field, value); err != nil {
+			fmt.Printf("Failed to update %s - %v\n", name, err)
+			os.Exit(exitCode(err))
+		}
+
+		success("Successfully set %s to %s", name, value)
+	},
+}
+
 func init() {
 	rootCmd.AddCommand(editCmd)
 
 	editCmd.AddCommand(editDescrptionCmd)
 	editCmd.AddCommand(editCommentCmd)
 	editCmd.AddCommand(editMyWorklogCmd)
+	editCmd.AddCommand(editFieldCmd)
 
 	editDescrptionCmd.SetUsageTemplate(editDescriptionUsage)
 	editCommentCmd.SetUsageTemplate(editCommentUsage)
+	editFieldCmd.SetUsageTemplate(editFieldUsage)
 	editMyWorklogCmd.Flags().BoolVarP(&MergeToday, "merge-today", "", false, "merge/import the records from that date")
 	editMyWorklogCmd.Flags().StringVarP(&AdoptUser, "adopt-user", "", "",
 		"adopt/import records registered by user on date")
@@ -237,7 +324,7 @@ func init() {
 
 func mergeWorklogs(myWorklog []types.SimplifiedTimesheet) []types.SimplifiedTimesheet {
 	date := util.Today() // Set the date today
-	ts := jira.GetTimesheet(date, date, ShowEntireWeek)
+	ts := must(JiraClient.GetTimesheet(ctx, date, date, ShowEntireWeek))
 	wlToday := util.GetWorklogsSorted(ts, false)
 
 	// Reset the ID and the date, and append the logs on today
@@ -257,12 +344,12 @@ func mergeWorklogs(myWorklog []types.SimplifiedTimesheet) []types.SimplifiedTime
 }
 
 func adoptRecordsFromUser(myWorklog []types.SimplifiedTimesheet, date, username string) []types.SimplifiedTimesheet {
-	if !jira.UserExists(username) {
+	if !must(JiraClient.UserExists(ctx, username)) {
 		fmt.Printf("User %s does not exist.\n", username)
-		os.Exit(1)
+		os.Exit(exitNotFound)
 	}
 
-	ts := jira.GetTimesheetForUser(date, AdoptUser)
+	ts := must(JiraClient.GetTimesheetForUser(ctx, date, AdoptUser))
 	wlToday := util.GetWorklogsSorted(ts, false)
 
 	for _, w := range wlToday {
@@ -287,11 +374,11 @@ func updateChangedWorklogs(worklogs, editedWorklogs []types.SimplifiedTimesheet)
 		for _, w := range worklogs {
 			if e.ID == w.ID && e.ID != 666 &&
 				(e.StartDate != w.StartDate || e.TimeSpent != w.TimeSpent || e.Comment != w.Comment) {
-				err := jira.UpdateWorklog(e)
+				err := JiraClient.UpdateWorklog(ctx, e)
 				if err != nil {
 					fmt.Printf("Failed to update worklog id: %d, key; %s\n", e.ID, e.Key)
 					fmt.Printf("%v\n", err)
-					os.Exit(1)
+					os.Exit(exitCode(err))
 				}
 				success++
 
@@ -307,28 +394,84 @@ func updateChangedWorklogs(worklogs, editedWorklogs []types.SimplifiedTimesheet)
 
 func addNewWorklogs(editedWorklogs []types.SimplifiedTimesheet) {
 	success := 0
+	keys := []string{}
 
 	for _, e := range editedWorklogs {
 		dateAndTime := strings.Split(e.StartDate, " ")
 
 		if e.ID == 666 {
-			err := jira.AddWorklog(dateAndTime[0], dateAndTime[1], e.Key, strconv.Itoa(e.TimeSpent), e.Comment)
+			err := JiraClient.AddWorklog(ctx, dateAndTime[0], dateAndTime[1], e.Key, strconv.Itoa(e.TimeSpent), e.Comment)
 			if err != nil {
 				fmt.Printf("Failed to add new worklog key; %s\n", e.Key)
 				fmt.Printf("%v\n", err)
-				os.Exit(1)
+				os.Exit(exitCode(err))
 			}
 			success++
+
+			if !slices.Contains(keys, e.Key) {
+				keys = append(keys, e.Key)
+			}
 		}
 	}
 
 	if success >= 1 {
 		fmt.Printf("Successfully added %d worklog entries\n", success)
 	}
+
+	for _, k := range keys {
+		checkBudget(k)
+	}
+}
+
+// findDeletedWorklogs returns the worklogs whose lines were deleted in the
+// editor. A line is looked up by its id only, so an edited line that can no
+// longer be parsed is not taken for a deleted one.
+func findDeletedWorklogs(worklogs []types.SimplifiedTimesheet, edited []byte) []types.SimplifiedTimesheet {
+	deleted := []types.SimplifiedTimesheet{}
+
+	for _, w := range worklogs {
+		if w.ID != 666 && !strings.Contains(string(edited), fmt.Sprintf("(#%d)", w.ID)) {
+			deleted = append(deleted, w)
+		}
+	}
+
+	return deleted
+}
+
+// confirmDeletedWorklogs lists the worklogs about to be deleted, and
+// with confirm.deletes returns if the user wants to go ahead.
+func confirmDeletedWorklogs(deleted []types.SimplifiedTimesheet) bool {
+	if len(deleted) == 0 {
+		return true
+	}
+
+	fmt.Println("The following worklog entries will be deleted:")
+
+	for _, w := range deleted {
+		fmt.Printf("(#%d)    %-10s    %-16s    %-6s    %s\n", w.ID, w.Key, w.StartDate,
+			convert.SecondsToHoursAndMinutes(w.TimeSpent, false), w.Comment)
+	}
+
+	return confirm(Cfg.Confirm.Deletes, fmt.Sprintf("Delete %d worklog entries and save the other changes", len(deleted)))
+}
+
+func deleteWorklogs(deleted []types.SimplifiedTimesheet) {
+	for _, w := range deleted {
+		err := JiraClient.DeleteWorklog(ctx, w.Key, strconv.Itoa(w.ID))
+		if err != nil {
+			fmt.Printf("Failed to delete worklog id: %d, key; %s\n", w.ID, w.Key)
+			fmt.Printf("%v\n", err)
+			os.Exit(exitCode(err))
+		}
+	}
+
+	if len(deleted) >= 1 {
+		success("Successfully deleted %d worklog entries", len(deleted))
+	}
 }
 
 func getComment(key, commentID string) types.Comment {
-	comments := jira.GetComments(key)
+	comments := must(JiraClient.GetComments(ctx, key))
 
 	if commentID == "" && len(comments) >= 1 {
 		return comments[len(comments)-1]
@@ -343,6 +486,55 @@ func getComment(key, commentID string) types.Comment {
 	return types.Comment{}
 }
 
+// findField returns the id and name of the field matching
+// either the id or the name of one of the fields of the issue.
+func findField(key, field string) (string, string) {
+	_, names, err := JiraClient.GetIssueFields(ctx, key, []string{"*all"})
+	exitOnError(err)
+
+	if name, ok := names[field]; ok {
+		return field, name
+	}
+
+	for id, name := range names {
+		if strings.EqualFold(name, field) {
+			return id, name
+		}
+	}
+
+	fmt.Printf("Field %s does not exist on %s\n", field, key)
+	os.Exit(exitNotFound)
+
+	return "", ""
+}
+
+func setInsightField(key, field, name string, objectKeys []string) {
+	objects := []string{}
+
+	for _, k := range objectKeys {
+		object, err := JiraClient.GetInsightObject(ctx, k)
+		if err != nil {
+			fmt.Printf("Failed to look up Insight object %s - %v\n", strings.ToUpper(k), err)
+			os.Exit(exitCode(err))
+		}
+
+		objects = append(objects, object.String())
+	}
+
+	if err := JiraClient.UpdateInsightField(ctx, key, field, objectKeys); err != nil {
+		fmt.Printf("Failed to update %s - %v\n", name, err)
+		os.Exit(exitCode(err))
+	}
+
+	if len(objects) == 0 {
+		success("Successfully cleared %s", name)
+
+		return
+	}
+
+	success("Successfully set %s to %s", name, strings.Join(objects, ", "))
+}
+
 func parseEditedWorklog(date string, logs []byte) []types.SimplifiedTimesheet {
 	// (#123456)    ISSUE-1       14:30    0h 30m    Some comment
 	re := regexp.MustCompile(
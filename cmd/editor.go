@@ -25,6 +25,9 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+
+	"github.com/mhersson/gojira/pkg/jira"
+	"github.com/mhersson/gojira/pkg/util/markup"
 )
 
 const DefaultEditor = "vim"
@@ -88,3 +91,25 @@ func captureInputFromEditor(text, pattern string) ([]byte, error) {
 
 	return bytes, nil
 }
+
+// captureTextFromEditor is captureInputFromEditor for descriptions and
+// comments. With editMarkdown the wiki markup is edited as markdown, and
+// converted back on save. Jira Cloud converts the markdown itself.
+func captureTextFromEditor(text, pattern string) ([]byte, error) {
+	if !editsMarkdown() {
+		return captureInputFromEditor(text, pattern)
+	}
+
+	edited, err := captureInputFromEditor(markup.ToMarkdown(text), pattern+".md")
+	if err != nil || len(edited) == 0 {
+		return edited, err
+	}
+
+	return []byte(markup.FromMarkdown(string(edited))), nil
+}
+
+// editsMarkdown returns true if descriptions and comments are written in
+// the editor as markdown, to be converted to wiki markup.
+func editsMarkdown() bool {
+	return Cfg.EditMarkdown && !jira.IsCloud(Cfg)
+}
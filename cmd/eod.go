@@ -0,0 +1,251 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mhersson/gojira/pkg/jira"
+	"github.com/mhersson/gojira/pkg/types"
+	"github.com/mhersson/gojira/pkg/util"
+	"github.com/mhersson/gojira/pkg/util/convert"
+	"github.com/mhersson/gojira/pkg/util/format"
+)
+
+const eodUsage string = `Sums up your day, for a logout script or a cron job: the time logged
+today against the working hours of the day, the issues in progress
+without any work logged today, and the timer if it is still running.
+
+Today is the day in your own time zone, and the work logged is counted
+by when it was started in your time zone, whatever the time zone of the
+Jira server. Days off, by numberOfWorkingDays and the public holidays
+of the countryCode, have no working hours.
+
+The exit code is 1 when the day looks incomplete, i.e. less time is
+logged than the working hours, an issue in progress has no work logged
+today, or the timer is running.
+
+Usage:
+  gojira eod [flags]
+
+Flags:
+  -h, --help                   help for eod
+  -o, --output [FORMAT]        output format, json
+
+Example:
+  # Stop the logout if the day is not done
+  gojira eod || exit
+`
+
+// endOfDay is the summary of the work done today.
+type endOfDay struct {
+	Date       string       `json:"date"`
+	TimeZone   string       `json:"timeZone"`
+	Target     int          `json:"targetSeconds"`
+	Logged     int          `json:"loggedSeconds"`
+	Issues     []eodIssue   `json:"issues"`
+	NoWorklogs []eodIssue   `json:"inProgressWithoutWorklogs"`
+	Timer      *types.Timer `json:"timer,omitempty"`
+	Complete   bool         `json:"complete"`
+}
+
+type eodIssue struct {
+	Key       string `json:"key"`
+	Summary   string `json:"summary"`
+	TimeSpent int    `json:"timeSpentSeconds,omitempty"`
+}
+
+var eodCmd = &cobra.Command{
+	Use:   "eod",
+	Short: "Sum up the work logged today, for a logout script",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		checkOutputFormat("json")
+
+		eod := getEndOfDay(time.Now())
+
+		if OutputFormat == "json" {
+			printJSON(eod)
+		} else {
+			printEndOfDay(eod)
+		}
+
+		if !eod.Complete {
+			os.Exit(exitFailure)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(eodCmd)
+
+	eodCmd.SetUsageTemplate(eodUsage)
+}
+
+func getEndOfDay(now time.Time) endOfDay {
+	today := now.Format("2006-01-02")
+	zone, _ := now.Zone()
+
+	eod := endOfDay{
+		Date: today, TimeZone: zone, Target: workingSeconds(now), Issues: []eodIssue{}, NoWorklogs: []eodIssue{},
+	}
+
+	// An expired timer is logged first, to be counted with the worklogs
+	autoStopExpiredTimer()
+
+	// The worklog dates in the filter are in the time zone of the server,
+	// so the days around today are included and the worklogs checked here
+	filter := fmt.Sprintf("worklogAuthor = currentUser() AND worklogDate >= %s AND worklogDate <= %s",
+		now.AddDate(0, 0, -1).Format("2006-01-02"), now.AddDate(0, 0, 1).Format("2006-01-02"))
+
+	var (
+		worked     []types.Issue
+		inProgress []types.Issue
+		wg         sync.WaitGroup
+	)
+
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+
+		worked = must(JiraClient.GetIssuesSelecting(ctx, filter, jira.OrderByPriority, jira.BriefFields))
+	}()
+
+	go func() {
+		defer wg.Done()
+
+		inProgress = must(JiraClient.GetIssuesSelecting(ctx,
+			`assignee = currentUser() AND statusCategory = "In Progress"`, jira.OrderByPriority, jira.BriefFields))
+	}()
+
+	wg.Wait()
+
+	for i, spent := range timeSpentToday(worked, today) {
+		if spent > 0 {
+			eod.Issues = append(eod.Issues, eodIssue{Key: worked[i].Key, Summary: worked[i].Fields.Summary, TimeSpent: spent})
+			eod.Logged += spent
+		}
+	}
+
+	for _, issue := range inProgress {
+		if !slices.ContainsFunc(eod.Issues, func(i eodIssue) bool { return i.Key == issue.Key }) {
+			eod.NoWorklogs = append(eod.NoWorklogs, eodIssue{Key: issue.Key, Summary: issue.Fields.Summary})
+		}
+	}
+
+	if t, ok := loadTimer(); ok && t.Running {
+		eod.Timer = &t
+	}
+
+	eod.Complete = eod.Logged >= eod.Target && len(eod.NoWorklogs) == 0 && eod.Timer == nil
+
+	return eod
+}
+
+// workingSeconds returns the working hours of the day in seconds, which
+// is none on the days off and the public holidays.
+func workingSeconds(day time.Time) int {
+	// The working days are counted from Monday
+	if (int(day.Weekday())+6)%7 >= Cfg.NumWorkingDays {
+		return 0
+	}
+
+	date := day.Format("2006-01-02")
+
+	if Cfg.CountryCode != "" && slices.Contains(publicHolidays(date, date), date) {
+		return 0
+	}
+
+	return int(Cfg.WorkingHoursPerDay * 3600)
+}
+
+// timeSpentToday fetches the worklogs concurrently, and returns the time
+// logged by the user on each issue started on the day in the local time zone.
+func timeSpentToday(issues []types.Issue, today string) []int {
+	spent := make([]int, len(issues))
+	sem := make(chan struct{}, describeConcurrency)
+
+	var wg sync.WaitGroup
+
+	for i, issue := range issues {
+		wg.Add(1)
+
+		go func(i int, key string) {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			for _, w := range must(JiraClient.GetWorklogs(ctx, key)) {
+				started, err := util.ParseJiraTime(w.Started)
+				if err == nil && w.Author.Name == Cfg.Username && started.Local().Format("2006-01-02") == today {
+					spent[i] += w.TimeSpentSeconds
+				}
+			}
+		}(i, issue.Key)
+	}
+
+	wg.Wait()
+
+	return spent
+}
+
+func printEndOfDay(eod endOfDay) {
+	color := format.Color.Green
+	if eod.Logged < eod.Target {
+		color = format.Color.Red
+	}
+
+	fmt.Printf("%sEnd of day %s (%s)%s\n", format.Color.Ul+format.Color.Yellow, eod.Date, eod.TimeZone, format.Color.Nocolor)
+	fmt.Printf("Logged:   %s%s%s of %s\n", color, convert.SecondsToHoursAndMinutes(eod.Logged, false),
+		format.Color.Nocolor, convert.SecondsToHoursAndMinutes(eod.Target, false))
+
+	for _, i := range eod.Issues {
+		fmt.Printf("  %-15s%-10s%s\n", i.Key, convert.SecondsToHoursAndMinutes(i.TimeSpent, false), i.Summary)
+	}
+
+	if len(eod.NoWorklogs) > 0 {
+		fmt.Printf("\n%sIn progress without work logged today:%s\n", format.Color.Yellow, format.Color.Nocolor)
+
+		for _, i := range eod.NoWorklogs {
+			fmt.Printf("  %-15s%s\n", i.Key, i.Summary)
+		}
+	}
+
+	if eod.Timer != nil {
+		fmt.Printf("\n%sThe timer is running on %s, for %s%s\n", format.Color.Yellow, eod.Timer.Key,
+			convert.DurationToDaysAndHours(eod.Timer.Elapsed(time.Now())), format.Color.Nocolor)
+	}
+
+	if eod.Complete {
+		fmt.Printf("\n%sThe day looks complete%s\n", format.Color.Green, format.Color.Nocolor)
+	} else {
+		fmt.Printf("\n%sThe day looks incomplete%s\n", format.Color.Red, format.Color.Nocolor)
+	}
+}
@@ -0,0 +1,226 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mhersson/gojira/pkg/types"
+	"github.com/mhersson/gojira/pkg/util/convert"
+	"github.com/mhersson/gojira/pkg/util/critpath"
+	"github.com/mhersson/gojira/pkg/util/format"
+	"github.com/mhersson/gojira/pkg/util/validate"
+)
+
+const epicUsage string = `Analyze the issues in an epic.
+
+critical-path builds the graph of the issues in the epic blocking each
+other, and uses the remaining estimates to find the chain of issues
+deciding when the epic can be done at the earliest, assuming everyone
+can work in parallel. The issues on the critical path are highlighted,
+as they are the bottleneck of the epic. Issues that are done count as
+no work left, and so do open issues without a remaining estimate.
+
+The earliest completion date counts from today, with the configured
+number of working hours per day, and skips weekends.
+
+Usage:
+  gojira epic critical-path [EPIC KEY]
+
+Available Commands:
+  critical-path  Show the critical path through the epic
+
+Aliases:
+  critical-path, cp
+
+Flags:
+  -h, --help                   help for epic
+
+Example:
+  gojira epic critical-path OSE-100
+`
+
+// blocksLinkType is the name of the link type used for blocking issues.
+const blocksLinkType = "Blocks"
+
+var epicCmd = &cobra.Command{
+	Use:   "epic",
+	Short: "Analyze the issues in an epic",
+	Args:  cobra.NoArgs,
+}
+
+var epicCriticalPathCmd = &cobra.Command{
+	Use:     "critical-path",
+	Short:   "Show the critical path through the epic",
+	Aliases: []string{"cp"},
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		key := strings.ToUpper(args[0])
+		if !validate.IssueKey(&key) {
+			fmt.Println("Invalid key")
+			os.Exit(exitUsage)
+		}
+
+		epic := must(JiraClient.GetIssue(ctx, key))
+		if epic.Fields.IssueType.Name != "Epic" {
+			fmt.Printf("%s is not an epic\n", key)
+			os.Exit(exitUsage)
+		}
+
+		issues := must(JiraClient.GetDependencyIssues(ctx, "cf[10500] = "+key))
+		if len(issues) == 0 {
+			fmt.Printf("There are no issues in %s\n", key)
+
+			return
+		}
+
+		result, err := critpath.Analyze(dependencyTasks(issues))
+		if err != nil {
+			fmt.Printf("Failed to find the critical path - %s\n", err.Error())
+			os.Exit(exitCode(err))
+		}
+
+		fmt.Printf("%s %s\n\n", key, epic.Fields.Summary)
+		printCriticalPath(issues, result)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(epicCmd)
+	epicCmd.AddCommand(epicCriticalPathCmd)
+
+	epicCmd.SetUsageTemplate(epicUsage)
+	epicCriticalPathCmd.SetUsageTemplate(epicUsage)
+}
+
+// dependencyTasks turns the issues into tasks depending on the issues
+// blocking them. Both sides of a link show up in the links of each
+// issue, so a blocking issue is found from either of them.
+func dependencyTasks(issues []types.DependencyIssue) []critpath.Task {
+	deps := map[string][]string{}
+
+	addDep := func(issue, blocker string) {
+		if blocker != "" && !slices.Contains(deps[issue], blocker) {
+			deps[issue] = append(deps[issue], blocker)
+		}
+	}
+
+	for _, i := range issues {
+		for _, l := range i.Fields.IssueLinks {
+			if !strings.EqualFold(l.Type.Name, blocksLinkType) {
+				continue
+			}
+
+			if l.OutwardIssue.Key != "" {
+				addDep(l.OutwardIssue.Key, i.Key)
+			}
+
+			addDep(i.Key, l.InwardIssue.Key)
+		}
+	}
+
+	tasks := []critpath.Task{}
+
+	for _, i := range issues {
+		tasks = append(tasks, critpath.Task{
+			Key:       i.Key,
+			Duration:  remainingSeconds(i),
+			DependsOn: deps[i.Key],
+		})
+	}
+
+	return tasks
+}
+
+func remainingSeconds(i types.DependencyIssue) int {
+	if i.Fields.Status.StatusCategory.Key == "done" {
+		return 0
+	}
+
+	return i.Fields.TimeEstimate
+}
+
+// addWorkingTime returns the date the given seconds of work are done,
+// working the configured hours per day on weekdays only.
+func addWorkingTime(from time.Time, seconds int) time.Time {
+	perDay := int(Cfg.WorkingHoursPerDay * 3600)
+	if perDay <= 0 {
+		perDay = 8 * 3600
+	}
+
+	days := (seconds + perDay - 1) / perDay
+	t := from
+
+	for days > 0 {
+		t = t.AddDate(0, 0, 1)
+		if t.Weekday() != time.Saturday && t.Weekday() != time.Sunday {
+			days--
+		}
+	}
+
+	return t
+}
+
+func printCriticalPath(issues []types.DependencyIssue, result critpath.Result) {
+	slices.SortStableFunc(issues, func(a, b types.DependencyIssue) int {
+		return result.Finish[a.Key] - result.Finish[b.Key]
+	})
+
+	today := time.Now()
+	unestimated := 0
+
+	fmt.Printf("%s%s%-12s %-20s %10s  %-10s  %s%s%s\n", format.Color.Ul, format.Color.Yellow,
+		"Key", "Status", "Remaining", "Finish", "Summary", format.Color.Nocolor, format.Color.Nocolor)
+
+	for _, i := range issues {
+		remaining := remainingSeconds(i)
+		if remaining == 0 && i.Fields.Status.StatusCategory.Key != "done" {
+			unestimated++
+		}
+
+		color, nocolor := "", ""
+		if slices.Contains(result.Path, i.Key) {
+			color, nocolor = format.Color.Red, format.Color.Nocolor
+		}
+
+		summary := i.Fields.Summary
+		truncateSummaries(summaryLength(58), &summary)
+
+		fmt.Printf("%s%-12s %-20s %10s  %-10s  %s%s\n", color, i.Key, i.Fields.Status.Name,
+			convert.SecondsToHoursAndMinutes(remaining, false),
+			addWorkingTime(today, result.Finish[i.Key]).Format("2006-01-02"), summary, nocolor)
+	}
+
+	fmt.Printf("\nCritical path:       %s%s%s\n", format.Color.Red, strings.Join(result.Path, " -> "), format.Color.Nocolor)
+	fmt.Printf("Remaining on path:   %s\n", convert.SecondsToHoursAndMinutes(result.Total, false))
+	fmt.Printf("Earliest completion: %s\n", addWorkingTime(today, result.Total).Format("2006-01-02"))
+
+	if unestimated > 0 {
+		fmt.Printf("\n%d open issues have no remaining estimate, and count as no work left\n", unestimated)
+	}
+}
@@ -0,0 +1,160 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mhersson/gojira/pkg/types"
+	"github.com/mhersson/gojira/pkg/util"
+)
+
+const escalateUsage string = `This command raises the priority of an issue, adds an escalation
+comment with the reason, and adds the users configured in
+escalationWatchers as watchers of the issue.
+
+By default the active issue is escalated,
+but this can be changed by adding the issue key as argument.
+
+Usage:
+  gojira escalate [ISSUE KEY] --to <PRIORITY> [flags]
+
+Flags:
+  -h, --help                   help for escalate
+  -r, --reason                 the reason for the escalation (prompted if not set)
+  -t, --to                     the new priority, e.g. Blocker
+
+Example:
+  gojira escalate GOJIRA-1 --to Blocker --reason "Customer X is down"
+`
+
+// Used by `escalate`.
+var (
+	EscalatePriority string
+	EscalateReason   string
+)
+
+type escalation struct {
+	From   string
+	To     string
+	User   string
+	Reason string
+}
+
+var escalateCmd = &cobra.Command{
+	Use:   "escalate",
+	Short: "Raise the priority of an issue and notify",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		exitIfReadOnly()
+
+		if len(args) == 1 {
+			IssueKey = strings.ToUpper(args[0])
+		}
+		checkIssueKey(&IssueKey, IssueFile)
+		checkPermission(IssueKey, "edit the issue", permEditIssues)
+
+		priority := getPriorityByName(EscalatePriority)
+		if priority.ID == "" {
+			fmt.Printf("%s is not a valid priority\n", EscalatePriority)
+			os.Exit(exitUsage)
+		}
+
+		issue := must(JiraClient.GetIssue(ctx, IssueKey))
+		if strings.EqualFold(issue.Fields.Priority.Name, priority.Name) {
+			fmt.Printf("%s already has priority %s\n", IssueKey, priority.Name)
+			os.Exit(exitUsage)
+		}
+
+		if EscalateReason == "" {
+			EscalateReason = util.GetUserInput("Reason for the escalation (press enter to quit): ", ".+")
+		}
+
+		err := JiraClient.UpdatePriority(ctx, IssueKey, priority.ID)
+		if err != nil {
+			fmt.Printf("Failed to update priority - %s\n", err.Error())
+			os.Exit(exitCode(err))
+		}
+
+		comment := util.ExecuteTemplate("escalation-comment.tmpl", escalation{
+			From:   issue.Fields.Priority.Name,
+			To:     priority.Name,
+			User:   Cfg.Username,
+			Reason: EscalateReason,
+		})
+
+		commentAdded := true
+
+		err = JiraClient.AddComment(ctx, IssueKey, comment)
+		if err != nil {
+			fmt.Printf("Failed to add escalation comment - %s\n", err.Error())
+
+			commentAdded = false
+		}
+
+		watchers := []string{}
+
+		for _, w := range Cfg.EscalationWatchers {
+			if err := JiraClient.AddWatcher(ctx, IssueKey, w); err != nil {
+				fmt.Printf("Failed to add %s as watcher - %s\n", w, err.Error())
+
+				continue
+			}
+
+			watchers = append(watchers, w)
+		}
+
+		success("%s escalated from %s to %s", IssueKey, issue.Fields.Priority.Name, priority.Name)
+
+		if commentAdded {
+			fmt.Println("Escalation comment added")
+		}
+
+		if len(watchers) > 0 {
+			fmt.Printf("Watchers notified: %s\n", strings.Join(watchers, ", "))
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(escalateCmd)
+
+	escalateCmd.SetUsageTemplate(escalateUsage)
+	escalateCmd.Flags().StringVarP(&EscalatePriority, "to", "t", "", "the new priority")
+	escalateCmd.Flags().StringVarP(&EscalateReason, "reason", "r", "", "the reason for the escalation")
+	_ = escalateCmd.MarkFlagRequired("to")
+}
+
+func getPriorityByName(name string) types.Priority {
+	for _, p := range must(JiraClient.GetPriorities(ctx)) {
+		if strings.EqualFold(p.Name, name) {
+			return p
+		}
+	}
+
+	return types.Priority{}
+}
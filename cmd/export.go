@@ -0,0 +1,246 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mhersson/gojira/pkg/util"
+	"github.com/mhersson/gojira/pkg/util/convert"
+	"github.com/mhersson/gojira/pkg/util/ics"
+)
+
+const exportICSUsage string = `Export sprints, due dates and your logged work as calendar events
+in an iCalendar file, which can be imported or subscribed to in most
+calendar apps. The events keep their ids between exports, so importing
+the file again updates the events instead of duplicating them.
+
+  sprints   the start to the end of the sprints on the active sprint board,
+            or the board set with --board
+  due       the due dates of your unresolved issues, or the issues matching
+            the filter set with --filter
+  worklog   your logged work since the date set with --since
+
+Usage:
+  gojira export ics [flags]
+
+Flags:
+  -b, --board [NAME OF BOARD]  the board of the sprints
+  -f, --filter [JQL FILTER]    the issues with due dates
+  -h, --help                   help for ics
+      --out [FILE]             the iCalendar file (default stdout)
+  -s, --since [DATE]           the start of the logged work, e.g. 2024-03-01 or 7d (default 30d)
+  -w, --what [EVENTS]          comma separated list of sprints, due and worklog (default due,worklog)
+
+Example:
+  gojira export ics --what sprints,due,worklog --out gojira.ics
+`
+
+// Used by `export ics`.
+var (
+	ExportWhat  string
+	ExportOut   string
+	ExportBoard string
+	ExportSince string
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export Jira data to other formats",
+	Args:  cobra.NoArgs,
+}
+
+var exportICSCmd = &cobra.Command{
+	Use:   "ics",
+	Short: "Export sprints, due dates and logged work as calendar events",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		events := []ics.Event{}
+
+		for _, what := range strings.Split(ExportWhat, ",") {
+			switch strings.TrimSpace(what) {
+			case "sprints":
+				events = append(events, sprintEvents()...)
+			case "due":
+				events = append(events, dueDateEvents()...)
+			case "worklog":
+				events = append(events, worklogEvents()...)
+			default:
+				fmt.Printf("Invalid event type %s, must be sprints, due or worklog\n", what)
+				os.Exit(exitUsage)
+			}
+		}
+
+		out := os.Stdout
+
+		if ExportOut != "" {
+			f, err := os.Create(ExportOut)
+			if err != nil {
+				fmt.Printf("Failed to create %s - %s\n", ExportOut, err.Error())
+				os.Exit(exitCode(err))
+			}
+			defer f.Close()
+
+			out = f
+		}
+
+		if err := ics.Write(out, events, time.Now()); err != nil {
+			fmt.Printf("Failed to write the events - %s\n", err.Error())
+			os.Exit(exitCode(err))
+		}
+
+		if ExportOut != "" {
+			success("Exported %d events to %s", len(events), ExportOut)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+
+	exportCmd.AddCommand(exportICSCmd)
+
+	exportICSCmd.SetUsageTemplate(exportICSUsage)
+	exportICSCmd.Flags().StringVarP(&ExportWhat, "what", "w", "due,worklog", "the events to export")
+	exportICSCmd.Flags().StringVar(&ExportOut, "out", "", "the iCalendar file")
+	exportICSCmd.Flags().StringVarP(&ExportBoard, "board", "b", "", "the board of the sprints")
+	exportICSCmd.Flags().StringVarP(&JQLFilter, "filter", "f", "", "the issues with due dates")
+	exportICSCmd.Flags().StringVarP(&ExportSince, "since", "s", "30d", "the start of the logged work")
+}
+
+// eventUID returns a globally unique id, that stays the same between exports.
+func eventUID(kind, id string) string {
+	host := Cfg.JiraURL
+	if u, err := url.Parse(Cfg.JiraURL); err == nil {
+		host = u.Hostname()
+	}
+
+	return kind + "-" + id + "@gojira." + host
+}
+
+func sprintEvents() []ics.Event {
+	board := ExportBoard
+	if board == "" {
+		board = activeBoard("sprint")
+	}
+
+	rapidView := must(JiraClient.GetRapidViewID(ctx, board))
+	if rapidView == nil || !rapidView.SprintSupportEnabled {
+		fmt.Printf("%s does not exist or sprint support is not enabled\n", board)
+		os.Exit(exitNotFound)
+	}
+
+	events := []ics.Event{}
+
+	for _, s := range must(JiraClient.GetBoardSprints(ctx, rapidView.ID)) {
+		start, err1 := time.Parse(time.RFC3339, s.StartDate)
+		end, err2 := time.Parse(time.RFC3339, s.EndDate)
+
+		// Future sprints are not planned yet
+		if err1 != nil || err2 != nil {
+			continue
+		}
+
+		events = append(events, ics.Event{
+			UID:         eventUID("sprint", fmt.Sprint(s.ID)),
+			Summary:     s.Name,
+			Description: rapidView.Name + " (" + strings.ToLower(s.State) + ")",
+			Start:       start,
+			End:         end,
+		})
+	}
+
+	return events
+}
+
+func dueDateEvents() []ics.Event {
+	filter := JQLFilter
+	if filter == "" {
+		filter = "assignee = currentUser() AND resolution = Unresolved AND duedate is not EMPTY"
+	}
+
+	events := []ics.Event{}
+
+	for _, i := range must(JiraClient.GetIssuesSelecting(ctx, filter, "duedate", []string{"summary", "status", "duedate"})) {
+		due, err := time.Parse("2006-01-02", i.Fields.DueDate)
+		if err != nil {
+			continue
+		}
+
+		events = append(events, ics.Event{
+			UID:         eventUID("due", i.Key),
+			Summary:     "Due: " + i.Key + " " + i.Fields.Summary,
+			Description: "Status: " + i.Fields.Status.Name,
+			URL:         issueURL(i.Key),
+			Start:       due,
+			End:         due.AddDate(0, 0, 1),
+			AllDay:      true,
+		})
+	}
+
+	return events
+}
+
+func worklogEvents() []ics.Event {
+	since, err := convert.SinceToTime(ExportSince, time.Now())
+	if err != nil {
+		fmt.Printf("Invalid since %s - %s\n", ExportSince, err.Error())
+		os.Exit(exitUsage)
+	}
+
+	events := []ics.Event{}
+
+	issues := must(JiraClient.GetIssuesSelecting(ctx,
+		`worklogAuthor = currentUser() AND worklogDate >= "`+since.Format("2006-01-02")+`"`,
+		"updated DESC", []string{"summary"}))
+
+	for _, i := range issues {
+		for _, w := range must(JiraClient.GetWorklogs(ctx, i.Key)) {
+			started, err := util.ParseJiraTime(w.Started)
+			if err != nil || w.Author.Name != Cfg.Username || started.Before(since) {
+				continue
+			}
+
+			events = append(events, ics.Event{
+				UID:         eventUID("worklog", w.ID),
+				Summary:     i.Key + " " + i.Fields.Summary,
+				Description: w.Comment,
+				URL:         issueURL(i.Key),
+				Start:       started,
+				End:         started.Add(time.Duration(w.TimeSpentSeconds) * time.Second),
+			})
+		}
+	}
+
+	slices.SortFunc(events, func(a, b ics.Event) int {
+		return a.Start.Compare(b.Start)
+	})
+
+	return events
+}
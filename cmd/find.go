@@ -0,0 +1,185 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mhersson/gojira/pkg/types"
+	"github.com/mhersson/gojira/pkg/util/convert"
+	"github.com/mhersson/gojira/pkg/util/format"
+	"github.com/mhersson/gojira/pkg/util/index"
+)
+
+const findUsage string = `Searches the summary, description and comments of your issues
+in the local cache, and displays the best matches with the matching
+words highlighted. Misspelled and partial words also match, e.g.
+"pacemakr" or "pace" will find issues about pacemaker.
+
+The cache holds the issues you are assigned to, have reported or watch,
+and is updated the first time find is used, or when adding --refresh.
+
+Usage:
+  gojira find <WORDS> [flags]
+
+Flags:
+  -h, --help                   help for find
+  -l, --limit [NUMBER]         max number of issues to display (default 10)
+  -r, --refresh                update the cache before searching
+
+Example:
+  gojira find "timeout pacemaker"
+`
+
+// Used by `find`.
+var (
+	FindRefresh bool
+	FindLimit   int
+)
+
+// The cache is considered stale after a week.
+const issueCacheMaxAge = 7 * 24 * time.Hour
+
+const findSnippetLength = 80
+
+var findCmd = &cobra.Command{
+	Use:   "find",
+	Short: "Search your issues in the local cache",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		issues, updated, err := loadIssueCache()
+
+		switch {
+		case FindRefresh || errors.Is(err, os.ErrNotExist):
+			issues = refreshIssueCache()
+		case err != nil:
+			fmt.Printf("Failed to load the issue cache - %s\n", err.Error())
+			os.Exit(exitCode(err))
+		case time.Since(updated) > issueCacheMaxAge:
+			fmt.Printf("The cache was updated %s ago, use --refresh to update it\n",
+				convert.DurationToDaysAndHours(time.Since(updated)))
+		}
+
+		findIssues(issues, strings.Join(args, " "))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(findCmd)
+
+	findCmd.SetUsageTemplate(findUsage)
+	findCmd.Flags().BoolVarP(&FindRefresh, "refresh", "r", false, "update the cache before searching")
+	findCmd.Flags().IntVarP(&FindLimit, "limit", "l", 10, "max number of issues to display")
+}
+
+// loadIssueCache returns the cached issues, and when the cache was updated.
+func loadIssueCache() ([]types.IssueDescription, time.Time, error) {
+	issues := []types.IssueDescription{}
+
+	info, err := os.Stat(IssueCacheFile)
+	if err != nil {
+		return issues, time.Time{}, err
+	}
+
+	data, err := os.ReadFile(IssueCacheFile)
+	if err != nil {
+		return issues, time.Time{}, err
+	}
+
+	err = json.Unmarshal(data, &issues)
+
+	return issues, info.ModTime(), err
+}
+
+func refreshIssueCache() []types.IssueDescription {
+	issues := must(JiraClient.GetIssueDescriptions(ctx,
+		"assignee = currentUser() OR reporter = currentUser() OR watcher = currentUser()", "updated DESC"))
+
+	data, err := json.Marshal(issues)
+	if err == nil {
+		err = os.MkdirAll(CacheFolder, 0700)
+	}
+
+	if err == nil {
+		err = os.WriteFile(IssueCacheFile, data, 0600)
+	}
+
+	if err != nil {
+		fmt.Printf("Failed to write the issue cache - %s\n", err.Error())
+	}
+
+	return issues
+}
+
+func findIssues(issues []types.IssueDescription, query string) {
+	docs := []index.Document{}
+	byKey := map[string]types.IssueDescription{}
+	bodies := map[string]string{}
+
+	for _, i := range issues {
+		body := []string{i.Fields.Description}
+		for _, c := range i.Fields.Comment.Comments {
+			body = append(body, c.Body)
+		}
+
+		docs = append(docs, index.Document{ID: i.Key, Title: i.Fields.Summary, Body: strings.Join(body, "\n")})
+		byKey[i.Key] = i
+		bodies[i.Key] = docs[len(docs)-1].Body
+	}
+
+	results := index.New(docs).Search(query)
+	if len(results) == 0 {
+		fmt.Println("No issues matched")
+
+		return
+	}
+
+	if FindLimit > 0 && len(results) > FindLimit {
+		results = results[:FindLimit]
+	}
+
+	mark := func(s string) string { return format.Color.Bold + format.Color.Yellow + s + format.Color.Nocolor }
+
+	for _, r := range results {
+		issue := byKey[r.ID]
+
+		fmt.Printf("%s%-15s%s%s\n", format.IssueType(issue.Fields.IssueType.Name, true), issue.Key,
+			format.Status(issue.Fields.Status.Name, true),
+			index.Snippet(issue.Fields.Summary, r.Terms, len(issue.Fields.Summary), mark))
+
+		matchesBody := slices.ContainsFunc(index.Tokenize(bodies[r.ID]), func(t string) bool {
+			return slices.Contains(r.Terms, t)
+		})
+
+		if matchesBody {
+			fmt.Printf("%-37s%s\n", "", index.Snippet(bodies[r.ID], r.Terms, findSnippetLength, mark))
+		}
+	}
+}
@@ -26,8 +26,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -35,19 +38,47 @@ import (
 	"github.com/mhersson/gojira/pkg/jira"
 	"github.com/mhersson/gojira/pkg/types"
 	"github.com/mhersson/gojira/pkg/util"
+	"github.com/mhersson/gojira/pkg/util/calendar"
 	"github.com/mhersson/gojira/pkg/util/convert"
+	"github.com/mhersson/gojira/pkg/util/emoji"
 	"github.com/mhersson/gojira/pkg/util/format"
+	"github.com/mhersson/gojira/pkg/util/markup"
 	"github.com/mhersson/gojira/pkg/util/validate"
 )
 
-var GetAllSprints bool
+var (
+	GetAllSprints   bool
+	SprintChanges   bool   // Used by `get sprint` to show scope changes
+	SavedJiraFilter string // Used by `get all` to run a saved filter
+	IssueColumns    string // Used by `get all` to select the columns
+	IssueLimit      int    // Used by `get all` to cap the number of issues
+	StatsTrend      bool   // Used by `get myworklog stats` to show the trend
+	IssueOrder      string // Used by `get all` and `get kanban` to order the issues
+	EpicBoard       string // Used by `get epics` to list the epics on a board
+	AllBoards       bool   // Used by `get sprint` and `get kanban` to show all favourite boards
+	WorklogSince    string // Used by `get worklog` to show the work logged since
+	WorklogUntil    string // Used by `get worklog` to show the work logged until
+	WorklogAuthor   string // Used by `get worklog` to show the work logged by someone
+)
 
 const getAllIssuesUsage string = `This command will by default display all unresolved
 issues assinged to you, but by using the --filter flag
-you can compose your own jql filter. All query results,
+you can compose your own jql filter, or with the --jira-filter
+flag run one of the filters saved in Jira, by name or id.
+Unless the filter has its own ordering, all query results,
 default as well as custom ones, will be sorted by priority
 and their latest update time.
 
+With --order rank the issues are ordered by rank, i.e. the order on the
+board, and the position of each issue is displayed.
+
+All issues matching the filter are fetched, 50 at a time. Use --limit
+to cap the number of issues, e.g. for broad filters on large projects.
+
+The columns can be selected with --columns, as a comma separated
+list of field ids, including custom fields. The column header is
+the field id unless another header is given after a colon.
+
 Usage:
   gojira get all [flags]
 
@@ -55,8 +86,14 @@ Aliases:
   all, l
 
 Flags:
+  -c, --columns [FIELDS]       select the columns to display
   -f, --filter [JQL FILTER]    write your own jql filter
   -h, --help                   help for all
+  -j, --jira-filter [NAME|ID]  run a filter saved in Jira
+  -l, --limit [NUMBER]         display at most this many issues
+  -o, --output [FORMAT]        output format, csv, json, yaml or exec:FORMATTER
+      --template [TEMPLATE]    render the output with a go template, or @FILE
+      --order [priority|rank]  order the issues by priority (default) or rank
 
 Examples:
   # Display all issues assigned to you (default)
@@ -68,6 +105,12 @@ Examples:
   # All open issues on project OSE
   gojira get all -f "project = OSE and resolution = unresolved"
 
+  # Run the saved filter "My open bugs"
+  gojira get all -j "My open bugs"
+
+  # Display the team and due date of your issues
+  gojira get all --columns key,summary,customfield_10800:Team,duedate
+
 `
 
 const getCommentsUsage string = `
@@ -84,10 +127,66 @@ Flags:
   -h, --help                   help for comment
 `
 
+const getAttachmentsUsage string = `
+By default the attachments of the active issue are displayed,
+but this can be changed by adding the issue key as argument.
+Use gojira download to fetch them.
+
+Usage:
+  gojira get attachments [ISSUE KEY] [flags]
+
+Aliases:
+  attachments, at
+
+Flags:
+  -h, --help                   help for attachments
+  -o, --output [FORMAT]        output format, json
+`
+
+const getWatchersUsage string = `
+By default the watchers of the active issue are displayed,
+but this can be changed by adding the issue key as argument.
+Use gojira watch and unwatch to change the watchers.
+
+Usage:
+  gojira get watchers [ISSUE KEY] [flags]
+
+Aliases:
+  watchers, wa
+
+Flags:
+  -h, --help                   help for watchers
+  -o, --output [FORMAT]        output format, json
+`
+
+const getLinkTypesUsage string = `
+Display the link types, and how they read from each of the linked
+issues. Both descriptions, and the name, can be used with add link
+and delete link.
+
+Usage:
+  gojira get linktypes [flags]
+
+Aliases:
+  linktypes, lt
+
+Flags:
+  -h, --help                   help for linktypes
+  -o, --output [FORMAT]        output format, json
+`
+
 const getWorklogUsage string = `
 By default the worklog from the active issue is displayed,
 but this can be changed by adding the issue key as argument.
 
+Worklogs added with billable, overtime or account attributes show them
+in a separate column, and the time spent is summed up per attribute.
+
+Filter the worklog by when the work was started with --since and --until,
+given as today, yesterday, a number of days (3d), a duration (12h) or a
+date (yyyy-mm-dd), and by who logged it with --author, e.g. me. The days
+given to --until are included.
+
 Usage:
   gojira get worklog [ISSUE KEY] [flags]
 
@@ -95,7 +194,16 @@ Aliases:
   worklog, w
 
 Flags:
+  -a, --author [USER]          only the work logged by the user, me for yourself
+      --file [FILE]            write the csv output to the file
   -h, --help                   help for worklog
+  -o, --output [FORMAT]        output format, csv, json, yaml or exec:FORMATTER
+  -s, --since [TIME]           only the work started since
+      --template [TEMPLATE]    render the output with a go template, or @FILE
+  -u, --until [TIME]           only the work started until
+
+Example:
+  gojira get worklog OSE-1 --since 7d --author me
 `
 
 const myWorklogUsage string = `This command will show the issues you have worked on
@@ -112,14 +220,26 @@ Aliases:
   myworklog, m
 
 Flags:
+      --file [FILE]            write the csv output to the file
   -h, --help                   help for myworklog
   -w, --week                   current week (only with timesheet plugin)
+  -o, --output [FORMAT]        output format, csv, json, yaml or exec:FORMATTER
+      --template [TEMPLATE]    render the output with a go template, or @FILE
+
+Example:
+  # Export the hours logged this week to a spreadsheet
+  gojira get myworklog --week --file week.csv
 `
 
 const myWorklogStatisticsUsage string = `Shows per week worklog statistics for a given period.
 Aligns the week numbers to the dates entered,
 and calculates the average and total amount of hours per week.
 
+With --trend the weekly totals are compared to the expected hours,
+using a rolling 4 week average, and the weeks where the average is
+more than 10% over or under the expected hours are flagged. The
+linear trend and the median and 90th percentile of the weekly hours
+are shown for the whole period.
 
 Usage:
   gojira get myworklog stats [yyyy-mm-dd] [yyyy-mm-dd]
@@ -128,10 +248,19 @@ Aliases:
   stats, s
 
 Flags:
+      --file [FILE]            write the csv output to the file
   -h, --help                   help for myworklog
+  -t, --trend                  show the rolling average and trend
+  -o, --output [FORMAT]        output format, csv is the only one supported
 `
 
 const getSprintUsage string = `
+With --changes the issues added to or removed from the sprint after
+it was started are listed instead, with the time and who made the change.
+
+With --all-boards the sprints of all your favourite boards with
+sprint support are displayed, see gojira board.
+
 Usage:
   gojira get sprint [NAME OF BOARD]
 
@@ -141,9 +270,54 @@ Aliases:
 Flags:
   -h, --help                   help for sprint
   -a, --all                    get all sprints (future and  active)
+      --all-boards             show the sprints of all favourite boards
+  -c, --changes                show the scope changes after the sprint started
+  -o, --output [FORMAT]        output format, csv, json, yaml or exec:FORMATTER
+      --template [TEMPLATE]    render the output with a go template, or @FILE
+`
+
+const getEpicsUsage string = `
+Lists the unresolved epics in a project, or on a board, with their status,
+the number of issues in the epic, and how many percent of them are done.
+
+Usage:
+  gojira get epics [PROJECT KEY] [flags]
+
+Aliases:
+  epics, e
+
+Flags:
+  -b, --board [NAME OF BOARD]  list the epics on the board
+  -h, --help                   help for epics
+  -o, --output [FORMAT]        output format, csv is the only one supported
+
+Examples:
+  gojira get epics OSE
+  gojira get epics --board "Team A"
+`
+
+const getFiltersUsage string = `
+Lists your favourite filters saved in Jira. The filters
+can be run by name or id with get all --jira-filter.
+
+Usage:
+  gojira get filters [flags]
+
+Aliases:
+  filters, f
+
+Flags:
+  -h, --help                   help for filters
 `
 
 const getKanbanBoardUsage string = `
+The issues are displayed in the board order. With --order rank the
+position of each issue is displayed, and with --order priority the
+issues are ordered by priority and latest update time instead.
+
+With --all-boards all your favourite boards without sprint support
+are displayed one after another, see gojira board.
+
 Usage:
   gojira get kanban [NAME OF BOARD]
 
@@ -152,7 +326,10 @@ Aliases:
 
 Flags:
   -h, --help                   help for kanban
+      --all-boards             show all favourite kanban boards
   -c, --closed                 show closed issues
+  -o, --output [FORMAT]        output format, csv is the only one supported
+      --order [priority|rank]  order the issues by priority or rank
 `
 
 // getCmd represents the get command.
@@ -171,11 +348,126 @@ var getAllIssuesCmd = &cobra.Command{
 	Args:    cobra.NoArgs,
 	Aliases: []string{"l"},
 	Run: func(cmd *cobra.Command, args []string) {
-		myIssues := jira.GetIssues(JQLFilter)
+		if SavedJiraFilter != "" {
+			JQLFilter = getSavedFilter(SavedJiraFilter).JQL
+		}
+
+		checkOutputFormat("csv", "json")
+		orderBy := issueOrderBy(jira.OrderByPriority)
+
+		if IssueLimit < 0 {
+			fmt.Println("The limit can not be negative")
+			os.Exit(exitUsage)
+		}
+
+		JiraClient.SetSearchLimit(IssueLimit)
+
+		if IssueColumns != "" {
+			columns := parseColumns(IssueColumns)
+			fields := []string{"status"}
+
+			for _, c := range columns {
+				if !strings.EqualFold(c.Field, "key") {
+					fields = append(fields, c.Field)
+				}
+			}
+
+			issues := must(JiraClient.GetIssuesWithFields(ctx, JQLFilter, orderBy, fields))
+
+			switch OutputFormat {
+			case "csv":
+				printColumnsCSV(issues, columns)
+			case "json":
+				printJSON(issues)
+			default:
+				printIssuesWithColumns(issues, columns)
+			}
+
+			return
+		}
+
+		myIssues := must(JiraClient.GetIssuesOrderedBy(ctx, JQLFilter, orderBy))
+
+		switch OutputFormat {
+		case "csv":
+			printIssuesCSV(myIssues, false)
+
+			return
+		case "json":
+			printJSON(myIssues)
+
+			return
+		}
+
 		printIssues(myIssues, true, false)
 	},
 }
 
+var getEpicsCmd = &cobra.Command{
+	Use:     "epics",
+	Short:   "Display the epics in a project or on a board",
+	Args:    cobra.MaximumNArgs(1),
+	Aliases: []string{"e"},
+	Run: func(cmd *cobra.Command, args []string) {
+		checkOutputFormat("csv")
+
+		var epics []types.Issue
+
+		switch {
+		case len(args) == 1 && EpicBoard != "":
+			fmt.Println("Can not use both a project and a board")
+			os.Exit(exitUsage)
+		case len(args) == 1:
+			project := getProject(args[0])
+			epics = must(JiraClient.GetIssues(ctx, "project = "+project.Key+" AND issuetype = Epic AND resolution = Unresolved"))
+		case EpicBoard != "":
+			rapidView := must(JiraClient.GetRapidViewID(ctx, EpicBoard))
+			if rapidView == nil {
+				fmt.Printf("Board %s does not exist\n", EpicBoard)
+				os.Exit(exitNotFound)
+			}
+
+			keys := []string{}
+			for _, e := range must(JiraClient.GetBoardEpics(ctx, rapidView.ID)) {
+				keys = append(keys, e.Key)
+			}
+
+			if len(keys) > 0 {
+				epics = must(JiraClient.GetIssues(ctx, "key in ("+strings.Join(keys, ",")+")"))
+			}
+		default:
+			fmt.Println("Please specify a project or a board")
+			os.Exit(exitUsage)
+		}
+
+		if len(epics) == 0 {
+			fmt.Println("No epics found")
+
+			return
+		}
+
+		progress := getEpicProgress(epics)
+
+		if OutputFormat == "csv" {
+			printEpicsCSV(progress)
+
+			return
+		}
+
+		printEpics(progress)
+	},
+}
+
+var getFiltersCmd = &cobra.Command{
+	Use:     "filters",
+	Short:   "Display your favourite filters saved in Jira",
+	Args:    cobra.NoArgs,
+	Aliases: []string{"f"},
+	Run: func(cmd *cobra.Command, args []string) {
+		printFilters(must(JiraClient.GetFavouriteFilters(ctx)))
+	},
+}
+
 var getActiveCmd = &cobra.Command{
 	Use:     "active",
 	Short:   "Display the active issue, sprint or kanban board",
@@ -189,9 +481,9 @@ var getActiveIssueCmd = &cobra.Command{
 	Args:    cobra.NoArgs,
 	Aliases: []string{"i"},
 	Run: func(cmd *cobra.Command, args []string) {
-		key := util.GetActiveIssue(IssueFile)
-		summary := getSummary(key)
-		fmt.Printf("Active issue: %s %s\n", key, summary)
+		key, err := util.GetActiveIssue(IssueFile)
+		exitIfNotSet(err)
+		fmt.Printf("Active issue: %s %s\n", key, getIssueBrief(key).Fields.Summary)
 	},
 }
 
@@ -201,7 +493,7 @@ var getActiveSprintCmd = &cobra.Command{
 	Aliases: []string{"s"},
 	Args:    cobra.NoArgs,
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Printf("Active sprint board: %s\n", util.GetActiveSprintOrKanban(BoardFile, "sprint"))
+		fmt.Printf("Active sprint board: %s\n", activeBoard("sprint"))
 	},
 }
 
@@ -211,7 +503,7 @@ var getActiveKanbanCmd = &cobra.Command{
 	Aliases: []string{"k"},
 	Args:    cobra.NoArgs,
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Printf("Active kanban board: %s\n", util.GetActiveSprintOrKanban(BoardFile, "kanban"))
+		fmt.Printf("Active kanban board: %s\n", activeBoard("kanban"))
 	},
 }
 
@@ -221,7 +513,7 @@ var getStatusCmd = &cobra.Command{
 	Args:    cobra.NoArgs,
 	Aliases: []string{"st"},
 	Run: func(cmd *cobra.Command, args []string) {
-		jira.CheckIssueKey(&IssueKey, IssueFile)
+		checkIssueKey(&IssueKey, IssueFile)
 		status := getStatus(IssueKey)
 		printStatus(status, false)
 	},
@@ -233,10 +525,10 @@ var getTransistionsCmd = &cobra.Command{
 	Args:    cobra.NoArgs,
 	Aliases: []string{"t"},
 	Run: func(cmd *cobra.Command, args []string) {
-		jira.CheckIssueKey(&IssueKey, IssueFile)
+		checkIssueKey(&IssueKey, IssueFile)
 		status := getStatus(IssueKey)
 		printStatus(status, false)
-		tr := jira.GetTransistions(IssueKey)
+		tr := must(JiraClient.GetTransistions(ctx, IssueKey))
 		printTransitions(tr)
 	},
 }
@@ -250,12 +542,77 @@ var getCommentsCmd = &cobra.Command{
 		if len(args) == 1 {
 			IssueKey = strings.ToUpper(args[0])
 		}
-		jira.CheckIssueKey(&IssueKey, IssueFile)
-		comments := jira.GetComments(IssueKey)
+		checkIssueKey(&IssueKey, IssueFile)
+		comments := must(JiraClient.GetComments(ctx, IssueKey))
 		printComments(comments, 0)
 	},
 }
 
+var getAttachmentsCmd = &cobra.Command{
+	Use:     "attachments",
+	Short:   "Display the attachments",
+	Args:    cobra.MaximumNArgs(1),
+	Aliases: []string{"at"},
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 1 {
+			IssueKey = strings.ToUpper(args[0])
+		}
+		checkOutputFormat("json")
+		checkIssueKey(&IssueKey, IssueFile)
+		issue := must(JiraClient.GetIssue(ctx, IssueKey))
+
+		if OutputFormat == "json" {
+			printJSON(issue.Fields.Attachments)
+
+			return
+		}
+
+		printAttachments(issue.Fields.Attachments)
+	},
+}
+
+var getWatchersCmd = &cobra.Command{
+	Use:     "watchers",
+	Short:   "Display the watchers",
+	Args:    cobra.MaximumNArgs(1),
+	Aliases: []string{"wa"},
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 1 {
+			IssueKey = strings.ToUpper(args[0])
+		}
+		checkOutputFormat("json")
+		checkIssueKey(&IssueKey, IssueFile)
+		watchers := must(JiraClient.GetWatchers(ctx, IssueKey))
+
+		if OutputFormat == "json" {
+			printJSON(watchers)
+
+			return
+		}
+
+		printWatchers(IssueKey, watchers)
+	},
+}
+
+var getLinkTypesCmd = &cobra.Command{
+	Use:     "linktypes",
+	Short:   "Display the issue link types",
+	Args:    cobra.NoArgs,
+	Aliases: []string{"lt"},
+	Run: func(cmd *cobra.Command, args []string) {
+		checkOutputFormat("json")
+		linkTypes := must(JiraClient.GetIssueLinkTypes(ctx))
+
+		if OutputFormat == "json" {
+			printJSON(linkTypes)
+
+			return
+		}
+
+		printLinkTypes(linkTypes)
+	},
+}
+
 var getWorklogCmd = &cobra.Command{
 	Use:     "worklog",
 	Short:   "Display the worklog",
@@ -265,9 +622,29 @@ var getWorklogCmd = &cobra.Command{
 		if len(args) == 1 {
 			IssueKey = strings.ToUpper(args[0])
 		}
-		jira.CheckIssueKey(&IssueKey, IssueFile)
-		worklogs := jira.GetWorklogs(IssueKey)
-		printWorklogs(IssueKey, worklogs)
+		checkOutputFormat("csv", "json")
+		checkIssueKey(&IssueKey, IssueFile)
+		all := must(JiraClient.GetWorklogs(ctx, IssueKey))
+		worklogs := filterWorklogs(all, time.Now())
+
+		switch OutputFormat {
+		case "csv":
+			printWorklogsCSV(worklogs)
+
+			return
+		case "json":
+			printJSON(worklogs)
+
+			return
+		}
+
+		if len(worklogs) == len(all) {
+			printWorklogs(IssueKey, worklogs)
+
+			return
+		}
+
+		printFilteredWorklogs(worklogs, len(all))
 	},
 }
 
@@ -277,33 +654,67 @@ var getMyWorklogCmd = &cobra.Command{
 	Args:    cobra.MaximumNArgs(1),
 	Aliases: []string{"m"},
 	Run: func(cmd *cobra.Command, args []string) {
+		checkOutputFormat("csv", "json")
+
 		date := util.GetCurrentDate()
 		if len(args) == 1 {
 			date = args[0]
 		}
-		if validate.Date(date) {
-			if Cfg.UseTimesheetPlugin {
-				ts := jira.GetTimesheet(date, date, ShowEntireWeek)
-				if len(ts) == 0 && util.DateIsToday(date) {
+		if !validate.Date(date) {
+			return
+		}
+
+		if useTimesheetPlugin() {
+			ts, err := JiraClient.GetTimesheet(ctx, date, date, ShowEntireWeek)
+			if !timesheetMissing(err) {
+				exitOnError(err)
+
+				if len(ts) == 0 && must(util.DateIsToday(date)) && OutputFormat != "json" {
 					fmt.Println("You havn't logged any hours today.")
 					os.Exit(0)
 				}
 
-				worklogs := util.GetWorklogsSorted(ts, true)
-				printTimesheet(worklogs)
-			} else {
-				issues := jira.GetIssues("worklogDate = " + date +
-					" AND worklogAuthor = currentUser()")
+				worklogs := util.GetWorklogsSorted(ts, false)
 
-				if len(issues) == 0 && util.DateIsToday(date) {
-					fmt.Println("You havn't logged any hours today.")
-					os.Exit(0)
+				switch OutputFormat {
+				case "csv":
+					printTimesheetCSV(worklogs)
+
+					return
+				case "json":
+					printJSON(worklogs)
+
+					return
 				}
 
-				myIssues := getUserTimeOnIssueAtDate(Cfg.Username, date, issues)
-				printMyWorklog(myIssues)
+				printTimesheet(worklogs)
+
+				return
 			}
 		}
+
+		issues := must(JiraClient.GetIssues(ctx, "worklogDate = "+date+
+			" AND worklogAuthor = currentUser()"))
+
+		if len(issues) == 0 && must(util.DateIsToday(date)) && OutputFormat != "json" {
+			fmt.Println("You havn't logged any hours today.")
+			os.Exit(0)
+		}
+
+		myIssues := getUserTimeOnIssueAtDate(Cfg.Username, date, issues)
+
+		switch OutputFormat {
+		case "csv":
+			printMyWorklogCSV(myIssues)
+
+			return
+		case "json":
+			printJSON(myIssues)
+
+			return
+		}
+
+		printMyWorklog(myIssues)
 	},
 }
 
@@ -313,9 +724,11 @@ var getMyWorklogStatistics = &cobra.Command{
 	Aliases: []string{"s"},
 	Args:    cobra.ExactArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
-		if !Cfg.UseTimesheetPlugin {
+		checkOutputFormat("csv")
+
+		if !useTimesheetPlugin() {
 			fmt.Println("This command is only available with the timesheet plugin")
-			os.Exit(1)
+			os.Exit(exitFailure)
 		}
 		if validate.Date(args[0]) && validate.Date(args[1]) {
 			t1, _ := time.Parse("2006-01-02", args[0])
@@ -324,15 +737,22 @@ var getMyWorklogStatistics = &cobra.Command{
 				t2, t1 = t1, t2
 			}
 
-			fromDate, _ := util.WeekStartEndDate(t1.ISOWeek())
-			_, toDate := util.WeekStartEndDate(t2.ISOWeek())
+			fromDate, _ := util.WeekStartEndDate(weekRule(), t1)
+			_, toDate := util.WeekStartEndDate(weekRule(), t2)
 
 			if t2.Sub(t1).Hours() > (24 * 365) {
 				fmt.Println("1 year is the max time period.")
-				os.Exit(1)
+				os.Exit(exitUsage)
 			}
 
-			ts := jira.GetTimesheet(fromDate, toDate, false)
+			ts, err := JiraClient.GetTimesheet(ctx, fromDate, toDate, false)
+			if timesheetMissing(err) {
+				fmt.Println("This command is only available with the timesheet plugin")
+				os.Exit(exitFailure)
+			}
+
+			exitOnError(err)
+
 			if len(ts) == 0 {
 				fmt.Printf("You havn't logged any hours between %s - %s\n", args[0], args[1])
 				os.Exit(0)
@@ -340,54 +760,129 @@ var getMyWorklogStatistics = &cobra.Command{
 
 			worklogs := util.GetWorklogsSorted(ts, true)
 
-			if _, err := os.Stat(ConfigFolder); errors.Is(err, os.ErrNotExist) {
-				_ = os.Mkdir(ConfigFolder, 0o755)
+			weeks := util.GroupWorklogsByWeek(weekRule(), fromDate, toDate, worklogs, publicHolidays(fromDate, toDate))
+
+			switch {
+			case StatsTrend && OutputFormat == "csv":
+				printTrendCSV(weeks)
+			case StatsTrend:
+				printTrend(weeks)
+			case OutputFormat == "csv":
+				printStatisticsCSV(weeks)
+			default:
+				printStatistics(weeks)
 			}
-
-			publicHolidays := util.LoadPublicHolidays(
-				filepath.Join(ConfigFolder, "public-holidays-"+t1.Format("2006")+"-"+Cfg.CountryCode+".json"),
-				t1.Format("2006"),
-				Cfg.CountryCode)
-
-			weeks := util.GroupWorklogsByWeek(fromDate, toDate, worklogs, util.GetPublicHolidayDates(publicHolidays))
-
-			printStatistics(weeks)
 		} else {
 			fmt.Println("Invalid date.")
 		}
 	},
 }
 
+// weekRule returns how the weeks are numbered.
+func weekRule() calendar.Rule {
+	rule, _ := calendar.ParseRule(Cfg.WeekNumbering)
+
+	return rule
+}
+
+// publicHolidays returns the dates of the public holidays in the years
+// from fromDate to toDate, as a period around New Year spans two years.
+func publicHolidays(fromDate, toDate string) []string {
+	if _, err := os.Stat(StateFolder); errors.Is(err, os.ErrNotExist) {
+		_ = os.MkdirAll(StateFolder, 0o755)
+	}
+
+	dates := []string{}
+	first, _ := strconv.Atoi(fromDate[:4])
+	last, _ := strconv.Atoi(toDate[:4])
+
+	for i := first; i <= last; i++ {
+		year := strconv.Itoa(i)
+		holidays := util.LoadPublicHolidays(
+			filepath.Join(StateFolder, "public-holidays-"+year+"-"+Cfg.CountryCode+".json"),
+			year,
+			Cfg.CountryCode)
+
+		dates = append(dates, util.GetPublicHolidayDates(holidays)...)
+	}
+
+	return dates
+}
+
 var getSprintCmd = &cobra.Command{
 	Use:     "sprint",
 	Short:   "Display sprint board",
 	Aliases: []string{"s"},
 	Args:    cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		var board string
-		if len(args) >= 1 {
-			board = args[0]
-		} else {
-			board = util.GetActiveSprintOrKanban(BoardFile, "sprint")
-		}
-		rapidView := jira.GetRapidViewID(board)
-		if rapidView != nil && rapidView.SprintSupportEnabled {
-			issueTypes := jira.GetIssueTypes()
-			priorities := jira.GetPriorities()
-			sprints, issues := jira.GetSprints(rapidView.ID)
-			for i := range sprints {
-				sprint := sprints[i]
+		checkOutputFormat("csv", "json")
+
+		issueTypes := must(JiraClient.GetIssueTypes(ctx))
+		priorities := must(JiraClient.GetPriorities(ctx))
+		rows := [][]string{}
+		sprintsJSON := []sprintJSON{}
+		boards := boardsToShow(args, "sprint")
+
+		startPager()
+		defer stopPager()
+
+		for _, board := range boards {
+			rapidView := must(JiraClient.GetRapidViewID(ctx, board))
+			if rapidView == nil || !rapidView.SprintSupportEnabled {
+				if !AllBoards {
+					fmt.Printf("%s does not exist or sprint support is not enabled\n", board)
+				}
+
+				continue
+			}
+
+			for _, sprint := range getOpenSprints(rapidView) {
 				if !sprint.MatchesFilter(Cfg.SprintFilter) {
 					continue
 				}
 				if sprint.State != "ACTIVE" && !GetAllSprints {
 					continue
 				}
-				fmt.Println(format.SprintHeader(sprint))
-				printSprintIssues(&sprint, issues, *issueTypes, priorities)
+
+				// Only fetch the issues of the sprints shown, as
+				// the future sprints can hold the entire backlog
+				var issues []types.SprintIssue
+				if !SprintChanges {
+					issues = getSprintIssues(rapidView.ID, &sprint)
+				}
+
+				switch {
+				case OutputFormat == "json" && SprintChanges:
+					sprintsJSON = append(sprintsJSON, sprintJSON{
+						Board: rapidView.Name, Sprint: sprint, Changes: getSprintChanges(rapidView.ID, &sprint),
+					})
+				case OutputFormat == "json":
+					sprintsJSON = append(sprintsJSON, sprintJSON{
+						Board: rapidView.Name, Sprint: sprint, Issues: issuesInSprint(&sprint, issues),
+					})
+				case OutputFormat == "csv" && SprintChanges:
+					rows = append(rows, sprintChangesCSV(&sprint, getSprintChanges(rapidView.ID, &sprint))...)
+				case OutputFormat == "csv":
+					rows = append(rows, sprintIssuesCSV(&sprint, issues, *issueTypes, priorities)...)
+				case SprintChanges:
+					header(format.SprintHeader(sprint))
+					printSprintChanges(getSprintChanges(rapidView.ID, &sprint))
+				default:
+					header(format.SprintHeader(sprint))
+					printSprintIssues(&sprint, issues, *issueTypes, priorities)
+				}
 			}
-		} else {
-			fmt.Printf("%s does not exist or sprint support is not enabled\n", board)
+		}
+
+		switch OutputFormat {
+		case "csv":
+			if SprintChanges {
+				printCSV(sprintChangesCSVHeader, rows)
+			} else {
+				printCSV(sprintIssuesCSVHeader, rows)
+			}
+		case "json":
+			printJSON(sprintsJSON)
 		}
 	},
 }
@@ -398,26 +893,36 @@ var getKanbanBoardCmd = &cobra.Command{
 	Aliases: []string{"k"},
 	Args:    cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		var board string
-		if len(args) >= 1 {
-			board = args[0]
-		} else {
-			board = util.GetActiveSprintOrKanban(BoardFile, "kanban")
-		}
+		checkOutputFormat("csv")
 
-		rapidView := jira.GetRapidViewID(board)
-		if rapidView == nil {
-			fmt.Printf("Board %s does not exist\n", board)
-			os.Exit(1)
-		}
+		all := []types.Issue{}
 
-		issues := jira.GetKanbanIssues(rapidView.ID)
+		for _, board := range boardsToShow(args, "kanban") {
+			rapidView := must(JiraClient.GetRapidViewID(ctx, board))
+			if rapidView == nil {
+				fmt.Printf("Board %s does not exist\n", board)
+				os.Exit(exitNotFound)
+			}
 
-		fmt.Println(format.KanbanBoardHeader(board))
-		if cmd.Flag("closed").Changed {
-			printIssues(issues, true, true)
-		} else {
-			printIssues(issues, true, false)
+			// The sprint boards are shown by get sprint
+			if AllBoards && rapidView.SprintSupportEnabled {
+				continue
+			}
+
+			issues := must(JiraClient.GetKanbanIssues(ctx, rapidView.ID, issueOrderBy("")))
+
+			if OutputFormat == "csv" {
+				all = append(all, issues...)
+
+				continue
+			}
+
+			header(format.KanbanBoardHeader(board))
+			printIssues(issues, true, cmd.Flag("closed").Changed)
+		}
+
+		if OutputFormat == "csv" {
+			printIssuesCSV(all, cmd.Flag("closed").Changed)
 		}
 	},
 }
@@ -429,17 +934,36 @@ func init() {
 	getCmd.AddCommand(getStatusCmd)
 	getCmd.AddCommand(getTransistionsCmd)
 	getCmd.AddCommand(getCommentsCmd)
+	getCmd.AddCommand(getAttachmentsCmd)
+	getCmd.AddCommand(getLinkTypesCmd)
+	getCmd.AddCommand(getWatchersCmd)
 	getCmd.AddCommand(getWorklogCmd)
 	getCmd.AddCommand(getMyWorklogCmd)
 	getCmd.AddCommand(getSprintCmd)
 	getCmd.AddCommand(getKanbanBoardCmd)
+	getCmd.AddCommand(getFiltersCmd)
+	getCmd.AddCommand(getEpicsCmd)
 
 	getAllIssuesCmd.Flags().StringVarP(&JQLFilter,
 		"filter", "f", "", "write your own jql filter")
+	getAllIssuesCmd.Flags().StringVarP(&SavedJiraFilter,
+		"jira-filter", "j", "", "run a filter saved in Jira by name or id")
+	getAllIssuesCmd.Flags().StringVarP(&IssueColumns,
+		"columns", "c", "", "comma separated list of fields to display")
+	getAllIssuesCmd.Flags().IntVarP(&IssueLimit,
+		"limit", "l", 0, "display at most this many issues")
+	getAllIssuesCmd.MarkFlagsMutuallyExclusive("filter", "jira-filter")
 
 	getAllIssuesCmd.SetUsageTemplate(getAllIssuesUsage)
 	getCommentsCmd.SetUsageTemplate(getCommentsUsage)
+	getAttachmentsCmd.SetUsageTemplate(getAttachmentsUsage)
+	getLinkTypesCmd.SetUsageTemplate(getLinkTypesUsage)
+	getWatchersCmd.SetUsageTemplate(getWatchersUsage)
 	getWorklogCmd.SetUsageTemplate(getWorklogUsage)
+	getWorklogCmd.Flags().StringVar(&OutputFile, "file", "", "write the csv output to the file")
+	getWorklogCmd.Flags().StringVarP(&WorklogSince, "since", "s", "", "only the work started since")
+	getWorklogCmd.Flags().StringVarP(&WorklogUntil, "until", "u", "", "only the work started until")
+	getWorklogCmd.Flags().StringVarP(&WorklogAuthor, "author", "a", "", "only the work logged by the user")
 
 	getActiveCmd.AddCommand(getActiveIssueCmd)
 	getActiveCmd.AddCommand(getActiveSprintCmd)
@@ -447,35 +971,83 @@ func init() {
 
 	getMyWorklogCmd.SetUsageTemplate(myWorklogUsage)
 	getMyWorklogCmd.Flags().BoolVarP(&ShowEntireWeek, "week", "w", false, "view current week (only with timesheet plugin)")
+	getMyWorklogCmd.Flags().StringVar(&OutputFile, "file", "", "write the csv output to the file")
 	getMyWorklogCmd.AddCommand(getMyWorklogStatistics)
 
 	getMyWorklogStatistics.SetUsageTemplate(myWorklogStatisticsUsage)
+	getMyWorklogStatistics.Flags().BoolVarP(&StatsTrend, "trend", "t", false, "show the rolling average and trend")
+	getMyWorklogStatistics.Flags().StringVar(&OutputFile, "file", "", "write the csv output to the file")
 
 	getSprintCmd.SetUsageTemplate(getSprintUsage)
 	getSprintCmd.Flags().BoolVarP(&GetAllSprints, "all", "a", false, "get all sprints")
+	getSprintCmd.Flags().BoolVar(&AllBoards, "all-boards", false, "show the sprints of all favourite boards")
+	getSprintCmd.Flags().BoolVarP(&SprintChanges, "changes", "c", false,
+		"show the scope changes after the sprint started")
+
+	getFiltersCmd.SetUsageTemplate(getFiltersUsage)
+
+	addTemplateFlag(getAllIssuesCmd, getWorklogCmd, getMyWorklogCmd, getSprintCmd)
+
+	getEpicsCmd.SetUsageTemplate(getEpicsUsage)
+	getEpicsCmd.Flags().StringVarP(&EpicBoard, "board", "b", "", "list the epics on the board")
 
 	getKanbanBoardCmd.SetUsageTemplate(getKanbanBoardUsage)
 	getKanbanBoardCmd.Flags().BoolP("closed", "c", false, "Show closed issues")
+	getKanbanBoardCmd.Flags().BoolVar(&AllBoards, "all-boards", false, "show all favourite kanban boards")
+
+	getAllIssuesCmd.Flags().StringVar(&IssueOrder, "order", "", "order the issues by priority or rank")
+	getKanbanBoardCmd.Flags().StringVar(&IssueOrder, "order", "", "order the issues by priority or rank")
+
 }
 
-func getStatus(key string) string {
-	jsonResponse := jira.GetIssues("key = " + key)
-	if len(jsonResponse) != 1 {
-		fmt.Printf("Issue %s does not exist\n", key)
-		os.Exit(1)
+// issueOrderBy returns the ordering selected with --order, or the default.
+func issueOrderBy(defaultOrder string) string {
+	switch IssueOrder {
+	case "":
+		return defaultOrder
+	case "priority":
+		return jira.OrderByPriority
+	case "rank":
+		return jira.OrderByRank
+	default:
+		fmt.Printf("Invalid order %s, must be either priority or rank\n", IssueOrder)
+		os.Exit(exitUsage)
 	}
 
-	return jsonResponse[0].Fields.Status.Name
+	return ""
 }
 
-func getSummary(key string) string {
-	issues := jira.GetIssues("key = " + key)
+// getIssueBrief returns the issue with only the summary, status
+// and issue type, and exits if the issue does not exist.
+func getIssueBrief(key string) types.Issue {
+	issues := must(JiraClient.GetIssuesSelecting(ctx, "key = "+key, jira.OrderByPriority, jira.BriefFields))
 	if len(issues) != 1 {
 		fmt.Printf("Issue %s does not exist\n", key)
-		os.Exit(1)
+		os.Exit(exitNotFound)
 	}
 
-	return issues[0].Fields.Summary
+	return issues[0]
+}
+
+func getStatus(key string) string {
+	return getIssueBrief(key).Fields.Status.Name
+}
+
+func getSavedFilter(nameOrID string) types.Filter {
+	if regexp.MustCompile(`^[0-9]+$`).MatchString(nameOrID) {
+		return must(JiraClient.GetFilter(ctx, nameOrID))
+	}
+
+	for _, f := range must(JiraClient.GetFavouriteFilters(ctx)) {
+		if strings.EqualFold(f.Name, nameOrID) {
+			return f
+		}
+	}
+
+	fmt.Printf("Found no favourite filter named %s\n", nameOrID)
+	os.Exit(exitNotFound)
+
+	return types.Filter{}
 }
 
 func getUserTimeOnIssueAtDate(user, date string, issues []types.Issue) []types.TimeSpentUserIssue {
@@ -501,7 +1073,7 @@ func getTimeSpentOnIssue(user, date string, key string) int {
 	// Returns the number of hours and minutes a user
 	// has logged on an issue on the given date as total
 	// number of seconds
-	wl := jira.GetWorklogs(key)
+	wl := must(JiraClient.GetWorklogs(ctx, key))
 
 	timeSpent := 0
 
@@ -534,29 +1106,268 @@ func getPriorityNameByID(priorities []types.Priority, id string) string {
 	return "Unknown"
 }
 
-func printIssues(issues []types.Issue, header bool, printClosed bool) {
-	if header {
-		fmt.Printf("%s%s\n%-15s%-12s%-10s%-64s%-20s%-15s%s\n", format.Color.Ul, format.Color.Yellow,
-			"Key", "Type", "Priority", "Summary", "Status", "Assignee", format.Color.Nocolor)
+// Max width of a column selected with --columns.
+const maxColumnWidth = 60
+
+// column is a field displayed in the issue list.
+type column struct {
+	Field  string
+	Header string
+}
+
+func parseColumns(spec string) []column {
+	columns := []column{}
+
+	for _, c := range strings.Split(spec, ",") {
+		field, header, found := strings.Cut(strings.TrimSpace(c), ":")
+		if field == "" {
+			continue
+		}
+
+		if !found || header == "" {
+			header = field
+		}
+
+		columns = append(columns, column{Field: field, Header: header})
 	}
 
-	for _, v := range issues {
-		if len(v.Fields.Summary) >= 60 {
-			v.Fields.Summary = v.Fields.Summary[:60] + ".."
+	return columns
+}
+
+// columnRows returns the values of the columns for all issues that are not closed.
+func columnRows(issues []types.RawIssue, columns []column) [][]string {
+	rows := [][]string{}
+
+	for _, issue := range issues {
+		status := convert.FieldToString(issue.Fields["status"])
+		if slices.Contains([]string{"Closed", "Resolved", "Verified"}, status) {
+			continue
+		}
+
+		row := []string{}
+
+		for _, c := range columns {
+			value := convert.FieldToString(issue.Fields[c.Field])
+			if strings.EqualFold(c.Field, "key") {
+				value = issue.Key
+			}
+
+			row = append(row, value)
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows
+}
+
+func printIssuesWithColumns(issues []types.RawIssue, columns []column) {
+	table := format.Table{}
+
+	for _, c := range columns {
+		col := format.Column{Header: c.Header, Max: maxColumnWidth, Shrink: len(c.Header)}
+		if Wide {
+			col.Max = 0
 		}
 
+		table.Columns = append(table.Columns, col)
+	}
+
+	for _, row := range columnRows(issues, columns) {
+		for i, value := range row {
+			row[i] = strings.ReplaceAll(value, "\n", " ")
+		}
+
+		table.AddRow(row...)
+	}
+
+	table.Render(os.Stdout, tableWidth())
+}
+
+func printIssues(issues []types.Issue, header bool, printClosed bool) {
+	table := format.Table{HideHeader: !header}
+
+	// Show the position on the board when ordered by rank
+	ranked := IssueOrder == "rank"
+	if ranked {
+		table.Columns = append(table.Columns, format.Column{Header: "#"})
+	}
+
+	table.Columns = append(table.Columns,
+		format.Column{Header: "Key"},
+		format.Column{Header: "Type", Color: format.IssueTypeColor},
+		format.Column{Header: "Priority", Color: format.PriorityColor},
+		summaryColumn(),
+		format.Column{Header: "Status", Color: format.StatusColor},
+		format.Column{Header: "Assignee", Shrink: minAssigneeLength})
+
+	for i, v := range issues {
 		if !printClosed && slices.Contains([]string{"Closed", "Resolved", "Verified"}, v.Fields.Status.Name) {
 			continue
 		}
 
-		fmt.Printf("%-15s%s%s%-64s%s%s\n",
-			v.Key,
-			format.IssueType(v.Fields.IssueType.Name, true),
-			format.Priority(v.Fields.Priority.Name, true),
-			v.Fields.Summary,
-			format.Status(v.Fields.Status.Name, false),
-			v.Fields.Assignee.DisplayName)
+		row := []string{v.Key, v.Fields.IssueType.Name, v.Fields.Priority.Name,
+			v.Fields.Summary, v.Fields.Status.Name, v.Fields.Assignee.DisplayName}
+
+		if ranked {
+			row = append([]string{strconv.Itoa(i + 1)}, row...)
+		}
+
+		table.AddRow(row...)
+	}
+
+	table.Render(os.Stdout, tableWidth())
+}
+
+// epicProgress is an epic with the number of issues in it.
+type epicProgress struct {
+	Epic   types.Issue
+	Issues int
+	Done   int
+}
+
+func (e epicProgress) Percent() int {
+	if e.Issues == 0 {
+		return 0
+	}
+
+	return e.Done * 100 / e.Issues
+}
+
+// getEpicProgress fetches the issues in the epics concurrently.
+func getEpicProgress(epics []types.Issue) []epicProgress {
+	progress := make([]epicProgress, len(epics))
+	sem := make(chan struct{}, describeConcurrency)
+
+	var wg sync.WaitGroup
+
+	for i, epic := range epics {
+		wg.Add(1)
+
+		go func(i int, epic types.Issue) {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			progress[i].Epic = epic
+
+			for _, issue := range must(JiraClient.GetIssuesInEpic(ctx, epic.Key)) {
+				progress[i].Issues++
+
+				if issue.IsDone() {
+					progress[i].Done++
+				}
+			}
+		}(i, epic)
+	}
+
+	wg.Wait()
+
+	return progress
+}
+
+func printEpics(progress []epicProgress) {
+	summaries := []*string{}
+	for i := range progress {
+		summaries = append(summaries, &progress[i].Epic.Fields.Summary)
+	}
+
+	width := truncateSummaries(summaryLength(53), summaries...)
+
+	fmt.Printf("%s%s\n%-15s%-*s%-20s%-8s%-10s%s\n", format.Color.Ul, format.Color.Yellow,
+		"Key", width, "Summary", "Status", "Issues", "Done %", format.Color.Nocolor)
+
+	for _, p := range progress {
+		fmt.Printf("%-15s%-*s%s%-8d%d\n",
+			p.Epic.Key, width, p.Epic.Fields.Summary, format.Status(p.Epic.Fields.Status.Name, false),
+			p.Issues, p.Percent())
+	}
+}
+
+func printFilters(filters []types.Filter) {
+	if len(filters) == 0 {
+		fmt.Println("You have no favourite filters")
+
+		return
+	}
+
+	fmt.Printf("%s%s\n%-10s%-40s%-25s%s%s\n", format.Color.Ul, format.Color.Yellow,
+		"ID", "Name", "Owner", "JQL", format.Color.Nocolor)
+
+	for _, f := range filters {
+		name := f.Name
+		if len(name) > 38 {
+			name = name[:38] + ".."
+		}
+
+		fmt.Printf("%-10s%-40s%-25s%s\n", f.ID, name, f.Owner.DisplayName, f.JQL)
+	}
+}
+
+func printAttachments(attachments []types.Attachment) {
+	if len(attachments) == 0 {
+		fmt.Println("The issue has no attachments")
+
+		return
+	}
+
+	table := format.Table{Columns: []format.Column{
+		{Header: "Name", Shrink: len("Name")},
+		{Header: "Size"},
+		{Header: "Author", Shrink: minAssigneeLength},
+		{Header: "Created"},
+	}}
+
+	for _, a := range attachments {
+		created := a.Created
+		if t, err := util.ParseJiraTime(a.Created); err == nil {
+			created = t.Format("2006-01-02 15:04")
+		}
+
+		table.AddRow(a.Filename, convert.BytesToHumanSize(a.Size), a.Author.DisplayName, created)
+	}
+
+	table.Render(os.Stdout, tableWidth())
+}
+
+func printWatchers(key string, watchers types.Watchers) {
+	if len(watchers.Watchers) == 0 {
+		fmt.Printf("Nobody is watching %s\n", key)
+
+		return
+	}
+
+	table := format.Table{Columns: []format.Column{
+		{Header: "Name", Shrink: minAssigneeLength},
+		{Header: "Username"},
+		{Header: "Email", Shrink: len("Email")},
+	}}
+
+	for _, w := range watchers.Watchers {
+		username := w.Name
+		if username == "" {
+			username = w.AccountID
+		}
+
+		table.AddRow(w.DisplayName, username, w.EmailAddress)
+	}
+
+	table.Render(os.Stdout, tableWidth())
+}
+
+func printLinkTypes(linkTypes []types.IssueLinkType) {
+	table := format.Table{Columns: []format.Column{
+		{Header: "Name"},
+		{Header: "Outward"},
+		{Header: "Inward"},
+	}}
+
+	for _, t := range linkTypes {
+		table.AddRow(t.Name, t.Outward, t.Inward)
 	}
+
+	table.Render(os.Stdout, tableWidth())
 }
 
 func printStatus(status string, hasBeenUpdated bool) {
@@ -577,6 +1388,22 @@ func printTransitions(transitions []types.Transition) {
 	}
 }
 
+// renderText returns the description or comment with the emoji shortcodes
+// as emoji, and the wiki markup rendered unless rawMarkup is set. On Jira
+// Cloud the text converted from the Atlassian Document Format is kept as is.
+func renderText(text string) string {
+	text = emoji.Replace(text)
+
+	switch {
+	case jira.IsCloud(Cfg):
+		return text
+	case Cfg.RawMarkup:
+		return strings.ReplaceAll(text, "{noformat}", "```")
+	}
+
+	return markup.Render(text)
+}
+
 func printComments(comments []types.Comment, maxNumber int) {
 	c := comments
 	if len(comments) >= maxNumber && maxNumber != 0 {
@@ -586,32 +1413,175 @@ func printComments(comments []types.Comment, maxNumber int) {
 	for _, v := range c {
 		fmt.Printf("%sComment:    %s%-45sCreated: %s\n", format.Color.Yellow, format.Color.Nocolor, v.ID, v.Created[:16])
 		fmt.Printf("Visibility: %-45sAuthor: %s (%s)\n", v.Visibility.Value, v.Author.DisplayName, v.Author.Name)
-		fmt.Printf("\n%s", strings.ReplaceAll(v.Body, "{noformat}", "```"))
+		fmt.Printf("\n%s", renderText(v.Body))
 		fmt.Println("\n" + format.Color.Ul + strings.Repeat(" ", 100) + format.Color.Nocolor)
 	}
 }
 
+// filterWorklogs returns the worklogs matching --since, --until and --author.
+func filterWorklogs(worklogs []types.Worklog, now time.Time) []types.Worklog {
+	var since, until time.Time
+
+	var err error
+
+	if WorklogSince != "" {
+		if since, err = convert.SinceToTime(WorklogSince, now); err != nil {
+			fmt.Printf("Invalid since %s - %s\n", WorklogSince, err.Error())
+			os.Exit(exitUsage)
+		}
+	}
+
+	if WorklogUntil != "" {
+		if until, err = convert.UntilToTime(WorklogUntil, now); err != nil {
+			fmt.Printf("Invalid until %s - %s\n", WorklogUntil, err.Error())
+			os.Exit(exitUsage)
+		}
+	}
+
+	author := WorklogAuthor
+	if strings.EqualFold(author, "me") {
+		author = Cfg.Username
+	}
+
+	filtered := []types.Worklog{}
+
+	for _, w := range worklogs {
+		if author != "" && !strings.EqualFold(w.Author.Name, author) &&
+			!strings.Contains(strings.ToLower(w.Author.DisplayName), strings.ToLower(author)) {
+			continue
+		}
+
+		if !since.IsZero() || !until.IsZero() {
+			started, err := util.ParseJiraTime(w.Started)
+			if err != nil || (!since.IsZero() && started.Before(since)) || (!until.IsZero() && !started.Before(until)) {
+				continue
+			}
+		}
+
+		filtered = append(filtered, w)
+	}
+
+	return filtered
+}
+
+// printFilteredWorklogs prints the worklogs left by the filters,
+// with the time spent on them instead of on the whole issue.
+func printFilteredWorklogs(worklogs []types.Worklog, total int) {
+	if len(worklogs) == 0 {
+		fmt.Printf("None of the %d worklogs match the filters\n", total)
+
+		return
+	}
+
+	printWorklogs("", worklogs)
+
+	spent := 0
+	for _, w := range worklogs {
+		spent += w.TimeSpentSeconds
+	}
+
+	fmt.Printf("%sTime spent:%s %s in %d of %d worklogs\n", format.Color.Green, format.Color.Nocolor,
+		convert.SecondsToHoursAndMinutes(spent, false), len(worklogs), total)
+}
+
 func printWorklogs(issueKey string, worklogs []types.Worklog) {
 	totalTimeSpent := 0
+	attributes := make([]string, len(worklogs))
+	width := 0
+
+	for i := range worklogs {
+		if attrs, ok := worklogs[i].Attributes(); ok {
+			attributes[i] = formatWorklogAttributes(attrs)
+			width = max(width, len(attributes[i])+2)
+		}
+	}
 
-	for _, v := range worklogs {
+	for i, v := range worklogs {
 		totalTimeSpent += v.TimeSpentSeconds
 
-		fmt.Printf("%s %s%-30s%sTime Spent: %s%-8s%s%s\n",
-			v.Started[:16],
+		fmt.Printf("%s %-10s%s%-30s%sTime Spent: %s%-8s%s%s%-*s%s%s\n",
+			v.Started[:16], "(#"+v.ID+")",
 			format.Color.Cyan, v.Author.DisplayName, format.Color.Nocolor,
-			format.Color.Yellow, v.TimeSpent, format.Color.Nocolor, v.Comment)
+			format.Color.Yellow, v.TimeSpent, format.Color.Nocolor,
+			format.Color.Magenta, width, attributes[i], format.Color.Nocolor, v.Comment)
 	}
 
 	if totalTimeSpent == 0 {
 		fmt.Println("No work has been logged on this issue")
 	} else {
-		printTimeTracking(issueKey)
+		if width > 0 {
+			printWorklogAttributeTotals(worklogs)
+		}
+
+		// The time tracking is for the whole issue, not a filtered worklog
+		if issueKey != "" {
+			printTimeTracking(issueKey)
+		}
+	}
+}
+
+func formatWorklogAttributes(attrs types.WorklogAttributes) string {
+	a := []string{}
+
+	if attrs.Billable {
+		a = append(a, "billable")
+	}
+
+	if attrs.Overtime {
+		a = append(a, "overtime")
+	}
+
+	if attrs.Account != "" {
+		a = append(a, attrs.Account)
+	}
+
+	return strings.Join(a, ", ")
+}
+
+// printWorklogAttributeTotals sums up the time spent
+// as billable, non-billable, overtime and per account.
+func printWorklogAttributeTotals(worklogs []types.Worklog) {
+	billable, nonBillable, overtime := 0, 0, 0
+	accounts := map[string]int{}
+
+	for _, w := range worklogs {
+		attrs, _ := w.Attributes()
+
+		if attrs.Billable {
+			billable += w.TimeSpentSeconds
+		} else {
+			nonBillable += w.TimeSpentSeconds
+		}
+
+		if attrs.Overtime {
+			overtime += w.TimeSpentSeconds
+		}
+
+		if attrs.Account != "" {
+			accounts[attrs.Account] += w.TimeSpentSeconds
+		}
+	}
+
+	fmt.Printf("%sBillable:%s %-9s%sNon-billable:%s %-9s%sOvertime:%s %s\n",
+		format.Color.Green, format.Color.Nocolor, convert.SecondsToHoursAndMinutes(billable, false),
+		format.Color.Blue, format.Color.Nocolor, convert.SecondsToHoursAndMinutes(nonBillable, false),
+		format.Color.Yellow, format.Color.Nocolor, convert.SecondsToHoursAndMinutes(overtime, false))
+
+	names := []string{}
+	for name := range accounts {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	for _, name := range names {
+		fmt.Printf("%sAccount %s:%s %s\n", format.Color.Magenta, name, format.Color.Nocolor,
+			convert.SecondsToHoursAndMinutes(accounts[name], false))
 	}
 }
 
 func printTimeTracking(key string) {
-	issue := jira.GetIssue(key)
+	issue := must(JiraClient.GetIssue(ctx, key))
 
 	colorRemaining := format.Color.Yellow
 	if issue.Fields.TimeTracking.Remaining == "0h" && issue.Fields.TimeTracking.Estimate != "" {
@@ -626,22 +1596,27 @@ func printTimeTracking(key string) {
 
 func printMyWorklog(ti []types.TimeSpentUserIssue) {
 	if len(ti) >= 1 {
-		fmt.Printf("%s%s\n%-12s%-15s%-64s%s%s\n", format.Color.Ul, format.Color.Yellow,
-			"Date", "Key", "Summary", "Time Spent", format.Color.Nocolor)
+		ti = slices.Clone(ti)
+		summaries := []*string{}
+
+		for i := range ti {
+			summaries = append(summaries, &ti[i].Summary)
+		}
+
+		width := truncateSummaries(summaryLength(37), summaries...)
+
+		fmt.Printf("%s%s\n%-12s%-15s%-*s%s%s\n", format.Color.Ul, format.Color.Yellow,
+			"Date", "Key", width, "Summary", "Time Spent", format.Color.Nocolor)
 
 		total := 0
 
 		for _, v := range ti {
-			if len(v.Summary) > 60 {
-				v.Summary = v.Summary[:60] + ".."
-			}
-
-			fmt.Printf("%-12s%-15s%-64s%s\n", v.Date, v.Key, v.Summary, v.TimeSpent)
+			fmt.Printf("%-12s%-15s%-*s%s\n", v.Date, v.Key, width, v.Summary, v.TimeSpent)
 			total += v.TimeSpentSeconds
 		}
 
 		fmt.Printf("%s%sTotal time spent:%s %s%s\n",
-			strings.Repeat(" ", 73), format.Color.Ul, format.Color.Nocolor,
+			strings.Repeat(" ", width+9), format.Color.Ul, format.Color.Nocolor,
 			convert.SecondsToHoursAndMinutes(total, false), format.Color.Nocolor)
 	} else {
 		fmt.Println("You have not logged any hours on this date")
@@ -650,18 +1625,31 @@ func printMyWorklog(ti []types.TimeSpentUserIssue) {
 
 func printTimesheet(worklogs []types.SimplifiedTimesheet) {
 	if len(worklogs) >= 1 {
-		fmt.Printf("%s%s\n%-11s%-7s%-15s%-44s%-33s%9s%s\n", format.Color.Ul, format.Color.Yellow,
-			"Date", "Time", "Key", "Summary", "Comment", "Time Spent", format.Color.Nocolor)
+		worklogs = slices.Clone(worklogs)
+		summaries := []*string{}
+
+		for i := range worklogs {
+			summaries = append(summaries, &worklogs[i].Summary)
+		}
+
+		width := truncateSummaries(summaryLength(75), summaries...)
+
+		fmt.Printf("%s%s\n%-11s%-7s%-15s%-*s%-33s%9s%s\n", format.Color.Ul, format.Color.Yellow,
+			"Date", "Time", "Key", width, "Summary", "Comment", "Time Spent", format.Color.Nocolor)
 
 		total := 0
 		for _, w := range worklogs {
+			if len(w.Comment) > 31 {
+				w.Comment = w.Comment[:31] + ".."
+			}
+
 			total += w.TimeSpent
-			fmt.Printf("%-18s%-15s%-44s%-33s%9s\n",
-				w.StartDate, w.Key, w.Summary, w.Comment, convert.SecondsToHoursAndMinutes(w.TimeSpent, false))
+			fmt.Printf("%-18s%-15s%-*s%-33s%9s\n",
+				w.StartDate, w.Key, width, w.Summary, w.Comment, convert.SecondsToHoursAndMinutes(w.TimeSpent, false))
 		}
 
 		fmt.Printf("%s%sTotal time spent: %11s%s\n",
-			strings.Repeat(" ", 90), format.Color.Ul,
+			strings.Repeat(" ", width+46), format.Color.Ul,
 			convert.SecondsToHoursAndMinutes(total, false), format.Color.Nocolor)
 	} else {
 		fmt.Println("You have not logged any hours on this date")
@@ -707,34 +1695,214 @@ func printStatisticsSummary(numWeeks int, weeksTotal float64) {
 	}
 }
 
+// sprintChange is an issue added to or removed from a sprint.
+type sprintChange struct {
+	Time    time.Time `json:"time"`
+	Change  string    `json:"change"`
+	Key     string    `json:"key"`
+	Summary string    `json:"summary"`
+	Author  string    `json:"author"`
+}
+
+// sprintJSON is a sprint with either its issues or its changes in the json output.
+type sprintJSON struct {
+	Board   string              `json:"board"`
+	Sprint  types.Sprint        `json:"sprint"`
+	Issues  []types.SprintIssue `json:"issues,omitempty"`
+	Changes []sprintChange      `json:"changes,omitempty"`
+}
+
+// getOpenSprints returns the active and future sprints of the board.
+func getOpenSprints(rapidView *types.RapidView) []types.Sprint {
+	stop := spin("Fetching the sprints of " + rapidView.Name)
+	sprints, err := JiraClient.GetOpenSprints(ctx, rapidView.ID)
+
+	stop()
+	exitOnError(err)
+
+	return sprints
+}
+
+// getSprintIssues returns the issues in the sprint, and sets
+// the issue ids of the sprint to them, in the sprint order.
+func getSprintIssues(rapidViewID int, sprint *types.Sprint) []types.SprintIssue {
+	stop := spin("Fetching the issues in " + sprint.Name)
+	issues, err := JiraClient.GetSprintIssues(ctx, rapidViewID, sprint.ID)
+
+	stop()
+	exitOnError(err)
+
+	sprint.IssuesIDs = []int{}
+	for _, i := range issues {
+		sprint.IssuesIDs = append(sprint.IssuesIDs, i.ID)
+	}
+
+	return issues
+}
+
+// issuesInSprint returns the issues in the sprint, in the sprint order.
+func issuesInSprint(sprint *types.Sprint, issues []types.SprintIssue) []types.SprintIssue {
+	inSprint := []types.SprintIssue{}
+
+	for _, id := range sprint.IssuesIDs {
+		for _, v := range issues {
+			if v.ID == id {
+				inSprint = append(inSprint, v)
+
+				break
+			}
+		}
+	}
+
+	return inSprint
+}
+
+// getSprintChanges returns the issues added to or removed
+// from the sprint after it was started, sorted by time.
+func getSprintChanges(rapidViewID int, sprint *types.Sprint) []sprintChange {
+	stop := spin("Fetching the sprint report of " + sprint.Name)
+	report, err := JiraClient.GetSprintReport(ctx, rapidViewID, sprint.ID)
+
+	stop()
+	exitOnError(err)
+
+	changes := []sprintChange{}
+
+	for key := range report.Contents.IssueKeysAddedDuringSprint {
+		changes = append(changes, getSprintChange(key, sprint.Name, true))
+	}
+
+	for _, i := range report.Contents.PuntedIssues {
+		changes = append(changes, getSprintChange(i.Key, sprint.Name, false))
+	}
+
+	slices.SortFunc(changes, func(a, b sprintChange) int {
+		return a.Time.Compare(b.Time)
+	})
+
+	return changes
+}
+
+func printSprintChanges(changes []sprintChange) {
+	if len(changes) == 0 {
+		fmt.Println("No issues added or removed after the sprint started")
+
+		return
+	}
+
+	changes = slices.Clone(changes)
+	summaries := []*string{}
+
+	for i := range changes {
+		summaries = append(summaries, &changes[i].Summary)
+	}
+
+	width := truncateSummaries(summaryLength(63), summaries...)
+
+	fmt.Printf("%s%s%-18s%-10s%-15s%-*s%-20s%s\n", format.Color.Ul, format.Color.Yellow,
+		"Time", "Change", "Key", width, "Summary", "By", format.Color.Nocolor)
+
+	for _, c := range changes {
+		t := "unknown"
+		if !c.Time.IsZero() {
+			t = c.Time.Local().Format("2006-01-02 15:04")
+		}
+
+		fmt.Printf("%-18s%-10s%-15s%-*s%-20s\n", t, c.Change, c.Key, width, c.Summary, c.Author)
+	}
+}
+
+// getSprintChange finds the latest change in the issue changelog that
+// added the issue to, or removed it from, the sprint.
+func getSprintChange(key, sprint string, added bool) sprintChange {
+	issue := must(JiraClient.GetIssueWithChangelog(ctx, key))
+	change := sprintChange{Key: key, Summary: issue.Fields.Summary, Change: "Removed"}
+
+	if added {
+		change.Change = "Added"
+	}
+
+	for _, h := range issue.Changelog.Histories {
+		for _, item := range h.Items {
+			if !strings.EqualFold(item.Field, "sprint") {
+				continue
+			}
+
+			inFrom := slices.Contains(splitSprintNames(item.FromString), sprint)
+			inTo := slices.Contains(splitSprintNames(item.ToString), sprint)
+
+			if (added && !inFrom && inTo) || (!added && inFrom && !inTo) {
+				t, err := util.ParseJiraTime(h.Created)
+				if err == nil && t.After(change.Time) {
+					change.Time = t
+					change.Author = h.Author.DisplayName
+				}
+			}
+		}
+	}
+
+	return change
+}
+
+func splitSprintNames(sprints string) []string {
+	names := []string{}
+	for _, s := range strings.Split(sprints, ",") {
+		names = append(names, strings.TrimSpace(s))
+	}
+
+	return names
+}
+
 func printSprintIssues(
 	sprint *types.Sprint, issues []types.SprintIssue, issueTypes []types.IssueType, priorites []types.Priority,
 ) {
-	if len(issues) > 0 {
-		fmt.Printf("%s%s\n%-15s%-12s%-10s%-64s%-10s%-10s%-6s%-20s%s\n", format.Color.Ul, format.Color.Yellow,
-			"Key", "Type", "Priority", "Summary", "Est.", "Epic", "Done", "Assignee", format.Color.Nocolor)
-
-		for _, i := range sprint.IssuesIDs {
-			for _, v := range issues {
-				if v.ID == i {
-					if len(v.Summary) >= 60 {
-						v.Summary = v.Summary[:60] + ".."
-					}
-
-					fmt.Printf("%-15s%s%s%-64s%-10s%-10s%-15s%-20s\n",
-						v.Key,
-						format.IssueType(getIssueTypeNameByID(issueTypes, v.TypeID), true),
-						format.Priority(getPriorityNameByID(priorites, v.PriorityID), true),
-						v.Summary,
-						convert.SecondsToHoursAndMinutes(int(v.EstimateStatistic.StatFieldValue.Value), true),
-						v.Epic,
-						format.SprintStatus(v.Done),
-						v.AssigneeName,
-					)
-
-					break
+	if len(issues) == 0 {
+		return
+	}
+
+	table := format.Table{Columns: []format.Column{
+		{Header: "Key"},
+		{Header: "Type", Color: format.IssueTypeColor},
+		{Header: "Priority", Color: format.PriorityColor},
+		summaryColumn(),
+		{Header: "Est."},
+		{Header: "Epic", Shrink: len("Epic")},
+		{Header: "Done", Color: doneColor},
+		{Header: "Assignee", Shrink: minAssigneeLength},
+	}}
+
+	for _, i := range sprint.IssuesIDs {
+		for _, v := range issues {
+			if v.ID == i {
+				done := "No"
+				if v.Done {
+					done = "Yes"
 				}
+
+				table.AddRow(
+					v.Key,
+					getIssueTypeNameByID(issueTypes, v.TypeID),
+					getPriorityNameByID(priorites, v.PriorityID),
+					v.Summary,
+					convert.SecondsToHoursAndMinutes(int(v.EstimateStatistic.StatFieldValue.Value), true),
+					v.Epic,
+					done,
+					v.AssigneeName,
+				)
+
+				break
 			}
 		}
 	}
+
+	table.Render(os.Stdout, tableWidth())
+}
+
+// doneColor returns the color of the Done column in the sprint table.
+func doneColor(done string) string {
+	if done == "Yes" {
+		return format.Color.Green
+	}
+
+	return format.Color.Blue
 }
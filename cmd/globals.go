@@ -23,7 +23,9 @@ package cmd
 
 import (
 	"path"
+	"time"
 
+	"github.com/mhersson/gojira/pkg/jira"
 	"github.com/mhersson/gojira/pkg/types"
 )
 
@@ -36,20 +38,53 @@ var (
 )
 
 var (
-	IssueKey       string
-	WorkDate       string // Used by `add work` to specify date
-	WorkTime       string // Used by `add work` to specify at what time the work was done
-	WorkComment    string // Used by `add work` to add a custom comment to the log
-	JQLFilter      string // Used by `get all` to create customer queries
-	Assignee       string // Used by `update assignee`
-	VersionFlag    bool
-	ShowEntireWeek = false // Used by `get myworklog`
-	MergeToday     = false // Used by `edit myworklog`
-	AdoptUser      string  // Used by `edit myworklog`
-	ConfigFolder   = path.Join(getHomeFolder(), ".config/gojira")
-	IssueFile      = path.Join(ConfigFolder, "issue")
-	IssueTypeFile  = path.Join(ConfigFolder, "issuetype")
-	BoardFile      = path.Join(ConfigFolder, "board")
+	IssueKey        string
+	WorkDate        string // Used by `add work` to specify date
+	WorkTime        string // Used by `add work` to specify at what time the work was done
+	WorkComment     string // Used by `add work` to add a custom comment to the log
+	WorkBillable    bool   // Used by `add work` to mark the work as billable
+	WorkAccount     string // Used by `add work` to book the work on an account
+	WorkOvertime    bool   // Used by `add work` to mark the work as overtime
+	JQLFilter       string // Used by `get all` to create customer queries
+	CommentTemplate string // Used by `add comment`
+	OutputFormat    string // Used by all commands to select output format, if supported
+	OutputFile      string // Used by the worklog views to write the csv output to a file
+	OutputTemplate  string // Used by the commands supporting json to render the output with a go template
+	Assignee        string // Used by `update assignee`
+	VersionFlag     bool
+	ReadOnlyFlag    bool          // Used by all commands to block changes in Jira
+	AssumeYes       bool          // Used by all commands to skip the confirmations
+	FullSummary     bool          // Used by all tables to display the full summary
+	Wide            bool          // Used by all tables to display all columns in full
+	TruncateSummary int           // Used by all tables to set the summary length
+	RecordFile      string        // Used by all commands to record the traffic to Jira
+	ReplayFile      string        // Used by all commands to replay the traffic recorded with --record
+	Debug           string        // Used by all commands to log the requests to Jira
+	NoColor         bool          // Used by all commands to print without colors
+	Quiet           bool          // Used by all commands to skip the decorative output
+	NoPager         bool          // Used by `describe` and `get sprint` to print the output as is
+	RequestTimeout  time.Duration // Used by all commands to limit the time waiting for Jira
+	ShowEntireWeek  = false       // Used by `get myworklog`
+	MergeToday      = false       // Used by `edit myworklog`
+	AdoptUser       string        // Used by `edit myworklog`
+	ConfigPath      string        // Used by all commands to read another config file
+	ConfigFolder    = configFolder()
+	StateFolder     = stateFolder(ConfigFolder)
+	IssueFile       = path.Join(StateFolder, "issue")
+	IssueTypeFile   = path.Join(StateFolder, "issuetype")
+	BoardFile       = path.Join(StateFolder, "board")
+	BoardsFile      = path.Join(StateFolder, "boards")
+	TimerFile       = path.Join(StateFolder, "timer")
+	BudgetFile      = path.Join(StateFolder, "budgets.json")
+	TemplateFolder  = path.Join(ConfigFolder, "templates")
+	CacheFolder     = path.Join(StateFolder, "cache")
+	IssueCacheFile  = path.Join(CacheFolder, "issues.json")
+	ExistsCacheFile = path.Join(CacheFolder, "exists.json")
+	NoTimesheetFile = path.Join(CacheFolder, "no-timesheet-plugin")
+	SnapshotFolder  = path.Join(StateFolder, "snapshots")
 )
 
 var Cfg types.Config
+
+// JiraClient is configured from Cfg when the config is read.
+var JiraClient = jira.NewClient(types.Config{})
@@ -0,0 +1,147 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando/go-keyring"
+	"gopkg.in/yaml.v3"
+
+	"github.com/mhersson/gojira/pkg/jira"
+	"github.com/mhersson/gojira/pkg/jira/jiratest"
+	"github.com/mhersson/gojira/pkg/types"
+	"github.com/mhersson/gojira/pkg/util/format"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+// golden runs gojira with the arguments against the in-memory Jira,
+// and compares what was printed to stdout with testdata/NAME.golden.
+// Run the tests with -update to write the golden files, and review
+// the changes to them like any other change.
+func golden(t *testing.T, server *jiratest.Server, name string, args ...string) {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	cfg := server.Config()
+
+	// The keyring does not print a warning like a plain password
+	keyring.MockInit()
+	assert.NoError(t, keyring.Set(types.KeyringService, "jiratest", cfg.Password))
+
+	config, err := yaml.Marshal(map[string]string{
+		"JiraURL":      cfg.JiraURL,
+		"username":     cfg.Username,
+		"password":     "jiratest",
+		"passwordtype": "keyring",
+		"deployment":   cfg.Deployment,
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), config, 0o600))
+
+	viper.SetConfigFile(filepath.Join(dir, "config.yaml"))
+
+	// Keep the files of the user out of the test
+	IssueFile = filepath.Join(dir, "issue")
+	BoardFile = filepath.Join(dir, "board")
+	BoardsFile = filepath.Join(dir, "boards")
+	ExistsCacheFile = filepath.Join(dir, "exists.json")
+	IssueCacheFile = filepath.Join(dir, "issues.json")
+	ContextFile = filepath.Join(dir, "context")
+
+	JiraClient.SetTransport(jira.NewTransport())
+
+	color := format.Color
+	format.Color = types.Color{}
+
+	defer func() { format.Color = color }()
+
+	out := run(t, args...)
+	out = strings.ReplaceAll(out, server.URL, "https://jira.example.com")
+
+	file := filepath.Join("testdata", name+".golden")
+
+	if *update {
+		assert.NoError(t, os.WriteFile(file, []byte(out), 0o600))
+	}
+
+	want, err := os.ReadFile(file)
+	assert.NoError(t, err)
+	assert.Equal(t, string(want), out)
+}
+
+// newJira returns an in-memory Jira with an issue with comments and
+// worklogs, and a board with an active sprint.
+func newJira(t *testing.T) *jiratest.Server {
+	t.Helper()
+
+	server := jiratest.NewServer()
+	t.Cleanup(server.Close)
+
+	issue := jiratest.NewIssue("OSE-1", "Fix the flux capacitor", "In Progress")
+	issue.Fields["timetracking"] = map[string]any{"originalEstimate": "1d", "remainingEstimate": "4h", "timeSpent": "4h"}
+	server.AddIssue(issue)
+	server.AddIssue(jiratest.NewIssue("OSE-2", "Charge to 1.21 gigawatts", "To Do"))
+	server.AddIssue(jiratest.NewIssue("OSE-3", "Find plutonium", "Done"))
+
+	comment := types.Comment{Body: "It works if you hit it", Created: "2024-03-04T10:00:00.000+0000"}
+	comment.Author.Name = "doc"
+	comment.Author.DisplayName = "Emmett Brown"
+	server.AddComment("OSE-1", comment)
+
+	for _, started := range []string{"2024-03-04T09:00:00.000+0000", "2024-03-05T13:30:00.000+0000"} {
+		worklog := types.Worklog{Started: started, TimeSpent: "2h", TimeSpentSeconds: 7200, Comment: "Soldering"}
+		worklog.Author.Name = jiratest.Username
+		worklog.Author.DisplayName = "Bob"
+		server.AddWorklog("OSE-1", worklog)
+	}
+
+	server.AddBoard(jiratest.Board{ID: 7, Name: "Team", Sprints: []jiratest.Sprint{
+		{ID: 2, Name: "Sprint 2", State: "active", Issues: []string{"OSE-1", "OSE-2", "OSE-3"}},
+	}})
+
+	return server
+}
+
+func TestGoldenGetWorklog(t *testing.T) {
+	golden(t, newJira(t), "get-worklog", "get", "worklog", "OSE-1")
+}
+
+func TestGoldenGetComments(t *testing.T) {
+	golden(t, newJira(t), "get-comments", "get", "comments", "OSE-1")
+}
+
+func TestGoldenGetSprint(t *testing.T) {
+	golden(t, newJira(t), "get-sprint", "get", "sprint", "Team")
+}
+
+func TestGoldenGetAll(t *testing.T) {
+	golden(t, newJira(t), "get-all", "get", "all")
+}
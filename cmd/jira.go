@@ -0,0 +1,251 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mhersson/gojira/pkg/jira"
+	"github.com/mhersson/gojira/pkg/util/validate"
+)
+
+// interruptGrace is the time given to the requests to return after
+// Ctrl-C, before exiting anyway, e.g. while waiting for input.
+const interruptGrace = 2 * time.Second
+
+// ctx is passed on with every request to Jira, and is cancelled on Ctrl-C.
+var ctx = context.Background()
+
+// cancelOnInterrupt returns a context cancelled on the first Ctrl-C.
+// A second Ctrl-C exits immediately.
+func cancelOnInterrupt(parent context.Context) (context.Context, context.CancelFunc) {
+	c, cancel := context.WithCancel(parent)
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt)
+
+	go func() {
+		select {
+		case <-sig:
+		case <-c.Done():
+			signal.Stop(sig)
+
+			return
+		}
+
+		cancel()
+		signal.Stop(sig)
+
+		time.Sleep(interruptGrace)
+		fail(jsonError{Code: "cancelled", Message: "Cancelled"}, exitCancelled)
+	}()
+
+	return c, cancel
+}
+
+// Exit codes, so scripts can tell the causes of failures apart.
+const (
+	exitFailure   = 1 // anything else that failed
+	exitUsage     = 2 // invalid arguments, flags or input
+	exitNotFound  = 3 // the issue, board, user or whatever was asked for does not exist
+	exitAuth      = 4 // Jira did not accept the credentials, or denied the access
+	exitNetwork   = 5 // Jira could not be reached, or did not answer in time
+	exitCancelled = 6 // cancelled with Ctrl-C or by answering no
+)
+
+// jsonError is a failure as printed on stderr with --output json, so
+// scripts can tell e.g. failed authentication from a missing issue.
+type jsonError struct {
+	Code       string `json:"code"`
+	Message    string `json:"message"`
+	HTTPStatus int    `json:"httpStatus,omitempty"`
+	Endpoint   string `json:"endpoint,omitempty"`
+}
+
+// httpErrorCodes are the codes of the failures for the http status from Jira.
+var httpErrorCodes = map[int]string{
+	http.StatusBadRequest:      "bad_request",
+	http.StatusUnauthorized:    "unauthorized",
+	http.StatusForbidden:       "forbidden",
+	http.StatusNotFound:        "not_found",
+	http.StatusConflict:        "conflict",
+	http.StatusTooManyRequests: "rate_limited",
+}
+
+// exitOnError prints the error from Jira and exits, if there is one.
+func exitOnError(err error) {
+	if err == nil {
+		return
+	}
+
+	e := jsonError{Code: "error", Message: "Error: " + err.Error()}
+
+	var apiErr *jira.APIError
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		fail(jsonError{Code: "cancelled", Message: "Cancelled"}, exitCancelled)
+	case errors.Is(err, context.DeadlineExceeded):
+		e = jsonError{Code: "timeout", Message: fmt.Sprintf("Error: no response from Jira within %s", RequestTimeout)}
+	case errors.Is(err, jira.ErrNoTimesheetPlugin):
+		e.Code = "no_timesheet_plugin"
+	case errors.Is(err, jira.ErrNoReactions):
+		e.Code = "no_reactions"
+	}
+
+	if errors.As(err, &apiErr) {
+		e.HTTPStatus = apiErr.StatusCode
+		e.Endpoint = apiErr.Endpoint
+
+		if e.Code == "error" {
+			e.Code = httpErrorCode(apiErr.StatusCode)
+		}
+	}
+
+	fail(e, exitCode(err))
+}
+
+// exitCode returns the exit code for the cause of the error,
+// exitFailure for errors not from talking to Jira.
+func exitCode(err error) int {
+	var apiErr *jira.APIError
+
+	var urlErr *url.Error
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return exitCancelled
+	case errors.Is(err, context.DeadlineExceeded):
+		return exitNetwork
+	case errors.Is(err, jira.ErrNoTimesheetPlugin), errors.Is(err, jira.ErrNoReactions):
+		return exitFailure
+	case errors.As(err, &apiErr):
+		switch apiErr.StatusCode {
+		case http.StatusUnauthorized, http.StatusForbidden:
+			return exitAuth
+		case http.StatusNotFound:
+			return exitNotFound
+		}
+
+		return exitFailure
+	case errors.As(err, &urlErr):
+		return exitNetwork
+	}
+
+	return exitFailure
+}
+
+func httpErrorCode(status int) string {
+	if code, ok := httpErrorCodes[status]; ok {
+		return code
+	}
+
+	if status >= http.StatusInternalServerError {
+		return "server_error"
+	}
+
+	return "http_error"
+}
+
+// fail prints the failure and exits with the exit code. The message
+// is printed as it is, or as json on stderr with --output json.
+func fail(e jsonError, code int) {
+	stopPager()
+
+	if OutputFormat != "json" {
+		fmt.Println(e.Message)
+		os.Exit(code)
+	}
+
+	e.Message = strings.TrimPrefix(e.Message, "Error: ")
+
+	out, _ := json.Marshal(e)
+	fmt.Fprintln(os.Stderr, string(out))
+	os.Exit(code)
+}
+
+// must returns the result from Jira, or exits on error.
+func must[T any](result T, err error) T {
+	exitOnError(err)
+
+	return result
+}
+
+// checkIssueKey exits if the key is invalid or the issue does not exist,
+// or sets the key to the active issue if it is empty. The active issue
+// was checked when it was set, so it is only checked if it is stale.
+func checkIssueKey(key *string, issueFile string) {
+	if *key == "" {
+		*key = activeIssue(issueFile)
+
+		return
+	}
+
+	if !validate.IssueKey(key) {
+		fail(jsonError{Code: "invalid_key", Message: "Invalid key"}, exitUsage)
+	}
+
+	if !must(JiraClient.IssueExists(ctx, key)) {
+		fail(jsonError{Code: "issue_not_found", Message: *key + " does not exist"}, exitNotFound)
+	}
+}
+
+// checkIssueKeys checks the keys concurrently like checkIssueKey.
+func checkIssueKeys(issueFile string, keys ...*string) {
+	var wg sync.WaitGroup
+
+	for _, key := range keys {
+		if *key == "" {
+			*key = activeIssue(issueFile)
+
+			continue
+		}
+
+		if !validate.IssueKey(key) {
+			fmt.Printf("Invalid key %s\n", *key)
+			os.Exit(exitUsage)
+		}
+
+		wg.Add(1)
+
+		go func(key *string) {
+			defer wg.Done()
+
+			if !must(JiraClient.IssueExists(ctx, key)) {
+				fmt.Printf("%s does not exist\n", *key)
+				os.Exit(exitNotFound)
+			}
+		}(key)
+	}
+
+	wg.Wait()
+}
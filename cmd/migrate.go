@@ -0,0 +1,326 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mhersson/gojira/pkg/jira"
+	"github.com/mhersson/gojira/pkg/types"
+	"github.com/mhersson/gojira/pkg/util/format"
+	"github.com/mhersson/gojira/pkg/util/validate"
+)
+
+const migrateUsage string = `Migrate the issues matching the filter from one Jira server to another.
+The issues are recreated with their summary, description, type, priority,
+labels, due date, comments, worklogs and attachments, and a report maps the
+old keys to the new ones.
+
+The servers are profiles in the config, where default is the main config,
+and the active context is used if a profile is not given. The comments and worklogs are added by you, so the
+original author and time are noted in the text.
+
+Usage:
+  gojira migrate --filter <JQL> [flags]
+
+Flags:
+  -f, --filter [JQL]           the issues to migrate
+      --from-profile [NAME]    the profile of the server to migrate from
+  -h, --help                   help for migrate
+  -p, --project [KEY]          the project to migrate to (default same key)
+      --to-profile [NAME]      the profile of the server to migrate to
+
+Example:
+  gojira migrate --from-profile old --to-profile new --filter "project = OSE"
+`
+
+// Used by `migrate`.
+var (
+	MigrateFromProfile string
+	MigrateToProfile   string
+	MigrateProject     string
+)
+
+// migration is the result of migrating one issue.
+type migration struct {
+	From     string   `json:"from"`
+	To       string   `json:"to,omitempty"`
+	Summary  string   `json:"summary"`
+	Problems []string `json:"problems,omitempty"`
+}
+
+// migrationTarget is what the issues are created with on the new server.
+type migrationTarget struct {
+	projects   []types.Project
+	issueTypes map[string][]types.IssueType
+	priorities []types.Priority
+}
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Migrate issues to another Jira server",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		exitIfReadOnly()
+		checkOutputFormat("csv", "json")
+
+		if strings.EqualFold(MigrateFromProfile, MigrateToProfile) {
+			fmt.Println("The issues must be migrated to another profile")
+			os.Exit(exitUsage)
+		}
+
+		from := profileClient(MigrateFromProfile)
+		to := profileClient(MigrateToProfile)
+
+		stop := spin("Fetching the issues")
+		issues, err := from.GetExportedIssues(ctx, JQLFilter)
+
+		stop()
+		exitOnError(err)
+
+		if len(issues) == 0 {
+			fmt.Println("No issues match the filter")
+
+			return
+		}
+
+		if !confirm(Cfg.Confirm.Bulk, fmt.Sprintf("Migrate %d issues", len(issues))) {
+			os.Exit(exitCancelled)
+		}
+
+		target := &migrationTarget{
+			projects:   must(to.GetValidProjects(ctx)),
+			issueTypes: map[string][]types.IssueType{},
+			priorities: must(to.GetPriorities(ctx)),
+		}
+
+		migrations := []migration{}
+		failed := 0
+
+		for n, issue := range issues {
+			stop := spin(fmt.Sprintf("Migrating %s (%d of %d)", issue.Key, n+1, len(issues)))
+			m := migrateIssue(from, to, target, issue)
+
+			stop()
+
+			if m.To == "" {
+				failed++
+			}
+
+			migrations = append(migrations, m)
+		}
+
+		switch OutputFormat {
+		case "csv":
+			printMigrationsCSV(migrations)
+		case "json":
+			printJSON(migrations)
+		default:
+			printMigrations(migrations)
+		}
+
+		if failed > 0 {
+			fmt.Fprintf(os.Stderr, "Migrated %d of %d issues\n", len(issues)-failed, len(issues))
+			os.Exit(exitFailure)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+
+	migrateCmd.SetUsageTemplate(migrateUsage)
+	migrateCmd.Flags().StringVarP(&JQLFilter, "filter", "f", "", "the issues to migrate")
+	migrateCmd.Flags().StringVar(&MigrateFromProfile, "from-profile", "", "the profile of the server to migrate from")
+	migrateCmd.Flags().StringVar(&MigrateToProfile, "to-profile", "", "the profile of the server to migrate to")
+	migrateCmd.Flags().StringVarP(&MigrateProject, "project", "p", "", "the project to migrate to")
+	_ = migrateCmd.MarkFlagRequired("filter")
+}
+
+// migrateIssue creates a copy of the issue on the new server, and copies
+// its comments, worklogs and attachments. The issue is not created if the
+// project or issue type is missing, the rest is noted as problems.
+func migrateIssue(from, to *jira.Client, target *migrationTarget, issue types.ExportedIssue) migration {
+	m := migration{From: issue.Key, Summary: issue.Fields.Summary}
+
+	key := strings.ToUpper(MigrateProject)
+	if key == "" {
+		key = issue.Fields.Project.Key
+	}
+
+	project := validate.ProjectKey(key, target.projects)
+	if project.ID == "" {
+		m.Problems = append(m.Problems, "project "+key+" does not exist")
+
+		return m
+	}
+
+	if _, ok := target.issueTypes[key]; !ok {
+		issueTypes, err := to.GetProjectIssueTypes(ctx, key)
+		if err != nil {
+			m.Problems = append(m.Problems, "failed to get the issue types of "+key+" - "+err.Error())
+
+			return m
+		}
+
+		target.issueTypes[key] = issueTypes
+	}
+
+	issueTypeID := ""
+
+	for _, t := range target.issueTypes[key] {
+		if strings.EqualFold(t.Name, issue.Fields.IssueType.Name) {
+			issueTypeID = t.ID
+		}
+	}
+
+	if issueTypeID == "" {
+		m.Problems = append(m.Problems, "issue type "+issue.Fields.IssueType.Name+" does not exist in "+key)
+
+		return m
+	}
+
+	priorityID := ""
+
+	for _, p := range target.priorities {
+		if strings.EqualFold(p.Name, issue.Fields.Priority.Name) {
+			priorityID = p.ID
+		}
+	}
+
+	if priorityID == "" && issue.Fields.Priority.Name != "" {
+		m.Problems = append(m.Problems, "priority "+issue.Fields.Priority.Name+" does not exist, using the default")
+	}
+
+	newKey, err := to.CreateIssueCopy(ctx, project.ID, issueTypeID, priorityID, issue)
+	if err != nil {
+		m.Problems = append(m.Problems, "failed to create the issue - "+err.Error())
+
+		return m
+	}
+
+	m.To = newKey
+	m.Problems = append(m.Problems, migrateComments(from, to, issue.Key, newKey)...)
+	m.Problems = append(m.Problems, migrateWorklogs(from, to, issue.Key, newKey)...)
+	m.Problems = append(m.Problems, migrateAttachments(from, to, issue, newKey)...)
+
+	return m
+}
+
+func migrateComments(from, to *jira.Client, key, newKey string) []string {
+	comments, err := from.GetComments(ctx, key)
+	if err != nil {
+		return []string{"failed to get the comments - " + err.Error()}
+	}
+
+	failed := 0
+
+	for _, c := range comments {
+		body := fmt.Sprintf("Comment by %s on %s:\n\n%s", c.Author.DisplayName, c.Created, c.Body)
+
+		if err := to.AddComment(ctx, newKey, []byte(body)); err != nil {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return []string{fmt.Sprintf("failed to add %d of %d comments", failed, len(comments))}
+	}
+
+	return nil
+}
+
+func migrateWorklogs(from, to *jira.Client, key, newKey string) []string {
+	worklogs, err := from.GetWorklogs(ctx, key)
+	if err != nil {
+		return []string{"failed to get the worklogs - " + err.Error()}
+	}
+
+	failed := 0
+
+	for _, w := range worklogs {
+		note := "Logged by " + w.Author.DisplayName
+		if w.Comment != "" {
+			note += ": " + w.Comment
+		}
+
+		w.Comment = note
+
+		if err := to.CopyWorklog(ctx, newKey, w); err != nil {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return []string{fmt.Sprintf("failed to add %d of %d worklogs", failed, len(worklogs))}
+	}
+
+	return nil
+}
+
+func migrateAttachments(from, to *jira.Client, issue types.ExportedIssue, newKey string) []string {
+	problems := []string{}
+
+	for _, a := range issue.Fields.Attachments {
+		content, err := from.GetAttachmentContent(ctx, a)
+		if err == nil {
+			err = to.AddAttachmentContent(ctx, newKey, a.Filename, content)
+		}
+
+		if err != nil {
+			problems = append(problems, "failed to copy attachment "+a.Filename+" - "+err.Error())
+		}
+	}
+
+	return problems
+}
+
+func printMigrations(migrations []migration) {
+	summaries := []*string{}
+	for i := range migrations {
+		summaries = append(summaries, &migrations[i].Summary)
+	}
+
+	width := truncateSummaries(summaryLength(30), summaries...)
+
+	fmt.Printf("%s%s%-15s%-15s%-*s%s\n", format.Color.Ul, format.Color.Yellow,
+		"From", "To", width, "Summary", format.Color.Nocolor)
+
+	for _, m := range migrations {
+		to := m.To
+		if to == "" {
+			to = format.Color.Red + fmt.Sprintf("%-15s", "Failed") + format.Color.Nocolor
+		} else {
+			to = fmt.Sprintf("%-15s", to)
+		}
+
+		fmt.Printf("%-15s%s%-*s\n", m.From, to, width, m.Summary)
+
+		for _, p := range m.Problems {
+			fmt.Printf("%s%30s%s%s\n", format.Color.Red, "", p, format.Color.Nocolor)
+		}
+	}
+}
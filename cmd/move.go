@@ -0,0 +1,146 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mhersson/gojira/pkg/types"
+)
+
+const moveWorklogUsage string = `This command moves a worklog entry from one issue to another.
+The entry is copied to the target issue, keeping the start time,
+time spent and comment, before the original entry is deleted.
+
+By default the worklog is moved from the active issue, but this can be
+changed by adding the issue key as argument. When this is the case the
+argument order is important, and the issue key must always come first.
+
+The worklog id can be found by running "get worklog".
+
+Usage:
+  gojira move worklog [ISSUE KEY] <WORKLOG ID> --to <ISSUE KEY> [flags]
+
+Aliases:
+  worklog, w
+
+Flags:
+  -h, --help                   help for worklog
+  -t, --to                     the issue key to move the worklog to
+
+Example:
+  # Move worklog 123456 from GOJIRA-1 to GOJIRA-2
+  gojira move worklog GOJIRA-1 123456 --to GOJIRA-2
+`
+
+var MoveToIssueKey string // Used by `move worklog`
+
+var moveCmd = &cobra.Command{
+	Use:     "move",
+	Short:   "Move a worklog to another issue",
+	Args:    cobra.NoArgs,
+	Aliases: []string{"mv"},
+}
+
+var moveWorklogCmd = &cobra.Command{
+	Use:     "worklog",
+	Short:   "Move a worklog to another issue",
+	Aliases: []string{"w"},
+	Args:    cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		exitIfReadOnly()
+
+		worklogID := args[0]
+		if len(args) == 2 {
+			IssueKey = strings.ToUpper(args[0])
+			worklogID = args[1]
+		}
+
+		if !regexp.MustCompile(`^[0-9]+$`).MatchString(worklogID) {
+			fmt.Println("Invalid worklog id")
+			os.Exit(exitUsage)
+		}
+
+		MoveToIssueKey = strings.ToUpper(MoveToIssueKey)
+		checkIssueKeys(IssueFile, &IssueKey, &MoveToIssueKey)
+
+		if IssueKey == MoveToIssueKey {
+			fmt.Println("The worklog is already on " + IssueKey)
+			os.Exit(exitUsage)
+		}
+
+		checkPermission(MoveToIssueKey, "log work", permWorkOnIssues)
+
+		worklog := getWorklog(IssueKey, worklogID)
+		if worklog.ID == "" {
+			fmt.Printf("Worklog %s does not exist on %s\n", worklogID, IssueKey)
+			os.Exit(exitNotFound)
+		}
+
+		if !confirm(Cfg.Confirm.Deletes, fmt.Sprintf("Delete worklog %s (%s) from %s after copying it to %s",
+			worklog.ID, worklog.TimeSpent, IssueKey, MoveToIssueKey)) {
+			os.Exit(exitCancelled)
+		}
+
+		err := JiraClient.CopyWorklog(ctx, MoveToIssueKey, worklog)
+		if err != nil {
+			fmt.Printf("Failed to add worklog to %s - %s\n", MoveToIssueKey, err.Error())
+			os.Exit(exitCode(err))
+		}
+
+		err = JiraClient.DeleteWorklog(ctx, IssueKey, worklog.ID)
+		if err != nil {
+			fmt.Printf("Worklog was added to %s, but failed to delete the original from %s - %s\n",
+				MoveToIssueKey, IssueKey, err.Error())
+			os.Exit(exitCode(err))
+		}
+
+		success("Successfully moved worklog %s (%s) from %s to %s", worklog.ID, worklog.TimeSpent, IssueKey, MoveToIssueKey)
+		checkBudget(MoveToIssueKey)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(moveCmd)
+
+	moveCmd.AddCommand(moveWorklogCmd)
+
+	moveWorklogCmd.SetUsageTemplate(moveWorklogUsage)
+	moveWorklogCmd.Flags().StringVarP(&MoveToIssueKey, "to", "t", "", "the issue key to move the worklog to")
+	_ = moveWorklogCmd.MarkFlagRequired("to")
+}
+
+func getWorklog(key, worklogID string) types.Worklog {
+	for _, w := range must(JiraClient.GetWorklogs(ctx, key)) {
+		if w.ID == worklogID {
+			return w
+		}
+	}
+
+	return types.Worklog{}
+}
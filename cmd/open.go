@@ -36,10 +36,14 @@ const openUsage string = `This command will open the issue in your default brows
 
 Usage:
   gojira open [ISSUE KEY] [flags]
+  gojira open create --project <PROJECT KEY> --type <ISSUE TYPE> [flags]
 
 Aliases:
   open, o
 
+Available Commands:
+  create      Open the create issue page in browser
+
 Flags:
   -h, --help                   help for open
 `
@@ -54,7 +58,7 @@ var openCmd = &cobra.Command{
 		if len(args) == 1 {
 			IssueKey = strings.ToUpper(args[0])
 		}
-		openbrowser(Cfg.JiraURL + "/browse/" + IssueKey)
+		openbrowser(issueURL(IssueKey))
 	},
 }
 
@@ -0,0 +1,361 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+	"gopkg.in/yaml.v3"
+
+	"github.com/mhersson/gojira/pkg/util"
+	"github.com/mhersson/gojira/pkg/util/format"
+)
+
+const (
+	// Summary length used when the terminal width is unknown, e.g. when piping the output.
+	defaultSummaryLength = 60
+	// Min summary length when fitting the table to the terminal width.
+	minSummaryLength = 20
+	// Min assignee length when fitting the table to the terminal width.
+	minAssigneeLength = 10
+)
+
+// The prefix of the output format piping the json output to an external formatter.
+const execOutputPrefix = "exec:"
+
+// The json output is rendered as yaml, or piped to an external formatter,
+// by printJSON when the output format is yaml or exec:FORMATTER.
+var (
+	outputYAML      bool
+	outputFormatter []string // The external formatter and its arguments
+)
+
+// checkOutputFormat exits if the output format is set,
+// but is not one of the formats supported by the command.
+// Commands supporting json also support yaml and external formatters.
+func checkOutputFormat(supported ...string) {
+	if OutputTemplate != "" {
+		if OutputFormat != "" {
+			fmt.Println("Can not use both --template and --output")
+			os.Exit(exitUsage)
+		}
+
+		OutputFormat = "json"
+
+		return
+	}
+
+	if OutputFile != "" {
+		if OutputFormat == "" {
+			OutputFormat = "csv"
+		}
+
+		if OutputFormat != "csv" {
+			fmt.Println("--file is only supported with csv output")
+			os.Exit(exitUsage)
+		}
+	}
+
+	if slices.Contains(supported, "json") {
+		if OutputFormat == "yaml" {
+			outputYAML = true
+			OutputFormat = "json"
+
+			return
+		}
+
+		if f, ok := strings.CutPrefix(OutputFormat, execOutputPrefix); ok {
+			outputFormatter = strings.Fields(f)
+			if len(outputFormatter) == 0 {
+				fmt.Println("Missing formatter, e.g. exec:mytool")
+				os.Exit(exitUsage)
+			}
+
+			OutputFormat = "json"
+
+			return
+		}
+
+		supported = append(slices.Clone(supported), "yaml", execOutputPrefix+"FORMATTER")
+	}
+
+	if OutputFormat != "" && !slices.Contains(supported, OutputFormat) {
+		fmt.Printf("Unsupported output format %s, must be one of: %s\n",
+			OutputFormat, strings.Join(supported, ", "))
+		os.Exit(exitUsage)
+	}
+}
+
+func printJSON(v interface{}) {
+	if OutputTemplate != "" {
+		printTemplate(v)
+
+		return
+	}
+
+	out, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		fmt.Printf("Failed to create json output - %v\n", err)
+		os.Exit(exitCode(err))
+	}
+
+	switch {
+	case outputYAML:
+		printYAML(out)
+	case len(outputFormatter) > 0:
+		runOutputFormatter(out)
+	default:
+		fmt.Println(string(out))
+	}
+}
+
+// printYAML prints the json output as yaml. The json is converted instead
+// of the value, to keep the field names, and the order, of the json output.
+func printYAML(out []byte) {
+	doc := &yaml.Node{}
+	if err := yaml.Unmarshal(out, doc); err != nil {
+		fmt.Printf("Failed to create yaml output - %v\n", err)
+		os.Exit(exitCode(err))
+	}
+
+	resetYAMLStyle(doc)
+
+	var b bytes.Buffer
+
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(2)
+
+	if err := enc.Encode(doc); err != nil {
+		fmt.Printf("Failed to create yaml output - %v\n", err)
+		os.Exit(exitCode(err))
+	}
+
+	fmt.Print(b.String())
+}
+
+// resetYAMLStyle removes the json flow and quoting style from the nodes,
+// so they are written in the default block style.
+func resetYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+
+	for _, n := range node.Content {
+		resetYAMLStyle(n)
+	}
+}
+
+// printTemplate renders the value with the go template set with --template,
+// or read from a file if the template starts with @. Lists are rendered one
+// item at a time, each on its own line.
+func printTemplate(v interface{}) {
+	text := OutputTemplate
+
+	if file, ok := strings.CutPrefix(text, "@"); ok {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			fmt.Printf("Failed to read template %s - %v\n", file, err)
+			os.Exit(exitCode(err))
+		}
+
+		text = string(content)
+	}
+
+	items := []interface{}{v}
+
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Slice {
+		items = []interface{}{}
+		for i := range rv.Len() {
+			items = append(items, rv.Index(i).Interface())
+		}
+	}
+
+	for _, item := range items {
+		out, err := util.ExecuteTemplateText(text, item)
+		if err != nil {
+			fmt.Printf("Failed to render the template - %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		if !bytes.HasSuffix(out, []byte("\n")) {
+			out = append(out, '\n')
+		}
+
+		fmt.Print(string(out))
+	}
+}
+
+// addTemplateFlag adds --template to the commands supporting json output.
+func addTemplateFlag(cmds ...*cobra.Command) {
+	for _, c := range cmds {
+		c.Flags().StringVar(&OutputTemplate, "template", "", "render the output with a go template, or @FILE")
+	}
+}
+
+// runOutputFormatter pipes the json output to the external formatter,
+// which prints the output in its own format.
+func runOutputFormatter(out []byte) {
+	cmd := exec.Command(outputFormatter[0], outputFormatter[1:]...) //nolint:gosec
+	cmd.Stdin = bytes.NewReader(out)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		fmt.Printf("Failed to run formatter %s - %v\n", outputFormatter[0], err)
+		os.Exit(exitCode(err))
+	}
+}
+
+// printCSV prints the rows with the header to stdout,
+// or writes them to the file set with --file.
+func printCSV(header []string, rows [][]string) {
+	out := os.Stdout
+
+	if OutputFile != "" {
+		f, err := os.Create(OutputFile)
+		if err != nil {
+			fmt.Printf("Failed to create %s - %v\n", OutputFile, err)
+			os.Exit(exitCode(err))
+		}
+		defer f.Close()
+
+		out = f
+	}
+
+	w := csv.NewWriter(out)
+
+	_ = w.Write(header)
+	_ = w.WriteAll(rows)
+
+	if err := w.Error(); err != nil {
+		fmt.Printf("Failed to create csv output - %v\n", err)
+		os.Exit(exitCode(err))
+	}
+
+	if OutputFile != "" {
+		success("Wrote %d rows to %s", len(rows), OutputFile)
+	}
+}
+
+// summaryLength returns the length summaries are truncated at in a table
+// where the other columns are otherColumns wide. The --full and --truncate
+// flags take precedence over the config, and if neither is set the summary
+// column is sized to the remaining terminal width. Returns 0 for no truncation.
+func summaryLength(otherColumns int) int {
+	switch {
+	case Wide, FullSummary:
+		return 0
+	case TruncateSummary > 0:
+		return TruncateSummary
+	case Cfg.FullSummary:
+		return 0
+	case Cfg.TruncateSummary > 0:
+		return Cfg.TruncateSummary
+	}
+
+	width, _, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		return defaultSummaryLength
+	}
+
+	// Leave room for the ".." and the space between the columns
+	return max(width-otherColumns-4, minSummaryLength)
+}
+
+// tableWidth returns the width the tables are fitted in, the terminal
+// width, or 0 for no limit with --wide or when it is not a terminal.
+func tableWidth() int {
+	if Wide {
+		return 0
+	}
+
+	width, _, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		return 0
+	}
+
+	return width
+}
+
+// summaryColumn returns the summary column of a table, shrunk to fit
+// the terminal unless --full or --truncate, or the config, says otherwise.
+// The summaries are truncated at defaultSummaryLength when the terminal
+// width is unknown, as with summaryLength.
+func summaryColumn() format.Column {
+	c := format.Column{Header: "Summary"}
+
+	switch {
+	case Wide, FullSummary:
+	case TruncateSummary > 0:
+		c.Max = TruncateSummary
+	case Cfg.FullSummary:
+	case Cfg.TruncateSummary > 0:
+		c.Max = Cfg.TruncateSummary
+	case tableWidth() == 0:
+		c.Max = defaultSummaryLength
+	default:
+		c.Shrink = minSummaryLength
+	}
+
+	return c
+}
+
+// truncateSummaries truncates the summaries at the given length, and returns
+// the width of the summary column, fitting the longest summary.
+func truncateSummaries(length int, summaries ...*string) int {
+	longest := len("Summary")
+
+	for _, s := range summaries {
+		if r := []rune(*s); length > 0 && len(r) > length {
+			*s = string(r[:length]) + ".."
+		}
+
+		longest = max(longest, len([]rune(*s)))
+	}
+
+	return longest + 2
+}
+
+// success prints the message in green, unless --quiet is given.
+func success(msg string, a ...any) {
+	if Quiet {
+		return
+	}
+
+	fmt.Printf("%s%s%s\n", format.Color.Green, fmt.Sprintf(msg, a...), format.Color.Nocolor)
+}
+
+// header prints the header of a table, unless --quiet is given.
+func header(s string) {
+	if !Quiet {
+		fmt.Println(s)
+	}
+}
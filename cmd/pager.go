@@ -0,0 +1,120 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// The pager used when PAGER is not set.
+const defaultPager = "less -R"
+
+// pager collects what is printed to stdout, to show it in the pager
+// if it does not fit in the terminal.
+type pager struct {
+	stdout *os.File
+	w      *os.File
+	output bytes.Buffer
+	done   chan struct{}
+}
+
+// activePager is the pager started by startPager, if any.
+var activePager *pager
+
+// startPager collects what is printed to stdout until stopPager is
+// called, when stdout is a terminal and neither --no-pager nor an output
+// format is given.
+func startPager() {
+	if activePager != nil || NoPager || OutputFormat != "" || OutputTemplate != "" ||
+		!term.IsTerminal(int(os.Stdout.Fd())) {
+		return
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		return
+	}
+
+	p := &pager{stdout: os.Stdout, w: w, done: make(chan struct{})}
+
+	go func() {
+		defer close(p.done)
+
+		_, _ = io.Copy(&p.output, r)
+		r.Close()
+	}()
+
+	os.Stdout = w
+	activePager = p
+}
+
+// stopPager shows the output collected since startPager in PAGER, or
+// less, if it is longer than the terminal is high, or else prints it.
+func stopPager() {
+	p := activePager
+	if p == nil {
+		return
+	}
+
+	activePager = nil
+	os.Stdout = p.stdout
+
+	p.w.Close()
+	<-p.done
+
+	_, height, err := term.GetSize(int(p.stdout.Fd()))
+	if err != nil || bytes.Count(p.output.Bytes(), []byte("\n")) < height {
+		_, _ = p.stdout.Write(p.output.Bytes())
+
+		return
+	}
+
+	command, ok := os.LookupEnv("PAGER")
+	if !ok || strings.TrimSpace(command) == "" {
+		command = defaultPager
+	}
+
+	args := strings.Fields(command)
+
+	// Ctrl-C is for the pager now, the requests to Jira are done
+	signal.Ignore(os.Interrupt)
+
+	cmd := exec.Command(args[0], args[1:]...) //nolint:gosec
+	cmd.Stdin = bytes.NewReader(p.output.Bytes())
+	cmd.Stdout = p.stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Start(); err != nil {
+		_, _ = p.stdout.Write(p.output.Bytes())
+
+		return
+	}
+
+	_ = cmd.Wait()
+}
@@ -0,0 +1,71 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/mhersson/gojira/pkg/util/format"
+)
+
+// Jira permission keys used by the pre-checks.
+const (
+	permAddComments       = "ADD_COMMENTS"
+	permAssignIssues      = "ASSIGN_ISSUES"
+	permDeleteAllWorklogs = "DELETE_ALL_WORKLOGS"
+	permDeleteOwnWorklogs = "DELETE_OWN_WORKLOGS"
+	permEditAllComments   = "EDIT_ALL_COMMENTS"
+	permEditIssues        = "EDIT_ISSUES"
+	permEditOwnComments   = "EDIT_OWN_COMMENTS"
+	permLinkIssues        = "LINK_ISSUES"
+	permManageWatchers    = "MANAGE_WATCHERS"
+	permTransitionIssues  = "TRANSITION_ISSUES"
+	permWorkOnIssues      = "WORK_ON_ISSUES"
+)
+
+// exitIfReadOnly must be called first by every command
+// that modifies data in Jira.
+func exitIfReadOnly() {
+	if Cfg.ReadOnly {
+		fmt.Println("Gojira is in read-only mode, no changes can be made")
+		os.Exit(exitFailure)
+	}
+}
+
+// checkPermission exits early unless the user has at least one of the given
+// permissions on the issue, so we don't fail after the user has spent time
+// in the editor.
+func checkPermission(key, action string, permissions ...string) {
+	granted := must(JiraClient.GetMyPermissions(ctx, key, permissions...))
+
+	for _, p := range permissions {
+		if g, ok := granted[p]; ok && g.HavePermission {
+			return
+		}
+	}
+
+	fmt.Printf("%sYou do not have permission to %s on %s%s\n",
+		format.Color.Red, action, key, format.Color.Nocolor)
+	os.Exit(exitAuth)
+}
@@ -0,0 +1,117 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mhersson/gojira/pkg/jira"
+	"github.com/mhersson/gojira/pkg/util/format"
+	"github.com/mhersson/gojira/pkg/util/stats"
+)
+
+const pingUsage string = `Measures the latency of the Jira server, and displays the Jira version
+and deployment type. A warning is printed if the deployment type does
+not match the deployment set in the config file.
+
+The first request is not counted, because it includes decrypting the password.
+
+Usage:
+  gojira ping [flags]
+
+Flags:
+  -h, --help                   help for ping
+  -n, --count                  number of samples (default 5)
+`
+
+var PingCount int
+
+var pingCmd = &cobra.Command{
+	Use:   "ping",
+	Short: "Measure the latency of the Jira server",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if PingCount < 1 {
+			fmt.Println("The number of samples must be at least 1")
+			os.Exit(exitUsage)
+		}
+
+		info, err := JiraClient.GetServerInfo(ctx)
+		if err != nil {
+			fmt.Printf("%sFailed to reach %s - %s%s\n", format.Color.Red, Cfg.JiraURL, err.Error(), format.Color.Nocolor)
+			os.Exit(exitCode(err))
+		}
+
+		samples := []float64{}
+
+		for range PingCount {
+			start := time.Now()
+
+			if _, err := JiraClient.GetServerInfo(ctx); err != nil {
+				fmt.Printf("%sRequest failed - %s%s\n", format.Color.Red, err.Error(), format.Color.Nocolor)
+
+				continue
+			}
+
+			samples = append(samples, float64(time.Since(start).Microseconds())/1000)
+		}
+
+		fmt.Printf("Jira:        %s (%s)\n", info.ServerTitle, info.BaseURL)
+		fmt.Printf("Version:     %s (build %d)\n", info.Version, info.BuildNumber)
+		fmt.Printf("Deployment:  %s\n", info.DeploymentType)
+
+		if len(samples) > 0 {
+			fmt.Printf("Latency:     p50 %.1fms, p95 %.1fms (%d of %d requests succeeded)\n",
+				stats.Percentile(samples, 50), stats.Percentile(samples, 95), len(samples), PingCount)
+		}
+
+		if isCloud := strings.EqualFold(info.DeploymentType, "Cloud"); isCloud != jira.IsCloud(Cfg) {
+			fmt.Printf("\n%sThe server is a %s deployment, but gojira uses the %s API (deployment is %s in the config)%s\n",
+				format.Color.Yellow, info.DeploymentType, apiVersion(jira.IsCloud(Cfg)), Cfg.Deployment, format.Color.Nocolor)
+		}
+
+		if len(samples) < PingCount {
+			os.Exit(exitFailure)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(pingCmd)
+
+	pingCmd.SetUsageTemplate(pingUsage)
+	pingCmd.Flags().IntVarP(&PingCount, "count", "n", 5, "number of samples")
+}
+
+// apiVersion returns the REST API version used by gojira.
+func apiVersion(cloud bool) string {
+	if cloud {
+		return "v3"
+	}
+
+	return "v2"
+}
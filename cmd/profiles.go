@@ -0,0 +1,71 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mhersson/gojira/pkg/jira"
+	"github.com/mhersson/gojira/pkg/types"
+)
+
+// profileClient returns a client for the Jira server of the profile,
+// or the client of the active context if the name is empty.
+func profileClient(name string) *jira.Client {
+	if name == "" {
+		return JiraClient
+	}
+
+	p, ok := lookupProfile(strings.ToLower(name))
+	if !ok {
+		fmt.Printf("There is no profile %s in the config\n", name)
+		os.Exit(exitNotFound)
+	}
+
+	c := jira.NewClient(withProfile(Cfg, p))
+	c.SetTLSConfig(loadTLSConfig())
+	c.SetTimeout(RequestTimeout)
+	c.SetMaxAttempts(Cfg.MaxAttempts)
+	logRequests(c)
+
+	return c
+}
+
+// withProfile returns the config with the Jira server, credentials and
+// auth mode of the profile. The password cache is inherited, as cached
+// passwords are kept per user and server.
+func withProfile(cfg types.Config, p types.Profile) types.Config {
+	cfg.JiraURL = strings.TrimSuffix(p.JiraURL, "/")
+	cfg.Username = p.Username
+	cfg.Password = p.Password
+	cfg.PasswordType = p.PasswordType
+	cfg.AuthMode = p.AuthMode
+	cfg.Deployment = "auto"
+
+	if p.Deployment != "" {
+		cfg.Deployment = strings.ToLower(p.Deployment)
+	}
+
+	return cfg
+}
@@ -0,0 +1,66 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mhersson/gojira/pkg/types"
+)
+
+func TestWithProfile(t *testing.T) {
+	cfg := types.Config{
+		JiraURL:       "https://jira.example.com",
+		Username:      "bob",
+		AuthMode:      "session",
+		PasswordCache: 30 * time.Minute,
+		Deployment:    "server",
+	}
+
+	p := types.Profile{
+		JiraURL:      "https://example.atlassian.net/",
+		Username:     "bob@example.com",
+		Password:     "jira/cloud-token",
+		PasswordType: "pass",
+	}
+
+	got := withProfile(cfg, p)
+	assert.Equal(t, "https://example.atlassian.net", got.JiraURL)
+	assert.Equal(t, "bob@example.com", got.Username)
+	assert.Equal(t, "auto", got.Deployment)
+	assert.Empty(t, got.AuthMode)
+	assert.Equal(t, 30*time.Minute, got.PasswordCache)
+
+	p.AuthMode = "session"
+	p.Deployment = "Server"
+
+	got = withProfile(cfg, p)
+	assert.Equal(t, "session", got.AuthMode)
+	assert.Equal(t, "server", got.Deployment)
+
+	// Back to the default context from another context
+	other := withProfile(cfg, types.Profile{JiraURL: "https://other.example.com"})
+	assert.Equal(t, cfg, withProfile(other, configProfile(cfg)))
+}
@@ -0,0 +1,124 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mhersson/gojira/pkg/jira"
+	"github.com/mhersson/gojira/pkg/util/emoji"
+	"github.com/mhersson/gojira/pkg/util/validate"
+)
+
+const reactUsage string = `React to a comment with an emoji, given by its shortcode, e.g. :+1:,
+or the emoji itself. Comment reactions are only available on Jira Cloud.
+
+By default the comment is on the active issue,
+but this can be changed by adding the issue key as argument.
+
+Usage:
+  gojira react [ISSUE KEY] <COMMENT ID> <EMOJI>
+
+Flags:
+  -h, --help                   help for react
+
+Example:
+  gojira react OSE-1 123456 :+1:
+  gojira react 123456 :tada:
+`
+
+var reactCmd = &cobra.Command{
+	Use:   "react",
+	Short: "React to a comment with an emoji",
+	Args:  cobra.RangeArgs(2, 3),
+	Run: func(cmd *cobra.Command, args []string) {
+		exitIfReadOnly()
+
+		if !jira.IsCloud(Cfg) {
+			fmt.Println("Comment reactions are only available on Jira Cloud")
+			os.Exit(exitFailure)
+		}
+
+		if len(args) == 3 {
+			IssueKey = strings.ToUpper(args[0])
+			args = args[1:]
+		}
+
+		commentID, shortcode := args[0], args[1]
+
+		if !validate.CommentID(commentID) {
+			fmt.Println("Invalid comment id")
+			os.Exit(exitUsage)
+		}
+
+		e, ok := reactionEmoji(shortcode)
+		if !ok {
+			fmt.Printf("Unknown emoji %s\n", shortcode)
+			os.Exit(exitUsage)
+		}
+
+		checkIssueKey(&IssueKey, IssueFile)
+
+		if getComment(IssueKey, commentID).ID == "" {
+			fmt.Printf("Comment %s does not exist on %s\n", commentID, IssueKey)
+			os.Exit(exitNotFound)
+		}
+
+		err := JiraClient.AddReaction(ctx, commentID, emoji.ID(e))
+		if errors.Is(err, jira.ErrNoReactions) {
+			fmt.Println("Comment reactions are not available on this Jira")
+			os.Exit(exitFailure)
+		}
+
+		if err != nil {
+			fmt.Printf("Failed to add reaction - %s\n", err.Error())
+			os.Exit(exitCode(err))
+		}
+
+		success("Reacted with %s to comment %s on %s", e, commentID, IssueKey)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(reactCmd)
+
+	reactCmd.SetUsageTemplate(reactUsage)
+}
+
+// reactionEmoji returns the emoji of the shortcode,
+// or the argument itself if it is an emoji.
+func reactionEmoji(arg string) (string, bool) {
+	if e, ok := emoji.Lookup(arg); ok {
+		return e, true
+	}
+
+	r, _ := utf8.DecodeRuneInString(arg)
+
+	// The emoji are all outside ascii, unlike the shortcodes
+	return arg, r >= 0x2000
+}
@@ -0,0 +1,140 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mhersson/gojira/pkg/recorder"
+)
+
+// replay runs gojira with the arguments against a trace recorded
+// with --record, and returns what was printed to stdout.
+// The trace must contain every request made by the command.
+func replay(t *testing.T, trace string, args ...string) string {
+	t.Helper()
+
+	rep, err := recorder.Load(filepath.Join("testdata", trace))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	viper.SetConfigFile(filepath.Join("testdata", "config.yaml"))
+
+	// Start with an empty cache, so the issue keys are checked
+	ExistsCacheFile = filepath.Join(t.TempDir(), "exists.json")
+
+	// Use the default context, whatever is active for the user
+	ContextFile = filepath.Join(t.TempDir(), "context")
+	JiraClient.SetTransport(rep)
+
+	defer JiraClient.SetTransport(http.DefaultTransport)
+
+	out := run(t, args...)
+
+	assert.Empty(t, rep.Unused(), "requests in the trace were not made")
+
+	return out
+}
+
+// run runs gojira with the arguments, and returns what was printed
+// to stdout. The flags are reset afterwards, so they do not carry
+// over to the next run.
+func run(t *testing.T, args ...string) string {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stdout := os.Stdout
+	os.Stdout = w
+
+	output := make(chan string)
+
+	go func() {
+		out, _ := io.ReadAll(r)
+		output <- string(out)
+	}()
+
+	rootCmd.SetArgs(args)
+	err = rootCmd.Execute()
+
+	os.Stdout = stdout
+	w.Close()
+
+	assert.NoError(t, err)
+
+	if c, _, err := rootCmd.Find(args); err == nil {
+		resetFlags(c)
+	}
+
+	return <-output
+}
+
+// resetFlags sets the flags of the command and its parents to their defaults.
+func resetFlags(c *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		if s, ok := f.Value.(pflag.SliceValue); ok {
+			_ = s.Replace(nil)
+		} else {
+			_ = f.Value.Set(f.DefValue)
+		}
+
+		f.Changed = false
+	}
+
+	for ; c != nil; c = c.Parent() {
+		c.Flags().VisitAll(reset)
+		c.PersistentFlags().VisitAll(reset)
+	}
+}
+
+func TestReplayGetWorklog(t *testing.T) {
+	out := replay(t, "get-worklog.json", "get", "worklog", "OSE-1", "--output", "csv")
+
+	assert.Contains(t, out, "Started,ID,Author,Time Spent,Seconds,Billable,Overtime,Account,Comment\n"+
+		"2024-03-01T09:00,55,Bob,1h,3600,false,false,,plain\n"+
+		"2024-03-02T09:00,56,Bob,2h,7200,true,true,ACME,cust\n")
+}
+
+func TestReplayFlag(t *testing.T) {
+	viper.SetConfigFile(filepath.Join("testdata", "config.yaml"))
+
+	ContextFile = filepath.Join(t.TempDir(), "context")
+
+	defer JiraClient.SetTransport(http.DefaultTransport)
+
+	out := run(t, "get", "worklog", "OSE-1", "--output", "csv", "--replay", filepath.Join("testdata", "get-worklog.json"))
+
+	assert.Contains(t, out, "2024-03-02T09:00,56,Bob,2h,7200,true,true,ACME,cust\n")
+}
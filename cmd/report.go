@@ -0,0 +1,866 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"net/smtp"
+	"os"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mhersson/gojira/pkg/jira"
+	"github.com/mhersson/gojira/pkg/types"
+	"github.com/mhersson/gojira/pkg/util"
+	"github.com/mhersson/gojira/pkg/util/convert"
+	"github.com/mhersson/gojira/pkg/util/format"
+	"github.com/mhersson/gojira/pkg/util/mail"
+	"github.com/mhersson/gojira/pkg/util/stats"
+)
+
+const reportUsage string = `Show and send reports, e.g. for your manager or the steering meeting.
+
+Usage:
+  gojira report [command]
+
+Available Commands:
+  labels          Show the issues and time spent per label
+  send            Render a status report and send it by mail
+  version-budget  Show the estimates and time spent in a fix version
+  year            Sum up your work in a year
+
+Flags:
+  -h, --help                   help for report
+
+Use "gojira report [command] --help" for more information about a command.
+`
+
+const reportSendUsage string = `Render a status report and send it by mail, e.g. the Friday status mail
+to your manager. The report holds the time you have logged per issue and
+the issues assigned to you that were resolved in the period.
+
+The report is rendered with a go template, either the built-in weekly
+template or a template file of your own. If the first line of the
+rendered report starts with "Subject:" it is used as the subject of
+the mail. The template is given the period (From, To), your username
+(User), the total time logged (TimeSpentSeconds), the time logged per
+issue (Worklogs) and the resolved issues (Resolved).
+
+The mail is sent as configured in the mail section of the config file,
+either by piping it to a sendmail style command or through an SMTP server.
+
+Valid values for since are today, yesterday, a number of days (3d),
+a duration (12h) or a date on the format yyyy-mm-dd.
+
+Usage:
+  gojira report send [flags]
+
+Flags:
+      --dry-run                print the mail instead of sending it
+  -h, --help                   help for send
+  -s, --since [SINCE]          start of the report period (default start of this week)
+  -t, --template [TEMPLATE]    weekly or a template file (default weekly)
+      --to [ADDRESS]           the recipients, can be repeated
+
+Example:
+  gojira report send --template weekly --to manager@example.com
+`
+
+const reportVersionBudgetUsage string = `Show the original estimates, the time spent and the remaining estimates
+of all issues in a fix version, per epic and in total. The variance is
+the time spent plus the remaining estimate minus the original estimate,
+so a positive variance means the work is over budget.
+
+Fix version names are not unique across projects, so use --project
+to only count the issues in one project.
+
+Usage:
+  gojira report version-budget [VERSION] [flags]
+
+Aliases:
+  version-budget, vb
+
+Flags:
+  -h, --help                   help for version-budget
+  -o, --output [FORMAT]        output format, csv, json, yaml or exec:FORMATTER
+  -p, --project [PROJECT KEY]  only count the issues in the project
+
+Example:
+  gojira report version-budget 2.5.0 --project OSE
+`
+
+const reportLabelsUsage string = `Show the number of issues and the time spent per label, for the issues
+matching the filter, e.g. to see how much time goes to unplanned work
+like support. The labels with the most time spent are listed first.
+
+An issue with several labels is counted once for each label, so the
+shares of the total time spent can add up to more than 100%. The time
+spent is all time logged on the issues, also outside the filter.
+
+Usage:
+  gojira report labels [flags]
+
+Aliases:
+  labels, l
+
+Flags:
+  -f, --filter [JQL FILTER]    the issues to count
+  -h, --help                   help for labels
+  -o, --output [FORMAT]        output format, csv, json, yaml or exec:FORMATTER
+
+Example:
+  gojira report labels -f "project = OSE and updated >= startOfMonth()"
+`
+
+const reportYearUsage string = `Sum up your work in a year, e.g. as input to the annual review. The
+report holds the issues assigned to you that were resolved in the year,
+the time you logged per project and per epic, the longest streak of
+days with time logged, the average cycle time of the resolved issues
+and the issues you spent the most time on.
+
+The cycle time is the number of days from an issue was created until
+it was resolved. Weekends without time logged do not break a streak.
+The year defaults to the current year.
+
+Usage:
+  gojira report year [YEAR] [flags]
+
+Aliases:
+  year, y
+
+Flags:
+  -h, --help                   help for year
+  -o, --output [FORMAT]        output format, json, yaml or exec:FORMATTER
+
+Example:
+  gojira report year 2024
+`
+
+// Used by `report send`.
+var (
+	ReportTemplate string
+	ReportTo       []string
+	ReportSince    string
+	ReportDryRun   bool
+	ReportProject  string // Used by `report version-budget`
+)
+
+// epicBudget is the time tracking summed up for the issues in an epic,
+// or for all issues in the version when Key is empty.
+type epicBudget struct {
+	Key              string `json:"key"`
+	Summary          string `json:"summary"`
+	Issues           int    `json:"issues"`
+	OriginalEstimate int    `json:"originalEstimateSeconds"`
+	TimeSpent        int    `json:"timeSpentSeconds"`
+	Remaining        int    `json:"remainingEstimateSeconds"`
+}
+
+// Variance is how much more time the work takes than estimated.
+func (b epicBudget) Variance() int {
+	return b.TimeSpent + b.Remaining - b.OriginalEstimate
+}
+
+func (b *epicBudget) add(i types.TimeTrackingIssue) {
+	b.Issues++
+	b.OriginalEstimate += i.Fields.OriginalEstimate
+	b.TimeSpent += i.Fields.TimeSpent
+	b.Remaining += i.Fields.Remaining
+}
+
+// labelStats is the issues and time spent with a label.
+type labelStats struct {
+	Label     string  `json:"label"`
+	Issues    int     `json:"issues"`
+	TimeSpent int     `json:"timeSpentSeconds"`
+	Share     float64 `json:"share"`
+}
+
+// noLabel is used for the issues without labels.
+const noLabel = "(no label)"
+
+// labelBarWidth is the width of the bar showing the time spent on the top label.
+const labelBarWidth = 20
+
+// yearReview is the work done by the user in a year.
+type yearReview struct {
+	Year       int          `json:"year"`
+	User       string       `json:"user"`
+	Resolved   int          `json:"resolved"`
+	TimeSpent  int          `json:"timeSpentSeconds"`
+	DaysLogged int          `json:"daysLogged"`
+	Streak     reviewStreak `json:"longestStreak"`
+	CycleTime  float64      `json:"averageCycleTimeDays"`
+	Projects   []reviewTime `json:"projects"`
+	Epics      []reviewTime `json:"epics"`
+	Biggest    []reviewTime `json:"biggestIssues"`
+}
+
+type reviewStreak struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Days int    `json:"days"`
+}
+
+// reviewTime is the time logged on a project, an epic or an issue.
+type reviewTime struct {
+	Key       string `json:"key"`
+	Summary   string `json:"summary,omitempty"`
+	TimeSpent int    `json:"timeSpentSeconds"`
+}
+
+// reviewTop is the number of epics and issues shown in the year report.
+const reviewTop = 10
+
+// reportTemplates are the built-in report templates.
+var reportTemplates = map[string]string{
+	"weekly": "weekly-report.tmpl",
+}
+
+type reportWorklog struct {
+	Key              string
+	Summary          string
+	TimeSpent        string
+	TimeSpentSeconds int
+}
+
+type report struct {
+	From             string
+	To               string
+	User             string
+	TimeSpentSeconds int
+	Worklogs         []reportWorklog
+	Resolved         []types.Issue
+}
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Show and send reports",
+	Args:  cobra.NoArgs,
+}
+
+var reportSendCmd = &cobra.Command{
+	Use:   "send",
+	Short: "Render the report and send it by mail",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if Cfg.Mail.From == "" && !ReportDryRun {
+			fmt.Println("Sending reports requires mail.from in the config file")
+			os.Exit(exitFailure)
+		}
+
+		since := reportStart()
+		r := getReport(since, time.Now())
+
+		subject, body := mail.SplitSubject(string(renderReport(r)))
+		if subject == "" {
+			subject = "Status report " + r.From + " - " + r.To
+		}
+
+		msg := mail.Compose(Cfg.Mail.From, ReportTo, subject, body, time.Now())
+
+		if ReportDryRun {
+			fmt.Print(strings.ReplaceAll(string(msg), "\r\n", "\n"))
+
+			return
+		}
+
+		if err := sendMail(msg); err != nil {
+			fmt.Printf("Failed to send the report - %s\n", err.Error())
+			os.Exit(exitCode(err))
+		}
+
+		success("Sent the report to %s", strings.Join(ReportTo, ", "))
+	},
+}
+
+var reportVersionBudgetCmd = &cobra.Command{
+	Use:     "version-budget",
+	Short:   "Show the estimates and time spent in a fix version",
+	Aliases: []string{"vb"},
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		checkOutputFormat("csv", "json")
+
+		filter := `fixVersion = "` + strings.ReplaceAll(args[0], `"`, `\"`) + `"`
+		if ReportProject != "" {
+			filter = "project = " + strings.ToUpper(ReportProject) + " AND " + filter
+		}
+
+		issues := must(JiraClient.GetTimeTrackingIssues(ctx, filter))
+		if len(issues) == 0 {
+			fmt.Printf("There are no issues in %s\n", args[0])
+
+			return
+		}
+
+		budgets := getVersionBudget(issues)
+
+		switch OutputFormat {
+		case "csv":
+			printVersionBudgetCSV(budgets)
+		case "json":
+			printJSON(budgets)
+		default:
+			printVersionBudget(args[0], budgets)
+		}
+	},
+}
+
+var reportLabelsCmd = &cobra.Command{
+	Use:     "labels",
+	Short:   "Show the issues and time spent per label",
+	Aliases: []string{"l"},
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		checkOutputFormat("csv", "json")
+
+		issues := must(JiraClient.GetLabelIssues(ctx, JQLFilter))
+		if len(issues) == 0 {
+			fmt.Println("There are no issues matching the filter")
+
+			return
+		}
+
+		stats := getLabelStats(issues)
+
+		switch OutputFormat {
+		case "csv":
+			printLabelStatsCSV(stats)
+		case "json":
+			printJSON(stats)
+		default:
+			printLabelStats(len(issues), stats)
+		}
+	},
+}
+
+var reportYearCmd = &cobra.Command{
+	Use:     "year",
+	Short:   "Sum up your work in a year",
+	Aliases: []string{"y"},
+	Args:    cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		checkOutputFormat("json")
+
+		year := time.Now().Year()
+
+		if len(args) == 1 {
+			y, err := strconv.Atoi(args[0])
+			if err != nil || y < 1000 || y > 9999 {
+				fmt.Printf("Invalid year %s\n", args[0])
+				os.Exit(exitUsage)
+			}
+
+			year = y
+		}
+
+		review := getYearReview(year)
+
+		if OutputFormat == "json" {
+			printJSON(review)
+
+			return
+		}
+
+		printYearReview(review)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+	reportCmd.AddCommand(reportLabelsCmd)
+	reportCmd.AddCommand(reportSendCmd)
+	reportCmd.AddCommand(reportVersionBudgetCmd)
+	reportCmd.AddCommand(reportYearCmd)
+
+	reportCmd.SetUsageTemplate(reportUsage)
+	reportLabelsCmd.SetUsageTemplate(reportLabelsUsage)
+	reportSendCmd.SetUsageTemplate(reportSendUsage)
+	reportVersionBudgetCmd.SetUsageTemplate(reportVersionBudgetUsage)
+	reportYearCmd.SetUsageTemplate(reportYearUsage)
+
+	reportLabelsCmd.Flags().StringVarP(&JQLFilter, "filter", "f", "", "the issues to count")
+	_ = reportLabelsCmd.MarkFlagRequired("filter")
+
+	reportVersionBudgetCmd.Flags().StringVarP(&ReportProject, "project", "p", "", "only count the issues in the project")
+
+	reportSendCmd.Flags().StringVarP(&ReportTemplate, "template", "t", "weekly", "weekly or a template file")
+	reportSendCmd.Flags().StringSliceVar(&ReportTo, "to", nil, "the recipients, can be repeated")
+	reportSendCmd.Flags().StringVarP(&ReportSince, "since", "s", "", "start of the report period")
+	reportSendCmd.Flags().BoolVar(&ReportDryRun, "dry-run", false, "print the mail instead of sending it")
+	_ = reportSendCmd.MarkFlagRequired("to")
+}
+
+// reportStart returns the start of the report period, by default
+// the start of the current week.
+func reportStart() time.Time {
+	if ReportSince == "" {
+		return weekRule().WeekStart(time.Now())
+	}
+
+	since, err := convert.SinceToTime(ReportSince, time.Now())
+	if err != nil {
+		fmt.Printf("Invalid since %s - %s\n", ReportSince, err.Error())
+		os.Exit(exitUsage)
+	}
+
+	return since
+}
+
+func getReport(since, until time.Time) report {
+	r := report{
+		From: since.Format("2006-01-02"),
+		To:   until.Format("2006-01-02"),
+		User: Cfg.Username,
+	}
+
+	issues := must(JiraClient.GetIssuesSelecting(ctx,
+		`worklogAuthor = currentUser() AND worklogDate >= "`+r.From+`"`, "updated DESC", []string{"summary"}))
+
+	for _, i := range issues {
+		seconds := 0
+
+		for _, w := range must(JiraClient.GetWorklogs(ctx, i.Key)) {
+			started, err := util.ParseJiraTime(w.Started)
+			if err != nil || w.Author.Name != Cfg.Username || started.Before(since) || started.After(until) {
+				continue
+			}
+
+			seconds += w.TimeSpentSeconds
+		}
+
+		if seconds == 0 {
+			continue
+		}
+
+		r.TimeSpentSeconds += seconds
+		r.Worklogs = append(r.Worklogs, reportWorklog{
+			Key:              i.Key,
+			Summary:          i.Fields.Summary,
+			TimeSpent:        convert.SecondsToHoursAndMinutes(seconds, false),
+			TimeSpentSeconds: seconds,
+		})
+	}
+
+	slices.SortFunc(r.Worklogs, func(a, b reportWorklog) int {
+		return b.TimeSpentSeconds - a.TimeSpentSeconds
+	})
+
+	r.Resolved = must(JiraClient.GetIssuesSelecting(ctx,
+		`assignee = currentUser() AND resolved >= "`+r.From+`"`, "resolved ASC", jira.BriefFields))
+
+	return r
+}
+
+func renderReport(r report) []byte {
+	if name, ok := reportTemplates[ReportTemplate]; ok {
+		return util.ExecuteTemplate(name, r)
+	}
+
+	text, err := os.ReadFile(ReportTemplate)
+	if err != nil {
+		fmt.Printf("Failed to read template %s - %s\n", ReportTemplate, err.Error())
+		os.Exit(exitCode(err))
+	}
+
+	out, err := util.ExecuteTemplateText(string(text), r)
+	if err != nil {
+		fmt.Printf("Failed to render the report - %s\n", err.Error())
+		os.Exit(exitCode(err))
+	}
+
+	return out
+}
+
+// sendMail pipes the mail to the configured sendmail command, or sends
+// it through the configured SMTP server.
+func sendMail(msg []byte) error {
+	if Cfg.Mail.Sendmail != "" {
+		args := strings.Fields(Cfg.Mail.Sendmail)
+
+		c := exec.Command(args[0], args[1:]...) //nolint:gosec
+		c.Stdin = strings.NewReader(string(msg))
+		c.Stdout = os.Stdout
+		c.Stderr = os.Stderr
+
+		return c.Run()
+	}
+
+	if Cfg.Mail.SMTPServer == "" {
+		return fmt.Errorf("neither mail.sendmail nor mail.smtpServer is configured")
+	}
+
+	var auth smtp.Auth
+
+	if Cfg.Mail.Username != "" {
+		pw := types.JiraConfig{Password: Cfg.Mail.Password, PasswordType: Cfg.Mail.PasswordType}
+		if pw.PasswordType != "" {
+			if err := pw.Decrypt(); err != nil {
+				return err
+			}
+		}
+
+		host, _, _ := net.SplitHostPort(Cfg.Mail.SMTPServer)
+		auth = smtp.PlainAuth("", Cfg.Mail.Username, pw.Password, host)
+	}
+
+	return smtp.SendMail(Cfg.Mail.SMTPServer, auth, Cfg.Mail.From, ReportTo, msg)
+}
+
+// getVersionBudget sums up the issues per epic, ordered by epic key with
+// the issues without an epic next to last, and the total last.
+func getVersionBudget(issues []types.TimeTrackingIssue) []epicBudget {
+	byEpic := map[string]*epicBudget{}
+	total := epicBudget{Summary: "Total"}
+
+	for _, i := range issues {
+		if _, ok := byEpic[i.Fields.Epic]; !ok {
+			byEpic[i.Fields.Epic] = &epicBudget{Key: i.Fields.Epic}
+		}
+
+		byEpic[i.Fields.Epic].add(i)
+		total.add(i)
+	}
+
+	keys := []string{}
+
+	for k := range byEpic {
+		if k != "" {
+			keys = append(keys, k)
+		}
+	}
+
+	slices.Sort(keys)
+
+	if len(keys) > 0 {
+		for _, e := range must(JiraClient.GetIssuesSelecting(ctx, "key in ("+strings.Join(keys, ",")+")",
+			jira.OrderByPriority, []string{"summary"})) {
+			if b, ok := byEpic[e.Key]; ok {
+				b.Summary = e.Fields.Summary
+			}
+		}
+	}
+
+	budgets := []epicBudget{}
+
+	for _, k := range keys {
+		budgets = append(budgets, *byEpic[k])
+	}
+
+	if b, ok := byEpic[""]; ok {
+		b.Summary = "No epic"
+		budgets = append(budgets, *b)
+	}
+
+	return append(budgets, total)
+}
+
+func printVersionBudget(version string, budgets []epicBudget) {
+	fmt.Printf("Fix version %s\n\n", version)
+
+	fmt.Printf("%s%s%-12s %-40s %6s %10s %10s %10s %10s%s%s\n", format.Color.Ul, format.Color.Yellow,
+		"Epic", "Summary", "Issues", "Estimate", "Spent", "Remaining", "Variance",
+		format.Color.Nocolor, format.Color.Nocolor)
+
+	for i, b := range budgets {
+		summary := b.Summary
+		truncateSummaries(40, &summary)
+
+		if i == len(budgets)-1 {
+			fmt.Println()
+		}
+
+		color, sign, variance := format.Color.Green, "", b.Variance()
+		if variance > 0 {
+			color = format.Color.Red
+		} else if variance < 0 {
+			sign, variance = "-", -variance
+		}
+
+		fmt.Printf("%-12s %-40s %6d %10s %10s %10s %s%10s%s\n", b.Key, summary, b.Issues,
+			convert.SecondsToHoursAndMinutes(b.OriginalEstimate, false),
+			convert.SecondsToHoursAndMinutes(b.TimeSpent, false),
+			convert.SecondsToHoursAndMinutes(b.Remaining, false),
+			color, sign+convert.SecondsToHoursAndMinutes(variance, false), format.Color.Nocolor)
+	}
+}
+
+// getLabelStats sums up the issues per label, ordered by the time spent,
+// with the share of the total time spent on the issues.
+func getLabelStats(issues []types.LabelIssue) []labelStats {
+	byLabel := map[string]*labelStats{}
+	total := 0
+
+	for _, i := range issues {
+		total += i.Fields.TimeSpent
+
+		labels := i.Fields.Labels
+		if len(labels) == 0 {
+			labels = []string{noLabel}
+		}
+
+		for _, l := range labels {
+			if _, ok := byLabel[l]; !ok {
+				byLabel[l] = &labelStats{Label: l}
+			}
+
+			byLabel[l].Issues++
+			byLabel[l].TimeSpent += i.Fields.TimeSpent
+		}
+	}
+
+	stats := []labelStats{}
+
+	for _, l := range byLabel {
+		if total > 0 {
+			l.Share = float64(l.TimeSpent) / float64(total)
+		}
+
+		stats = append(stats, *l)
+	}
+
+	slices.SortFunc(stats, func(a, b labelStats) int {
+		switch {
+		case a.TimeSpent != b.TimeSpent:
+			return b.TimeSpent - a.TimeSpent
+		case a.Issues != b.Issues:
+			return b.Issues - a.Issues
+		}
+
+		return strings.Compare(a.Label, b.Label)
+	})
+
+	return stats
+}
+
+func printLabelStats(issues int, stats []labelStats) {
+	fmt.Printf("%d issues, %d labels\n\n", issues, len(stats))
+
+	fmt.Printf("%s%s%-30s %6s %10s %6s  %-*s%s%s\n", format.Color.Ul, format.Color.Yellow,
+		"Label", "Issues", "Spent", "Share", labelBarWidth, "",
+		format.Color.Nocolor, format.Color.Nocolor)
+
+	top := max(stats[0].TimeSpent, 1)
+
+	for _, l := range stats {
+		label := l.Label
+		truncateSummaries(30, &label)
+
+		bar := strings.Repeat("█", l.TimeSpent*labelBarWidth/top)
+
+		fmt.Printf("%-30s %6d %10s %5.1f%%  %s%s%s\n", label, l.Issues,
+			convert.SecondsToHoursAndMinutes(l.TimeSpent, false), l.Share*100,
+			format.Color.Blue, bar, format.Color.Nocolor)
+	}
+}
+
+// getYearReview sums up the issues resolved by the user and the time
+// the user logged in the year.
+func getYearReview(year int) yearReview {
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.Local)
+	to := from.AddDate(1, 0, 0)
+	during := func(field string) string {
+		return fmt.Sprintf(`%s >= "%s" AND %s < "%s"`, field, from.Format("2006-01-02"), field, to.Format("2006-01-02"))
+	}
+
+	review := yearReview{Year: year, User: Cfg.Username}
+
+	stop := spin(fmt.Sprintf("Fetching your work in %d", year))
+	defer stop()
+
+	resolved := must(JiraClient.GetReviewIssues(ctx,
+		"assignee = currentUser() AND "+during("resolved")))
+
+	review.Resolved = len(resolved)
+
+	days, n := 0.0, 0
+
+	for _, i := range resolved {
+		created, err := util.ParseJiraTime(i.Fields.Created)
+		if err != nil {
+			continue
+		}
+
+		done, err := util.ParseJiraTime(i.Fields.Resolved)
+		if err != nil {
+			continue
+		}
+
+		days += done.Sub(created).Hours() / 24
+		n++
+	}
+
+	if n > 0 {
+		review.CycleTime = days / float64(n)
+	}
+
+	issues := must(JiraClient.GetReviewIssues(ctx,
+		"worklogAuthor = currentUser() AND "+during("worklogDate")))
+
+	byProject, byEpic := map[string]int{}, map[string]int{}
+	logged := []time.Time{}
+
+	for _, i := range issues {
+		seconds := 0
+
+		for _, w := range must(JiraClient.GetWorklogs(ctx, i.Key)) {
+			started, err := util.ParseJiraTime(w.Started)
+			if err != nil || w.Author.Name != Cfg.Username || started.Before(from) || !started.Before(to) {
+				continue
+			}
+
+			seconds += w.TimeSpentSeconds
+			logged = append(logged, started.In(time.Local))
+		}
+
+		if seconds == 0 {
+			continue
+		}
+
+		review.TimeSpent += seconds
+		byProject[i.Fields.Project.Key] += seconds
+		byEpic[i.Fields.Epic] += seconds
+		review.Biggest = append(review.Biggest, reviewTime{Key: i.Key, Summary: i.Fields.Summary, TimeSpent: seconds})
+	}
+
+	first, last, streak := stats.LongestStreak(logged)
+	if streak > 0 {
+		review.Streak = reviewStreak{From: first.Format("2006-01-02"), To: last.Format("2006-01-02"), Days: streak}
+	}
+
+	dates := map[string]bool{}
+	for _, l := range logged {
+		dates[l.Format("2006-01-02")] = true
+	}
+
+	review.DaysLogged = len(dates)
+
+	for k, v := range byProject {
+		review.Projects = append(review.Projects, reviewTime{Key: k, TimeSpent: v})
+	}
+
+	for k, v := range byEpic {
+		review.Epics = append(review.Epics, reviewTime{Key: k, TimeSpent: v})
+	}
+
+	sortReviewTimes(review.Projects)
+	sortReviewTimes(review.Epics)
+	sortReviewTimes(review.Biggest)
+
+	review.Epics = review.Epics[:min(len(review.Epics), reviewTop)]
+	review.Biggest = review.Biggest[:min(len(review.Biggest), reviewTop)]
+
+	setEpicSummaries(review.Epics)
+
+	return review
+}
+
+// sortReviewTimes sorts by the time spent, with the most time first.
+func sortReviewTimes(times []reviewTime) {
+	slices.SortFunc(times, func(a, b reviewTime) int {
+		if a.TimeSpent != b.TimeSpent {
+			return b.TimeSpent - a.TimeSpent
+		}
+
+		return strings.Compare(a.Key, b.Key)
+	})
+}
+
+// setEpicSummaries looks up the summaries of the epics, and names
+// the time spent outside of epics.
+func setEpicSummaries(epics []reviewTime) {
+	keys := []string{}
+
+	for _, e := range epics {
+		if e.Key != "" {
+			keys = append(keys, e.Key)
+		}
+	}
+
+	summaries := map[string]string{"": "No epic"}
+
+	if len(keys) > 0 {
+		for _, e := range must(JiraClient.GetIssuesSelecting(ctx, "key in ("+strings.Join(keys, ",")+")",
+			"key", []string{"summary"})) {
+			summaries[e.Key] = e.Fields.Summary
+		}
+	}
+
+	for i := range epics {
+		epics[i].Summary = summaries[epics[i].Key]
+	}
+}
+
+func printYearReview(r yearReview) {
+	fmt.Printf("%sYear in review %d%s\n\n", format.Color.Green, r.Year, format.Color.Nocolor)
+
+	fmt.Printf("%-22s %d\n", "Issues resolved", r.Resolved)
+	fmt.Printf("%-22s %s on %d days\n", "Time logged",
+		convert.SecondsToHoursAndMinutes(r.TimeSpent, false), r.DaysLogged)
+
+	if r.Streak.Days > 0 {
+		fmt.Printf("%-22s %d days, %s - %s\n", "Longest streak", r.Streak.Days, r.Streak.From, r.Streak.To)
+	}
+
+	if r.CycleTime > 0 {
+		fmt.Printf("%-22s %.1f days\n", "Average cycle time", r.CycleTime)
+	}
+
+	if len(r.Projects) > 0 {
+		fmt.Printf("\n%s%s%-12s %10s %6s%s%s\n", format.Color.Ul, format.Color.Yellow,
+			"Project", "Spent", "Share", format.Color.Nocolor, format.Color.Nocolor)
+
+		for _, p := range r.Projects {
+			fmt.Printf("%-12s %10s %5.1f%%\n", p.Key,
+				convert.SecondsToHoursAndMinutes(p.TimeSpent, false),
+				float64(p.TimeSpent)/float64(r.TimeSpent)*100)
+		}
+	}
+
+	printReviewTimes("Epic", r.Epics, r.TimeSpent)
+	printReviewTimes("Issue", r.Biggest, r.TimeSpent)
+}
+
+func printReviewTimes(title string, times []reviewTime, total int) {
+	if len(times) == 0 {
+		return
+	}
+
+	fmt.Printf("\n%s%s%-12s %-40s %10s %6s%s%s\n", format.Color.Ul, format.Color.Yellow,
+		title, "Summary", "Spent", "Share", format.Color.Nocolor, format.Color.Nocolor)
+
+	for _, t := range times {
+		summary := t.Summary
+		truncateSummaries(40, &summary)
+
+		fmt.Printf("%-12s %-40s %10s %5.1f%%\n", t.Key, summary,
+			convert.SecondsToHoursAndMinutes(t.TimeSpent, false),
+			float64(t.TimeSpent)/float64(total)*100)
+	}
+}
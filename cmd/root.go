@@ -22,19 +22,27 @@ THE SOFTWARE.
 package cmd
 
 import (
+	"context"
+	"crypto/tls"
 	"fmt"
 	"os"
 	"os/exec"
 	"path"
 	"regexp"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	homedir "github.com/mitchellh/go-homedir"
 	"github.com/spf13/viper"
+	"golang.org/x/term"
 
 	"github.com/mhersson/gojira/pkg/jira"
+	"github.com/mhersson/gojira/pkg/recorder"
+	"github.com/mhersson/gojira/pkg/types"
+	"github.com/mhersson/gojira/pkg/util/format"
 )
 
 var rootCmdLong = `The Gojira JIRA client
@@ -80,9 +88,14 @@ var rootCmd = &cobra.Command{
 }
 
 func Execute() {
+	var cancel context.CancelFunc
+
+	ctx, cancel = cancelOnInterrupt(context.Background())
+	defer cancel()
+
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
-		os.Exit(1)
+		os.Exit(exitUsage)
 	}
 }
 
@@ -90,10 +103,38 @@ func init() {
 	cobra.OnInitialize(initConfig)
 
 	rootCmd.Flags().BoolVar(&VersionFlag, "version", false, "Print version information")
+	rootCmd.PersistentFlags().BoolVar(&ReadOnlyFlag, "read-only", false, "block all commands that make changes in Jira")
+	rootCmd.PersistentFlags().BoolVarP(&AssumeYes, "yes", "y", false, "answer yes to all confirmations")
+	rootCmd.PersistentFlags().BoolVar(&FullSummary, "full", false, "do not truncate summaries in tables")
+	rootCmd.PersistentFlags().BoolVar(&Wide, "wide", false, "do not truncate any columns in tables")
+	rootCmd.PersistentFlags().IntVar(&TruncateSummary, "truncate", 0, "truncate summaries in tables at this length")
+	rootCmd.PersistentFlags().StringVarP(&OutputFormat, "output", "o", "",
+		"output format, e.g. json, yaml, csv or exec:FORMATTER, for the commands supporting it")
+	rootCmd.PersistentFlags().DurationVar(&RequestTimeout, "timeout", 0,
+		"give up on requests to Jira not answered within this time, e.g. 30s")
+	rootCmd.PersistentFlags().StringVar(&RecordFile, "record", "",
+		"record all requests and responses to this file, e.g. to attach to a bug report")
+	rootCmd.PersistentFlags().StringVar(&ReplayFile, "replay", "",
+		"answer the requests with the responses recorded with --record, instead of asking Jira")
+	rootCmd.PersistentFlags().StringVar(&Debug, "debug", "",
+		"log the requests to Jira to stderr, with the headers and bodies with --debug=body")
+	rootCmd.PersistentFlags().Lookup("debug").NoOptDefVal = "requests"
+	rootCmd.PersistentFlags().StringVar(&ConfigPath, "config", "",
+		"read the config from this file instead of config.yaml in the config folder")
+	rootCmd.PersistentFlags().StringVar(&ContextName, "context", "",
+		"use the Jira server of this profile instead of the active context")
+	rootCmd.PersistentFlags().BoolVar(&NoColor, "no-color", false,
+		"print without colors, also set by NO_COLOR or when stdout is not a terminal")
+	rootCmd.PersistentFlags().BoolVar(&NoPager, "no-pager", false,
+		"do not show long output in $PAGER, or less, e.g. of describe and get sprint")
+	rootCmd.PersistentFlags().BoolVarP(&Quiet, "quiet", "q", false,
+		"skip the success messages, headers and spinners, e.g. in scripts")
 }
 
 func initConfig() {
-	home := getHomeFolder()
+	if !useColors() {
+		format.DisableColors()
+	}
 
 	ex, err := os.Executable()
 	if err != nil {
@@ -102,23 +143,71 @@ func initConfig() {
 
 	exedir := path.Dir(ex)
 
-	viper.AddConfigPath(path.Join(home, ".config/gojira"))
-	viper.AddConfigPath(exedir)
-	viper.SetConfigName("config")
+	if ConfigPath != "" {
+		viper.SetConfigFile(ConfigPath)
+	} else if viper.ConfigFileUsed() == "" {
+		// Setting the config name clears a config file already chosen
+		viper.AddConfigPath(ConfigFolder)
+		viper.AddConfigPath(exedir)
+		viper.SetConfigName("config")
+	}
 
 	// Set some default values
 	Cfg.NumWorkingDays = 5
 	Cfg.WorkingHoursPerDay = 7.5
 	Cfg.WorkingHoursPerWeek = 37.5
+	Cfg.Deployment = "auto"
+	Cfg.ExistsCacheTTL = 10 * time.Minute
+	Cfg.MaxAttempts = 3
+	Cfg.TakeStatus = "In Progress"
+	Cfg.DoneStatus = "Done"
+	Cfg.Confirm = types.ConfirmConfig{Transitions: true, Bulk: true, Deletes: true}
+
+	readEnv(viper.GetViper())
+
+	// Without a config file the environment is enough if it has the url
+	err = viper.ReadInConfig()
+
+	// A config file given with --config may not be written yet, e.g. by config init
+	if _, statErr := os.Stat(ConfigPath); err != nil && ConfigPath != "" && statErr == nil {
+		fmt.Printf("Failed to read %s - %s\n", ConfigPath, err.Error())
+		os.Exit(exitCode(err))
+	}
 
-	if err := viper.ReadInConfig(); err == nil {
+	if err == nil || viper.IsSet("JiraURL") {
 		Cfg.JiraURL = viper.GetString("JiraURL")
 		Cfg.Username = viper.GetString("username")
 		Cfg.Password = viper.GetString("password")
 		Cfg.PasswordType = viper.GetString("passwordtype")
+		Cfg.PasswordCache = viper.GetDuration("passwordCache")
+		Cfg.AuthMode = strings.ToLower(viper.GetString("authMode"))
+		Cfg.CACert, _ = homedir.Expand(viper.GetString("caCert"))
+		Cfg.ClientCert, _ = homedir.Expand(viper.GetString("clientCert"))
+		Cfg.ClientKey, _ = homedir.Expand(viper.GetString("clientKey"))
+		Cfg.InsecureSkipVerify = viper.GetBool("insecureSkipVerify")
 		Cfg.UseTimesheetPlugin = viper.GetBool("useTimesheetPlugin")
 		Cfg.CheckForUpdates = viper.GetBool("checkForUpdates")
 		Cfg.SprintFilter = viper.GetString("sprintFilter")
+		Cfg.ParticipantsField = viper.GetString("participantsField")
+		Cfg.TimerMax = viper.GetDuration("timerMax")
+
+		if viper.IsSet("existsCacheTTL") {
+			Cfg.ExistsCacheTTL = viper.GetDuration("existsCacheTTL")
+		}
+
+		if i := viper.GetInt("maxAttempts"); i > 0 {
+			Cfg.MaxAttempts = i
+		}
+
+		Cfg.ReadOnly = viper.GetBool("readOnly")
+		Cfg.FullSummary = viper.GetBool("fullSummary")
+		Cfg.TruncateSummary = viper.GetInt("truncateSummary")
+		Cfg.RawMarkup = viper.GetBool("rawMarkup")
+		Cfg.EditMarkdown = viper.GetBool("editMarkdown")
+
+		if d := viper.GetString("deployment"); d != "" {
+			Cfg.Deployment = strings.ToLower(d)
+		}
 
 		if i := viper.GetInt("numberOfWorkingDays"); i > 0 {
 			Cfg.NumWorkingDays = i
@@ -133,12 +222,58 @@ func initConfig() {
 		}
 
 		Cfg.CountryCode = viper.GetString("countryCode")
+		Cfg.WeekNumbering = strings.ToLower(viper.GetString("weekNumbering"))
 
 		Cfg.Aliases = viper.GetStringMapString("aliases")
+		Cfg.EscalationWatchers = viper.GetStringSlice("escalationWatchers")
+		Cfg.AssigneeRules = viper.GetStringMapString("assigneeRules")
+
+		if s := viper.GetString("takeStatus"); s != "" {
+			Cfg.TakeStatus = s
+		}
+
+		if s := viper.GetString("doneStatus"); s != "" {
+			Cfg.DoneStatus = s
+		}
 
-		if Cfg.JiraURL[len(Cfg.JiraURL)-1:] == "/" {
-			Cfg.JiraURL = Cfg.JiraURL[:len(Cfg.JiraURL)-1]
+		Cfg.DoneResolution = viper.GetString("doneResolution")
+
+		for key, value := range map[string]*bool{
+			"confirm.transitions": &Cfg.Confirm.Transitions,
+			"confirm.bulk":        &Cfg.Confirm.Bulk,
+			"confirm.deletes":     &Cfg.Confirm.Deletes,
+		} {
+			if viper.IsSet(key) {
+				*value = viper.GetBool(key)
+			}
 		}
+
+		Cfg.Confirm.WorklogHours = viper.GetFloat64("confirm.worklogHours")
+		Cfg.ActiveIssue.MaxDays = viper.GetInt("activeIssue.maxDays")
+		Cfg.ActiveIssue.Resolved = viper.GetBool("activeIssue.resolved")
+		Cfg.ActiveIssue.WhenStale = strings.ToLower(viper.GetString("activeIssue.whenStale"))
+
+		// The profile names are lower case, as all keys read by viper
+		if err := viper.UnmarshalKey("profiles", &Cfg.Profiles); err != nil {
+			fmt.Printf("Failed to read the profiles - %s\n", err.Error())
+			os.Exit(exitCode(err))
+		}
+
+		Cfg.InsightFields = viper.GetStringSlice("insightFields")
+		Cfg.Mail.From = viper.GetString("mail.from")
+		Cfg.Mail.SMTPServer = viper.GetString("mail.smtpServer")
+		Cfg.Mail.Username = viper.GetString("mail.username")
+		Cfg.Mail.Password = viper.GetString("mail.password")
+		Cfg.Mail.PasswordType = viper.GetString("mail.passwordtype")
+		Cfg.Mail.Sendmail = viper.GetString("mail.sendmail")
+
+		Cfg.JiraURL = strings.TrimSuffix(Cfg.JiraURL, "/")
+	}
+
+	useContext()
+
+	if ReadOnlyFlag {
+		Cfg.ReadOnly = true
 	}
 
 	if GojiraGitRevision != "" && Cfg.CheckForUpdates {
@@ -146,14 +281,167 @@ func initConfig() {
 		getLatestRevision(revs)
 	}
 
-	jira.Configure(Cfg)
+	// A trace must have every request, not only those missing in the cache
+	if RecordFile != "" || ReplayFile != "" {
+		Cfg.ExistsCacheTTL = 0
+	}
+
+	var replay *recorder.Replayer
+
+	if ReplayFile != "" {
+		replay = loadReplay()
+	}
+
+	tlsConfig := loadTLSConfig()
+
+	JiraClient.Configure(Cfg)
+	JiraClient.SetTLSConfig(tlsConfig)
+	JiraClient.SetExistsCache(ExistsCacheFile, Cfg.ExistsCacheTTL)
+	JiraClient.SetTimeout(RequestTimeout)
+	JiraClient.SetMaxAttempts(Cfg.MaxAttempts)
+
+	if RecordFile != "" {
+		transport := jira.NewTransport()
+		transport.TLSClientConfig = tlsConfig
+
+		rec, err := recorder.New(transport, RecordFile)
+		if err != nil {
+			fmt.Printf("Failed to create %s - %s\n", RecordFile, err.Error())
+			os.Exit(exitCode(err))
+		}
+
+		JiraClient.SetTransport(rec)
+	}
+
+	if replay != nil {
+		JiraClient.SetTransport(replay)
+	}
+
+	logRequests(JiraClient)
+}
+
+// loadReplay loads the trace given with --replay, and changes the config
+// to replay it without the Jira server and the credentials it was recorded
+// with. The trace tells if it was recorded with Jira Cloud.
+func loadReplay() *recorder.Replayer {
+	if RecordFile != "" {
+		fmt.Println("--record and --replay can not be used together")
+		os.Exit(exitUsage)
+	}
+
+	replay, err := recorder.Load(ReplayFile)
+	if err != nil {
+		fmt.Printf("Failed to load %s - %s\n", ReplayFile, err.Error())
+		os.Exit(exitCode(err))
+	}
+
+	if Cfg.JiraURL == "" {
+		Cfg.JiraURL = "https://jira.example.com"
+	}
+
+	Cfg.Password = "replay"
+	Cfg.PasswordType = "pat"
+	Cfg.AuthMode = ""
+	Cfg.Deployment = "server"
+
+	if slices.ContainsFunc(replay.Unused(), func(e recorder.Exchange) bool {
+		return strings.HasPrefix(e.URL, "/rest/api/3/")
+	}) {
+		Cfg.Deployment = "cloud"
+	}
+
+	return replay
+}
+
+// useColors returns if the output can be colored, which it can not with
+// --no-color, with NO_COLOR set (see https://no-color.org) or when
+// stdout is a file or a pipe.
+func useColors() bool {
+	if NoColor || os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+
+	return term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// logRequests makes the client log the requests to stderr with --debug.
+func logRequests(c *jira.Client) {
+	switch Debug {
+	case "":
+	case "requests", "body":
+		c.SetTransport(recorder.NewLogger(c.Transport(), os.Stderr, Debug == "body"))
+	default:
+		fmt.Println("--debug must be requests or body")
+		os.Exit(exitUsage)
+	}
+}
+
+// loadTLSConfig returns the certificates to use with Jira,
+// or nil to use the defaults.
+func loadTLSConfig() *tls.Config {
+	config, err := jira.TLSConfig(Cfg)
+	if err != nil {
+		fmt.Printf("Failed to set up TLS - %s\n", err.Error())
+		os.Exit(exitCode(err))
+	}
+
+	return config
+}
+
+// readEnv lets the environment override the config file. Every option can
+// be set with GOJIRA_ and the key in upper case, with the dots replaced by
+// underscores, e.g. GOJIRA_PASSWORDTYPE and GOJIRA_MAIL_FROM.
+func readEnv(v *viper.Viper) {
+	v.SetEnvPrefix("gojira")
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+
+	_ = v.BindEnv("JiraURL", "GOJIRA_URL", "GOJIRA_JIRAURL")
+}
+
+// configFolder returns the folder of the config file and the templates,
+// in $XDG_CONFIG_HOME if it is set.
+func configFolder() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return path.Join(dir, "gojira")
+	}
+
+	return path.Join(getHomeFolder(), ".config/gojira")
+}
+
+// legacyState is the state kept in the config folder
+// before the state got a folder of its own.
+var legacyState = []string{
+	"issue", "issuetype", "board", "boards", "timer", "budgets.json", "cache", "snapshots", "context", "contexts",
+}
+
+// stateFolder returns the folder of the active issue, the timer and the
+// rest of the state changed by gojira, in $XDG_STATE_HOME if it is set.
+// State already in the config folder is used there until it is moved.
+func stateFolder(configFolder string) string {
+	folder := path.Join(getHomeFolder(), ".local/state/gojira")
+	if dir := os.Getenv("XDG_STATE_HOME"); dir != "" {
+		folder = path.Join(dir, "gojira")
+	}
+
+	if _, err := os.Stat(folder); err == nil {
+		return folder
+	}
+
+	for _, name := range legacyState {
+		if _, err := os.Stat(path.Join(configFolder, name)); err == nil {
+			return configFolder
+		}
+	}
+
+	return folder
 }
 
 func getHomeFolder() string {
 	home, err := homedir.Dir()
 	if err != nil {
 		fmt.Println(err)
-		os.Exit(1)
+		os.Exit(exitCode(err))
 	}
 
 	return home
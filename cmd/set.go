@@ -38,6 +38,11 @@ just tells Gojira that this is your active issue, the one you are currently
 working on. Setting an issue as active removes the need of specifying an
 issueKey with (almost) every command
 
+With --local the issue is only active in the working directory, or in the
+whole git repository when the directory is in one, like direnv. Switching
+between checkouts then switches the active issue. The issues set active,
+e.g. by take, in a directory with a local active issue replace it there.
+
 The same goes for setting a board as active. It marks the given board as your
 board of interest, and will be used by the get sprint or kanban commands when
 no other board name is specified
@@ -55,8 +60,16 @@ Available Commands:
 
 Flags:
   -h, --help                   help for comment
+  -l, --local                  set the issue active in the working directory or git repository only
+
+Example:
+  gojira set active issue OSE-1234
+  gojira set active issue OSE-1234 --local
 `
 
+// Used by `set active issue` to set the issue active in the working directory
+var LocalIssue bool
+
 var setCmd = &cobra.Command{
 	Use:   "set",
 	Short: "Set issue or board active",
@@ -76,7 +89,8 @@ var setActiveIssueCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		IssueKey = strings.ToUpper(args[0])
 		setActiveIssue(IssueKey)
-		key := util.GetActiveIssue(IssueFile)
+		key, err := util.GetActiveIssue(IssueFile)
+		exitIfNotSet(err)
 		fmt.Printf("Issue %s is active\n", key)
 	},
 }
@@ -114,35 +128,58 @@ func init() {
 	setActiveCmd.AddCommand(setActiveIssueCmd)
 	setActiveCmd.AddCommand(setActiveSprintCmd)
 	setActiveCmd.AddCommand(setActiveKanbanCmd)
+
+	setActiveIssueCmd.Flags().BoolVarP(&LocalIssue, "local", "l", false,
+		"set the issue active in the working directory or git repository only")
 }
 
 func setActiveIssue(key string) {
-	issues := jira.GetIssues("key = " + key)
+	issues := must(JiraClient.GetIssuesSelecting(ctx, "key = "+key, jira.OrderByPriority, jira.BriefFields))
 	if len(issues) != 1 {
 		fmt.Printf("Issue %s does not exist, and can not be set active\n", key)
-		os.Exit(1)
+		os.Exit(exitNotFound)
+	}
+
+	// Once an issue is active in the working directory,
+	// the issues set active there replace it
+	file := util.ActiveIssueFile(IssueFile)
+
+	if LocalIssue {
+		wd, err := os.Getwd()
+		exitOnError(err)
+
+		file = util.LocalIssueFile(wd)
 	}
 
-	createConfigFolder()
+	if file != IssueFile {
+		if err := os.WriteFile(file, []byte(key), 0o600); err != nil {
+			fmt.Printf("Failed to set %s active - %s\n", key, err.Error())
+			os.Exit(exitCode(err))
+		}
+
+		return
+	}
+
+	createStateFolder()
 
 	err := os.WriteFile(IssueFile, []byte(key), 0o600)
 	if err != nil {
 		fmt.Printf("Failed to set %s active\n", key)
-		os.Exit(1)
+		os.Exit(exitFailure)
 	}
 
 	err = os.WriteFile(IssueTypeFile,
 		[]byte(issues[0].Fields.IssueType.ID), 0o600)
 	if err != nil {
 		fmt.Printf("Failed to set %s active\n", key)
-		os.Exit(1)
+		os.Exit(exitFailure)
 	}
 }
 
 func setActiveBoard(board, boardType string) {
-	if id := jira.GetRapidViewID(board); id == nil {
+	if id := must(JiraClient.GetRapidViewID(ctx, board)); id == nil {
 		fmt.Printf("Board %s does not exist, and can not be set active\n", board)
-		os.Exit(1)
+		os.Exit(exitNotFound)
 	}
 
 	var content []byte
@@ -151,7 +188,7 @@ func setActiveBoard(board, boardType string) {
 		content, err = os.ReadFile(BoardFile)
 		if err != nil {
 			fmt.Println("Failed to read existing board config")
-			os.Exit(1)
+			os.Exit(exitFailure)
 		}
 
 		p := regexp.MustCompile(boardType + `=(.*)`)
@@ -163,25 +200,29 @@ func setActiveBoard(board, boardType string) {
 		}
 
 	} else {
-		createConfigFolder()
+		createStateFolder()
 		content = []byte(boardType + "=" + board + "\n")
 	}
 
 	err := os.WriteFile(BoardFile, content, 0o600)
 	if err != nil {
 		fmt.Printf("Failed to set %s active\n", board)
-		os.Exit(1)
+		os.Exit(exitFailure)
 	}
 }
 
-func createConfigFolder() {
-	_, err := os.Stat(ConfigFolder)
+func createStateFolder() {
+	createFolder(StateFolder)
+}
+
+func createFolder(folder string) {
+	_, err := os.Stat(folder)
 
 	if os.IsNotExist(err) {
-		err := os.Mkdir(ConfigFolder, 0o755)
+		err := os.MkdirAll(folder, 0o755)
 		if err != nil {
 			fmt.Println(err)
-			os.Exit(1)
+			os.Exit(exitCode(err))
 		}
 	}
 }
@@ -0,0 +1,94 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mhersson/gojira/pkg/types"
+)
+
+const showUsage string = `This command prints the issue on a single line, which makes
+it well suited for scripts, status bars and shell prompts.
+
+By default the active issue is shown,
+but this can be changed by adding the issue key as argument.
+
+Usage:
+  gojira show [ISSUE KEY] [flags]
+
+Aliases:
+  show, sh
+
+Flags:
+  -h, --help                   help for show
+
+Example output:
+  GOJIRA-1 [Bug/Critical] In Progress (John Doe) Fix the flux capacitor
+`
+
+var showCmd = &cobra.Command{
+	Use:     "show",
+	Short:   "Display issue on a single line",
+	Aliases: []string{"sh"},
+	Args:    cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 1 {
+			IssueKey = strings.ToUpper(args[0])
+		}
+		checkIssueKey(&IssueKey, IssueFile)
+
+		issues := must(JiraClient.GetIssues(ctx, "key = "+IssueKey))
+		if len(issues) != 1 {
+			fmt.Printf("Issue %s does not exist\n", IssueKey)
+			os.Exit(exitNotFound)
+		}
+
+		fmt.Println(issueOneLine(issues[0]))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(showCmd)
+
+	showCmd.SetUsageTemplate(showUsage)
+}
+
+func issueOneLine(issue types.Issue) string {
+	assignee := issue.Fields.Assignee.DisplayName
+	if assignee == "" {
+		assignee = "Unassigned"
+	}
+
+	return fmt.Sprintf("%s [%s/%s] %s (%s) %s",
+		issue.Key,
+		issue.Fields.IssueType.Name,
+		issue.Fields.Priority.Name,
+		issue.Fields.Status.Name,
+		assignee,
+		issue.Fields.Summary)
+}
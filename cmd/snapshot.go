@@ -0,0 +1,325 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mhersson/gojira/pkg/jira"
+	"github.com/mhersson/gojira/pkg/util/format"
+	"github.com/mhersson/gojira/pkg/util/snapshot"
+)
+
+const snapshotUsage string = `Save the issues matching a filter as a named snapshot, and compare
+it with the current issues later, e.g. to show what changed during
+a sprint in the retrospective. The diff lists the issues that are new,
+removed or have changed status since the snapshot was saved.
+
+By default the snapshot holds the issues in the active sprint of the
+active sprint board, but this can be changed with --filter. The filter
+is saved with the snapshot, and used again by diff.
+
+Usage:
+  gojira snapshot save <NAME> [flags]
+  gojira snapshot diff <NAME>
+  gojira snapshot list
+  gojira snapshot remove <NAME>
+
+Available Commands:
+  diff        Compare a snapshot with the current issues
+  list        List the saved snapshots
+  remove      Remove a snapshot
+  save        Save the issues matching the filter as a snapshot
+
+Flags:
+  -f, --filter [JQL FILTER]    the issues to save (save)
+      --force                  overwrite an existing snapshot (save)
+  -h, --help                   help for snapshot
+
+Example:
+  gojira snapshot save sprint-start
+  gojira snapshot diff sprint-start
+`
+
+// Used by `snapshot save`.
+var SnapshotForce bool
+
+var snapshotCmd = &cobra.Command{
+	Use:     "snapshot",
+	Short:   "Save and compare snapshots of issue lists",
+	Aliases: []string{"snap"},
+	Args:    cobra.NoArgs,
+}
+
+var snapshotSaveCmd = &cobra.Command{
+	Use:     "save",
+	Short:   "Save the issues matching the filter as a snapshot",
+	Aliases: []string{"s"},
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[0]
+		checkSnapshotName(name)
+
+		if _, err := os.Stat(snapshotFile(name)); err == nil && !SnapshotForce {
+			fmt.Printf("Snapshot %s already exists, use --force to overwrite it\n", name)
+			os.Exit(exitFailure)
+		}
+
+		filter := JQLFilter
+		if filter == "" {
+			filter = activeSprintFilter()
+		}
+
+		s := snapshot.Snapshot{
+			Name:    name,
+			Filter:  filter,
+			Created: time.Now(),
+			Issues:  currentSnapshotIssues(filter),
+		}
+
+		saveSnapshot(s)
+
+		success("Saved snapshot %s with %d issues", name, len(s.Issues))
+	},
+}
+
+var snapshotDiffCmd = &cobra.Command{
+	Use:     "diff",
+	Short:   "Compare a snapshot with the current issues",
+	Aliases: []string{"d"},
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		s := loadSnapshot(args[0])
+
+		fmt.Printf("Snapshot %s saved %s, filter: %s\n\n", s.Name, s.Created.Format("2006-01-02 15:04"), s.Filter)
+
+		printSnapshotChanges(snapshot.Diff(s.Issues, currentSnapshotIssues(s.Filter)))
+	},
+}
+
+var snapshotListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List the saved snapshots",
+	Aliases: []string{"ls"},
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		files, _ := filepath.Glob(filepath.Join(SnapshotFolder, "*.json"))
+		if len(files) == 0 {
+			fmt.Println("There are no snapshots")
+
+			return
+		}
+
+		snapshots := []snapshot.Snapshot{}
+		for _, f := range files {
+			snapshots = append(snapshots, loadSnapshot(strings.TrimSuffix(filepath.Base(f), ".json")))
+		}
+
+		slices.SortFunc(snapshots, func(a, b snapshot.Snapshot) int {
+			return a.Created.Compare(b.Created)
+		})
+
+		fmt.Printf("%s%s%-25s%-18s%-8s%s%s%s\n", format.Color.Ul, format.Color.Yellow,
+			"Name", "Saved", "Issues", "Filter", format.Color.Nocolor, format.Color.Nocolor)
+
+		for _, s := range snapshots {
+			fmt.Printf("%-25s%-18s%-8d%s\n", s.Name, s.Created.Format("2006-01-02 15:04"), len(s.Issues), s.Filter)
+		}
+	},
+}
+
+var snapshotRemoveCmd = &cobra.Command{
+	Use:     "remove",
+	Short:   "Remove a snapshot",
+	Aliases: []string{"rm"},
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		checkSnapshotName(args[0])
+
+		if err := os.Remove(snapshotFile(args[0])); err != nil {
+			fmt.Printf("Failed to remove snapshot %s - %s\n", args[0], err.Error())
+			os.Exit(exitCode(err))
+		}
+
+		fmt.Printf("Removed snapshot %s\n", args[0])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(snapshotCmd)
+
+	snapshotCmd.SetUsageTemplate(snapshotUsage)
+	snapshotCmd.AddCommand(snapshotSaveCmd)
+	snapshotCmd.AddCommand(snapshotDiffCmd)
+	snapshotCmd.AddCommand(snapshotListCmd)
+	snapshotCmd.AddCommand(snapshotRemoveCmd)
+
+	snapshotSaveCmd.SetUsageTemplate(snapshotUsage)
+	snapshotSaveCmd.Flags().StringVarP(&JQLFilter, "filter", "f", "", "the issues to save")
+	snapshotSaveCmd.Flags().BoolVarP(&SnapshotForce, "force", "", false, "overwrite an existing snapshot")
+
+	snapshotDiffCmd.SetUsageTemplate(snapshotUsage)
+	snapshotListCmd.SetUsageTemplate(snapshotUsage)
+	snapshotRemoveCmd.SetUsageTemplate(snapshotUsage)
+}
+
+func checkSnapshotName(name string) {
+	if !regexp.MustCompile(`^[A-Za-z0-9_.-]+$`).MatchString(name) {
+		fmt.Println("Invalid snapshot name, use only letters, digits, dots, dashes and underscores")
+		os.Exit(exitUsage)
+	}
+}
+
+func snapshotFile(name string) string {
+	return filepath.Join(SnapshotFolder, name+".json")
+}
+
+// activeSprintFilter returns a filter matching the issues in the
+// active sprint of the active sprint board.
+func activeSprintFilter() string {
+	board := activeBoard("sprint")
+
+	rapidView := must(JiraClient.GetRapidViewID(ctx, board))
+	if rapidView == nil || !rapidView.SprintSupportEnabled {
+		fmt.Printf("%s does not exist or sprint support is not enabled\n", board)
+		os.Exit(exitNotFound)
+	}
+
+	for _, s := range getOpenSprints(rapidView) {
+		if s.State == "ACTIVE" && s.MatchesFilter(Cfg.SprintFilter) {
+			return "sprint = " + strconv.Itoa(s.ID)
+		}
+	}
+
+	fmt.Printf("There is no active sprint on %s, use --filter to select the issues\n", board)
+	os.Exit(exitFailure)
+
+	return ""
+}
+
+func currentSnapshotIssues(filter string) []snapshot.Issue {
+	issues := []snapshot.Issue{}
+
+	for _, i := range must(JiraClient.GetIssuesSelecting(ctx, filter, jira.OrderByPriority, jira.BriefFields)) {
+		issues = append(issues, snapshot.Issue{Key: i.Key, Summary: i.Fields.Summary, Status: i.Fields.Status.Name})
+	}
+
+	return issues
+}
+
+func loadSnapshot(name string) snapshot.Snapshot {
+	checkSnapshotName(name)
+
+	s := snapshot.Snapshot{}
+
+	content, err := os.ReadFile(snapshotFile(name))
+	if os.IsNotExist(err) {
+		fmt.Printf("Snapshot %s does not exist\n", name)
+		os.Exit(exitNotFound)
+	}
+
+	if err == nil {
+		err = json.Unmarshal(content, &s)
+	}
+
+	if err != nil {
+		fmt.Printf("Failed to read snapshot %s - %s\n", name, err.Error())
+		os.Exit(exitCode(err))
+	}
+
+	return s
+}
+
+func saveSnapshot(s snapshot.Snapshot) {
+	content, _ := json.MarshalIndent(s, "", "  ")
+
+	err := os.MkdirAll(SnapshotFolder, 0o700)
+	if err == nil {
+		err = os.WriteFile(snapshotFile(s.Name), content, 0o600)
+	}
+
+	if err != nil {
+		fmt.Printf("Failed to save snapshot %s - %s\n", s.Name, err.Error())
+		os.Exit(exitCode(err))
+	}
+}
+
+func printSnapshotChanges(changes []snapshot.Change) {
+	if len(changes) == 0 {
+		fmt.Println("No changes since the snapshot was saved")
+
+		return
+	}
+
+	summaries := []*string{}
+	for i := range changes {
+		summaries = append(summaries, &changes[i].Issue.Summary)
+	}
+
+	width := truncateSummaries(summaryLength(65), summaries...)
+
+	fmt.Printf("%s%s%-10s%-15s%-*s%s%s%s\n", format.Color.Ul, format.Color.Yellow,
+		"Change", "Key", width, "Summary", "Status", format.Color.Nocolor, format.Color.Nocolor)
+
+	for _, c := range changes {
+		color := format.Color.Nocolor
+
+		switch c.Kind {
+		case snapshot.Added:
+			color = format.Color.Green
+		case snapshot.Removed:
+			color = format.Color.Red
+		}
+
+		status := c.Issue.Status
+		if c.Kind == snapshot.StatusChanged {
+			status = c.From + " -> " + c.Issue.Status
+		}
+
+		fmt.Printf("%s%-10s%s%-15s%-*s%s\n", color, c.Kind, format.Color.Nocolor, c.Issue.Key, width, c.Issue.Summary, status)
+	}
+
+	added, removed, changed := 0, 0, 0
+
+	for _, c := range changes {
+		switch c.Kind {
+		case snapshot.Added:
+			added++
+		case snapshot.Removed:
+			removed++
+		default:
+			changed++
+		}
+	}
+
+	fmt.Printf("\n%d new, %d removed, %d changed status\n", added, removed, changed)
+}
@@ -0,0 +1,84 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// spinnerDelay is how long to wait before showing the spinner,
+// so it does not flicker on the requests answered quickly.
+const spinnerDelay = 500 * time.Millisecond
+
+const spinnerFrames = `|/-\`
+
+// spin shows a spinner with the message and the elapsed time on stderr
+// until stop is called, so slow requests do not appear hung. Nothing is
+// shown with --quiet, or if stderr is not a terminal.
+func spin(message string) (stop func()) {
+	if Quiet || !term.IsTerminal(int(os.Stderr.Fd())) {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+
+		started := time.Now()
+		ticker := time.NewTicker(100 * time.Millisecond)
+		shown := false
+
+		defer ticker.Stop()
+
+		for frame := 0; ; frame++ {
+			select {
+			case <-done:
+				if shown {
+					fmt.Fprint(os.Stderr, "\r\033[K")
+				}
+
+				return
+			case now := <-ticker.C:
+				elapsed := now.Sub(started)
+				if elapsed < spinnerDelay {
+					continue
+				}
+
+				shown = true
+
+				fmt.Fprintf(os.Stderr, "\r%c %s (%ds)",
+					spinnerFrames[frame%len(spinnerFrames)], message, int(elapsed.Seconds()))
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
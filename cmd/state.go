@@ -0,0 +1,299 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+const stateUsage string = `Export and import the local state of gojira, e.g. when moving
+to a new laptop. The state is everything in the gojira state folder,
+like the active issue and board, favourite boards, the work timer,
+budgets and the issue cache, and the templates in the config folder.
+
+The config file, which holds your aliases and your password, is only
+included when exporting with --include-config. When importing, existing
+files are kept unless --force is used.
+
+Usage:
+  gojira state export <FILE> [flags]
+  gojira state import <FILE> [flags]
+
+Available Commands:
+  export      Export the local state to a gzipped tar file
+  import      Import the local state from a gzipped tar file
+
+Flags:
+  -f, --force                  overwrite existing files (import)
+  -h, --help                   help for state
+  -c, --include-config         include the config file (export)
+
+Example:
+  gojira state export gojira.tgz --include-config
+  gojira state import gojira.tgz
+`
+
+// The name of the config file in the archive.
+const stateConfigFile = "config.yaml"
+
+// Used by the state commands.
+var (
+	StateIncludeConfig bool
+	StateForce         bool
+)
+
+var stateCmd = &cobra.Command{
+	Use:   "state",
+	Short: "Export and import the local state",
+	Args:  cobra.NoArgs,
+}
+
+var stateExportCmd = &cobra.Command{
+	Use:     "export",
+	Short:   "Export the local state to a gzipped tar file",
+	Aliases: []string{"e"},
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		files, err := exportState(args[0], StateIncludeConfig)
+		if err != nil {
+			fmt.Printf("Failed to export the state - %s\n", err.Error())
+			os.Exit(exitCode(err))
+		}
+
+		success("Exported %d files to %s", files, args[0])
+
+		if StateIncludeConfig {
+			fmt.Println("The export includes the config file, keep it safe")
+		}
+	},
+}
+
+var stateImportCmd = &cobra.Command{
+	Use:     "import",
+	Short:   "Import the local state from a gzipped tar file",
+	Aliases: []string{"i"},
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		imported, skipped, err := importState(args[0], StateForce)
+		if err != nil {
+			fmt.Printf("Failed to import the state - %s\n", err.Error())
+			os.Exit(exitCode(err))
+		}
+
+		for _, s := range skipped {
+			fmt.Printf("Skipped %s, it already exists\n", s)
+		}
+
+		success("Imported %d files from %s", imported, args[0])
+
+		if len(skipped) > 0 {
+			fmt.Println("Use --force to overwrite the existing files")
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(stateCmd)
+
+	stateCmd.SetUsageTemplate(stateUsage)
+	stateCmd.AddCommand(stateExportCmd)
+	stateCmd.AddCommand(stateImportCmd)
+
+	stateExportCmd.SetUsageTemplate(stateUsage)
+	stateExportCmd.Flags().BoolVarP(&StateIncludeConfig, "include-config", "c", false, "include the config file")
+
+	stateImportCmd.SetUsageTemplate(stateUsage)
+	stateImportCmd.Flags().BoolVarP(&StateForce, "force", "f", false, "overwrite existing files")
+}
+
+// The folder of the templates in the archive.
+const stateTemplateFolder = "templates"
+
+// isConfigFile returns true if the path, relative to the state
+// folder, is a config file read by viper. The state is kept in the
+// config folder by those who had it there before the state folder.
+func isConfigFile(name string) bool {
+	ext := filepath.Ext(name)
+
+	return !strings.Contains(name, "/") && strings.TrimSuffix(name, ext) == "config"
+}
+
+// exportState writes the files in the state folder and the templates to
+// a gzipped tar file, and returns the number of files written.
+func exportState(filename string, includeConfig bool) (int, error) {
+	out, err := os.Create(filename)
+	if err != nil {
+		return 0, err
+	}
+	defer out.Close()
+
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+	files := 0
+
+	err = filepath.WalkDir(StateFolder, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+
+		name, _ := filepath.Rel(StateFolder, p)
+		name = filepath.ToSlash(name)
+
+		if isConfigFile(name) {
+			return nil
+		}
+
+		files++
+
+		return addFileToTar(tw, p, name)
+	})
+
+	// The templates are already exported if the state is in the config folder
+	if _, statErr := os.Stat(TemplateFolder); err == nil && statErr == nil && StateFolder != ConfigFolder {
+		err = filepath.WalkDir(TemplateFolder, func(p string, d fs.DirEntry, err error) error {
+			if err != nil || !d.Type().IsRegular() {
+				return err
+			}
+
+			name, _ := filepath.Rel(TemplateFolder, p)
+
+			files++
+
+			return addFileToTar(tw, p, stateTemplateFolder+"/"+filepath.ToSlash(name))
+		})
+	}
+
+	if err == nil && includeConfig {
+		files++
+		err = addFileToTar(tw, configFile(), stateConfigFile)
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tw.Close(); err != nil {
+		return 0, err
+	}
+
+	if err := gz.Close(); err != nil {
+		return 0, err
+	}
+
+	return files, out.Close()
+}
+
+func addFileToTar(tw *tar.Writer, filename, name string) error {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	header := &tar.Header{Name: name, Mode: 0o600, Size: int64(len(content))}
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+
+	_, err = tw.Write(content)
+
+	return err
+}
+
+// importState extracts the files of the gzipped tar file into the state
+// folder, and the templates into the config folder, and returns the number of files imported and the files skipped
+// because they already exist.
+func importState(filename string, force bool) (int, []string, error) {
+	in, err := os.Open(filename)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer in.Close()
+
+	gz, err := gzip.NewReader(in)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	tr := tar.NewReader(gz)
+	imported := 0
+	skipped := []string{}
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return imported, skipped, err
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		if !filepath.IsLocal(header.Name) {
+			return imported, skipped, fmt.Errorf("invalid file name %s in archive", header.Name)
+		}
+
+		target := filepath.Join(StateFolder, filepath.FromSlash(header.Name))
+
+		switch {
+		case header.Name == stateConfigFile:
+			target = configFile()
+		case strings.HasPrefix(header.Name, stateTemplateFolder+"/"):
+			target = filepath.Join(ConfigFolder, filepath.FromSlash(header.Name))
+		}
+
+		if _, err := os.Stat(target); err == nil && !force {
+			skipped = append(skipped, target)
+
+			continue
+		}
+
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return imported, skipped, err
+		}
+
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return imported, skipped, err
+		}
+
+		if err := os.WriteFile(target, content, 0o600); err != nil {
+			return imported, skipped, err
+		}
+
+		imported++
+	}
+
+	return imported, skipped, nil
+}
@@ -0,0 +1,101 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mhersson/gojira/pkg/util"
+	"github.com/mhersson/gojira/pkg/util/gitlog"
+	"github.com/mhersson/gojira/pkg/util/validate"
+)
+
+const suggestWorklogUsage string = `This command inspects the git reflog of the repository in the
+current directory, and suggests worklogs for the given date (default today).
+
+The work is grouped by the issue keys found in commit messages and branch
+names, and the time spent is estimated from the gaps between the commits.
+Gaps longer than two hours are considered breaks, and the first commit after
+a break is given 30 minutes. The suggestions are opened in $EDITOR where they
+can be adjusted, or removed, before they are added to your worklog.
+
+Usage:
+  gojira suggest-worklog [yyyy-mm-dd] [flags]
+
+Aliases:
+  suggest-worklog, sw
+
+Flags:
+  -h, --help                   help for suggest-worklog
+`
+
+var suggestWorklogCmd = &cobra.Command{
+	Use:     "suggest-worklog",
+	Short:   "Suggest worklogs from your local git commits",
+	Aliases: []string{"sw"},
+	Args:    cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		exitIfReadOnly()
+
+		date := util.Today()
+		if len(args) == 1 {
+			date = args[0]
+		}
+
+		if !validate.Date(date) {
+			fmt.Println("Invalid date. Date must be on the format yyyy-mm-dd")
+			os.Exit(exitUsage)
+		}
+
+		reflog := runGit([]string{"log", "-g", "--date=unix", "--format=" + gitlog.ReflogFormat, "HEAD"})
+		events := gitlog.ParseReflog(reflog)
+
+		suggestions := gitlog.Suggest(events, date, gitlog.DefaultMaxGap, gitlog.DefaultLeadTime)
+		if len(suggestions) == 0 {
+			fmt.Printf("Found no commits referring to an issue on %s\n", date)
+
+			return
+		}
+
+		out := util.ExecuteTemplate("edit-worklog.tmpl", suggestions)
+		edited, err := captureInputFromEditor(string(out), "suggest-worklog-*")
+		cobra.CheckErr(err)
+
+		if len(edited) == 0 {
+			fmt.Println("Canceled by user, no worklogs added")
+
+			return
+		}
+
+		addNewWorklogs(parseEditedWorklog(date, edited))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(suggestWorklogCmd)
+
+	suggestWorklogCmd.SetUsageTemplate(suggestWorklogUsage)
+}
@@ -0,0 +1,319 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mhersson/gojira/pkg/jira"
+	"github.com/mhersson/gojira/pkg/types"
+	"github.com/mhersson/gojira/pkg/util/format"
+	"github.com/mhersson/gojira/pkg/util/todo"
+)
+
+const syncUsage string = `Export your open issues to plain-text todo lists, org-mode files
+or Taskwarrior, with the due date as deadline. Every sync replaces the
+exported issues with the current issues in Jira, but issues you have
+marked as done are kept as done.
+
+With --import the issues marked as done are transitioned to done in
+Jira, using the first transition to a status in the done category.
+Transitions requiring fields, like a resolution, may fail and must
+then be done with gojira update status.
+
+Usage:
+  gojira sync org --file <FILE> [flags]
+  gojira sync taskwarrior [flags]
+
+Available Commands:
+  org         Sync your issues with an org-mode file
+  taskwarrior Sync your issues with Taskwarrior
+
+Flags:
+  -f, --filter [JQL FILTER]    the issues to export (default your unresolved issues)
+      --file [FILE]            the org-mode file (org)
+  -h, --help                   help for sync
+  -i, --import                 transition the issues marked as done
+
+Example:
+  gojira sync org --file ~/org/jira.org --import
+  gojira sync taskwarrior
+`
+
+// Used by the sync commands.
+var (
+	SyncFile   string
+	SyncImport bool
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Sync your issues with org-mode or Taskwarrior",
+	Args:  cobra.NoArgs,
+}
+
+var syncOrgCmd = &cobra.Command{
+	Use:   "org",
+	Short: "Sync your issues with an org-mode file",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if SyncImport {
+			exitIfReadOnly()
+		}
+
+		done := []string{}
+
+		if f, err := os.Open(SyncFile); err == nil {
+			done, err = todo.ParseOrg(f)
+			f.Close()
+
+			if err != nil {
+				fmt.Printf("Failed to read %s - %s\n", SyncFile, err.Error())
+				os.Exit(exitCode(err))
+			}
+		}
+
+		entries := syncEntries(must(JiraClient.GetIssuesSelecting(ctx, syncFilter(), jira.OrderByPriority, syncFields)), done)
+
+		out, err := os.Create(SyncFile)
+		if err == nil {
+			err = todo.WriteOrg(out, entries)
+			out.Close()
+		}
+
+		if err != nil {
+			fmt.Printf("Failed to write %s - %s\n", SyncFile, err.Error())
+			os.Exit(exitCode(err))
+		}
+
+		success("Exported %d issues to %s", len(entries), SyncFile)
+	},
+}
+
+var syncTaskwarriorCmd = &cobra.Command{
+	Use:     "taskwarrior",
+	Short:   "Sync your issues with Taskwarrior",
+	Aliases: []string{"task", "tw"},
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if SyncImport {
+			exitIfReadOnly()
+		}
+
+		existing := exportTasks()
+
+		// Keys are not stored in the tasks, so find the done
+		// issues by the task ids derived from the keys
+		completed := map[string]bool{}
+		deleted := map[string]bool{}
+
+		for _, t := range existing {
+			completed[t.UUID] = t.Status == "completed"
+			deleted[t.UUID] = t.Status == "deleted"
+		}
+
+		issues := must(JiraClient.GetIssuesSelecting(ctx, syncFilter(), jira.OrderByPriority, syncFields))
+		done := []string{}
+
+		for _, i := range issues {
+			if completed[todo.TaskUUID(i.Key)] {
+				done = append(done, i.Key)
+			}
+		}
+
+		now := time.Now()
+		tasks := []todo.Task{}
+		exported := map[string]bool{}
+
+		for _, e := range syncEntries(issues, done) {
+			task := todo.ToTask(e, now)
+			exported[task.UUID] = true
+
+			// Do not bring back tasks deleted in Taskwarrior, or complete them again
+			if !deleted[task.UUID] && !completed[task.UUID] {
+				tasks = append(tasks, task)
+			}
+		}
+
+		// Complete the tasks of issues no longer open in Jira
+		for _, t := range existing {
+			if t.Status == "pending" && !exported[t.UUID] {
+				t.Status = "completed"
+				t.End = todo.TaskTime(now)
+				tasks = append(tasks, t)
+			}
+		}
+
+		importTasks(tasks)
+
+		success("Exported %d issues to Taskwarrior", len(exported))
+	},
+}
+
+// The fields needed to export the issues.
+var syncFields = []string{"summary", "status", "priority", "duedate"}
+
+func init() {
+	rootCmd.AddCommand(syncCmd)
+
+	syncCmd.SetUsageTemplate(syncUsage)
+	syncCmd.AddCommand(syncOrgCmd)
+	syncCmd.AddCommand(syncTaskwarriorCmd)
+
+	for _, c := range []*cobra.Command{syncOrgCmd, syncTaskwarriorCmd} {
+		c.SetUsageTemplate(syncUsage)
+		c.Flags().StringVarP(&JQLFilter, "filter", "f", "", "the issues to export")
+		c.Flags().BoolVarP(&SyncImport, "import", "i", false, "transition the issues marked as done")
+	}
+
+	syncOrgCmd.Flags().StringVar(&SyncFile, "file", "", "the org-mode file")
+	_ = syncOrgCmd.MarkFlagRequired("file")
+}
+
+func syncFilter() string {
+	if JQLFilter != "" {
+		return JQLFilter
+	}
+
+	return "assignee = currentUser() AND resolution = Unresolved"
+}
+
+// syncEntries returns the issues as todo entries. The issues marked as
+// done are transitioned to done with --import, if confirmed, and left
+// out if the transition succeeds, or else marked as done.
+func syncEntries(issues []types.Issue, done []string) []todo.Entry {
+	entries := []todo.Entry{}
+	transitioned := 0
+	pending := 0
+	importing := SyncImport
+
+	if importing {
+		marked := 0
+
+		for _, i := range issues {
+			if slices.Contains(done, i.Key) {
+				marked++
+			}
+		}
+
+		importing = marked == 0 || confirm(Cfg.Confirm.Transitions || Cfg.Confirm.Bulk,
+			fmt.Sprintf("Transition %d issues to done", marked))
+	}
+
+	for _, i := range issues {
+		isDone := slices.Contains(done, i.Key)
+
+		switch {
+		case isDone && importing:
+			if err := transitionToDone(i.Key); err != nil {
+				fmt.Printf("%sFailed to transition %s to done - %s%s\n",
+					format.Color.Red, i.Key, err.Error(), format.Color.Nocolor)
+			} else {
+				transitioned++
+
+				continue
+			}
+		case isDone:
+			pending++
+		}
+
+		entry := todo.Entry{
+			Key:      i.Key,
+			Project:  strings.Split(i.Key, "-")[0],
+			Summary:  i.Fields.Summary,
+			Status:   i.Fields.Status.Name,
+			Priority: i.Fields.Priority.Name,
+			URL:      issueURL(i.Key),
+			Done:     isDone,
+		}
+
+		if due, err := time.ParseInLocation("2006-01-02", i.Fields.DueDate, time.Local); err == nil {
+			entry.Due = due
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if transitioned > 0 {
+		success("Transitioned %d issues to done", transitioned)
+	}
+
+	if pending > 0 {
+		fmt.Printf("%d issues are marked as done, use --import to transition them in Jira\n", pending)
+	}
+
+	return entries
+}
+
+// transitionToDone moves the issue to the first status in the done category.
+func transitionToDone(key string) error {
+	for _, t := range must(JiraClient.GetTransistions(ctx, key)) {
+		if t.To.StatusCategory.Key == "done" {
+			return JiraClient.UpdateStatus(ctx, key, t.ID)
+		}
+	}
+
+	return &types.Error{Message: "no transition to a done status"}
+}
+
+// exportTasks returns the tasks exported to Taskwarrior by gojira.
+func exportTasks() []todo.Task {
+	out, err := exec.Command("task", "rc.verbose=nothing", "+"+todo.TaskTag, "export").Output()
+	if err != nil {
+		fmt.Printf("Failed to run task export - %s\n", err.Error())
+		os.Exit(exitCode(err))
+	}
+
+	tasks := []todo.Task{}
+	if err := json.Unmarshal(out, &tasks); err != nil {
+		fmt.Printf("Failed to read the tasks - %s\n", err.Error())
+		os.Exit(exitCode(err))
+	}
+
+	return tasks
+}
+
+func importTasks(tasks []todo.Task) {
+	if len(tasks) == 0 {
+		return
+	}
+
+	content, _ := json.Marshal(tasks)
+
+	cmd := exec.Command("task", "rc.verbose=nothing", "rc.confirmation=off", "import")
+	cmd.Stdin = bytes.NewReader(content)
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		fmt.Printf("Failed to run task import - %s\n", err.Error())
+		os.Exit(exitCode(err))
+	}
+}
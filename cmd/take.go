@@ -0,0 +1,166 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mhersson/gojira/pkg/types"
+)
+
+const takeUsage string = `Start working on an issue. The issue is assigned to you, moved to
+In Progress and set as the active issue, and with --timer the timer
+is started on it.
+
+By default the active issue is taken,
+but this can be changed by adding the issue key as argument.
+
+The status to move the issue to is set with takeStatus in the config
+file, and can be overridden with --status. The issue is moved by the
+transition to the status, or by the transition with that name. An
+issue already in the status is not moved.
+
+Usage:
+  gojira take [ISSUE KEY] [flags]
+
+Flags:
+  -h, --help                   help for take
+  -s, --status [STATUS]        the status to move the issue to (default In Progress)
+  -t, --timer                  start the timer on the issue
+
+Example:
+  gojira take OSE-1 --timer
+`
+
+// Used by `take`.
+var (
+	TakeStatus string
+	TakeTimer  bool
+)
+
+var takeCmd = &cobra.Command{
+	Use:   "take",
+	Short: "Assign an issue to you and start progress",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		exitIfReadOnly()
+
+		if len(args) == 1 {
+			IssueKey = strings.ToUpper(args[0])
+		}
+
+		checkIssueKey(&IssueKey, IssueFile)
+		checkPermission(IssueKey, "assign the issue", permAssignIssues)
+		checkPermission(IssueKey, "change the status", permTransitionIssues)
+
+		if TakeTimer {
+			autoStopExpiredTimer()
+
+			if t, ok := loadTimer(); ok && t.Running {
+				fmt.Printf("The timer is already running on %s, stop it first\n", t.Key)
+				os.Exit(exitFailure)
+			}
+		}
+
+		status := TakeStatus
+		if status == "" {
+			status = Cfg.TakeStatus
+		}
+
+		issue := getIssueBrief(IssueKey)
+		moved := !strings.EqualFold(issue.Fields.Status.Name, status)
+
+		// Find the transition before changing anything,
+		// so the issue is not left half taken
+		var t types.Transition
+
+		if moved {
+			var ok bool
+
+			t, ok = findTransition(must(JiraClient.GetTransistions(ctx, IssueKey)), status)
+			if !ok {
+				fmt.Printf("%s can not be moved from %s to %s\n", IssueKey, issue.Fields.Status.Name, status)
+				os.Exit(exitFailure)
+			}
+
+			if !confirm(Cfg.Confirm.Transitions,
+				fmt.Sprintf("Move %s from %s to %s", IssueKey, issue.Fields.Status.Name, t.To.Name)) {
+				os.Exit(exitCancelled)
+			}
+		}
+
+		if err := JiraClient.UpdateAssignee(ctx, IssueKey, Cfg.Username); err != nil {
+			fmt.Printf("Failed to update assignee - %s\n", err.Error())
+			os.Exit(exitCode(err))
+		}
+
+		fmt.Printf("%s is assigned to %s\n", IssueKey, Cfg.Username)
+
+		if moved {
+			if err := JiraClient.UpdateStatus(ctx, IssueKey, t.ID); err != nil {
+				fmt.Printf("Update failed: %s\n", err.Error())
+				os.Exit(exitCode(err))
+			}
+
+			fmt.Printf("%s is moved to %s\n", IssueKey, t.To.Name)
+		} else {
+			fmt.Printf("%s is already %s\n", IssueKey, issue.Fields.Status.Name)
+		}
+
+		setActiveIssue(IssueKey)
+		success("%s is active", IssueKey)
+
+		if TakeTimer {
+			startTimer(IssueKey, Cfg.TimerMax)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(takeCmd)
+
+	takeCmd.SetUsageTemplate(takeUsage)
+	takeCmd.Flags().StringVarP(&TakeStatus, "status", "s", "", "the status to move the issue to")
+	takeCmd.Flags().BoolVarP(&TakeTimer, "timer", "t", false, "start the timer on the issue")
+}
+
+// findTransition returns the transition to the status,
+// or else the transition with the status as its name.
+func findTransition(transitions []types.Transition, status string) (types.Transition, bool) {
+	for _, t := range transitions {
+		if strings.EqualFold(t.To.Name, status) {
+			return t, true
+		}
+	}
+
+	for _, t := range transitions {
+		if strings.EqualFold(t.Name, status) {
+			return t, true
+		}
+	}
+
+	return types.Transition{}, false
+}
@@ -0,0 +1,320 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mhersson/gojira/pkg/types"
+	"github.com/mhersson/gojira/pkg/util"
+	"github.com/mhersson/gojira/pkg/util/convert"
+	"github.com/mhersson/gojira/pkg/util/format"
+)
+
+const timerUsage string = `Track time spent on an issue, and log it as work when the timer is stopped.
+
+By default the timer is started on the active issue, but this can be
+changed by adding the issue key as argument.
+
+A timer running longer than the max duration, e.g. because you forgot to
+stop it, is stopped automatically and the work is logged at the max duration
+with a note in the worklog comment. The timer is checked every time a timer
+command is run. The default max duration is set with timerMax in the
+config file, and can be overridden with --max. A stopped timer can be resumed.
+
+Usage:
+  gojira timer start [ISSUE KEY] [flags]
+  gojira timer stop [flags]
+  gojira timer status
+  gojira timer resume [flags]
+
+Available Commands:
+  resume      Start a new timer on the issue of the last stopped timer
+  start       Start the timer
+  status      Show the status of the timer
+  stop        Stop the timer and log the work
+
+Flags:
+  -c, --comment                worklog comment (stop)
+  -h, --help                   help for timer
+  -m, --max                    max duration of the timer, e.g 4h (start and resume)
+
+Example:
+  gojira timer start OSE-1 --max 4h
+  gojira timer stop --comment "Fixed the flaky test"
+`
+
+// Used by the timer commands.
+var (
+	TimerMax     time.Duration
+	TimerComment string
+)
+
+var timerCmd = &cobra.Command{
+	Use:   "timer",
+	Short: "Track time spent on an issue",
+	Args:  cobra.NoArgs,
+}
+
+var timerStartCmd = &cobra.Command{
+	Use:     "start",
+	Short:   "Start the timer",
+	Args:    cobra.MaximumNArgs(1),
+	Aliases: []string{"s"},
+	Run: func(cmd *cobra.Command, args []string) {
+		exitIfReadOnly()
+		autoStopExpiredTimer()
+
+		if t, ok := loadTimer(); ok && t.Running {
+			fmt.Printf("The timer is already running on %s, stop it first\n", t.Key)
+			os.Exit(exitFailure)
+		}
+
+		if len(args) == 1 {
+			IssueKey = strings.ToUpper(args[0])
+		}
+
+		checkIssueKey(&IssueKey, IssueFile)
+		checkPermission(IssueKey, "log work", permWorkOnIssues)
+
+		startTimer(IssueKey, timerMax(cmd))
+	},
+}
+
+var timerResumeCmd = &cobra.Command{
+	Use:     "resume",
+	Short:   "Start a new timer on the issue of the last stopped timer",
+	Args:    cobra.NoArgs,
+	Aliases: []string{"r"},
+	Run: func(cmd *cobra.Command, args []string) {
+		exitIfReadOnly()
+		autoStopExpiredTimer()
+
+		t, ok := loadTimer()
+
+		switch {
+		case !ok:
+			fmt.Println("There is no timer to resume")
+			os.Exit(exitFailure)
+		case t.Running:
+			fmt.Printf("The timer is already running on %s\n", t.Key)
+			os.Exit(exitFailure)
+		}
+
+		maxDuration := t.Max
+		if cmd.Flags().Changed("max") {
+			maxDuration = TimerMax
+		}
+
+		startTimer(t.Key, maxDuration)
+	},
+}
+
+var timerStopCmd = &cobra.Command{
+	Use:     "stop",
+	Short:   "Stop the timer and log the work",
+	Args:    cobra.NoArgs,
+	Aliases: []string{"st"},
+	Run: func(cmd *cobra.Command, args []string) {
+		exitIfReadOnly()
+
+		// An expired timer is stopped here too, to keep the comment
+		t, ok := loadTimer()
+		if !ok || !t.Running {
+			fmt.Println("The timer is not running")
+			os.Exit(exitFailure)
+		}
+
+		now := time.Now()
+
+		if !confirmWorklog(t.Key, t.Elapsed(now).Round(time.Minute)) {
+			os.Exit(exitCancelled)
+		}
+
+		stopTimer(&t, TimerComment, now)
+	},
+}
+
+var timerStatusCmd = &cobra.Command{
+	Use:     "status",
+	Short:   "Show the status of the timer",
+	Args:    cobra.NoArgs,
+	Aliases: []string{"stat"},
+	Run: func(cmd *cobra.Command, args []string) {
+		autoStopExpiredTimer()
+
+		t, ok := loadTimer()
+		if !ok || !t.Running {
+			fmt.Println("The timer is not running")
+
+			if ok {
+				fmt.Printf("Last timer was on %s, run `gojira timer resume` to continue\n", t.Key)
+			}
+
+			return
+		}
+
+		now := time.Now()
+		fmt.Printf("Issue:    %s\n", t.Key)
+		fmt.Printf("Started:  %s\n", t.Started.Format("2006-01-02 15:04"))
+		fmt.Printf("Elapsed:  %s\n", convert.DurationToDaysAndHours(t.Elapsed(now)))
+
+		if t.Max > 0 {
+			fmt.Printf("Max:      %s (stops at %s)\n",
+				convert.DurationToDaysAndHours(t.Max), t.Started.Add(t.Max).Format("2006-01-02 15:04"))
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(timerCmd)
+
+	timerCmd.SetUsageTemplate(timerUsage)
+	timerCmd.AddCommand(timerStartCmd)
+	timerCmd.AddCommand(timerStopCmd)
+	timerCmd.AddCommand(timerStatusCmd)
+	timerCmd.AddCommand(timerResumeCmd)
+
+	timerStartCmd.SetUsageTemplate(timerUsage)
+	timerStartCmd.Flags().DurationVarP(&TimerMax, "max", "m", 0, "max duration of the timer")
+
+	timerResumeCmd.SetUsageTemplate(timerUsage)
+	timerResumeCmd.Flags().DurationVarP(&TimerMax, "max", "m", 0, "max duration of the timer")
+
+	timerStopCmd.SetUsageTemplate(timerUsage)
+	timerStopCmd.Flags().StringVarP(&TimerComment, "comment", "c", "", "worklog comment")
+
+	timerStatusCmd.SetUsageTemplate(timerUsage)
+}
+
+// timerMax returns the max duration from the flag if set,
+// or else the default from the config.
+func timerMax(cmd *cobra.Command) time.Duration {
+	if cmd.Flags().Changed("max") {
+		return TimerMax
+	}
+
+	return Cfg.TimerMax
+}
+
+func loadTimer() (types.Timer, bool) {
+	var t types.Timer
+
+	content, err := os.ReadFile(TimerFile)
+	if err != nil {
+		return t, false
+	}
+
+	if err := json.Unmarshal(content, &t); err != nil {
+		fmt.Printf("Failed to read timer - %s\n", err.Error())
+		os.Exit(exitCode(err))
+	}
+
+	return t, true
+}
+
+func saveTimer(t types.Timer) {
+	createStateFolder()
+
+	content, _ := json.Marshal(t)
+
+	if err := os.WriteFile(TimerFile, content, 0o600); err != nil {
+		fmt.Printf("Failed to save timer - %s\n", err.Error())
+		os.Exit(exitCode(err))
+	}
+}
+
+func startTimer(key string, maxDuration time.Duration) {
+	if maxDuration < 0 {
+		fmt.Println("The max duration can not be negative")
+		os.Exit(exitUsage)
+	}
+
+	saveTimer(types.Timer{Key: key, Started: time.Now(), Max: maxDuration, Running: true})
+
+	msg := "Timer started on " + key
+	if maxDuration > 0 {
+		msg += ", it will stop automatically after " + convert.DurationToDaysAndHours(maxDuration)
+	}
+
+	success("%s", msg)
+}
+
+// stopTimer stops the timer and logs the elapsed time, capped at the
+// max duration, as work on the issue. Less than a minute is not logged.
+func stopTimer(t *types.Timer, comment string, now time.Time) {
+	elapsed := t.Elapsed(now).Round(time.Minute)
+
+	if t.Expired(now) {
+		note := fmt.Sprintf("Timer stopped automatically at the max duration of %s",
+			convert.DurationToDaysAndHours(t.Max))
+		if comment != "" {
+			comment += "\n\n"
+		}
+
+		comment += "(" + note + ")"
+
+		fmt.Printf("%s%s, it was started %s%s\n",
+			format.Color.Yellow, note, t.Started.Format("2006-01-02 15:04"), format.Color.Nocolor)
+	}
+
+	t.Running = false
+
+	if elapsed < time.Minute {
+		saveTimer(*t)
+		fmt.Println("Timer stopped after less than a minute, no work logged")
+
+		return
+	}
+
+	err := JiraClient.AddWorklog(ctx, t.Started.Format("2006-01-02"), t.Started.Format("15:04"), t.Key,
+		strconv.FormatFloat(elapsed.Seconds(), 'f', 0, 64), util.MakeStringJSONSafe(comment))
+	if err != nil {
+		fmt.Printf("Failed to add worklog, the timer is still running - %s\n", err.Error())
+		os.Exit(exitCode(err))
+	}
+
+	saveTimer(*t)
+
+	success("Timer stopped, logged %s on %s", convert.DurationToDaysAndHours(elapsed), t.Key)
+	checkBudget(t.Key)
+}
+
+// autoStopExpiredTimer stops and logs a timer that has run longer than its
+// max duration. Returns true if the timer was stopped.
+func autoStopExpiredTimer() bool {
+	t, ok := loadTimer()
+	if !ok || !t.Expired(time.Now()) || Cfg.ReadOnly {
+		return false
+	}
+
+	stopTimer(&t, "", time.Now())
+
+	return true
+}
@@ -0,0 +1,65 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mhersson/gojira/pkg/jira"
+)
+
+// timesheetRecheck is how long a missing timesheet plugin is remembered,
+// before asking Jira again in case it has been installed.
+const timesheetRecheck = 24 * time.Hour
+
+// useTimesheetPlugin reports if the timesheet plugin is enabled in the
+// config, and has not recently been found missing in Jira.
+func useTimesheetPlugin() bool {
+	if !Cfg.UseTimesheetPlugin {
+		return false
+	}
+
+	info, err := os.Stat(NoTimesheetFile)
+
+	return err != nil || time.Since(info.ModTime()) >= timesheetRecheck
+}
+
+// timesheetMissing reports if the error is caused by a missing timesheet
+// plugin. If so, the result is remembered and a warning is printed.
+func timesheetMissing(err error) bool {
+	if !errors.Is(err, jira.ErrNoTimesheetPlugin) {
+		return false
+	}
+
+	fmt.Fprintln(os.Stderr, "Warning: the timesheet plugin was not found in Jira, "+
+		"set useTimesheetPlugin to false in the config if it has been removed")
+
+	if err := os.MkdirAll(filepath.Dir(NoTimesheetFile), 0o755); err == nil {
+		_ = os.WriteFile(NoTimesheetFile, nil, 0o600)
+	}
+
+	return true
+}
@@ -0,0 +1,109 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mhersson/gojira/pkg/jira"
+)
+
+const tokenUsage string = `Create personal access tokens on Jira Data Center 8.14 or newer.
+The token is only shown once, so keep it safe, e.g. in pass, and
+set passwordtype to pat to use it instead of your password.
+
+Usage:
+  gojira token create <NAME> [flags]
+
+Available Commands:
+  create      Create a personal access token
+
+Flags:
+  -e, --expires [DAYS]         days until the token expires, 0 for never (default 90)
+  -h, --help                   help for token
+
+Example:
+  gojira token create laptop --expires 30
+`
+
+// Used by `token create`.
+var TokenExpires int
+
+var tokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: "Manage personal access tokens",
+	Args:  cobra.NoArgs,
+}
+
+var tokenCreateCmd = &cobra.Command{
+	Use:     "create",
+	Short:   "Create a personal access token",
+	Args:    cobra.ExactArgs(1),
+	Aliases: []string{"c"},
+	Run: func(cmd *cobra.Command, args []string) {
+		exitIfReadOnly()
+
+		if jira.IsCloud(Cfg) {
+			fmt.Println("Personal access tokens are not supported on Jira Cloud, create an API token instead")
+			os.Exit(exitFailure)
+		}
+
+		if TokenExpires < 0 {
+			fmt.Println("The number of days can not be negative")
+			os.Exit(exitUsage)
+		}
+
+		token, err := JiraClient.CreatePersonalAccessToken(ctx, args[0], TokenExpires)
+		if err != nil {
+			fmt.Printf("Failed to create token - %s\n", err.Error())
+			os.Exit(exitCode(err))
+		}
+
+		if Quiet {
+			fmt.Println(token.RawToken)
+
+			return
+		}
+
+		success("Successfully created token %s", token.Name)
+
+		if token.ExpiringAt != "" {
+			fmt.Printf("It expires %s\n", token.ExpiringAt)
+		}
+
+		fmt.Printf("\n%s\n\n", token.RawToken)
+		fmt.Println("The token is not shown again. Set passwordtype to pat and the password to the token to use it.")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(tokenCmd)
+
+	tokenCmd.SetUsageTemplate(tokenUsage)
+	tokenCmd.AddCommand(tokenCreateCmd)
+
+	tokenCreateCmd.SetUsageTemplate(tokenUsage)
+	tokenCreateCmd.Flags().IntVarP(&TokenExpires, "expires", "e", 90, "days until the token expires, 0 for never")
+}
@@ -0,0 +1,145 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/mhersson/gojira/pkg/types"
+	"github.com/mhersson/gojira/pkg/util/format"
+	"github.com/mhersson/gojira/pkg/util/stats"
+)
+
+const (
+	trendWindow    = 4   // Number of weeks in the rolling average
+	trendTolerance = 0.1 // Max deviation from the expected hours before a week is flagged
+)
+
+// weekTrend is the trend analysis of a single week.
+type weekTrend struct {
+	Week     types.Week
+	Total    float64
+	Expected float64
+	Rolling  float64
+	Diff     float64
+	Flag     string
+}
+
+func getTrend(weeks []types.Week) []weekTrend {
+	totals := []float64{}
+	expected := []float64{}
+
+	for _, w := range weeks {
+		totals = append(totals, w.TotalTime())
+		expected = append(expected, Cfg.WorkingHoursPerWeek-Cfg.WorkingHoursPerDay*float64(w.PublicHolidays))
+	}
+
+	rolling := stats.RollingAverage(totals, trendWindow)
+	rollingExpected := stats.RollingAverage(expected, trendWindow)
+
+	trend := []weekTrend{}
+
+	for i, w := range weeks {
+		t := weekTrend{
+			Week:     w,
+			Total:    totals[i],
+			Expected: expected[i],
+			Rolling:  rolling[i],
+			Diff:     rolling[i] - rollingExpected[i],
+		}
+
+		// Only flag when there is a full window of weeks
+		if i >= trendWindow-1 && math.Abs(t.Diff) > rollingExpected[i]*trendTolerance {
+			t.Flag = "under"
+			if t.Diff > 0 {
+				t.Flag = "over"
+			}
+		}
+
+		trend = append(trend, t)
+	}
+
+	return trend
+}
+
+func printTrend(weeks []types.Week) {
+	if len(weeks) == 0 {
+		fmt.Println("There are no hours registered for this period")
+
+		return
+	}
+
+	trend := getTrend(weeks)
+	totals := []float64{}
+
+	fmt.Printf("%s%s\n%-9s%-11s%-12s%-10s%-10s%-10s%-10s%s%s\n", format.Color.Ul, format.Color.Yellow,
+		"Week#", "Start", "End", "Total", "Expected", "4w avg", "Diff", "Flag", format.Color.Nocolor)
+
+	for _, t := range trend {
+		totals = append(totals, t.Total)
+
+		flag := ""
+		if t.Flag != "" {
+			flag = format.Color.Red + t.Flag + format.Color.Nocolor
+		}
+
+		fmt.Printf(" %-8d%-11s%-12s%-10.2f%-10.2f%-10.2f%-10s%s\n",
+			t.Week.Number(), t.Week.StartDate.Format("01/02"), t.Week.EndDate.Format("01/02"),
+			t.Total, t.Expected, t.Rolling, fmt.Sprintf("%+.2f", t.Diff), flag)
+	}
+
+	fmt.Printf("\nWeekly hours: median %.2f, 90th percentile %.2f\n",
+		stats.Percentile(totals, 50), stats.Percentile(totals, 90))
+	fmt.Printf("Trend: %+.2f hours per week\n", stats.LinearTrend(totals))
+
+	last := trend[len(trend)-1]
+
+	switch last.Flag {
+	case "over":
+		fmt.Printf("\n%sYou have worked on average %.2f hours more than expected per week the last %d weeks%s\n",
+			format.Color.Red, last.Diff, trendWindow, format.Color.Nocolor)
+	case "under":
+		fmt.Printf("\n%sYou have worked on average %.2f hours less than expected per week the last %d weeks%s\n",
+			format.Color.Red, -last.Diff, trendWindow, format.Color.Nocolor)
+	}
+}
+
+func printTrendCSV(weeks []types.Week) {
+	rows := [][]string{}
+
+	for _, t := range getTrend(weeks) {
+		rows = append(rows, []string{
+			strconv.Itoa(t.Week.Number()),
+			t.Week.StartDate.Format("2006-01-02"),
+			t.Week.EndDate.Format("2006-01-02"),
+			strconv.FormatFloat(t.Total, 'f', 2, 64),
+			strconv.FormatFloat(t.Expected, 'f', 2, 64),
+			strconv.FormatFloat(t.Rolling, 'f', 2, 64),
+			strconv.FormatFloat(t.Diff, 'f', 2, 64),
+			t.Flag,
+		})
+	}
+
+	printCSV([]string{"Week", "Start", "End", "Total", "Expected", "Rolling Average", "Difference", "Flag"}, rows)
+}
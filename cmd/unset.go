@@ -26,6 +26,8 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+
+	"github.com/mhersson/gojira/pkg/util"
 )
 
 // TODO: Split into subcommands for issue, sprint and kanban
@@ -36,7 +38,7 @@ var unsetCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		switch args[0] {
 		case "issue":
-			unsetActive(IssueFile)
+			unsetActive(util.ActiveIssueFile(IssueFile))
 			fmt.Println("Active issue cleared")
 		case "board":
 			unsetActive(BoardFile)
@@ -55,6 +57,6 @@ func unsetActive(file string) {
 	err := os.Remove(file)
 	if err != nil && !os.IsNotExist(err) {
 		fmt.Println("Failed to clear active issue")
-		os.Exit(1)
+		os.Exit(exitFailure)
 	}
 }
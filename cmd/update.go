@@ -24,15 +24,27 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
 
-	"github.com/mhersson/gojira/pkg/jira"
+	"github.com/mhersson/gojira/pkg/types"
+	"github.com/mhersson/gojira/pkg/util"
+	"github.com/mhersson/gojira/pkg/util/format"
 	"github.com/spf13/cobra"
 )
 
 const updateStatusUsage string = `By default the active issue gets updated,
 but this can be changed by adding the issue key as argument.
 
+Before the status is changed the new status and its category are
+displayed, together with what happens to the resolution, and the
+required fields on the transition screen, which must be confirmed.
+
+If the new status has a rule in assigneeRules in the config file,
+the issue is then assigned to the user in the rule, to you (me),
+or unassigned (unassigned).
+
 Usage:
   gojira update status [ISSUE KEY] [flags]
 
@@ -74,22 +86,33 @@ var updateStatusCmd = &cobra.Command{
 	Args:    cobra.MaximumNArgs(1),
 	Aliases: []string{"s"},
 	Run: func(cmd *cobra.Command, args []string) {
+		exitIfReadOnly()
+
 		if len(args) == 1 {
 			IssueKey = strings.ToUpper(args[0])
 		}
-		jira.CheckIssueKey(&IssueKey, IssueFile)
-		status := getStatus(IssueKey)
-		printStatus(status, false)
-		tr := jira.GetTransistions(IssueKey)
+		checkIssueKey(&IssueKey, IssueFile)
+		checkPermission(IssueKey, "change the status", permTransitionIssues)
+		issue := getIssueBrief(IssueKey)
+		printStatus(issue.Fields.Status.Name, false)
+		tr := must(JiraClient.GetTransistions(ctx, IssueKey))
 		printTransitions(tr)
 		if len(tr) >= 1 {
-			err := jira.UpdateStatus(IssueKey, tr)
+			t := selectTransition(tr)
+			printTransitionPreview(issue, t)
+
+			if !confirm(Cfg.Confirm.Transitions, "Do you want to continue") {
+				os.Exit(exitCancelled)
+			}
+
+			err := JiraClient.UpdateStatus(ctx, IssueKey, t.ID)
 			if err != nil {
 				fmt.Printf("Update failed: %s", err.Error())
-				os.Exit(1)
+				os.Exit(exitCode(err))
 			}
-			status = getStatus(IssueKey)
+			status := getStatus(IssueKey)
 			printStatus(status, true)
+			applyAssigneeRule(IssueKey, status)
 		}
 	},
 }
@@ -100,25 +123,129 @@ var updateAssigneeCmd = &cobra.Command{
 	Aliases: []string{"a"},
 	Args:    cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
+		exitIfReadOnly()
+
 		if len(args) == 1 {
 			IssueKey = strings.ToUpper(args[0])
 		}
-		jira.CheckIssueKey(&IssueKey, IssueFile)
+		checkIssueKey(&IssueKey, IssueFile)
+		checkPermission(IssueKey, "assign the issue", permAssignIssues)
 
 		if Assignee == "" {
 			Assignee = Cfg.Username
 		}
 
-		err := jira.UpdateAssignee(IssueKey, Assignee)
+		err := JiraClient.UpdateAssignee(ctx, IssueKey, Assignee)
 		if err != nil {
 			fmt.Printf("Failed to update assignee - %s\n", err.Error())
-			os.Exit(1)
+			os.Exit(exitCode(err))
 		}
 
 		fmt.Printf("%s is assigned to %s\n", IssueKey, Assignee)
 	},
 }
 
+func selectTransition(transitions []types.Transition) types.Transition {
+	for {
+		i, _ := strconv.Atoi(util.GetUserInput("", `^[0-9]+$`))
+		if i < len(transitions) {
+			return transitions[i]
+		}
+
+		fmt.Println("Invalid choice")
+	}
+}
+
+// printTransitionPreview shows the effects of the transition,
+// so a misnamed transition does not come as a surprise.
+func printTransitionPreview(issue types.Issue, t types.Transition) {
+	category := t.To.StatusCategory.Name
+	if category == "" {
+		category = t.To.StatusCategory.Key
+	}
+
+	fmt.Printf("\n%s%sTransition:%s %s\n", format.Color.Yellow, format.Color.Bold, format.Color.Nocolor, t.Name)
+	fmt.Printf("New status:  %s (%s)\n", t.To.Name, category)
+
+	resolution := issue.Fields.Resolution.Name
+
+	switch f, onScreen := t.Fields["resolution"]; {
+	case onScreen && f.Required:
+		fmt.Println("Resolution:  must be set on the transition screen")
+	case onScreen:
+		fmt.Println("Resolution:  can be set on the transition screen")
+	case resolution == "" && t.To.StatusCategory.Key == "done":
+		fmt.Printf("Resolution:  %snot set, the issue will be done but unresolved%s\n",
+			format.Color.Red, format.Color.Nocolor)
+	case resolution == "":
+		fmt.Println("Resolution:  not set")
+	case t.To.StatusCategory.Key != "done":
+		fmt.Printf("Resolution:  %s, unless cleared by the workflow\n", resolution)
+	default:
+		fmt.Printf("Resolution:  %s\n", resolution)
+	}
+
+	if !t.HasScreen {
+		return
+	}
+
+	required := []string{}
+
+	for id, f := range t.Fields {
+		if f.Required && id != "resolution" {
+			required = append(required, f.Name)
+		}
+	}
+
+	slices.Sort(required)
+
+	if len(required) > 0 {
+		fmt.Printf("Required:    %s%s%s (gojira can not set these, the transition may fail)\n",
+			format.Color.Red, strings.Join(required, ", "), format.Color.Nocolor)
+	}
+}
+
+// applyAssigneeRule assigns the issue according to
+// the assignee rule for the status, if there is one.
+func applyAssigneeRule(key, status string) {
+	var user string
+
+	found := false
+
+	for s, u := range Cfg.AssigneeRules {
+		if strings.EqualFold(s, status) {
+			user, found = u, true
+		}
+	}
+
+	if !found {
+		return
+	}
+
+	var err error
+
+	switch strings.ToLower(user) {
+	case "unassigned":
+		err = JiraClient.UnassignIssue(ctx, key)
+	case "me":
+		user = Cfg.Username
+		fallthrough
+	default:
+		err = JiraClient.UpdateAssignee(ctx, key, user)
+	}
+
+	if err != nil {
+		fmt.Printf("Failed to apply the assignee rule for %s - %s\n", status, err.Error())
+		os.Exit(exitCode(err))
+	}
+
+	if strings.EqualFold(user, "unassigned") {
+		fmt.Printf("%s is unassigned (assignee rule for %s)\n", key, status)
+	} else {
+		fmt.Printf("%s is assigned to %s (assignee rule for %s)\n", key, user, status)
+	}
+}
+
 func init() {
 	rootCmd.AddCommand(updateCmd)
 
@@ -0,0 +1,170 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mhersson/gojira/pkg/util/validate"
+)
+
+const urlUsage string = `This command prints the url of the issue instead of opening it
+in the browser. By default the url of the active issue is printed,
+but this can be changed by adding the issue key as argument.
+
+Usage:
+  gojira url [ISSUE KEY] [flags]
+  gojira url create --project <PROJECT KEY> --type <ISSUE TYPE> [flags]
+
+Available Commands:
+  create      Print a link to the create issue page
+
+Flags:
+  -h, --help                   help for url
+`
+
+const createLinkUsage string = `This command builds a link to the create issue page in Jira,
+with the project, issue type and summary already filled in.
+The open command opens the link in your default browser,
+while the url command just prints it.
+
+Usage:
+  gojira open create --project <PROJECT KEY> --type <ISSUE TYPE> [flags]
+  gojira url create --project <PROJECT KEY> --type <ISSUE TYPE> [flags]
+
+Flags:
+  -d, --description            prefill the description
+  -h, --help                   help for create
+  -p, --project                the project key
+  -s, --summary                prefill the summary
+  -t, --type                   name of the issue type, e.g. Bug
+
+Example:
+  gojira open create --project OSE --type Bug --summary "Fix the flux capacitor"
+`
+
+// Used by `open create` and `url create`.
+var (
+	CreateLinkProject     string
+	CreateLinkIssueType   string
+	CreateLinkSummary     string
+	CreateLinkDescription string
+)
+
+var urlCmd = &cobra.Command{
+	Use:   "url",
+	Short: "Print the url of an issue",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 1 {
+			IssueKey = strings.ToUpper(args[0])
+		}
+		checkIssueKey(&IssueKey, IssueFile)
+
+		fmt.Println(issueURL(IssueKey))
+	},
+}
+
+var urlCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Print a link to the create issue page",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println(createIssueURL())
+	},
+}
+
+var openCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Open the create issue page in browser",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		openbrowser(createIssueURL())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(urlCmd)
+	urlCmd.AddCommand(urlCreateCmd)
+	openCmd.AddCommand(openCreateCmd)
+
+	urlCmd.SetUsageTemplate(urlUsage)
+
+	for _, c := range []*cobra.Command{urlCreateCmd, openCreateCmd} {
+		c.SetUsageTemplate(createLinkUsage)
+		c.Flags().StringVarP(&CreateLinkProject, "project", "p", "", "the project key")
+		c.Flags().StringVarP(&CreateLinkIssueType, "type", "t", "", "name of the issue type")
+		c.Flags().StringVarP(&CreateLinkSummary, "summary", "s", "", "prefill the summary")
+		c.Flags().StringVarP(&CreateLinkDescription, "description", "d", "", "prefill the description")
+		_ = c.MarkFlagRequired("project")
+		_ = c.MarkFlagRequired("type")
+	}
+}
+
+func issueURL(key string) string {
+	return Cfg.JiraURL + "/browse/" + key
+}
+
+func createIssueURL() string {
+	key := strings.ToUpper(CreateLinkProject)
+
+	project := validate.ProjectKey(key, must(JiraClient.GetValidProjects(ctx)))
+	if project.ID == "" {
+		fmt.Printf("%s is not a valid project key\n", key)
+		os.Exit(exitUsage)
+	}
+
+	issueTypeID := ""
+
+	for _, t := range must(JiraClient.GetProjectIssueTypes(ctx, project.Key)) {
+		if strings.EqualFold(t.Name, CreateLinkIssueType) {
+			issueTypeID = t.ID
+
+			break
+		}
+	}
+
+	if issueTypeID == "" {
+		fmt.Printf("%s is not a valid issue type for %s\n", CreateLinkIssueType, project.Key)
+		os.Exit(exitUsage)
+	}
+
+	params := url.Values{}
+	params.Set("pid", project.ID)
+	params.Set("issuetype", issueTypeID)
+
+	if CreateLinkSummary != "" {
+		params.Set("summary", CreateLinkSummary)
+	}
+
+	if CreateLinkDescription != "" {
+		params.Set("description", CreateLinkDescription)
+	}
+
+	return Cfg.JiraURL + "/secure/CreateIssueDetails!init.jspa?" + params.Encode()
+}
@@ -0,0 +1,190 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mhersson/gojira/pkg/types"
+)
+
+const watchUsage string = `Start watching an issue, to be notified when it changes.
+
+By default the active issue is watched,
+but this can be changed by adding the issue key as argument.
+
+Use --user to add another user as a watcher instead, e.g. when handing
+the issue over to a colleague. The user is given by the user name, or
+by the account id on Jira Cloud, and the permission to manage the
+watchers of the issue is required.
+
+Usage:
+  gojira watch [ISSUE KEY] [flags]
+
+Flags:
+  -h, --help                   help for watch
+  -u, --user [USER]            add the user as a watcher
+
+Example:
+  # Have alice watch GOJIRA-1
+  gojira watch GOJIRA-1 --user alice
+`
+
+const unwatchUsage string = `Stop watching an issue.
+
+By default the active issue is unwatched,
+but this can be changed by adding the issue key as argument.
+
+Use --user to remove another user from the watchers instead. The user
+is given by the user name, or by the account id on Jira Cloud, and the
+permission to manage the watchers of the issue is required.
+
+Usage:
+  gojira unwatch [ISSUE KEY] [flags]
+
+Flags:
+  -h, --help                   help for unwatch
+  -u, --user [USER]            remove the user from the watchers
+`
+
+var WatchUser string // Used by `watch` and `unwatch`
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Start watching an issue",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		exitIfReadOnly()
+
+		if len(args) == 1 {
+			IssueKey = strings.ToUpper(args[0])
+		}
+
+		checkIssueKey(&IssueKey, IssueFile)
+
+		if WatchUser != "" {
+			checkPermission(IssueKey, "manage the watchers", permManageWatchers)
+		}
+
+		watchers := must(JiraClient.GetWatchers(ctx, IssueKey))
+		if isWatching(watchers, WatchUser) {
+			success("%s already watching %s", watcherSubject(), IssueKey)
+
+			return
+		}
+
+		var err error
+
+		if WatchUser == "" {
+			err = JiraClient.Watch(ctx, IssueKey)
+		} else {
+			err = JiraClient.AddWatcher(ctx, IssueKey, WatchUser)
+		}
+
+		if err != nil {
+			fmt.Printf("Failed to add the watcher to %s - %s\n", IssueKey, err.Error())
+			os.Exit(exitCode(err))
+		}
+
+		success("%s now watching %s", watcherSubject(), IssueKey)
+	},
+}
+
+var unwatchCmd = &cobra.Command{
+	Use:   "unwatch",
+	Short: "Stop watching an issue",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		exitIfReadOnly()
+
+		if len(args) == 1 {
+			IssueKey = strings.ToUpper(args[0])
+		}
+
+		checkIssueKey(&IssueKey, IssueFile)
+
+		if WatchUser != "" {
+			checkPermission(IssueKey, "manage the watchers", permManageWatchers)
+		}
+
+		watchers := must(JiraClient.GetWatchers(ctx, IssueKey))
+		if !isWatching(watchers, WatchUser) {
+			success("%s not watching %s", watcherSubject(), IssueKey)
+
+			return
+		}
+
+		var err error
+
+		if WatchUser == "" {
+			err = JiraClient.Unwatch(ctx, IssueKey)
+		} else {
+			err = JiraClient.RemoveWatcher(ctx, IssueKey, WatchUser)
+		}
+
+		if err != nil {
+			fmt.Printf("Failed to remove the watcher from %s - %s\n", IssueKey, err.Error())
+			os.Exit(exitCode(err))
+		}
+
+		success("%s no longer watching %s", watcherSubject(), IssueKey)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+	rootCmd.AddCommand(unwatchCmd)
+
+	watchCmd.SetUsageTemplate(watchUsage)
+	watchCmd.Flags().StringVarP(&WatchUser, "user", "u", "", "add the user as a watcher")
+	unwatchCmd.SetUsageTemplate(unwatchUsage)
+	unwatchCmd.Flags().StringVarP(&WatchUser, "user", "u", "", "remove the user from the watchers")
+}
+
+// isWatching returns if the user is one of the watchers,
+// or if you are watching when the user is not set.
+func isWatching(watchers types.Watchers, user string) bool {
+	if user == "" {
+		return watchers.IsWatching
+	}
+
+	for _, w := range watchers.Watchers {
+		if w.Name == user || (w.AccountID != "" && w.AccountID == user) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// watcherSubject returns "You are", or "<user> is" when --user is set.
+func watcherSubject() string {
+	if WatchUser == "" {
+		return "You are"
+	}
+
+	return WatchUser + " is"
+}
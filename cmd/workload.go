@@ -0,0 +1,197 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mhersson/gojira/pkg/types"
+	"github.com/mhersson/gojira/pkg/util/convert"
+	"github.com/mhersson/gojira/pkg/util/format"
+)
+
+const getWorkloadUsage string = `
+Sums up the unresolved issues per assignee, with the remaining estimate
+and the number of overdue issues, to balance the assignments during planning.
+
+On a board with sprint support the issues in the active sprint are
+counted, together with all overdue issues on the board. On a kanban
+board all unresolved issues are counted.
+
+By default the active sprint board is used.
+
+Usage:
+  gojira get workload [NAME OF BOARD] [flags]
+
+Aliases:
+  workload, load
+
+Flags:
+  -h, --help                   help for workload
+  -o, --output [FORMAT]        output format, csv is the only one supported
+`
+
+const unassigned = "Unassigned"
+
+type workload struct {
+	Assignee  string
+	Issues    int
+	Remaining int // Seconds
+	Overdue   int
+}
+
+var getWorkloadCmd = &cobra.Command{
+	Use:     "workload",
+	Short:   "Display the workload per assignee on a board",
+	Aliases: []string{"load"},
+	Args:    cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		checkOutputFormat("csv")
+
+		board := boardsToShow(args, "sprint")[0]
+
+		rapidView := must(JiraClient.GetRapidViewID(ctx, board))
+		if rapidView == nil {
+			fmt.Printf("Board %s does not exist\n", board)
+			os.Exit(exitNotFound)
+		}
+
+		jql := "resolution = Unresolved"
+
+		if rapidView.SprintSupportEnabled {
+			ids := []string{}
+
+			for _, s := range getOpenSprints(rapidView) {
+				if s.State == "ACTIVE" {
+					ids = append(ids, strconv.Itoa(s.ID))
+				}
+			}
+
+			if len(ids) == 0 {
+				jql = "duedate < startOfDay() AND " + jql
+			} else {
+				jql = "(sprint in (" + strings.Join(ids, ",") + ") OR duedate < startOfDay()) AND " + jql
+			}
+		}
+
+		workloads := getWorkloads(must(JiraClient.GetWorkloadIssues(ctx, rapidView.ID, jql)), time.Now())
+
+		if OutputFormat == "csv" {
+			printWorkloadsCSV(workloads)
+
+			return
+		}
+
+		header(format.KanbanBoardHeader(rapidView.Name))
+		printWorkloads(workloads)
+	},
+}
+
+func init() {
+	getCmd.AddCommand(getWorkloadCmd)
+
+	getWorkloadCmd.SetUsageTemplate(getWorkloadUsage)
+}
+
+// getWorkloads sums up the issues per assignee, with the
+// largest remaining estimate first, and unassigned issues last.
+func getWorkloads(issues []types.WorkloadIssue, now time.Time) []workload {
+	byAssignee := map[string]*workload{}
+	today := now.Format("2006-01-02")
+
+	for _, i := range issues {
+		assignee := i.Fields.Assignee.DisplayName
+		if assignee == "" {
+			assignee = unassigned
+		}
+
+		w, ok := byAssignee[assignee]
+		if !ok {
+			w = &workload{Assignee: assignee}
+			byAssignee[assignee] = w
+		}
+
+		w.Issues++
+		w.Remaining += i.Fields.TimeEstimate
+
+		if i.Fields.DueDate != "" && i.Fields.DueDate < today {
+			w.Overdue++
+		}
+	}
+
+	workloads := []workload{}
+	for _, w := range byAssignee {
+		workloads = append(workloads, *w)
+	}
+
+	slices.SortFunc(workloads, func(a, b workload) int {
+		switch {
+		case a.Assignee == unassigned:
+			return 1
+		case b.Assignee == unassigned:
+			return -1
+		case a.Remaining != b.Remaining:
+			return b.Remaining - a.Remaining
+		}
+
+		return strings.Compare(a.Assignee, b.Assignee)
+	})
+
+	return workloads
+}
+
+func printWorkloads(workloads []workload) {
+	if len(workloads) == 0 {
+		fmt.Println("There are no unresolved issues on the board")
+
+		return
+	}
+
+	fmt.Printf("%s%s%-30s%-10s%-12s%s%s\n", format.Color.Ul, format.Color.Yellow,
+		"Assignee", "Issues", "Remaining", "Overdue", format.Color.Nocolor)
+
+	total := workload{}
+
+	for _, w := range workloads {
+		overdue := strconv.Itoa(w.Overdue)
+		if w.Overdue > 0 {
+			overdue = format.Color.Red + overdue + format.Color.Nocolor
+		}
+
+		fmt.Printf("%-30s%-10d%-12s%s\n", w.Assignee, w.Issues,
+			convert.SecondsToHoursAndMinutes(w.Remaining, false), overdue)
+
+		total.Issues += w.Issues
+		total.Remaining += w.Remaining
+		total.Overdue += w.Overdue
+	}
+
+	fmt.Printf("%s%-30s%-10d%-12s%d%s\n", format.Color.Bold, "Total", total.Issues,
+		convert.SecondsToHoursAndMinutes(total.Remaining, false), total.Overdue, format.Color.Nocolor)
+}
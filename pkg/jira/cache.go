@@ -0,0 +1,90 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package jira
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// existsCache remembers the issues found to exist, so commands run
+// shortly after each other do not check the same issue again. Only
+// existing issues are cached, as a missing issue may be created.
+type existsCache struct {
+	mu      sync.Mutex
+	file    string
+	ttl     time.Duration
+	checked map[string]time.Time
+}
+
+func (e *existsCache) load() {
+	if e.checked != nil {
+		return
+	}
+
+	e.checked = map[string]time.Time{}
+
+	data, err := os.ReadFile(e.file)
+	if err != nil {
+		return
+	}
+
+	_ = json.Unmarshal(data, &e.checked)
+}
+
+func (e *existsCache) has(key string) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.load()
+
+	checked, ok := e.checked[key]
+
+	return ok && time.Since(checked) < e.ttl
+}
+
+// add remembers the key, and saves the cache without the expired keys.
+func (e *existsCache) add(key string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.load()
+	e.checked[key] = time.Now()
+
+	for k, checked := range e.checked {
+		if time.Since(checked) >= e.ttl {
+			delete(e.checked, k)
+		}
+	}
+
+	data, err := json.Marshal(e.checked)
+	if err != nil {
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(e.file), 0o700); err == nil {
+		_ = os.WriteFile(e.file, data, 0o600)
+	}
+}
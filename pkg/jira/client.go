@@ -0,0 +1,172 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package jira
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mhersson/gojira/pkg/types"
+	"github.com/mhersson/gojira/pkg/util/calendar"
+)
+
+// Client talks to one Jira server. It is safe for concurrent use,
+// but must not be copied after first use. The setters are not safe
+// for concurrent use, so configure the client before using it.
+type Client struct {
+	cfg         types.JiraConfig
+	authMu      sync.Mutex
+	transport   http.RoundTripper
+	searchLimit int
+	existing    *existsCache
+	timeout     time.Duration
+	maxAttempts int
+	httpClient  *http.Client
+	cloud       bool
+	session     bool
+	loggedIn    bool
+	weeks       calendar.Rule
+}
+
+// maxConnsPerHost is the number of connections kept open to Jira, enough
+// for the commands sending many requests concurrently.
+const maxConnsPerHost = 16
+
+// DefaultClient is the client used by the package level functions.
+var DefaultClient = newClient()
+
+// NewClient returns a client for the Jira server in the config.
+func NewClient(config types.Config) *Client {
+	c := newClient()
+	c.Configure(config)
+
+	return c
+}
+
+func newClient() *Client {
+	transport := NewTransport()
+
+	return &Client{transport: transport, httpClient: &http.Client{Transport: transport}}
+}
+
+// NewTransport returns a transport keeping the connections to Jira open
+// between the requests, so each request does not pay for a new TLS
+// handshake. Wrap it when replacing the transport of a client.
+func NewTransport() *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.MaxIdleConnsPerHost = maxConnsPerHost
+
+	return t
+}
+
+// Configure sets the server and credentials of the client, and if it
+// talks to Jira Cloud. The password is decrypted again, and with session
+// auth a new session is created, before the next request.
+func (c *Client) Configure(config types.Config) {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+
+	c.cfg.Server = config.JiraURL
+	c.cfg.Username = config.Username
+	c.cfg.Password = config.Password
+	c.cfg.PasswordType = config.PasswordType
+	c.cfg.PasswordCache = config.PasswordCache
+	c.cfg.Decrypted = false
+	c.cloud = IsCloud(config)
+	c.session = useSession(config)
+	c.loggedIn = false
+	c.httpClient.Jar = newCookieJar(c.session)
+
+	// An unknown rule is reported when the config is validated
+	c.weeks, _ = calendar.ParseRule(config.WeekNumbering)
+}
+
+// IsCloud reports if the config is for Jira Cloud, either by the deployment,
+// or by the server being on atlassian.net if the deployment is auto.
+func IsCloud(config types.Config) bool {
+	switch strings.ToLower(config.Deployment) {
+	case "cloud":
+		return true
+	case "auto":
+		u, err := url.Parse(config.JiraURL)
+
+		return err == nil && strings.HasSuffix(u.Hostname(), ".atlassian.net")
+	}
+
+	return false
+}
+
+// SetTransport replaces the transport used for all requests to Jira,
+// e.g. to record or replay the traffic.
+func (c *Client) SetTransport(t http.RoundTripper) {
+	c.transport = t
+	c.httpClient.Transport = c.roundTripper()
+}
+
+// Transport returns the transport used for all requests to Jira,
+// e.g. to wrap it and set the wrapper with SetTransport.
+func (c *Client) Transport() http.RoundTripper {
+	return c.transport
+}
+
+// SetSearchLimit caps the number of issues returned by the searches,
+// 0 means no limit.
+func (c *Client) SetSearchLimit(limit int) {
+	c.searchLimit = limit
+}
+
+// SetExistsCache makes the client remember the issues found to exist
+// in the file for the ttl, 0 disables the cache.
+func (c *Client) SetExistsCache(file string, ttl time.Duration) {
+	if ttl <= 0 {
+		c.existing = nil
+
+		return
+	}
+
+	c.existing = &existsCache{file: file, ttl: ttl}
+}
+
+// SetTimeout limits the time waiting for each request to Jira,
+// 0 means no limit other than the context of the request.
+func (c *Client) SetTimeout(timeout time.Duration) {
+	c.timeout = timeout
+}
+
+// SetMaxAttempts makes the client retry requests rejected by a busy or
+// rate limiting Jira, up to max attempts in total. 1 disables retries.
+func (c *Client) SetMaxAttempts(attempts int) {
+	c.maxAttempts = attempts
+	c.httpClient.Transport = c.roundTripper()
+}
+
+// roundTripper returns the transport, with retries if enabled.
+func (c *Client) roundTripper() http.RoundTripper {
+	if c.maxAttempts <= 1 {
+		return c.transport
+	}
+
+	return &retryTransport{next: c.transport, maxAttempts: c.maxAttempts}
+}
@@ -0,0 +1,535 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/mhersson/gojira/pkg/types"
+	"github.com/mhersson/gojira/pkg/util"
+	"github.com/mhersson/gojira/pkg/util/validate"
+)
+
+// The functions in this file use the default client, and are kept for
+// callers written before the Client was introduced. Like before, they
+// exit if Jira responds with an error.
+
+func exitOnError(err error) {
+	if err != nil {
+		log.Fatalf("Error:%s", err)
+	}
+}
+
+func must[T any](result T, err error) T {
+	exitOnError(err)
+
+	return result
+}
+
+// Configure configures the default client.
+//
+// Deprecated: use NewClient or Client.Configure.
+func Configure(config types.Config) {
+	DefaultClient.Configure(config)
+}
+
+// SetTransport replaces the transport of the default client.
+//
+// Deprecated: use Client.SetTransport.
+func SetTransport(t http.RoundTripper) {
+	DefaultClient.SetTransport(t)
+}
+
+// SetSearchLimit caps the searches of the default client.
+//
+// Deprecated: use Client.SetSearchLimit.
+func SetSearchLimit(limit int) {
+	DefaultClient.SetSearchLimit(limit)
+}
+
+// GetIssues calls GetIssues on the default client.
+//
+// Deprecated: use Client.GetIssues.
+func GetIssues(filter string) []types.Issue {
+	return must(DefaultClient.GetIssues(context.Background(), filter))
+}
+
+// GetIssuesOrderedBy calls GetIssuesOrderedBy on the default client.
+//
+// Deprecated: use Client.GetIssuesOrderedBy.
+func GetIssuesOrderedBy(filter, orderBy string) []types.Issue {
+	return must(DefaultClient.GetIssuesOrderedBy(context.Background(), filter, orderBy))
+}
+
+// GetIssuesSelecting calls GetIssuesSelecting on the default client.
+//
+// Deprecated: use Client.GetIssuesSelecting.
+func GetIssuesSelecting(filter, orderBy string, fields []string) []types.Issue {
+	return must(DefaultClient.GetIssuesSelecting(context.Background(), filter, orderBy, fields))
+}
+
+// GetIssuesWithFields calls GetIssuesWithFields on the default client.
+//
+// Deprecated: use Client.GetIssuesWithFields.
+func GetIssuesWithFields(filter, orderBy string, fields []string) []types.RawIssue {
+	return must(DefaultClient.GetIssuesWithFields(context.Background(), filter, orderBy, fields))
+}
+
+// GetDependencyIssues calls GetDependencyIssues on the default client.
+//
+// Deprecated: use Client.GetDependencyIssues.
+func GetDependencyIssues(filter string) []types.DependencyIssue {
+	return must(DefaultClient.GetDependencyIssues(context.Background(), filter))
+}
+
+// GetIssueDescriptions calls GetIssueDescriptions on the default client.
+//
+// Deprecated: use Client.GetIssueDescriptions.
+func GetIssueDescriptions(filter, orderBy string) []types.IssueDescription {
+	return must(DefaultClient.GetIssueDescriptions(context.Background(), filter, orderBy))
+}
+
+// GetServerInfo calls GetServerInfo on the default client.
+//
+// Deprecated: use Client.GetServerInfo.
+func GetServerInfo() (types.ServerInfo, error) {
+	return DefaultClient.GetServerInfo(context.Background())
+}
+
+// GetFavouriteFilters calls GetFavouriteFilters on the default client.
+//
+// Deprecated: use Client.GetFavouriteFilters.
+func GetFavouriteFilters() []types.Filter {
+	return must(DefaultClient.GetFavouriteFilters(context.Background()))
+}
+
+// GetFilter calls GetFilter on the default client.
+//
+// Deprecated: use Client.GetFilter.
+func GetFilter(id string) types.Filter {
+	return must(DefaultClient.GetFilter(context.Background(), id))
+}
+
+// GetTimesheet calls GetTimesheet on the default client.
+//
+// Deprecated: use Client.GetTimesheet.
+func GetTimesheet(fromDate, toDate string, showEntireWeek bool) []types.Timesheet {
+	return must(DefaultClient.GetTimesheet(context.Background(), fromDate, toDate, showEntireWeek))
+}
+
+// GetTimesheetForUser calls GetTimesheetForUser on the default client.
+//
+// Deprecated: use Client.GetTimesheetForUser.
+func GetTimesheetForUser(date, username string) []types.Timesheet {
+	return must(DefaultClient.GetTimesheetForUser(context.Background(), date, username))
+}
+
+// GetValidProjects calls GetValidProjects on the default client.
+//
+// Deprecated: use Client.GetValidProjects.
+func GetValidProjects() []types.Project {
+	return must(DefaultClient.GetValidProjects(context.Background()))
+}
+
+// GetComponents calls GetComponents on the default client.
+//
+// Deprecated: use Client.GetComponents.
+func GetComponents(projectKey string) []types.Component {
+	return must(DefaultClient.GetComponents(context.Background(), projectKey))
+}
+
+// GetVersions calls GetVersions on the default client.
+//
+// Deprecated: use Client.GetVersions.
+func GetVersions(projectKey string) []types.Version {
+	return must(DefaultClient.GetVersions(context.Background(), projectKey))
+}
+
+// GetProjectIssueTypes calls GetProjectIssueTypes on the default client.
+//
+// Deprecated: use Client.GetProjectIssueTypes.
+func GetProjectIssueTypes(projectKey string) []types.IssueType {
+	return must(DefaultClient.GetProjectIssueTypes(context.Background(), projectKey))
+}
+
+// GetPriorities calls GetPriorities on the default client.
+//
+// Deprecated: use Client.GetPriorities.
+func GetPriorities() []types.Priority {
+	return must(DefaultClient.GetPriorities(context.Background()))
+}
+
+// GetIssueTypes calls GetIssueTypes on the default client.
+//
+// Deprecated: use Client.GetIssueTypes.
+func GetIssueTypes() *[]types.IssueType {
+	return must(DefaultClient.GetIssueTypes(context.Background()))
+}
+
+// GetIssue calls GetIssue on the default client.
+//
+// Deprecated: use Client.GetIssue.
+func GetIssue(key string) types.IssueDescription {
+	return must(DefaultClient.GetIssue(context.Background(), key))
+}
+
+// GetWatchers calls GetWatchers on the default client.
+//
+// Deprecated: use Client.GetWatchers.
+func GetWatchers(key string) types.Watchers {
+	return must(DefaultClient.GetWatchers(context.Background(), key))
+}
+
+// GetIssueField calls GetIssueField on the default client.
+//
+// Deprecated: use Client.GetIssueField.
+func GetIssueField(key, field string) json.RawMessage {
+	return must(DefaultClient.GetIssueField(context.Background(), key, field))
+}
+
+// GetIssueFields calls GetIssueFields on the default client.
+//
+// Deprecated: use Client.GetIssueFields.
+func GetIssueFields(key string, fields []string) (map[string]json.RawMessage, map[string]string) {
+	values, names, err := DefaultClient.GetIssueFields(context.Background(), key, fields)
+	exitOnError(err)
+
+	return values, names
+}
+
+// GetInsightObject calls GetInsightObject on the default client.
+//
+// Deprecated: use Client.GetInsightObject.
+func GetInsightObject(objectKey string) (types.InsightObject, error) {
+	return DefaultClient.GetInsightObject(context.Background(), objectKey)
+}
+
+// GetTimeSpent calls GetTimeSpent on the default client.
+//
+// Deprecated: use Client.GetTimeSpent.
+func GetTimeSpent(key string) int {
+	return must(DefaultClient.GetTimeSpent(context.Background(), key))
+}
+
+// GetIssuesInEpic calls GetIssuesInEpic on the default client.
+//
+// Deprecated: use Client.GetIssuesInEpic.
+func GetIssuesInEpic(key string) []types.Issue {
+	return must(DefaultClient.GetIssuesInEpic(context.Background(), key))
+}
+
+// GetTransistions calls GetTransistions on the default client.
+//
+// Deprecated: use Client.GetTransistions.
+func GetTransistions(key string) []types.Transition {
+	return must(DefaultClient.GetTransistions(context.Background(), key))
+}
+
+// GetComments calls GetComments on the default client.
+//
+// Deprecated: use Client.GetComments.
+func GetComments(key string) []types.Comment {
+	return must(DefaultClient.GetComments(context.Background(), key))
+}
+
+// GetWorklogs calls GetWorklogs on the default client.
+//
+// Deprecated: use Client.GetWorklogs.
+func GetWorklogs(key string) []types.Worklog {
+	return must(DefaultClient.GetWorklogs(context.Background(), key))
+}
+
+// GetRapidViews calls GetRapidViews on the default client.
+//
+// Deprecated: use Client.GetRapidViews.
+func GetRapidViews() []types.RapidView {
+	return must(DefaultClient.GetRapidViews(context.Background()))
+}
+
+// GetRapidViewID calls GetRapidViewID on the default client.
+//
+// Deprecated: use Client.GetRapidViewID.
+func GetRapidViewID(board string) *types.RapidView {
+	return must(DefaultClient.GetRapidViewID(context.Background(), board))
+}
+
+// GetSprints calls GetSprints on the default client.
+//
+// Deprecated: use Client.GetSprints.
+func GetSprints(rapidViewID int) ([]types.Sprint, []types.SprintIssue) {
+	sprints, issues, err := DefaultClient.GetSprints(context.Background(), rapidViewID)
+	exitOnError(err)
+
+	return sprints, issues
+}
+
+// GetSprintReport calls GetSprintReport on the default client.
+//
+// Deprecated: use Client.GetSprintReport.
+func GetSprintReport(rapidViewID, sprintID int) types.SprintReport {
+	return must(DefaultClient.GetSprintReport(context.Background(), rapidViewID, sprintID))
+}
+
+// GetBoardEpics calls GetBoardEpics on the default client.
+//
+// Deprecated: use Client.GetBoardEpics.
+func GetBoardEpics(boardID int) []types.Epic {
+	return must(DefaultClient.GetBoardEpics(context.Background(), boardID))
+}
+
+// GetWorkloadIssues calls GetWorkloadIssues on the default client.
+//
+// Deprecated: use Client.GetWorkloadIssues.
+func GetWorkloadIssues(boardID int, jql string) []types.WorkloadIssue {
+	return must(DefaultClient.GetWorkloadIssues(context.Background(), boardID, jql))
+}
+
+// GetBoardSprints calls GetBoardSprints on the default client.
+//
+// Deprecated: use Client.GetBoardSprints.
+func GetBoardSprints(boardID int) []types.BoardSprint {
+	return must(DefaultClient.GetBoardSprints(context.Background(), boardID))
+}
+
+// GetKanbanIssues calls GetKanbanIssues on the default client.
+//
+// Deprecated: use Client.GetKanbanIssues.
+func GetKanbanIssues(boardID int, orderBy string) []types.Issue {
+	return must(DefaultClient.GetKanbanIssues(context.Background(), boardID, orderBy))
+}
+
+// CheckIssueKey exits if the key is invalid or the issue does not
+// exist, or sets the key to the active issue if it is empty.
+//
+// Deprecated: use Client.IssueExists.
+func CheckIssueKey(key *string, issueFile string) {
+	if *key == "" {
+		active, err := util.GetActiveIssue(issueFile)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+
+		*key = active
+
+		return
+	}
+
+	if !validate.IssueKey(key) {
+		fmt.Println("Invalid key")
+		os.Exit(1)
+	}
+
+	if !IssueExists(key) {
+		fmt.Printf("%s does not exist\n", *key)
+		os.Exit(1)
+	}
+}
+
+// IssueExists calls IssueExists on the default client.
+//
+// Deprecated: use Client.IssueExists.
+func IssueExists(issueKey *string) bool {
+	return must(DefaultClient.IssueExists(context.Background(), issueKey))
+}
+
+// UserExists calls UserExists on the default client.
+//
+// Deprecated: use Client.UserExists.
+func UserExists(username string) bool {
+	return must(DefaultClient.UserExists(context.Background(), username))
+}
+
+// GetMyPermissions calls GetMyPermissions on the default client.
+//
+// Deprecated: use Client.GetMyPermissions.
+func GetMyPermissions(key string, permissions ...string) map[string]types.Permission {
+	return must(DefaultClient.GetMyPermissions(context.Background(), key, permissions...))
+}
+
+// UpdateStatus calls UpdateStatus on the default client.
+//
+// Deprecated: use Client.UpdateStatus.
+func UpdateStatus(key, id string) error {
+	return DefaultClient.UpdateStatus(context.Background(), key, id)
+}
+
+// UnassignIssue calls UnassignIssue on the default client.
+//
+// Deprecated: use Client.UnassignIssue.
+func UnassignIssue(key string) error {
+	return DefaultClient.UnassignIssue(context.Background(), key)
+}
+
+// UpdateAssignee calls UpdateAssignee on the default client.
+//
+// Deprecated: use Client.UpdateAssignee.
+func UpdateAssignee(key string, user string) error {
+	return DefaultClient.UpdateAssignee(context.Background(), key, user)
+}
+
+// UpdatePriority calls UpdatePriority on the default client.
+//
+// Deprecated: use Client.UpdatePriority.
+func UpdatePriority(key string, priorityID string) error {
+	return DefaultClient.UpdatePriority(context.Background(), key, priorityID)
+}
+
+// UpdateField calls UpdateField on the default client.
+//
+// Deprecated: use Client.UpdateField.
+func UpdateField(key, field, value string) error {
+	return DefaultClient.UpdateField(context.Background(), key, field, value)
+}
+
+// UpdateInsightField calls UpdateInsightField on the default client.
+//
+// Deprecated: use Client.UpdateInsightField.
+func UpdateInsightField(key, field string, objectKeys []string) error {
+	return DefaultClient.UpdateInsightField(context.Background(), key, field, objectKeys)
+}
+
+// AddWatcher calls AddWatcher on the default client.
+//
+// Deprecated: use Client.AddWatcher.
+func AddWatcher(key string, user string) error {
+	return DefaultClient.AddWatcher(context.Background(), key, user)
+}
+
+// AddAttachment calls AddAttachment on the default client.
+//
+// Deprecated: use Client.AddAttachment.
+func AddAttachment(key, file string) error {
+	return DefaultClient.AddAttachment(context.Background(), key, file)
+}
+
+// GetIssueLinkTypes calls GetIssueLinkTypes on the default client.
+//
+// Deprecated: use Client.GetIssueLinkTypes.
+func GetIssueLinkTypes() []types.IssueLinkType {
+	return must(DefaultClient.GetIssueLinkTypes(context.Background()))
+}
+
+// LinkIssues calls LinkIssues on the default client.
+//
+// Deprecated: use Client.LinkIssues.
+func LinkIssues(linkType, from, to string) error {
+	return DefaultClient.LinkIssues(context.Background(), linkType, from, to)
+}
+
+// CreateNewIssue calls CreateNewIssue on the default client.
+//
+// Deprecated: use Client.CreateNewIssue.
+func CreateNewIssue(project types.Project, issueTypeID,
+	priorityID, summary, description string,
+) (string, error) {
+	return DefaultClient.CreateNewIssue(context.Background(), project, issueTypeID, priorityID, summary, description)
+}
+
+// CreateComponent calls CreateComponent on the default client.
+//
+// Deprecated: use Client.CreateComponent.
+func CreateComponent(projectKey, name, lead, description string) error {
+	return DefaultClient.CreateComponent(context.Background(), projectKey, name, lead, description)
+}
+
+// CreateVersion calls CreateVersion on the default client.
+//
+// Deprecated: use Client.CreateVersion.
+func CreateVersion(projectKey, name, releaseDate, description string) error {
+	return DefaultClient.CreateVersion(context.Background(), projectKey, name, releaseDate, description)
+}
+
+// ReleaseVersion calls ReleaseVersion on the default client.
+//
+// Deprecated: use Client.ReleaseVersion.
+func ReleaseVersion(id, releaseDate string) error {
+	return DefaultClient.ReleaseVersion(context.Background(), id, releaseDate)
+}
+
+// ArchiveVersion calls ArchiveVersion on the default client.
+//
+// Deprecated: use Client.ArchiveVersion.
+func ArchiveVersion(id string) error {
+	return DefaultClient.ArchiveVersion(context.Background(), id)
+}
+
+// AddWorklog calls AddWorklog on the default client.
+//
+// Deprecated: use Client.AddWorklog.
+func AddWorklog(wDate, wTime, key, seconds, comment string) error {
+	return DefaultClient.AddWorklog(context.Background(), wDate, wTime, key, seconds, comment)
+}
+
+// AddWorklogWithAttributes calls AddWorklogWithAttributes on the default client.
+//
+// Deprecated: use Client.AddWorklogWithAttributes.
+func AddWorklogWithAttributes(wDate, wTime, key, seconds, comment string, attrs types.WorklogAttributes) error {
+	return DefaultClient.AddWorklogWithAttributes(context.Background(), wDate, wTime, key, seconds, comment, attrs)
+}
+
+// CopyWorklog calls CopyWorklog on the default client.
+//
+// Deprecated: use Client.CopyWorklog.
+func CopyWorklog(key string, worklog types.Worklog) error {
+	return DefaultClient.CopyWorklog(context.Background(), key, worklog)
+}
+
+// DeleteWorklog calls DeleteWorklog on the default client.
+//
+// Deprecated: use Client.DeleteWorklog.
+func DeleteWorklog(key, id string) error {
+	return DefaultClient.DeleteWorklog(context.Background(), key, id)
+}
+
+// AddComment calls AddComment on the default client.
+//
+// Deprecated: use Client.AddComment.
+func AddComment(key string, comment []byte) error {
+	return DefaultClient.AddComment(context.Background(), key, comment)
+}
+
+// UpdateDescription calls UpdateDescription on the default client.
+//
+// Deprecated: use Client.UpdateDescription.
+func UpdateDescription(key string, desc []byte) error {
+	return DefaultClient.UpdateDescription(context.Background(), key, desc)
+}
+
+// UpdateComment calls UpdateComment on the default client.
+//
+// Deprecated: use Client.UpdateComment.
+func UpdateComment(key string, comment []byte, id string) error {
+	return DefaultClient.UpdateComment(context.Background(), key, comment, id)
+}
+
+// UpdateWorklog calls UpdateWorklog on the default client.
+//
+// Deprecated: use Client.UpdateWorklog.
+func UpdateWorklog(worklog types.SimplifiedTimesheet) error {
+	return DefaultClient.UpdateWorklog(context.Background(), worklog)
+}
@@ -0,0 +1,108 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package jira
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// ErrNoTimesheetPlugin is returned by the timesheet requests when
+// the timesheet plugin is not installed in Jira.
+var ErrNoTimesheetPlugin = errors.New("the timesheet plugin is not installed")
+
+// ErrNoReactions is returned when Jira does not support comment reactions.
+var ErrNoReactions = errors.New("comment reactions are not supported")
+
+// APIError is returned when Jira responds with an error status,
+// with the error messages from the response, if any, and the path
+// of the request.
+type APIError struct {
+	StatusCode int
+	Status     string
+	Messages   []string
+	Endpoint   string
+}
+
+func (e *APIError) Error() string {
+	if len(e.Messages) == 0 {
+		return e.Status
+	}
+
+	return e.Status + " - " + strings.Join(e.Messages, ", ")
+}
+
+func newAPIError(resp *http.Response, body []byte) *APIError {
+	e := &APIError{StatusCode: resp.StatusCode, Status: checkResponseCode(resp)}
+
+	if resp.Request != nil {
+		e.Endpoint = resp.Request.URL.Path
+	}
+
+	messages := struct {
+		ErrorMessages []string          `json:"errorMessages"`
+		Errors        map[string]string `json:"errors"`
+	}{}
+
+	if json.Unmarshal(body, &messages) != nil {
+		return e
+	}
+
+	e.Messages = messages.ErrorMessages
+
+	fields := []string{}
+	for f := range messages.Errors {
+		fields = append(fields, f)
+	}
+
+	slices.Sort(fields)
+
+	for _, f := range fields {
+		e.Messages = append(e.Messages, f+": "+messages.Errors[f])
+	}
+
+	return e
+}
+
+// reactionsError tells a Jira without comment reactions apart from other errors.
+func reactionsError(err error) error {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%w: %w", ErrNoReactions, err)
+	}
+
+	return err
+}
+
+// timesheetError tells a missing timesheet plugin apart from other errors.
+func timesheetError(err error) error {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%w: %w", ErrNoTimesheetPlugin, err)
+	}
+
+	return err
+}
@@ -26,11 +26,14 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log"
+	"mime/multipart"
 	"net/http"
+	neturl "net/url"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
@@ -38,313 +41,1194 @@ import (
 
 	"github.com/mhersson/gojira/pkg/types"
 	"github.com/mhersson/gojira/pkg/util"
-	"github.com/mhersson/gojira/pkg/util/validate"
+	"github.com/mhersson/gojira/pkg/util/adf"
 )
 
-var jcfg types.JiraConfig
-
 const restAPIIssueURL = "/rest/api/2/issue/"
 
-func Configure(config types.Config) {
-	jcfg.Server = config.JiraURL
-	jcfg.Username = config.Username
-	jcfg.Password = config.Password
-	jcfg.PasswordType = config.PasswordType
-	jcfg.Decrypted = false
-}
+// searchPageSize is the number of issues asked for per search request.
+// Jira may return fewer, e.g. when the server caps maxResults lower.
+const searchPageSize = 50
 
-func GetIssues(filter string) []types.Issue {
-	url := jcfg.Server + "/rest/api/2/search"
+// Orderings used unless the filter has its own.
+const (
+	OrderByPriority = "priority, updated"
+	OrderByRank     = "rank"
+)
 
-	if filter == "" {
-		filter = `assignee = ` + jcfg.Username +
-			` AND resolution = Unresolved order by priority, updated`
-	} else {
-		filter += " order by priority, updated"
-	}
-
-	payload := []byte(`{"jql": "` + filter + `",
-		"startAt":0,
-		"maxResults":50,
-		"fields":[
-		"summary",
-		"status",
-		"updated",
-		"assignee",
-		"issuetype",
-		"priority"]
-	}`)
+// The fields needed to display issues in a table, and the fields
+// needed by commands that only look up the summary, status or type.
+var (
+	TableFields = []string{"summary", "status", "updated", "assignee", "issuetype", "priority"}
+	BriefFields = []string{"summary", "status", "issuetype", "resolution"}
+)
+
+func (c *Client) GetIssues(ctx context.Context, filter string) ([]types.Issue, error) {
+	return c.GetIssuesOrderedBy(ctx, filter, OrderByPriority)
+}
+
+func (c *Client) GetIssuesOrderedBy(ctx context.Context, filter, orderBy string) ([]types.Issue, error) {
+	return c.GetIssuesSelecting(ctx, filter, orderBy, TableFields)
+}
 
+// GetIssuesSelecting returns the issues with only the given fields,
+// to keep the payload small when the other fields are not used.
+func (c *Client) GetIssuesSelecting(ctx context.Context, filter, orderBy string, fields []string) ([]types.Issue, error) {
 	jsonResponse := new(struct {
 		Issues []types.Issue `json:"issues"`
 	})
 
-	query(http.MethodPost, url, payload, jsonResponse)
+	if err := c.search(ctx, filter, orderBy, fields, jsonResponse); err != nil {
+		return nil, err
+	}
+
+	return jsonResponse.Issues, nil
+}
+
+// GetIssuesWithFields returns the issues matching the filter with the
+// given fields as raw json, for fields not known in advance.
+func (c *Client) GetIssuesWithFields(ctx context.Context, filter, orderBy string, fields []string) ([]types.RawIssue, error) {
+	jsonResponse := new(struct {
+		Issues []types.RawIssue `json:"issues"`
+	})
+
+	if err := c.search(ctx, filter, orderBy, fields, jsonResponse); err != nil {
+		return nil, err
+	}
+
+	return jsonResponse.Issues, nil
+}
+
+// GetDependencyIssues returns the issues matching the filter with
+// their remaining estimate and links.
+func (c *Client) GetDependencyIssues(ctx context.Context, filter string) ([]types.DependencyIssue, error) {
+	jsonResponse := new(struct {
+		Issues []types.DependencyIssue `json:"issues"`
+	})
+
+	if err := c.search(ctx, filter, OrderByRank, []string{"summary", "status", "timeestimate", "issuelinks"}, jsonResponse); err != nil {
+		return nil, err
+	}
+
+	return jsonResponse.Issues, nil
+}
+
+// GetTimeTrackingIssues returns the issues matching the filter
+// with their estimates, time spent and epic, also summed up with
+// their subtasks.
+func (c *Client) GetTimeTrackingIssues(ctx context.Context, filter string) ([]types.TimeTrackingIssue, error) {
+	jsonResponse := new(struct {
+		Issues []types.TimeTrackingIssue `json:"issues"`
+	})
+
+	if err := c.search(ctx, filter, OrderByRank, []string{
+		"summary", "customfield_10500", "timeoriginalestimate", "timespent", "timeestimate",
+		"aggregatetimeoriginalestimate", "aggregatetimespent", "aggregatetimeestimate",
+	}, jsonResponse); err != nil {
+		return nil, err
+	}
+
+	return jsonResponse.Issues, nil
+}
+
+// GetLabelIssues returns the issues matching the filter
+// with their labels and time spent.
+func (c *Client) GetLabelIssues(ctx context.Context, filter string) ([]types.LabelIssue, error) {
+	jsonResponse := new(struct {
+		Issues []types.LabelIssue `json:"issues"`
+	})
+
+	if err := c.search(ctx, filter, OrderByPriority, []string{"labels", "timespent"}, jsonResponse); err != nil {
+		return nil, err
+	}
+
+	return jsonResponse.Issues, nil
+}
+
+// GetReviewIssues returns the issues matching the filter
+// with their project, epic, created and resolution dates.
+func (c *Client) GetReviewIssues(ctx context.Context, filter string) ([]types.ReviewIssue, error) {
+	jsonResponse := new(struct {
+		Issues []types.ReviewIssue `json:"issues"`
+	})
+
+	if err := c.search(ctx, filter, "key", []string{
+		"summary", "project", "customfield_10500", "created", "resolutiondate",
+	}, jsonResponse); err != nil {
+		return nil, err
+	}
+
+	return jsonResponse.Issues, nil
+}
+
+// GetExportedIssues returns the issues matching the filter with
+// the fields copied when migrating them to another Jira server.
+func (c *Client) GetExportedIssues(ctx context.Context, filter string) ([]types.ExportedIssue, error) {
+	jsonResponse := new(struct {
+		Issues []types.ExportedIssue `json:"issues"`
+	})
+
+	fields := []string{"summary", "description", "issuetype", "priority", "project", "labels", "duedate", "attachment"}
+
+	if err := c.search(ctx, filter, "key", fields, jsonResponse); err != nil {
+		return nil, err
+	}
+
+	return jsonResponse.Issues, nil
+}
+
+// GetIssueDescriptions returns the issues matching the filter
+// with their description and comments.
+func (c *Client) GetIssueDescriptions(ctx context.Context, filter, orderBy string) ([]types.IssueDescription, error) {
+	jsonResponse := new(struct {
+		Issues []types.IssueDescription `json:"issues"`
+	})
+
+	if err := c.search(ctx, filter, orderBy, []string{
+		"summary", "description", "comment", "status", "updated", "issuetype", "priority",
+	}, jsonResponse); err != nil {
+		return nil, err
+	}
+
+	return jsonResponse.Issues, nil
+}
+
+func (c *Client) search(ctx context.Context, filter, orderBy string, fields []string, jsonResponse interface{}) error {
+	url := c.cfg.Server + "/rest/api/2/search"
+
+	if filter == "" {
+		filter = `assignee = ` + c.cfg.Username + ` AND resolution = Unresolved`
+	}
+
+	if !strings.Contains(strings.ToLower(filter), "order by") {
+		filter += " order by " + orderBy
+	}
+
+	escaped := []string{}
+	for _, f := range fields {
+		escaped = append(escaped, util.MakeStringJSONSafe(f))
+	}
+
+	// Fetch the pages until all issues, or the limit, are fetched,
+	// and decode them together into the response the caller expects.
+	issues := []json.RawMessage{}
+
+	for {
+		pageSize := searchPageSize
+		if c.searchLimit > 0 {
+			pageSize = min(pageSize, c.searchLimit-len(issues))
+		}
+
+		payload := []byte(`{"jql": "` + util.MakeStringJSONSafe(filter) + `",
+		"startAt":` + strconv.Itoa(len(issues)) + `,
+		"maxResults":` + strconv.Itoa(pageSize) + `,
+		"fields":["` + strings.Join(escaped, `","`) + `"]
+	}`)
+
+		page := new(struct {
+			Total  int               `json:"total"`
+			Issues []json.RawMessage `json:"issues"`
+		})
+
+		if err := c.query(ctx, http.MethodPost, url, payload, page); err != nil {
+			return err
+		}
+
+		issues = append(issues, page.Issues...)
+
+		if len(page.Issues) == 0 || len(issues) >= page.Total ||
+			(c.searchLimit > 0 && len(issues) >= c.searchLimit) {
+			break
+		}
+	}
+
+	if c.searchLimit > 0 && len(issues) > c.searchLimit {
+		issues = issues[:c.searchLimit]
+	}
+
+	body, _ := json.Marshal(map[string][]json.RawMessage{"issues": issues})
+
+	return decode(body, jsonResponse)
+}
+
+// GetServerInfo returns the server info, or an error if
+// the server can not be reached, e.g. when probing latency.
+func (c *Client) GetServerInfo(ctx context.Context) (types.ServerInfo, error) {
+	url := c.cfg.Server + "/rest/api/2/serverInfo"
+
+	info := types.ServerInfo{}
+
+	resp, err := c.update(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return info, err
+	}
+
+	err = decode(resp, &info)
+
+	return info, err
+}
+
+// GetMyself returns the user logged in, used to check the credentials.
+func (c *Client) GetMyself(ctx context.Context) (types.User, error) {
+	url := c.cfg.Server + "/rest/api/2/myself"
+
+	user := types.User{}
+
+	if err := c.query(ctx, http.MethodGet, url, nil, &user); err != nil {
+		return types.User{}, err
+	}
+
+	return user, nil
+}
+
+func (c *Client) GetFavouriteFilters(ctx context.Context) ([]types.Filter, error) {
+	url := c.cfg.Server + "/rest/api/2/filter/favourite"
+
+	jsonResponse := &[]types.Filter{}
+
+	if err := c.query(ctx, http.MethodGet, url, nil, jsonResponse); err != nil {
+		return nil, err
+	}
+
+	return *jsonResponse, nil
+}
+
+func (c *Client) GetFilter(ctx context.Context, id string) (types.Filter, error) {
+	url := c.cfg.Server + "/rest/api/2/filter/" + id
+
+	jsonResponse := &types.Filter{}
+
+	if err := c.query(ctx, http.MethodGet, url, nil, jsonResponse); err != nil {
+		return types.Filter{}, err
+	}
 
-	return jsonResponse.Issues
+	return *jsonResponse, nil
 }
 
-func GetTimesheet(fromDate, toDate string, showEntireWeek bool) []types.Timesheet {
-	url := jcfg.Server + "/rest/timesheet-gadget/1.0/raw-timesheet.json?startDate=" + fromDate + "&endDate=" + toDate
+func (c *Client) GetTimesheet(ctx context.Context, fromDate, toDate string, showEntireWeek bool) ([]types.Timesheet, error) {
+	url := c.cfg.Server + "/rest/timesheet-gadget/1.0/raw-timesheet.json?startDate=" + fromDate + "&endDate=" + toDate
 
 	if showEntireWeek {
 		// Date is already validated, so should be safe
 		// to drop the error check here
 		t, _ := time.Parse("2006-01-02", fromDate)
-		start, end := util.WeekStartEndDate(t.ISOWeek())
-		url = jcfg.Server + "/rest/timesheet-gadget/1.0/raw-timesheet.json?startDate=" + start + "&endDate=" + end
+		start, end := util.WeekStartEndDate(c.weeks, t)
+		url = c.cfg.Server + "/rest/timesheet-gadget/1.0/raw-timesheet.json?startDate=" + start + "&endDate=" + end
 	}
 
 	jsonResponse := new(struct {
 		Worklog []types.Timesheet `json:"worklog"`
 	})
 
-	query(http.MethodGet, url, nil, jsonResponse)
+	if err := c.query(ctx, http.MethodGet, url, nil, jsonResponse); err != nil {
+		return nil, timesheetError(err)
+	}
 
-	return jsonResponse.Worklog
+	return jsonResponse.Worklog, nil
 }
 
-func GetTimesheetForUser(date, username string) []types.Timesheet {
-	url := jcfg.Server + "/rest/timesheet-gadget/1.0/raw-timesheet.json?startDate=" +
+func (c *Client) GetTimesheetForUser(ctx context.Context, date, username string) ([]types.Timesheet, error) {
+	url := c.cfg.Server + "/rest/timesheet-gadget/1.0/raw-timesheet.json?startDate=" +
 		date + "&endDate=" + date + "&targetUser=" + username
 
 	jsonResponse := new(struct {
 		Worklog []types.Timesheet `json:"worklog"`
 	})
 
-	query(http.MethodGet, url, nil, jsonResponse)
+	if err := c.query(ctx, http.MethodGet, url, nil, jsonResponse); err != nil {
+		return nil, timesheetError(err)
+	}
 
-	return jsonResponse.Worklog
+	return jsonResponse.Worklog, nil
 }
 
-func GetValidProjects() []types.Project {
-	url := jcfg.Server + "/rest/api/2/project"
+func (c *Client) GetValidProjects(ctx context.Context) ([]types.Project, error) {
+	url := c.cfg.Server + "/rest/api/2/project"
 
 	jsonResponse := new([]types.Project)
 
-	query(http.MethodGet, url, nil, jsonResponse)
+	if err := c.query(ctx, http.MethodGet, url, nil, jsonResponse); err != nil {
+		return nil, err
+	}
+
+	return *jsonResponse, nil
+}
+
+func (c *Client) GetComponents(ctx context.Context, projectKey string) ([]types.Component, error) {
+	url := c.cfg.Server + "/rest/api/2/project/" + strings.ToUpper(projectKey) + "/components"
+
+	jsonResponse := &[]types.Component{}
+
+	if err := c.query(ctx, http.MethodGet, url, nil, jsonResponse); err != nil {
+		return nil, err
+	}
+
+	return *jsonResponse, nil
+}
+
+func (c *Client) GetVersions(ctx context.Context, projectKey string) ([]types.Version, error) {
+	url := c.cfg.Server + "/rest/api/2/project/" + strings.ToUpper(projectKey) + "/versions"
+
+	jsonResponse := &[]types.Version{}
+
+	if err := c.query(ctx, http.MethodGet, url, nil, jsonResponse); err != nil {
+		return nil, err
+	}
 
-	return *jsonResponse
+	return *jsonResponse, nil
 }
 
-func GetProjectIssueTypes(projectKey string) []types.IssueType {
-	url := jcfg.Server + "/rest/api/2/issue/createmeta/" + projectKey + "/issuetypes"
+func (c *Client) GetProjectIssueTypes(ctx context.Context, projectKey string) ([]types.IssueType, error) {
+	url := c.cfg.Server + "/rest/api/2/issue/createmeta/" + projectKey + "/issuetypes"
 
 	jsonResponse := new(struct {
 		Values []types.IssueType `json:"values"`
 	})
 
-	query(http.MethodGet, url, nil, jsonResponse)
+	if err := c.query(ctx, http.MethodGet, url, nil, jsonResponse); err != nil {
+		return nil, err
+	}
 
-	return jsonResponse.Values
+	return jsonResponse.Values, nil
 }
 
-func GetPriorities() []types.Priority {
-	url := jcfg.Server + "/rest/api/2/priority"
+func (c *Client) GetPriorities(ctx context.Context) ([]types.Priority, error) {
+	url := c.cfg.Server + "/rest/api/2/priority"
 
 	jsonResponse := &[]types.Priority{}
 
-	query(http.MethodGet, url, nil, jsonResponse)
+	if err := c.query(ctx, http.MethodGet, url, nil, jsonResponse); err != nil {
+		return nil, err
+	}
+
+	return *jsonResponse, nil
+}
+
+func (c *Client) GetIssueTypes(ctx context.Context) (*[]types.IssueType, error) {
+	url := c.cfg.Server + "/rest/api/2/issuetype"
+
+	jsonResponse := &[]types.IssueType{}
+
+	if err := c.query(ctx, http.MethodGet, url, nil, jsonResponse); err != nil {
+		return nil, err
+	}
+
+	return jsonResponse, nil
+}
+
+func (c *Client) GetIssue(ctx context.Context, key string) (types.IssueDescription, error) {
+	return c.getIssue(ctx, key, "")
+}
+
+// GetIssueWithChangelog is GetIssue with the history of the issue,
+// which can be large, so only use it when the history is needed.
+func (c *Client) GetIssueWithChangelog(ctx context.Context, key string) (types.IssueDescription, error) {
+	return c.getIssue(ctx, key, "?expand=changelog")
+}
+
+func (c *Client) getIssue(ctx context.Context, key, query string) (types.IssueDescription, error) {
+	url := c.cfg.Server + restAPIIssueURL + strings.ToUpper(key) + query
+
+	jsonResponse := &types.IssueDescription{}
+
+	if err := c.query(ctx, http.MethodGet, url, nil, jsonResponse); err != nil {
+		return types.IssueDescription{}, err
+	}
+
+	return *jsonResponse, nil
+}
+
+func (c *Client) GetWatchers(ctx context.Context, key string) (types.Watchers, error) {
+	url := c.cfg.Server + restAPIIssueURL + strings.ToUpper(key) + "/watchers"
+
+	jsonResponse := &types.Watchers{}
+
+	if err := c.query(ctx, http.MethodGet, url, nil, jsonResponse); err != nil {
+		return types.Watchers{}, err
+	}
+
+	return *jsonResponse, nil
+}
+
+// GetIssueField returns the raw json value of a single field,
+// typically a custom field not part of the issue types.
+func (c *Client) GetIssueField(ctx context.Context, key, field string) (json.RawMessage, error) {
+	url := c.cfg.Server + restAPIIssueURL + strings.ToUpper(key) + "?fields=" + field
+
+	jsonResponse := new(struct {
+		Fields map[string]json.RawMessage `json:"fields"`
+	})
+
+	if err := c.query(ctx, http.MethodGet, url, nil, jsonResponse); err != nil {
+		return nil, err
+	}
+
+	return jsonResponse.Fields[field], nil
+}
+
+// GetIssueFields returns the raw json values of the fields,
+// and the names of the fields keyed by their id.
+func (c *Client) GetIssueFields(ctx context.Context, key string, fields []string) (map[string]json.RawMessage, map[string]string, error) {
+	url := c.cfg.Server + restAPIIssueURL + strings.ToUpper(key) +
+		"?fields=" + strings.Join(fields, ",") + "&expand=names"
+
+	jsonResponse := new(struct {
+		Fields map[string]json.RawMessage `json:"fields"`
+		Names  map[string]string          `json:"names"`
+	})
+
+	if err := c.query(ctx, http.MethodGet, url, nil, jsonResponse); err != nil {
+		return nil, nil, err
+	}
+
+	return jsonResponse.Fields, jsonResponse.Names, nil
+}
+
+// GetInsightObject looks up an Insight (Assets) object by its object key.
+func (c *Client) GetInsightObject(ctx context.Context, objectKey string) (types.InsightObject, error) {
+	url := c.cfg.Server + "/rest/insight/1.0/object/" + strings.ToUpper(objectKey)
+
+	object := types.InsightObject{}
+
+	resp, err := c.send(ctx, http.MethodGet, url, "application/json; charset=utf-8", nil)
+	if err != nil {
+		return object, err
+	}
+
+	if err := decode(resp, &object); err != nil {
+		return object, err
+	}
+
+	return object, nil
+}
+
+// GetTimeSpent returns the total time logged on the issue in seconds.
+func (c *Client) GetTimeSpent(ctx context.Context, key string) (int, error) {
+	url := c.cfg.Server + restAPIIssueURL + strings.ToUpper(key) + "?fields=timetracking"
+
+	jsonResponse := new(struct {
+		Fields struct {
+			TimeTracking struct {
+				TimeSpentSeconds int `json:"timeSpentSeconds"`
+			} `json:"timetracking"`
+		} `json:"fields"`
+	})
+
+	if err := c.query(ctx, http.MethodGet, url, nil, jsonResponse); err != nil {
+		return 0, err
+	}
+
+	return jsonResponse.Fields.TimeTracking.TimeSpentSeconds, nil
+}
+
+func (c *Client) GetIssuesInEpic(ctx context.Context, key string) ([]types.Issue, error) {
+	url := c.cfg.Server + "/rest/api/2/search?jql=cf[10500]=" + strings.ToUpper(key)
+
+	jsonResponse := new(struct {
+		Issues []types.Issue `json:"issues"`
+	})
+
+	if err := c.query(ctx, http.MethodGet, url, nil, jsonResponse); err != nil {
+		return nil, err
+	}
+
+	return jsonResponse.Issues, nil
+}
+
+func (c *Client) GetTransistions(ctx context.Context, key string) ([]types.Transition, error) {
+	url := c.cfg.Server + restAPIIssueURL + strings.ToUpper(key) + "/transitions?expand=transitions.fields"
+
+	jsonResponse := new(struct {
+		Transitions []types.Transition `json:"transitions"`
+	})
+
+	if err := c.query(ctx, http.MethodGet, url, nil, jsonResponse); err != nil {
+		return nil, err
+	}
+
+	return jsonResponse.Transitions, nil
+}
+
+func (c *Client) GetComments(ctx context.Context, key string) ([]types.Comment, error) {
+	url := c.cfg.Server + restAPIIssueURL + strings.ToUpper(key) + "/comment"
+
+	jsonResponse := new(struct {
+		Comments []types.Comment `json:"comments"`
+	})
+
+	if err := c.query(ctx, http.MethodGet, url, nil, jsonResponse); err != nil {
+		return nil, err
+	}
+
+	return jsonResponse.Comments, nil
+}
+
+func (c *Client) GetWorklogs(ctx context.Context, key string) ([]types.Worklog, error) {
+	url := c.cfg.Server + restAPIIssueURL + strings.ToUpper(key) + "/worklog?expand=properties"
+
+	jsonResponse := new(struct {
+		Worklogs []types.Worklog `json:"worklogs"`
+	})
+
+	if err := c.query(ctx, http.MethodGet, url, nil, jsonResponse); err != nil {
+		return nil, err
+	}
+
+	return jsonResponse.Worklogs, nil
+}
+
+func (c *Client) GetRapidViews(ctx context.Context) ([]types.RapidView, error) {
+	url := c.cfg.Server + "/rest/greenhopper/1.0/rapidview"
+
+	resp := new(struct {
+		Views []types.RapidView `json:"views"`
+	})
+
+	if err := c.query(ctx, http.MethodGet, url, nil, resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Views, nil
+}
+
+// GetRapidViewID returns the board with the name, or nil if there is no such board.
+func (c *Client) GetRapidViewID(ctx context.Context, board string) (*types.RapidView, error) {
+	views, err := c.GetRapidViews(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, x := range views {
+		if strings.EqualFold(board, x.Name) {
+			return &x, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// GetSprints returns the active and future sprints of the board, with
+// the issues in them. The issues are fetched one sprint at the time,
+// so use GetOpenSprints and GetSprintIssues to fetch only the sprints
+// needed on boards with big sprints.
+func (c *Client) GetSprints(ctx context.Context, rapidViewID int) ([]types.Sprint, []types.SprintIssue, error) {
+	sprints, err := c.GetOpenSprints(ctx, rapidViewID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	issues := []types.SprintIssue{}
+
+	for i := range sprints {
+		inSprint, err := c.GetSprintIssues(ctx, rapidViewID, sprints[i].ID)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, v := range inSprint {
+			sprints[i].IssuesIDs = append(sprints[i].IssuesIDs, v.ID)
+		}
+
+		issues = append(issues, inSprint...)
+	}
+
+	return sprints, issues, nil
+}
+
+// GetOpenSprints returns the active and future sprints of the board,
+// without the issues in them. The state is upper case, e.g. ACTIVE.
+func (c *Client) GetOpenSprints(ctx context.Context, boardID int) ([]types.Sprint, error) {
+	url := fmt.Sprintf("%s/rest/agile/1.0/board/%d/sprint?state=active,future", c.cfg.Server, boardID)
+
+	sprints := []types.Sprint{}
+
+	if err := c.agilePages(ctx, url, &sprints); err != nil {
+		return nil, err
+	}
+
+	for i := range sprints {
+		sprints[i].State = strings.ToUpper(sprints[i].State)
+	}
+
+	return sprints, nil
+}
+
+// sprintIssueFields are the only fields needed for the sprint issues.
+const sprintIssueFields = "summary,issuetype,priority,assignee,status,timeoriginalestimate,timeestimate,epic"
+
+// GetSprintIssues returns the issues in the sprint in the board order,
+// with the original estimate as the estimate statistic, and the remaining
+// estimate as the tracking statistic.
+func (c *Client) GetSprintIssues(ctx context.Context, boardID, sprintID int) ([]types.SprintIssue, error) {
+	url := fmt.Sprintf("%s/rest/agile/1.0/board/%d/sprint/%d/issue?fields=%s",
+		c.cfg.Server, boardID, sprintID, sprintIssueFields)
+
+	resp := []struct {
+		ID     string `json:"id"`
+		Key    string `json:"key"`
+		Fields struct {
+			Summary   string `json:"summary"`
+			IssueType struct {
+				ID string `json:"id"`
+			} `json:"issuetype"`
+			Priority struct {
+				ID string `json:"id"`
+			} `json:"priority"`
+			Assignee types.User `json:"assignee"`
+			Status   struct {
+				StatusCategory struct {
+					Key string `json:"key"`
+				} `json:"statusCategory"`
+			} `json:"status"`
+			Estimate  float64 `json:"timeoriginalestimate"`
+			Remaining float64 `json:"timeestimate"`
+			Epic      struct {
+				Key string `json:"key"`
+			} `json:"epic"`
+		} `json:"fields"`
+	}{}
+
+	if err := c.agilePages(ctx, url, &resp); err != nil {
+		return nil, err
+	}
+
+	issues := make([]types.SprintIssue, 0, len(resp))
+
+	for _, r := range resp {
+		id, _ := strconv.Atoi(r.ID)
+
+		// The assignee is the user id, as it was from greenhopper
+		issue := types.SprintIssue{
+			ID:           id,
+			Key:          r.Key,
+			TypeID:       r.Fields.IssueType.ID,
+			Summary:      r.Fields.Summary,
+			PriorityID:   r.Fields.Priority.ID,
+			Assignee:     c.userID(r.Fields.Assignee),
+			AssigneeName: r.Fields.Assignee.DisplayName,
+			Epic:         r.Fields.Epic.Key,
+			Done:         r.Fields.Status.StatusCategory.Key == "done",
+		}
+		issue.EstimateStatistic.StatFieldID = "timeoriginalestimate"
+		issue.EstimateStatistic.StatFieldValue.Value = r.Fields.Estimate
+		issue.TrackingStatistic.StatFieldID = "timeestimate"
+		issue.TrackingStatistic.StatFieldValue.Value = r.Fields.Remaining
+
+		issues = append(issues, issue)
+	}
+
+	return issues, nil
+}
+
+// agilePageSize is the number of values fetched per page from the agile api.
+const agilePageSize = 50
+
+// agilePages fetches all the pages of values, or issues, from the agile
+// api, and decodes them together into the slice pointed to by values.
+func (c *Client) agilePages(ctx context.Context, url string, values interface{}) error {
+	sep := "?"
+	if strings.Contains(url, "?") {
+		sep = "&"
+	}
+
+	all := []json.RawMessage{}
+
+	for {
+		page := new(struct {
+			IsLast bool              `json:"isLast"`
+			Total  int               `json:"total"`
+			Values []json.RawMessage `json:"values"`
+			Issues []json.RawMessage `json:"issues"`
+		})
+
+		pageURL := fmt.Sprintf("%s%sstartAt=%d&maxResults=%d", url, sep, len(all), agilePageSize)
+
+		if err := c.query(ctx, http.MethodGet, pageURL, nil, page); err != nil {
+			return err
+		}
+
+		// The sprints are values ending with isLast, the issues come with a total
+		items := append(page.Values, page.Issues...)
+		all = append(all, items...)
+
+		if len(items) == 0 || page.IsLast || (page.Total > 0 && len(all) >= page.Total) {
+			break
+		}
+	}
+
+	body, _ := json.Marshal(all)
+
+	return decode(body, values)
+}
+
+// GetSprintReport returns the sprint report, which
+// includes the issues added and removed after the sprint started.
+func (c *Client) GetSprintReport(ctx context.Context, rapidViewID, sprintID int) (types.SprintReport, error) {
+	url := fmt.Sprintf(
+		"%s/rest/greenhopper/1.0/rapid/charts/sprintreport?rapidViewId=%d&sprintId=%d",
+		c.cfg.Server, rapidViewID, sprintID)
+
+	resp := &types.SprintReport{}
+
+	if err := c.query(ctx, http.MethodGet, url, nil, resp); err != nil {
+		return types.SprintReport{}, err
+	}
+
+	return *resp, nil
+}
+
+// GetBoardEpics returns the epics on the board that are not done.
+func (c *Client) GetBoardEpics(ctx context.Context, boardID int) ([]types.Epic, error) {
+	url := fmt.Sprintf("%s/rest/agile/1.0/board/%d/epic?done=false", c.cfg.Server, boardID)
+
+	epics := []types.Epic{}
+
+	if err := c.agilePages(ctx, url, &epics); err != nil {
+		return nil, err
+	}
+
+	return epics, nil
+}
+
+// GetWorkloadIssues returns the issues on the board matching the jql,
+// with the assignee, remaining estimate and due date.
+func (c *Client) GetWorkloadIssues(ctx context.Context, boardID int, jql string) ([]types.WorkloadIssue, error) {
+	url := fmt.Sprintf("%s/rest/agile/1.0/board/%d/issue?fields=assignee,timeestimate,duedate&jql=%s",
+		c.cfg.Server, boardID, neturl.QueryEscape(jql))
+
+	issues := []types.WorkloadIssue{}
+
+	if err := c.agilePages(ctx, url, &issues); err != nil {
+		return nil, err
+	}
+
+	return issues, nil
+}
+
+// GetBoardSprints returns the sprints of the board with their start
+// and end dates, which are not part of the sprints from GetSprints.
+func (c *Client) GetBoardSprints(ctx context.Context, boardID int) ([]types.BoardSprint, error) {
+	url := fmt.Sprintf("%s/rest/agile/1.0/board/%d/sprint", c.cfg.Server, boardID)
+
+	sprints := []types.BoardSprint{}
+
+	if err := c.agilePages(ctx, url, &sprints); err != nil {
+		return nil, err
+	}
+
+	return sprints, nil
+}
+
+// GetKanbanIssues returns the issues on the board, in the
+// order given by orderBy if set, or else the board order.
+func (c *Client) GetKanbanIssues(ctx context.Context, boardID int, orderBy string) ([]types.Issue, error) {
+	url := fmt.Sprintf("%s/rest/agile/1.0/board/%d/issue", c.cfg.Server, boardID)
+
+	if orderBy != "" {
+		url += "?jql=" + neturl.QueryEscape("order by "+orderBy)
+	}
+
+	resp := new(struct {
+		Issues []types.Issue `json:"issues"`
+	})
+
+	if err := c.query(ctx, http.MethodGet, url, nil, resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Issues, nil
+}
+
+func (c *Client) IssueExists(ctx context.Context, issueKey *string) (bool, error) {
+	cacheKey := c.cfg.Server + "/" + *issueKey
+	if c.existing != nil && c.existing.has(cacheKey) {
+		return true, nil
+	}
+
+	// Only the status code is used, so ask for as little as possible
+	url := c.cfg.Server + restAPIIssueURL + *issueKey + "?fields=key"
+
+	found, err := c.exists(ctx, url)
+	if found && c.existing != nil {
+		c.existing.add(cacheKey)
+	}
+
+	return found, err
+}
+
+func (c *Client) UserExists(ctx context.Context, username string) (bool, error) {
+	url := c.cfg.Server + "/rest/api/2/user/?username=" + username
+
+	return c.exists(ctx, url)
+}
+
+func (c *Client) GetMyPermissions(ctx context.Context, key string, permissions ...string) (map[string]types.Permission, error) {
+	url := c.cfg.Server + "/rest/api/2/mypermissions?issueKey=" + strings.ToUpper(key) +
+		"&permissions=" + strings.Join(permissions, ",")
+
+	jsonResponse := new(struct {
+		Permissions map[string]types.Permission `json:"permissions"`
+	})
+
+	if err := c.query(ctx, http.MethodGet, url, nil, jsonResponse); err != nil {
+		return nil, err
+	}
+
+	return jsonResponse.Permissions, nil
+}
+
+func (c *Client) UpdateStatus(ctx context.Context, key, id string) error {
+	url := c.cfg.Server + restAPIIssueURL + strings.ToUpper(key) + "/transitions"
+
+	payload := []byte(`{
+		"update": {
+			"comment": [
+				{
+					"add": {
+						"body": ` + c.richText("Status updated by Gojira") + `
+					}
+				}
+			]
+		},
+		"transition": {
+			"id": "` + id + `"
+		}
+	}`)
+
+	_, err := c.update(ctx, http.MethodPost, url, payload)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ResolveIssue changes the status like UpdateStatus, but with a comment of
+// your own, and sets the resolution unless it is empty. The resolution
+// can only be set if it is on the screen of the transition.
+func (c *Client) ResolveIssue(ctx context.Context, key, id, resolution, comment string) error {
+	url := c.cfg.Server + restAPIIssueURL + strings.ToUpper(key) + "/transitions"
+
+	if comment == "" {
+		comment = "Status updated by Gojira"
+	}
+
+	fields := ""
+	if resolution != "" {
+		fields = `"fields": {"resolution": {"name": "` + util.MakeStringJSONSafe(resolution) + `"}},`
+	}
+
+	payload := []byte(`{
+		"update": {
+			"comment": [
+				{
+					"add": {
+						"body": ` + c.richText(util.MakeStringJSONSafe(comment)) + `
+					}
+				}
+			]
+		},` + fields + `
+		"transition": {
+			"id": "` + id + `"
+		}
+	}`)
+
+	_, err := c.update(ctx, http.MethodPost, url, payload)
+
+	return err
+}
+
+// UnassignIssue removes the assignee of the issue.
+func (c *Client) UnassignIssue(ctx context.Context, key string) error {
+	url := c.cfg.Server + restAPIIssueURL + strings.ToUpper(key) + "/assignee"
+
+	// Jira Cloud identifies users by the account id only
+	payload := []byte(`{"name":null}`)
+	if c.cloud {
+		payload = []byte(`{"accountId":null}`)
+	}
+
+	_, err := c.update(ctx, http.MethodPut, url, payload)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *Client) UpdateAssignee(ctx context.Context, key string, user string) error {
+	url := c.cfg.Server + restAPIIssueURL + strings.ToUpper(key) + "/assignee"
+	payload := []byte(`{"name":"` + user + `"}`)
+
+	_, err := c.update(ctx, http.MethodPut, url, payload)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *Client) UpdatePriority(ctx context.Context, key string, priorityID string) error {
+	url := c.cfg.Server + restAPIIssueURL + strings.ToUpper(key)
+	payload := []byte(`{"fields":{"priority":{"id":"` + priorityID + `"}}}`)
+
+	_, err := c.update(ctx, http.MethodPut, url, payload)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateField sets the value of a text field.
+func (c *Client) UpdateField(ctx context.Context, key, field, value string) error {
+	url := c.cfg.Server + restAPIIssueURL + strings.ToUpper(key)
+	payload := []byte(`{"fields":{"` + field + `":"` + util.MakeStringJSONSafe(value) + `"}}`)
+
+	_, err := c.update(ctx, http.MethodPut, url, payload)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateInsightField replaces the objects of an Insight field.
+// An empty list of object keys clears the field.
+func (c *Client) UpdateInsightField(ctx context.Context, key, field string, objectKeys []string) error {
+	url := c.cfg.Server + restAPIIssueURL + strings.ToUpper(key)
+
+	objects := []string{}
+	for _, k := range objectKeys {
+		objects = append(objects, `{"key":"`+util.MakeStringJSONSafe(strings.ToUpper(k))+`"}`)
+	}
+
+	payload := []byte(`{"fields":{"` + field + `":[` + strings.Join(objects, ",") + `]}}`)
+
+	_, err := c.update(ctx, http.MethodPut, url, payload)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *Client) AddWatcher(ctx context.Context, key string, user string) error {
+	url := c.cfg.Server + restAPIIssueURL + strings.ToUpper(key) + "/watchers"
+	payload := []byte(`"` + util.MakeStringJSONSafe(user) + `"`)
+
+	_, err := c.update(ctx, http.MethodPost, url, payload)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Watch adds the current user to the watchers of the issue.
+func (c *Client) Watch(ctx context.Context, key string) error {
+	user, err := c.GetMyself(ctx)
+	if err != nil {
+		return err
+	}
+
+	return c.AddWatcher(ctx, key, c.userID(user))
+}
+
+// Unwatch removes the current user from the watchers of the issue.
+func (c *Client) Unwatch(ctx context.Context, key string) error {
+	user, err := c.GetMyself(ctx)
+	if err != nil {
+		return err
+	}
 
-	return *jsonResponse
+	return c.RemoveWatcher(ctx, key, c.userID(user))
 }
 
-func GetIssueTypes() *[]types.IssueType {
-	url := jcfg.Server + "/rest/api/2/issuetype"
+// RemoveWatcher removes the user, given by the user name, or the
+// account id on Jira Cloud, from the watchers of the issue.
+func (c *Client) RemoveWatcher(ctx context.Context, key, user string) error {
+	param := "username"
+	if c.cloud {
+		param = "accountId"
+	}
 
-	jsonResponse := &[]types.IssueType{}
+	url := c.cfg.Server + restAPIIssueURL + strings.ToUpper(key) + "/watchers?" +
+		param + "=" + neturl.QueryEscape(user)
 
-	query(http.MethodGet, url, nil, jsonResponse)
+	_, err := c.update(ctx, http.MethodDelete, url, nil)
+	if err != nil {
+		return err
+	}
 
-	return jsonResponse
+	return nil
 }
 
-func GetIssue(key string) types.IssueDescription {
-	url := jcfg.Server + restAPIIssueURL + strings.ToUpper(key)
-
-	jsonResponse := &types.IssueDescription{}
-
-	query(http.MethodGet, url, nil, jsonResponse)
+// userID returns the id the API knows the user by, the account
+// id on Jira Cloud and the user name on Server and Data Center.
+func (c *Client) userID(user types.User) string {
+	if c.cloud {
+		return user.AccountID
+	}
 
-	return *jsonResponse
+	return user.Name
 }
 
-func GetIssuesInEpic(key string) []types.Issue {
-	url := jcfg.Server + "/rest/api/2/search?jql=cf[10500]=" + strings.ToUpper(key)
+// GetRemoteLinks returns the links from the issue to web pages.
+func (c *Client) GetRemoteLinks(ctx context.Context, key string) ([]types.RemoteLink, error) {
+	url := c.cfg.Server + restAPIIssueURL + strings.ToUpper(key) + "/remotelink"
 
-	jsonResponse := new(struct {
-		Issues []types.Issue `json:"issues"`
-	})
+	jsonResponse := []types.RemoteLink{}
 
-	query(http.MethodGet, url, nil, jsonResponse)
+	if err := c.query(ctx, http.MethodGet, url, nil, &jsonResponse); err != nil {
+		return nil, err
+	}
 
-	return jsonResponse.Issues
+	return jsonResponse, nil
 }
 
-func GetTransistions(key string) []types.Transition {
-	url := jcfg.Server + restAPIIssueURL + strings.ToUpper(key) + "/transitions"
-
-	jsonResponse := new(struct {
-		Transitions []types.Transition `json:"transitions"`
-	})
+// AddRemoteLink links the issue to the web page with the title.
+func (c *Client) AddRemoteLink(ctx context.Context, key, link, title string) error {
+	url := c.cfg.Server + restAPIIssueURL + strings.ToUpper(key) + "/remotelink"
+	payload := []byte(`{"object": {
+		"url": "` + util.MakeStringJSONSafe(link) + `",
+		"title": "` + util.MakeStringJSONSafe(title) + `"
+	}}`)
 
-	query(http.MethodGet, url, nil, jsonResponse)
+	_, err := c.update(ctx, http.MethodPost, url, payload)
+	if err != nil {
+		return err
+	}
 
-	return jsonResponse.Transitions
+	return nil
 }
 
-func GetComments(key string) []types.Comment {
-	url := jcfg.Server + restAPIIssueURL + strings.ToUpper(key) + "/comment"
-
-	jsonResponse := new(struct {
-		Comments []types.Comment `json:"comments"`
-	})
-
-	query(http.MethodGet, url, nil, jsonResponse)
+// AddAttachment uploads the file as an attachment to the issue.
+func (c *Client) AddAttachment(ctx context.Context, key, file string) error {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
 
-	return jsonResponse.Comments
+	return c.AddAttachmentContent(ctx, key, filepath.Base(file), content)
 }
 
-func GetWorklogs(key string) []types.Worklog {
-	url := jcfg.Server + restAPIIssueURL + strings.ToUpper(key) + "/worklog"
-
-	jsonResponse := new(struct {
-		Worklogs []types.Worklog `json:"worklogs"`
-	})
+// AddAttachmentContent uploads the content as an attachment
+// with the file name to the issue.
+func (c *Client) AddAttachmentContent(ctx context.Context, key, name string, content []byte) error {
+	var buf bytes.Buffer
 
-	query(http.MethodGet, url, nil, jsonResponse)
+	w := multipart.NewWriter(&buf)
 
-	return jsonResponse.Worklogs
-}
+	part, err := w.CreateFormFile("file", name)
+	if err == nil {
+		_, err = part.Write(content)
+	}
 
-func GetRapidViewID(board string) *types.RapidView {
-	url := jcfg.Server + "/rest/greenhopper/1.0/rapidview"
+	if err == nil {
+		err = w.Close()
+	}
 
-	resp := new(struct {
-		Views []types.RapidView `json:"views"`
-	})
+	if err != nil {
+		return err
+	}
 
-	query(http.MethodGet, url, nil, resp)
+	url := c.cfg.Server + restAPIIssueURL + strings.ToUpper(key) + "/attachments"
 
-	for _, x := range resp.Views {
-		if strings.EqualFold(board, x.Name) {
-			return &x
-		}
+	_, err = c.send(ctx, http.MethodPost, url, w.FormDataContentType(), buf.Bytes())
+	if err != nil {
+		return err
 	}
 
 	return nil
 }
 
-func GetSprints(rapidViewID int) ([]types.Sprint, []types.SprintIssue) {
-	url := fmt.Sprintf(
-		"%s/rest/greenhopper/1.0/xboard/plan/backlog/data.json?rapidViewId=%d",
-		jcfg.Server, rapidViewID)
-
-	resp := new(struct {
-		Issues  []types.SprintIssue `json:"issues"`
-		Sprints []types.Sprint      `json:"sprints"`
-	})
-
-	query(http.MethodGet, url, nil, resp)
-
-	return resp.Sprints, resp.Issues
+// GetAttachmentContent downloads the content of the attachment.
+func (c *Client) GetAttachmentContent(ctx context.Context, attachment types.Attachment) ([]byte, error) {
+	return c.send(ctx, http.MethodGet, attachment.Content, "application/octet-stream", nil)
 }
 
-func GetKanbanIssues(boardID int) []types.Issue {
-	url := fmt.Sprintf("%s/rest/agile/1.0/board/%d/issue", jcfg.Server, boardID)
+// DownloadAttachment streams the content of the attachment to w,
+// without holding it in memory, and returns the number of bytes written.
+func (c *Client) DownloadAttachment(ctx context.Context, attachment types.Attachment, w io.Writer) (int64, error) {
+	if err := c.authenticate(ctx); err != nil {
+		return 0, err
+	}
 
-	resp := new(struct {
-		Issues []types.Issue `json:"issues"`
-	})
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
 
-	query(http.MethodGet, url, nil, resp)
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, c.apiURL(attachment.Content), nil)
+	c.authorize(req)
 
-	return resp.Issues
-}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("%w", err)
+	}
+	defer resp.Body.Close()
 
-func CheckIssueKey(key *string, issueFile string) {
-	if *key != "" {
-		if !validate.IssueKey(key) {
-			fmt.Println("Invalid key")
-			os.Exit(1)
-		}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
 
-		if !IssueExists(key) {
-			fmt.Printf("%s does not exist\n", *key)
-			os.Exit(1)
-		}
-	} else {
-		*key = util.GetActiveIssue(issueFile)
+		return 0, newAPIError(resp, body)
 	}
-}
 
-func IssueExists(issueKey *string) bool {
-	url := jcfg.Server + restAPIIssueURL + *issueKey
+	n, err := io.Copy(w, resp.Body)
+	if err != nil {
+		return n, fmt.Errorf("%w", err)
+	}
 
-	return exists(url)
+	return n, nil
 }
 
-func UserExists(username string) bool {
-	url := jcfg.Server + "/rest/api/2/user/?username=" + username
-
-	return exists(url)
-}
+func (c *Client) GetIssueLinkTypes(ctx context.Context) ([]types.IssueLinkType, error) {
+	url := c.cfg.Server + "/rest/api/2/issueLinkType"
 
-func UpdateStatus(key string, transitions []types.Transition) error {
-	r := fmt.Sprintf("^([0-%d])$", len(transitions)-1)
-	index := util.GetUserInput("", r)
+	jsonResponse := new(struct {
+		IssueLinkTypes []types.IssueLinkType `json:"issueLinkTypes"`
+	})
 
-	i, err := strconv.Atoi(index)
-	if err != nil {
-		return fmt.Errorf("%w", err)
+	if err := c.query(ctx, http.MethodGet, url, nil, jsonResponse); err != nil {
+		return nil, err
 	}
 
-	url := jcfg.Server + restAPIIssueURL + strings.ToUpper(key) + "/transitions"
-	id := transitions[i].ID
+	return jsonResponse.IssueLinkTypes, nil
+}
 
+// LinkIssues links the issues so that the
+// outward description reads "from <outward> to".
+func (c *Client) LinkIssues(ctx context.Context, linkType, from, to string) error {
+	url := c.cfg.Server + "/rest/api/2/issueLink"
 	payload := []byte(`{
-		"update": {
-			"comment": [
-				{
-					"add": {
-						"body": "Status updated by Gojira"
-					}
-				}
-			]
-		},
-		"transition": {
-			"id": "` + id + `"
-		}
+		"type": {"name": "` + util.MakeStringJSONSafe(linkType) + `"},
+		"inwardIssue": {"key": "` + strings.ToUpper(from) + `"},
+		"outwardIssue": {"key": "` + strings.ToUpper(to) + `"}
 	}`)
 
-	resp, err := update(http.MethodPost, url, payload)
+	_, err := c.update(ctx, http.MethodPost, url, payload)
 	if err != nil {
-		fmt.Printf("%s\n", resp)
-
 		return err
 	}
 
 	return nil
 }
 
-func UpdateAssignee(key string, user string) error {
-	url := jcfg.Server + restAPIIssueURL + strings.ToUpper(key) + "/assignee"
-	payload := []byte(`{"name":"` + user + `"}`)
+// DeleteIssueLink deletes the link with the id.
+func (c *Client) DeleteIssueLink(ctx context.Context, id string) error {
+	url := c.cfg.Server + "/rest/api/2/issueLink/" + id
 
-	resp, err := update(http.MethodPut, url, payload)
+	_, err := c.update(ctx, http.MethodDelete, url, nil)
 	if err != nil {
-		fmt.Printf("%s\n", resp)
-
 		return err
 	}
 
 	return nil
 }
 
-func CreateNewIssue(project types.Project, issueTypeID,
+func (c *Client) CreateNewIssue(ctx context.Context, project types.Project, issueTypeID,
 	priorityID, summary, description string,
 ) (string, error) {
-	url := jcfg.Server + "/rest/api/2/issue"
+	url := c.cfg.Server + "/rest/api/2/issue"
 	method := http.MethodPost
 
 	payload := []byte(`{
@@ -353,7 +1237,7 @@ func CreateNewIssue(project types.Project, issueTypeID,
 				"id": "` + project.ID + `"
 			},
 			"summary": "` + summary + `",
-			"description": "` + description + `",
+			"description": ` + c.richText(description) + `,
 			"issuetype": {
 				"id": "` + issueTypeID + `"
 			},
@@ -374,7 +1258,7 @@ func CreateNewIssue(project types.Project, issueTypeID,
 				"summary"`))
 	}
 
-	body, err := update(method, url, payload)
+	body, err := c.update(ctx, method, url, payload)
 	if err != nil {
 		return string(body), err
 	}
@@ -391,107 +1275,312 @@ func CreateNewIssue(project types.Project, issueTypeID,
 	return resp.Key, nil
 }
 
-func AddWorklog(wDate, wTime, key, seconds, comment string) error {
-	url := jcfg.Server + restAPIIssueURL + strings.ToUpper(key) + "/worklog"
+// CreateIssueCopy creates a copy of the issue in the project, with the
+// summary, description, labels and due date of the issue, e.g. when
+// migrating it from another Jira server. The priority is left to the
+// default of the project if priorityID is empty.
+func (c *Client) CreateIssueCopy(ctx context.Context, projectID, issueTypeID, priorityID string,
+	issue types.ExportedIssue,
+) (string, error) {
+	url := c.cfg.Server + "/rest/api/2/issue"
+
+	labels, _ := json.Marshal(issue.Fields.Labels)
+	if issue.Fields.Labels == nil {
+		labels = []byte("[]")
+	}
+
+	optional := ""
+
+	if priorityID != "" {
+		optional += `,
+			"priority": {"id": "` + priorityID + `"}`
+	}
+
+	if issue.Fields.DueDate != "" {
+		optional += `,
+			"duedate": "` + issue.Fields.DueDate + `"`
+	}
+
+	payload := []byte(`{
+		"fields":{
+			"project": {"id": "` + projectID + `"},
+			"summary": "` + util.MakeStringJSONSafe(issue.Fields.Summary) + `",
+			"description": ` + c.richText(util.MakeStringJSONSafe(issue.Fields.Description)) + `,
+			"issuetype": {"id": "` + issueTypeID + `"},
+			"labels": ` + string(labels) + optional + `
+		}
+	}`)
+
+	body, err := c.update(ctx, http.MethodPost, url, payload)
+	if err != nil {
+		return "", err
+	}
+
+	var resp struct {
+		Key string `json:"key"`
+	}
+
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return "", fmt.Errorf("%w", err)
+	}
+
+	return resp.Key, nil
+}
+
+func (c *Client) CreateComponent(ctx context.Context, projectKey, name, lead, description string) error {
+	url := c.cfg.Server + "/rest/api/2/component"
+	payload := []byte(`{
+		"project": "` + strings.ToUpper(projectKey) + `",
+		"name": "` + util.MakeStringJSONSafe(name) + `",
+		"description": "` + util.MakeStringJSONSafe(description) + `"` +
+		leadUserName(lead) + `
+	}`)
+
+	_, err := c.update(ctx, http.MethodPost, url, payload)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// CreatePersonalAccessToken creates a personal access token on Jira Data
+// Center, expiring after the number of days, or never if days is 0.
+func (c *Client) CreatePersonalAccessToken(ctx context.Context, name string,
+	days int,
+) (types.PersonalAccessToken, error) {
+	url := c.cfg.Server + "/rest/pat/latest/tokens"
+
+	expiration := ""
+	if days > 0 {
+		expiration = `,
+		"expirationDuration": ` + strconv.Itoa(days)
+	}
+
+	payload := []byte(`{
+		"name": "` + util.MakeStringJSONSafe(name) + `"` + expiration + `
+	}`)
+
+	token := types.PersonalAccessToken{}
+
+	if err := c.query(ctx, http.MethodPost, url, payload, &token); err != nil {
+		return types.PersonalAccessToken{}, err
+	}
+
+	return token, nil
+}
+
+func leadUserName(lead string) string {
+	if lead == "" {
+		return ""
+	}
+
+	return `,
+		"leadUserName": "` + lead + `"`
+}
+
+func (c *Client) CreateVersion(ctx context.Context, projectKey, name, releaseDate, description string) error {
+	url := c.cfg.Server + "/rest/api/2/version"
+	payload := []byte(`{
+		"project": "` + strings.ToUpper(projectKey) + `",
+		"name": "` + util.MakeStringJSONSafe(name) + `",
+		"description": "` + util.MakeStringJSONSafe(description) + `"` +
+		releaseDateField(releaseDate) + `
+	}`)
+
+	_, err := c.update(ctx, http.MethodPost, url, payload)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *Client) ReleaseVersion(ctx context.Context, id, releaseDate string) error {
+	return c.updateVersion(ctx, id, []byte(`{"released": true`+releaseDateField(releaseDate)+`}`))
+}
+
+func (c *Client) ArchiveVersion(ctx context.Context, id string) error {
+	return c.updateVersion(ctx, id, []byte(`{"archived": true}`))
+}
+
+func (c *Client) updateVersion(ctx context.Context, id string, payload []byte) error {
+	url := c.cfg.Server + "/rest/api/2/version/" + id
+
+	_, err := c.update(ctx, http.MethodPut, url, payload)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func releaseDateField(releaseDate string) string {
+	if releaseDate == "" {
+		return ""
+	}
+
+	return `,
+		"releaseDate": "` + releaseDate + `"`
+}
+
+func (c *Client) AddWorklog(ctx context.Context, wDate, wTime, key, seconds, comment string) error {
+	return c.addWorklog(ctx, key, setWorkStarttime(wDate, wTime), seconds, comment, nil)
+}
+
+// AddWorklogWithAttributes adds a worklog with the work attributes
+// stored as a worklog property.
+func (c *Client) AddWorklogWithAttributes(ctx context.Context, wDate, wTime, key, seconds, comment string, attrs types.WorklogAttributes) error {
+	value, err := json.Marshal(attrs)
+	if err != nil {
+		return err
+	}
+
+	properties := []types.WorklogProperty{{Key: types.WorklogAttributesKey, Value: value}}
+
+	return c.addWorklog(ctx, key, setWorkStarttime(wDate, wTime), seconds, comment, properties)
+}
+
+// CopyWorklog adds a copy of the worklog to the issue,
+// keeping the original start time, time spent, comment and properties.
+func (c *Client) CopyWorklog(ctx context.Context, key string, worklog types.Worklog) error {
+	return c.addWorklog(ctx, key, worklog.Started, strconv.Itoa(worklog.TimeSpentSeconds),
+		util.MakeStringJSONSafe(worklog.Comment), worklog.Properties)
+}
+
+func (c *Client) addWorklog(ctx context.Context, key, started, seconds, comment string, properties []types.WorklogProperty) error {
+	url := c.cfg.Server + restAPIIssueURL + strings.ToUpper(key) + "/worklog"
+
+	props := ""
+
+	if len(properties) > 0 {
+		p, err := json.Marshal(properties)
+		if err != nil {
+			return err
+		}
+
+		props = `,
+		"properties": ` + string(p)
+	}
+
 	payload := []byte(`{
-		"comment": "` + comment + `",
-		"started": "` + setWorkStarttime(wDate, wTime) + `",
-		"timeSpentSeconds": ` + seconds +
+		"comment": ` + c.richText(comment) + `,
+		"started": "` + started + `",
+		"timeSpentSeconds": ` + seconds + props +
 		`}`)
 
-	resp, err := update(http.MethodPost, url, payload)
+	_, err := c.update(ctx, http.MethodPost, url, payload)
 	if err != nil {
-		fmt.Printf("%s\n", resp)
+		return err
+	}
+
+	return nil
+}
 
+func (c *Client) DeleteWorklog(ctx context.Context, key, id string) error {
+	url := c.cfg.Server + restAPIIssueURL + strings.ToUpper(key) + "/worklog/" + id
+
+	_, err := c.update(ctx, http.MethodDelete, url, nil)
+	if err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func AddComment(key string, comment []byte) error {
-	url := jcfg.Server + restAPIIssueURL + strings.ToUpper(key) + "/comment"
+func (c *Client) AddComment(ctx context.Context, key string, comment []byte) error {
+	url := c.cfg.Server + restAPIIssueURL + strings.ToUpper(key) + "/comment"
 
 	escaped := util.MakeStringJSONSafe(string(comment))
 
 	payload := []byte(`{
-		"body": "` + escaped + `",
+		"body": ` + c.richText(escaped) + `,
 		"visibility": {
 			"type": "group",
 			"value": "Internal users"
 		}
 	}`)
 
-	resp, err := update(http.MethodPost, url, payload)
+	_, err := c.update(ctx, http.MethodPost, url, payload)
 	if err != nil {
-		fmt.Printf("%s\n", resp)
-
 		return err
 	}
 
 	return nil
 }
 
-func UpdateDescription(key string, desc []byte) error {
-	url := jcfg.Server + restAPIIssueURL + strings.ToUpper(key)
+// AddReaction reacts to the comment with the emoji, given by the hex
+// code points of the emoji, e.g. 1f44d. The reactions are only on Jira
+// Cloud, and not part of the documented REST API, so a Jira without them
+// returns ErrNoReactions.
+func (c *Client) AddReaction(ctx context.Context, commentID, emojiID string) error {
+	url := c.cfg.Server + "/rest/reactions/1.0/reactions"
+	payload := []byte(`{
+		"commentId": "` + commentID + `",
+		"emojiId": "` + emojiID + `"
+	}`)
+
+	if _, err := c.update(ctx, http.MethodPost, url, payload); err != nil {
+		return reactionsError(err)
+	}
+
+	return nil
+}
+
+func (c *Client) UpdateDescription(ctx context.Context, key string, desc []byte) error {
+	url := c.cfg.Server + restAPIIssueURL + strings.ToUpper(key)
 
 	jsonDesc := util.MakeStringJSONSafe(string(desc))
 
-	payload := []byte(`{"fields":{"description":"` + jsonDesc + `"}}`)
+	payload := []byte(`{"fields":{"description":` + c.richText(jsonDesc) + `}}`)
 
-	resp, err := update(http.MethodPut, url, payload)
+	_, err := c.update(ctx, http.MethodPut, url, payload)
 	if err != nil {
-		fmt.Printf("%s\n", resp)
-
 		return err
 	}
 
 	return nil
 }
 
-func UpdateComment(key string, comment []byte, id string) error {
-	url := jcfg.Server + restAPIIssueURL + strings.ToUpper(key) + "/comment/" + id
+func (c *Client) UpdateComment(ctx context.Context, key string, comment []byte, id string) error {
+	url := c.cfg.Server + restAPIIssueURL + strings.ToUpper(key) + "/comment/" + id
 
 	escaped := util.MakeStringJSONSafe(string(comment))
 
 	payload := []byte(`{
-		"body": "` + escaped + `",
+		"body": ` + c.richText(escaped) + `,
 		"visibility": {
 			"type": "group",
 			"value": "Internal users"
 		}
 	}`)
 
-	resp, err := update(http.MethodPut, url, payload)
+	_, err := c.update(ctx, http.MethodPut, url, payload)
 	if err != nil {
-		fmt.Printf("%s\n", resp)
-
 		return err
 	}
 
 	return nil
 }
 
-func UpdateWorklog(worklog types.SimplifiedTimesheet) error {
+func (c *Client) UpdateWorklog(ctx context.Context, worklog types.SimplifiedTimesheet) error {
 	dateAndTime := strings.Split(worklog.StartDate, " ")
 	if len(dateAndTime) != 2 {
 		return &types.Error{Message: "invalid date and time"}
 	}
 
-	url := jcfg.Server + restAPIIssueURL +
+	url := c.cfg.Server + restAPIIssueURL +
 		strings.ToUpper(worklog.Key) + "/worklog/" + strconv.Itoa(worklog.ID) + "/"
 
 	payload := []byte(`{
 		"id": "` + strconv.Itoa(worklog.ID) + `",
-		"comment": "` + worklog.Comment + `",
+		"comment": ` + c.richText(worklog.Comment) + `,
 		"started": "` + setWorkStarttime(dateAndTime[0], dateAndTime[1]) + `",
 		"timeSpentSeconds": ` + strconv.Itoa(worklog.TimeSpent) +
 		`}`)
 
-	resp, err := update(http.MethodPut, url, payload)
+	_, err := c.update(ctx, http.MethodPut, url, payload)
 	if err != nil {
-		fmt.Printf("%s\n", resp)
-
 		return err
 	}
 
@@ -519,17 +1608,25 @@ func setWorkStarttime(wDate, wTime string) string {
 	return t.UTC().Format("2006-01-02T15:04:05.000+0000")
 }
 
-func update(method, url string, payload []byte) ([]byte, error) {
-	jcfg.DecryptPassword()
+func (c *Client) update(ctx context.Context, method, url string, payload []byte) ([]byte, error) {
+	return c.send(ctx, method, url, "application/json; charset=utf-8", payload)
+}
 
-	ctx := context.Background()
-	req, _ := http.NewRequestWithContext(ctx, method, url, bytes.NewBuffer(payload))
-	req.Header.Set("Content-Type", "application/json; charset=utf-8")
-	req.SetBasicAuth(jcfg.Username, jcfg.Password)
+func (c *Client) send(ctx context.Context, method, url, contentType string, payload []byte) ([]byte, error) {
+	if err := c.authenticate(ctx); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
 
-	client := &http.Client{}
+	req, _ := http.NewRequestWithContext(ctx, method, c.apiURL(url), bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", contentType)
+	// Required by Jira when uploading attachments
+	req.Header.Set("X-Atlassian-Token", "no-check")
+	c.authorize(req)
 
-	resp, err := client.Do(req)
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("%w", err)
 	}
@@ -540,73 +1637,162 @@ func update(method, url string, payload []byte) ([]byte, error) {
 	if resp.StatusCode != http.StatusOK &&
 		resp.StatusCode != http.StatusCreated &&
 		resp.StatusCode != http.StatusNoContent {
-		return body, &types.Error{Message: checkResponseCode(resp)}
+		return body, newAPIError(resp, body)
 	}
 
 	return body, nil
 }
 
-func query(method string, url string, payload []byte, jsonResponse interface{}) {
-	// Create request
-	jcfg.DecryptPassword()
+func (c *Client) query(ctx context.Context, method string, url string, payload []byte, jsonResponse interface{}) error {
+	if err := c.authenticate(ctx); err != nil {
+		return err
+	}
 
-	ctx := context.Background()
-	req, _ := http.NewRequestWithContext(ctx, method, url, bytes.NewBuffer(payload))
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, method, c.apiURL(url), bytes.NewBuffer(payload))
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
-	req.SetBasicAuth(jcfg.Username, jcfg.Password)
-
-	client := &http.Client{}
+	c.authorize(req)
 
-	resp, err := client.Do(req)
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		log.Fatal(err)
+		return fmt.Errorf("%w", err)
 	}
+	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		log.Fatal(err)
+		return fmt.Errorf("%w", err)
 	}
 
 	if resp.StatusCode != http.StatusOK &&
 		resp.StatusCode != http.StatusCreated &&
 		resp.StatusCode != http.StatusNoContent {
-		log.Fatalf("Error:%s", checkResponseCode(resp))
+		return newAPIError(resp, body)
 	}
-	// fmt.Println(string(body))
 
-	err = json.Unmarshal(body, jsonResponse)
-	if err != nil {
-		log.Fatalf("Failed to parse json response: %s\n", err)
+	if c.cloud && len(body) > 0 {
+		if replaced, err := adf.ReplaceDocs(body); err == nil {
+			body = replaced
+		}
+	}
+
+	if err := decode(body, jsonResponse); err != nil {
+		return fmt.Errorf("failed to parse json response: %w", err)
 	}
 
-	resp.Body.Close()
+	return nil
 }
 
-func exists(url string) bool {
-	jcfg.DecryptPassword()
+func (c *Client) exists(ctx context.Context, url string) (bool, error) {
+	if err := c.authenticate(ctx); err != nil {
+		return false, err
+	}
 
-	ctx := context.Background()
-	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	req.Header.Set("Content-Type", "application/json; charset=utf-8")
-	req.SetBasicAuth(jcfg.Username, jcfg.Password)
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
 
-	client := &http.Client{}
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, c.apiURL(url), nil)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	c.authorize(req)
 
-	resp, err := client.Do(req)
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return false
+		return false, fmt.Errorf("%w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
 	}
 
 	if resp.StatusCode != http.StatusOK &&
 		resp.StatusCode != http.StatusCreated &&
 		resp.StatusCode != http.StatusNoContent {
-		log.Fatalf("Error:%s", checkResponseCode(resp))
+		body, _ := io.ReadAll(resp.Body)
+
+		return false, newAPIError(resp, body)
 	}
 
-	defer resp.Body.Close()
+	return true, nil
+}
+
+// authorize adds the credentials to the request, a bearer token for
+// personal access tokens, or else basic auth. With session auth the
+// session cookie is added by the cookie jar instead.
+func (c *Client) authorize(req *http.Request) {
+	if c.session {
+		return
+	}
+
+	if c.cfg.PasswordType == "pat" {
+		req.Header.Set("Authorization", "Bearer "+c.cfg.Password)
+
+		return
+	}
+
+	req.SetBasicAuth(c.cfg.Username, c.cfg.Password)
+}
+
+// apiURL returns the url of the v3 API on Jira Cloud, where the
+// v2 API does not take documents for descriptions and comments.
+func (c *Client) apiURL(url string) string {
+	if !c.cloud {
+		return url
+	}
+
+	return strings.Replace(url, "/rest/api/2/", "/rest/api/3/", 1)
+}
+
+// richText returns the json escaped text as a json value, a string for
+// the v2 API, or a document converted from the text for the v3 API.
+func (c *Client) richText(escaped string) string {
+	if !c.cloud {
+		return `"` + escaped + `"`
+	}
+
+	var text string
+	if err := json.Unmarshal([]byte(`"`+escaped+`"`), &text); err != nil {
+		text = escaped
+	}
 
-	return resp.StatusCode == http.StatusOK
+	doc, _ := json.Marshal(adf.FromText(text))
+
+	return string(doc)
+}
+
+// withTimeout limits the time waiting for a request, if a timeout is set.
+func (c *Client) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, c.timeout)
+}
+
+// decode unmarshals the response, but tolerates fields with an unexpected
+// type, e.g. a custom field changed by a plugin. Such fields are skipped
+// with a warning, so only invalid json or missing essential data fails.
+func decode(body []byte, jsonResponse interface{}) error {
+	err := json.Unmarshal(body, jsonResponse)
+
+	var typeErr *json.UnmarshalTypeError
+
+	switch {
+	case errors.As(err, &typeErr):
+		fmt.Fprintf(os.Stderr, "Warning: skipped %s in the response from Jira, expected %s but got %s\n",
+			typeErr.Field, typeErr.Type, typeErr.Value)
+	case err != nil:
+		return err
+	}
+
+	if v, ok := jsonResponse.(types.Validator); ok {
+		return v.Validate()
+	}
+
+	return nil
 }
 
 func checkResponseCode(resp *http.Response) string {
@@ -0,0 +1,593 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package jira_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mhersson/gojira/pkg/jira"
+	"github.com/mhersson/gojira/pkg/jira/jiratest"
+	"github.com/mhersson/gojira/pkg/types"
+)
+
+func newClient(t *testing.T, configure ...func(*types.Config)) (*jiratest.Server, *jira.Client) {
+	t.Helper()
+
+	server := jiratest.NewServer()
+	t.Cleanup(server.Close)
+
+	cfg := server.Config()
+	for _, c := range configure {
+		c(&cfg)
+	}
+
+	return server, jira.NewClient(cfg)
+}
+
+// newFixtureClient returns a client for a server answering with the handler,
+// for the endpoints not served by jiratest, and to make Jira fail.
+func newFixtureClient(t *testing.T, handler http.HandlerFunc) *jira.Client {
+	t.Helper()
+
+	return newDeploymentClient(t, "server", handler)
+}
+
+// newDeploymentClient returns a client of the deployment, cloud or
+// server, for the handler.
+func newDeploymentClient(t *testing.T, deployment string, handler http.HandlerFunc) *jira.Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return jira.NewClient(types.Config{
+		JiraURL:      server.URL,
+		Username:     "bob",
+		Password:     "token",
+		PasswordType: "pat",
+		Deployment:   deployment,
+	})
+}
+
+func TestIssueExists(t *testing.T) {
+	t.Parallel()
+
+	server, client := newClient(t)
+	server.AddIssue(jiratest.NewIssue("OSE-1", "Fix the flux capacitor", "To Do"))
+
+	key := "OSE-1"
+	found, err := client.IssueExists(context.Background(), &key)
+	assert.NoError(t, err)
+	assert.True(t, found)
+
+	key = "OSE-2"
+	found, err = client.IssueExists(context.Background(), &key)
+	assert.NoError(t, err)
+	assert.False(t, found)
+}
+
+func TestGetIssue(t *testing.T) {
+	t.Parallel()
+
+	server, client := newClient(t)
+	server.AddIssue(jiratest.NewIssue("OSE-1", "Fix the flux capacitor", "In Progress"))
+
+	issue, err := client.GetIssue(context.Background(), "ose-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "OSE-1", issue.Key)
+	assert.Equal(t, "Fix the flux capacitor", issue.Fields.Summary)
+	assert.Equal(t, "In Progress", issue.Fields.Status.Name)
+
+	_, err = client.GetIssue(context.Background(), "OSE-2")
+
+	var apiErr *jira.APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.Equal(t, "/rest/api/2/issue/OSE-2", apiErr.Endpoint)
+}
+
+func TestSearch(t *testing.T) {
+	t.Parallel()
+
+	server, client := newClient(t)
+
+	// More than two pages
+	for n := 1; n <= 120; n++ {
+		server.AddIssue(jiratest.NewIssue(fmt.Sprintf("OSE-%d", n), "Issue", "To Do"))
+	}
+
+	server.AddIssue(jiratest.NewIssue("OPS-1", "Other project", "To Do"))
+	server.AddIssue(jiratest.NewIssue("OSE-121", "Done", "Done"))
+
+	issues, err := client.GetIssuesSelecting(context.Background(), `project = OSE AND status != Done`,
+		"key", []string{"summary"})
+	assert.NoError(t, err)
+	assert.Len(t, issues, 120)
+	assert.Equal(t, "OSE-120", issues[119].Key)
+	assert.Empty(t, issues[0].Fields.Status.Name, "only the fields asked for are returned")
+
+	issues, err = client.GetIssuesSelecting(context.Background(), `key in (OPS-1, "OSE-121")`,
+		"key", []string{"summary"})
+	assert.NoError(t, err)
+	assert.Len(t, issues, 2)
+
+	client.SetSearchLimit(70)
+
+	issues, err = client.GetIssuesSelecting(context.Background(), "project = OSE", "key", []string{"summary"})
+	assert.NoError(t, err)
+	assert.Len(t, issues, 70)
+
+	_, err = client.GetIssuesSelecting(context.Background(), "summary ~ flux", "key", []string{"summary"})
+	assert.Error(t, err)
+}
+
+func TestWorklogs(t *testing.T) {
+	t.Parallel()
+
+	server, client := newClient(t)
+	server.AddIssue(jiratest.NewIssue("OSE-1", "Fix the flux capacitor", "In Progress"))
+
+	assert.NoError(t, client.AddWorklog(context.Background(), "2024-03-01", "09:00", "OSE-1", "5400", "Debugging"))
+
+	worklogs, err := client.GetWorklogs(context.Background(), "OSE-1")
+	assert.NoError(t, err)
+	assert.Len(t, worklogs, 1)
+	assert.Equal(t, 5400, worklogs[0].TimeSpentSeconds)
+	assert.Equal(t, "Debugging", worklogs[0].Comment)
+	assert.Equal(t, jiratest.Username, worklogs[0].Author.Name)
+
+	assert.NoError(t, client.DeleteWorklog(context.Background(), "OSE-1", worklogs[0].ID))
+	assert.Empty(t, server.Worklogs("OSE-1"))
+	assert.Error(t, client.DeleteWorklog(context.Background(), "OSE-1", worklogs[0].ID))
+}
+
+func TestComments(t *testing.T) {
+	t.Parallel()
+
+	server, client := newClient(t)
+	server.AddIssue(jiratest.NewIssue("OSE-1", "Fix the flux capacitor", "In Progress"))
+
+	assert.NoError(t, client.AddComment(context.Background(), "OSE-1", []byte("Almost there")))
+
+	comments, err := client.GetComments(context.Background(), "OSE-1")
+	assert.NoError(t, err)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "Almost there", comments[0].Body)
+}
+
+func TestTransitions(t *testing.T) {
+	t.Parallel()
+
+	server, client := newClient(t)
+	server.AddIssue(jiratest.NewIssue("OSE-1", "Fix the flux capacitor", "To Do"))
+
+	transitions, err := client.GetTransistions(context.Background(), "OSE-1")
+	assert.NoError(t, err)
+	assert.Len(t, transitions, 3)
+
+	assert.NoError(t, client.UpdateStatus(context.Background(), "OSE-1", transitions[2].ID))
+
+	issue, _ := server.Issue("OSE-1")
+	assert.Equal(t, "Done", issue.Fields["status"].(map[string]any)["name"])
+}
+
+func TestSprints(t *testing.T) {
+	t.Parallel()
+
+	server, client := newClient(t)
+
+	keys := []string{}
+
+	for n := 1; n <= 60; n++ {
+		keys = append(keys, fmt.Sprintf("OSE-%d", n))
+		server.AddIssue(jiratest.NewIssue(keys[n-1], "Issue", "To Do"))
+	}
+
+	server.AddBoard(jiratest.Board{ID: 7, Name: "Team", Sprints: []jiratest.Sprint{
+		{ID: 1, Name: "Sprint 1", State: "closed"},
+		{ID: 2, Name: "Sprint 2", State: "active", Issues: keys},
+		{ID: 3, Name: "Sprint 3", State: "future"},
+	}})
+
+	views, err := client.GetRapidViews(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []types.RapidView{{ID: 7, Name: "Team", SprintSupportEnabled: true}}, views)
+
+	sprints, err := client.GetOpenSprints(context.Background(), 7)
+	assert.NoError(t, err)
+	assert.Len(t, sprints, 2)
+	assert.Equal(t, "ACTIVE", sprints[0].State)
+
+	issues, err := client.GetSprintIssues(context.Background(), 7, 2)
+	assert.NoError(t, err)
+	assert.Len(t, issues, 60)
+	assert.Equal(t, jiratest.Username, issues[0].Assignee)
+	assert.Equal(t, "Bob", issues[0].AssigneeName)
+}
+
+func TestGetMyself(t *testing.T) {
+	t.Parallel()
+
+	_, client := newClient(t)
+
+	user, err := client.GetMyself(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, jiratest.Username, user.Name)
+	assert.Equal(t, "Bob Builder", user.DisplayName)
+}
+
+func TestDecryptPassword(t *testing.T) {
+	t.Parallel()
+
+	_, client := newClient(t)
+	assert.NoError(t, client.DecryptPassword())
+
+	_, client = newClient(t, func(c *types.Config) { c.PasswordType = "op" })
+	assert.EqualError(t, client.DecryptPassword(),
+		"The password must be a 1Password secret reference, e.g. op://vault/jira/password")
+}
+
+func TestTLSConfig(t *testing.T) {
+	t.Parallel()
+
+	server := jiratest.NewTLSServer()
+	t.Cleanup(server.Close)
+
+	caCert := filepath.Join(t.TempDir(), "ca.pem")
+	assert.NoError(t, os.WriteFile(caCert,
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0o600))
+
+	tlsConfig, err := jira.TLSConfig(server.Config())
+	assert.NoError(t, err)
+	assert.Nil(t, tlsConfig)
+
+	_, err = jira.NewClient(server.Config()).GetMyself(context.Background())
+	assert.Error(t, err, "the certificate of the server is not trusted")
+
+	for _, configure := range []func(*types.Config){
+		func(c *types.Config) { c.CACert = caCert },
+		func(c *types.Config) { c.InsecureSkipVerify = true },
+	} {
+		cfg := server.Config()
+		configure(&cfg)
+
+		tlsConfig, err := jira.TLSConfig(cfg)
+		assert.NoError(t, err)
+
+		client := jira.NewClient(cfg)
+		client.SetTLSConfig(tlsConfig)
+
+		_, err = client.GetMyself(context.Background())
+		assert.NoError(t, err)
+	}
+
+	_, err = jira.TLSConfig(types.Config{CACert: filepath.Join(t.TempDir(), "missing.pem")})
+	assert.Error(t, err)
+
+	_, err = jira.TLSConfig(types.Config{ClientCert: caCert})
+	assert.Error(t, err, "a client certificate needs the key")
+}
+
+func TestAuthentication(t *testing.T) {
+	t.Parallel()
+
+	server, client := newClient(t, func(c *types.Config) { c.AuthMode = "session" })
+	server.AddIssue(jiratest.NewIssue("OSE-1", "Fix the flux capacitor", "To Do"))
+
+	for range 3 {
+		_, err := client.GetComments(context.Background(), "OSE-1")
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, "POST /rest/auth/1/session", server.Requests()[0])
+	assert.Len(t, server.Requests(), 4)
+
+	_, client = newClient(t, func(c *types.Config) { c.Password = "wrong" })
+
+	_, err := client.GetComments(context.Background(), "OSE-1")
+
+	var apiErr *jira.APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+}
+
+func TestAPIError(t *testing.T) {
+	t.Parallel()
+
+	client := newFixtureClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"errorMessages": ["Invalid issue"], "errors": {"summary": "required", "assignee": "unknown"}}`))
+	})
+
+	_, err := client.GetMyself(context.Background())
+	assert.EqualError(t, err, "400 Bad Request - Invalid issue, assignee: unknown, summary: required")
+
+	var apiErr *jira.APIError
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	assert.Equal(t, "/rest/api/2/myself", apiErr.Endpoint)
+}
+
+func TestDownloadAttachment(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/secure/attachment/10000/notes.txt" {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		_, _ = w.Write([]byte("Great Scott!"))
+	}))
+	t.Cleanup(server.Close)
+
+	client := jira.NewClient(types.Config{
+		JiraURL:      server.URL,
+		Username:     "bob",
+		Password:     "token",
+		PasswordType: "pat",
+		Deployment:   "server",
+	})
+
+	var buf bytes.Buffer
+
+	n, err := client.DownloadAttachment(context.Background(), types.Attachment{
+		Filename: "notes.txt",
+		Content:  server.URL + "/secure/attachment/10000/notes.txt",
+	}, &buf)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12), n)
+	assert.Equal(t, "Great Scott!", buf.String())
+
+	_, err = client.DownloadAttachment(context.Background(), types.Attachment{
+		Content: server.URL + "/secure/attachment/10001/missing.txt",
+	}, &buf)
+
+	var apiErr *jira.APIError
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+}
+
+func TestIssueLinks(t *testing.T) {
+	t.Parallel()
+
+	requests := []string{}
+
+	client := newFixtureClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	assert.NoError(t, client.LinkIssues(context.Background(), "Blocks", "ose-1", "ose-2"))
+	assert.NoError(t, client.DeleteIssueLink(context.Background(), "3001"))
+	assert.Equal(t, []string{"POST /rest/api/2/issueLink", "DELETE /rest/api/2/issueLink/3001"}, requests)
+}
+
+func TestWatch(t *testing.T) {
+	t.Parallel()
+
+	requests := []string{}
+
+	client := newFixtureClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rest/api/2/myself" {
+			_, _ = w.Write([]byte(`{"name": "bob", "accountId": "5b10ac8d82e05b22cc7d4ef5"}`))
+
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.RequestURI()+" "+string(body))
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	assert.NoError(t, client.Watch(context.Background(), "ose-1"))
+	assert.NoError(t, client.Unwatch(context.Background(), "ose-1"))
+	assert.Equal(t, []string{
+		`POST /rest/api/2/issue/OSE-1/watchers "bob"`,
+		"DELETE /rest/api/2/issue/OSE-1/watchers?username=bob ",
+	}, requests)
+}
+
+func TestGetIssueWithChangelog(t *testing.T) {
+	t.Parallel()
+
+	requests := []string{}
+
+	client := newFixtureClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.RequestURI())
+
+		_, _ = w.Write([]byte(`{"key": "OSE-1"}`))
+	})
+
+	_, err := client.GetIssue(context.Background(), "ose-1")
+	assert.NoError(t, err)
+	_, err = client.GetIssueWithChangelog(context.Background(), "ose-1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"/rest/api/2/issue/OSE-1",
+		"/rest/api/2/issue/OSE-1?expand=changelog",
+	}, requests)
+}
+
+func TestUnassignIssue(t *testing.T) {
+	t.Parallel()
+
+	for deployment, want := range map[string]string{
+		"server": `PUT /rest/api/2/issue/OSE-1/assignee {"name":null}`,
+		"cloud":  `PUT /rest/api/3/issue/OSE-1/assignee {"accountId":null}`,
+	} {
+		requests := []string{}
+
+		client := newDeploymentClient(t, deployment, func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			requests = append(requests, r.Method+" "+r.URL.Path+" "+string(body))
+
+			w.WriteHeader(http.StatusNoContent)
+		})
+
+		assert.NoError(t, client.UnassignIssue(context.Background(), "ose-1"), deployment)
+		assert.Equal(t, []string{want}, requests, deployment)
+	}
+}
+
+func TestGetBoardEpics(t *testing.T) {
+	t.Parallel()
+
+	const total = 70
+
+	client := newFixtureClient(t, func(w http.ResponseWriter, r *http.Request) {
+		startAt, _ := strconv.Atoi(r.URL.Query().Get("startAt"))
+		end := min(startAt+50, total)
+
+		epics := []string{}
+		for i := startAt; i < end; i++ {
+			epics = append(epics, fmt.Sprintf(`{"id": %d, "key": "OSE-%d"}`, i+1, i+1))
+		}
+
+		_, _ = fmt.Fprintf(w, `{"startAt": %d, "isLast": %t, "values": [%s]}`,
+			startAt, end == total, strings.Join(epics, ","))
+	})
+
+	epics, err := client.GetBoardEpics(context.Background(), 7)
+	assert.NoError(t, err)
+	assert.Len(t, epics, total)
+	assert.Equal(t, "OSE-70", epics[total-1].Key)
+}
+
+func TestGetWorkloadIssues(t *testing.T) {
+	t.Parallel()
+
+	const total = 120
+
+	client := newFixtureClient(t, func(w http.ResponseWriter, r *http.Request) {
+		// Jira returns at most 50 issues per page, whatever is asked for
+		startAt, _ := strconv.Atoi(r.URL.Query().Get("startAt"))
+		end := min(startAt+50, total)
+
+		issues := []string{}
+		for i := startAt; i < end; i++ {
+			issues = append(issues, fmt.Sprintf(`{"key": "OSE-%d", "fields": {"timeestimate": 3600}}`, i+1))
+		}
+
+		_, _ = fmt.Fprintf(w, `{"startAt": %d, "total": %d, "issues": [%s]}`,
+			startAt, total, strings.Join(issues, ","))
+	})
+
+	issues, err := client.GetWorkloadIssues(context.Background(), 7, "resolution = Unresolved")
+	assert.NoError(t, err)
+	assert.Len(t, issues, total)
+	assert.Equal(t, "OSE-120", issues[total-1].Key)
+}
+
+func TestRetries(t *testing.T) {
+	t.Parallel()
+
+	attempts := 0
+
+	client := newFixtureClient(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+
+		switch {
+		case r.Method == http.MethodPost:
+			w.WriteHeader(http.StatusBadGateway)
+		case attempts < 3:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			_, _ = w.Write([]byte(`{"name": "bob"}`))
+		}
+	})
+	client.SetMaxAttempts(3)
+
+	user, err := client.GetMyself(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "bob", user.Name)
+	assert.Equal(t, 3, attempts)
+
+	// A new comment may have been added before the proxy failed
+	attempts = 0
+
+	assert.Error(t, client.AddComment(context.Background(), "OSE-1", []byte("Almost there")))
+	assert.Equal(t, 1, attempts)
+}
+
+func TestTimesheet(t *testing.T) {
+	t.Parallel()
+
+	client := newFixtureClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/rest/timesheet-gadget/1.0/raw-timesheet.json", r.URL.Path)
+		assert.Equal(t, "2024-03-01", r.URL.Query().Get("startDate"))
+
+		_, _ = w.Write([]byte(`{"worklog": [{"key": "OSE-1", "summary": "Fix the flux capacitor",
+			"entries": [{"id": 55, "author": "bob", "startDate": 1709280000000, "timeSpent": 3600}]}]}`))
+	})
+
+	timesheet, err := client.GetTimesheet(context.Background(), "2024-03-01", "2024-03-01", false)
+	assert.NoError(t, err)
+	assert.Len(t, timesheet, 1)
+	assert.Equal(t, "OSE-1", timesheet[0].Key)
+	assert.Equal(t, 3600, timesheet[0].Entries[0].TimeSpent)
+
+	client = newFixtureClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	_, err = client.GetTimesheet(context.Background(), "2024-03-01", "2024-03-01", false)
+	assert.ErrorIs(t, err, jira.ErrNoTimesheetPlugin)
+}
+
+func TestSprintReport(t *testing.T) {
+	t.Parallel()
+
+	client := newFixtureClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/rest/greenhopper/1.0/rapid/charts/sprintreport", r.URL.Path)
+		assert.Equal(t, "rapidViewId=7&sprintId=2", r.URL.RawQuery)
+
+		_, _ = w.Write([]byte(`{"sprint": {"id": 2, "name": "Sprint 2", "state": "ACTIVE"},
+			"contents": {"completedIssues": [{"key": "OSE-1"}], "puntedIssues": [{"key": "OSE-2"}],
+			"issueKeysAddedDuringSprint": {"OSE-2": true}}}`))
+	})
+
+	report, err := client.GetSprintReport(context.Background(), 7, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, "Sprint 2", report.Sprint.Name)
+	assert.Equal(t, "OSE-1", report.Contents.CompletedIssues[0].Key)
+	assert.Equal(t, "OSE-2", report.Contents.PuntedIssues[0].Key)
+	assert.True(t, report.Contents.IssueKeysAddedDuringSprint["OSE-2"])
+}
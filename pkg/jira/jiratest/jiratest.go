@@ -0,0 +1,754 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+// Package jiratest provides an in-memory Jira for tests. The server
+// simulates the subset of the REST API gojira uses, i.e. searching,
+// issues with their comments, worklogs and transitions, and boards
+// with their sprints, so features can be tested without a real Jira.
+package jiratest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mhersson/gojira/pkg/types"
+)
+
+// The credentials accepted by the server.
+const (
+	Username = "bob"
+	Password = "secret"
+)
+
+// sessionCookie is the session given when logging in with the credentials.
+const sessionCookie = "JSESSIONID"
+
+// Issue is an issue with its fields as Jira returns them,
+// e.g. "summary" and "status", keyed by the field id.
+type Issue struct {
+	Key    string
+	Fields map[string]any
+}
+
+// Board is a scrum board with its sprints.
+type Board struct {
+	ID      int
+	Name    string
+	Sprints []Sprint
+}
+
+// Sprint is a sprint with the keys of the issues in it.
+// The state is active, future or closed.
+type Sprint struct {
+	ID     int
+	Name   string
+	State  string
+	Issues []string
+}
+
+// transitions are the transitions of every issue, by id.
+var transitions = []struct {
+	ID       string
+	Status   string
+	Category string
+}{
+	{"11", "To Do", "new"},
+	{"21", "In Progress", "indeterminate"},
+	{"31", "Done", "done"},
+}
+
+// The issue types and priorities, the same on all servers.
+var (
+	issueTypes = []map[string]any{
+		{"id": "1", "name": "Task"},
+		{"id": "2", "name": "Bug"},
+		{"id": "3", "name": "Story"},
+		{"id": "4", "name": "Epic"},
+	}
+	priorities = []map[string]any{
+		{"id": "2", "name": "High"},
+		{"id": "3", "name": "Medium"},
+		{"id": "4", "name": "Low"},
+	}
+)
+
+// Server is an in-memory Jira. Add the issues and boards before
+// running the commands, and check the changes made afterwards.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	keys     []string
+	issues   map[string]*Issue
+	comments map[string][]types.Comment
+	worklogs map[string][]types.Worklog
+	boards   []Board
+	requests []string
+	nextID   int
+}
+
+// NewServer starts an in-memory Jira, close it when done.
+func NewServer() *Server {
+	s := newServer()
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+
+	return s
+}
+
+// NewTLSServer returns an empty Jira served over https,
+// with a certificate signed by no one the client trusts.
+func NewTLSServer() *Server {
+	s := newServer()
+	s.Server = httptest.NewTLSServer(http.HandlerFunc(s.serve))
+
+	return s
+}
+
+func newServer() *Server {
+	return &Server{
+		issues:   map[string]*Issue{},
+		comments: map[string][]types.Comment{},
+		worklogs: map[string][]types.Worklog{},
+		nextID:   10000,
+	}
+}
+
+// Config returns a config for the server, with the credentials it accepts.
+func (s *Server) Config() types.Config {
+	return types.Config{
+		JiraURL:      s.URL,
+		Username:     Username,
+		Password:     Password,
+		PasswordType: "plain",
+		Deployment:   "server",
+	}
+}
+
+// NewIssue returns an issue with the fields most commands need.
+func NewIssue(key, summary, status string) Issue {
+	category := "indeterminate"
+
+	for _, t := range transitions {
+		if t.Status == status {
+			category = t.Category
+		}
+	}
+
+	return Issue{Key: key, Fields: map[string]any{
+		"summary":   summary,
+		"status":    map[string]any{"name": status, "statusCategory": map[string]any{"key": category}},
+		"issuetype": map[string]any{"id": "1", "name": "Task"},
+		"priority":  map[string]any{"id": "3", "name": "Medium"},
+		"project":   map[string]any{"key": strings.Split(key, "-")[0]},
+		"assignee":  map[string]any{"name": Username, "displayName": "Bob"},
+		"created":   "2024-03-01T09:00:00.000+0000",
+		"updated":   "2024-03-01T09:00:00.000+0000",
+	}}
+}
+
+// AddIssue adds the issue, or replaces the issue with the same key.
+func (s *Server) AddIssue(issue Issue) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.issues[issue.Key]; !ok {
+		s.keys = append(s.keys, issue.Key)
+	}
+
+	s.issues[issue.Key] = &issue
+}
+
+// AddComment adds the comment to the issue, and gives it an id.
+func (s *Server) AddComment(key string, comment types.Comment) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	comment.ID = s.newID()
+	s.comments[key] = append(s.comments[key], comment)
+}
+
+// AddWorklog adds the worklog to the issue, and gives it an id.
+func (s *Server) AddWorklog(key string, worklog types.Worklog) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	worklog.ID = s.newID()
+	s.worklogs[key] = append(s.worklogs[key], worklog)
+}
+
+// AddBoard adds the board with its sprints.
+func (s *Server) AddBoard(board Board) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.boards = append(s.boards, board)
+}
+
+// Issue returns the issue, e.g. to check the fields changed.
+func (s *Server) Issue(key string) (Issue, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i, ok := s.issues[key]
+	if !ok {
+		return Issue{}, false
+	}
+
+	return *i, true
+}
+
+// Comments returns the comments on the issue.
+func (s *Server) Comments(key string) []types.Comment {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.comments[key])
+}
+
+// Worklogs returns the worklogs on the issue.
+func (s *Server) Worklogs(key string) []types.Worklog {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.worklogs[key])
+}
+
+// Requests returns the method and path of the requests served so far.
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.requests)
+}
+
+func (s *Server) newID() string {
+	s.nextID++
+
+	return strconv.Itoa(s.nextID)
+}
+
+// route is an endpoint, the groups of the pattern are passed to the handler.
+type route struct {
+	method  string
+	pattern *regexp.Regexp
+	handle  func(s *Server, w http.ResponseWriter, r *http.Request, args []string)
+}
+
+var routes = []route{
+	{http.MethodGet, regexp.MustCompile(`^/rest/api/2/serverInfo$`), (*Server).serverInfo},
+	{http.MethodGet, regexp.MustCompile(`^/rest/api/2/myself$`), (*Server).myself},
+	{http.MethodPost, regexp.MustCompile(`^/rest/api/2/search$`), (*Server).search},
+	{http.MethodGet, regexp.MustCompile(`^/rest/api/2/issuetype$`), (*Server).getIssueTypes},
+	{http.MethodGet, regexp.MustCompile(`^/rest/api/2/priority$`), (*Server).getPriorities},
+	{http.MethodGet, regexp.MustCompile(`^/rest/api/2/issue/([^/]+)$`), (*Server).getIssue},
+	{http.MethodPut, regexp.MustCompile(`^/rest/api/2/issue/([^/]+)$`), (*Server).editIssue},
+	{http.MethodPut, regexp.MustCompile(`^/rest/api/2/issue/([^/]+)/assignee$`), (*Server).assign},
+	{http.MethodGet, regexp.MustCompile(`^/rest/api/2/issue/([^/]+)/comment$`), (*Server).getComments},
+	{http.MethodPost, regexp.MustCompile(`^/rest/api/2/issue/([^/]+)/comment$`), (*Server).addComment},
+	{http.MethodGet, regexp.MustCompile(`^/rest/api/2/issue/([^/]+)/worklog$`), (*Server).getWorklogs},
+	{http.MethodPost, regexp.MustCompile(`^/rest/api/2/issue/([^/]+)/worklog$`), (*Server).addWorklog},
+	{http.MethodDelete, regexp.MustCompile(`^/rest/api/2/issue/([^/]+)/worklog/([^/]+)$`), (*Server).deleteWorklog},
+	{http.MethodGet, regexp.MustCompile(`^/rest/api/2/issue/([^/]+)/transitions$`), (*Server).getTransitions},
+	{http.MethodPost, regexp.MustCompile(`^/rest/api/2/issue/([^/]+)/transitions$`), (*Server).transition},
+	{http.MethodGet, regexp.MustCompile(`^/rest/greenhopper/1.0/rapidview$`), (*Server).getBoards},
+	{http.MethodGet, regexp.MustCompile(`^/rest/agile/1.0/board/(\d+)/sprint$`), (*Server).getSprints},
+	{http.MethodGet, regexp.MustCompile(`^/rest/agile/1.0/board/(\d+)/sprint/(\d+)/issue$`), (*Server).getSprintIssues},
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+
+	if r.Method == http.MethodPost && r.URL.Path == "/rest/auth/1/session" {
+		s.login(w, r)
+
+		return
+	}
+
+	if !authorized(r) {
+		reply(w, http.StatusUnauthorized, errorMessages("You are not authenticated"))
+
+		return
+	}
+
+	for _, rt := range routes {
+		if m := rt.pattern.FindStringSubmatch(r.URL.Path); m != nil && rt.method == r.Method {
+			rt.handle(s, w, r, m[1:])
+
+			return
+		}
+	}
+
+	reply(w, http.StatusNotFound, errorMessages("jiratest does not serve "+r.Method+" "+r.URL.Path))
+}
+
+// authorized reports if the request has the credentials, or the session cookie.
+func authorized(r *http.Request) bool {
+	if c, err := r.Cookie(sessionCookie); err == nil && c.Value == sessionID() {
+		return true
+	}
+
+	user, password, ok := r.BasicAuth()
+
+	return ok && user == Username && password == Password
+}
+
+func sessionID() string {
+	return "jiratest-" + Username
+}
+
+func (s *Server) login(w http.ResponseWriter, r *http.Request) {
+	var credentials struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+	}
+
+	if !decodeBody(w, r, &credentials) {
+		return
+	}
+
+	if credentials.Username != Username || credentials.Password != Password {
+		reply(w, http.StatusUnauthorized, errorMessages("Login failed"))
+
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{Name: sessionCookie, Value: sessionID(), Path: "/"})
+	reply(w, http.StatusOK, map[string]any{"session": map[string]string{"name": sessionCookie, "value": sessionID()}})
+}
+
+func (s *Server) serverInfo(w http.ResponseWriter, _ *http.Request, _ []string) {
+	reply(w, http.StatusOK, types.ServerInfo{
+		BaseURL:        s.URL,
+		Version:        "9.12.2",
+		BuildNumber:    912002,
+		DeploymentType: "Server",
+		ServerTitle:    "jiratest",
+	})
+}
+
+func (s *Server) myself(w http.ResponseWriter, _ *http.Request, _ []string) {
+	reply(w, http.StatusOK, types.User{Name: Username, DisplayName: "Bob Builder", EmailAddress: "bob@example.com"})
+}
+
+func (s *Server) getIssueTypes(w http.ResponseWriter, _ *http.Request, _ []string) {
+	reply(w, http.StatusOK, issueTypes)
+}
+
+func (s *Server) getPriorities(w http.ResponseWriter, _ *http.Request, _ []string) {
+	reply(w, http.StatusOK, priorities)
+}
+
+func (s *Server) search(w http.ResponseWriter, r *http.Request, _ []string) {
+	var query struct {
+		JQL        string   `json:"jql"`
+		StartAt    int      `json:"startAt"`
+		MaxResults int      `json:"maxResults"`
+		Fields     []string `json:"fields"`
+	}
+
+	if !decodeBody(w, r, &query) {
+		return
+	}
+
+	filter, err := parseJQL(query.JQL)
+	if err != nil {
+		reply(w, http.StatusBadRequest, errorMessages(err.Error()))
+
+		return
+	}
+
+	matching := []map[string]any{}
+
+	for _, k := range s.keys {
+		if filter(s.issues[k]) {
+			matching = append(matching, issueJSON(s.issues[k], query.Fields))
+		}
+	}
+
+	start, end := page(len(matching), query.StartAt, query.MaxResults)
+
+	reply(w, http.StatusOK, map[string]any{
+		"startAt":    query.StartAt,
+		"maxResults": query.MaxResults,
+		"total":      len(matching),
+		"issues":     matching[start:end],
+	})
+}
+
+func (s *Server) getIssue(w http.ResponseWriter, r *http.Request, args []string) {
+	issue, ok := s.issue(w, args[0])
+	if !ok {
+		return
+	}
+
+	fields := []string{}
+	if f := r.URL.Query().Get("fields"); f != "" {
+		fields = strings.Split(f, ",")
+	}
+
+	resp := issueJSON(issue, fields)
+	resp["changelog"] = map[string]any{"histories": []any{}}
+
+	reply(w, http.StatusOK, resp)
+}
+
+func (s *Server) editIssue(w http.ResponseWriter, r *http.Request, args []string) {
+	issue, ok := s.issue(w, args[0])
+	if !ok {
+		return
+	}
+
+	var edit struct {
+		Fields map[string]any `json:"fields"`
+	}
+
+	if !decodeBody(w, r, &edit) {
+		return
+	}
+
+	for k, v := range edit.Fields {
+		issue.Fields[k] = v
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) assign(w http.ResponseWriter, r *http.Request, args []string) {
+	issue, ok := s.issue(w, args[0])
+	if !ok {
+		return
+	}
+
+	var assignee struct {
+		Name *string `json:"name"`
+	}
+
+	if !decodeBody(w, r, &assignee) {
+		return
+	}
+
+	if assignee.Name == nil {
+		issue.Fields["assignee"] = nil
+	} else {
+		issue.Fields["assignee"] = map[string]any{"name": *assignee.Name, "displayName": *assignee.Name}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) getComments(w http.ResponseWriter, _ *http.Request, args []string) {
+	if _, ok := s.issue(w, args[0]); !ok {
+		return
+	}
+
+	comments := s.comments[args[0]]
+
+	reply(w, http.StatusOK, map[string]any{"total": len(comments), "comments": nonNil(comments)})
+}
+
+func (s *Server) addComment(w http.ResponseWriter, r *http.Request, args []string) {
+	if _, ok := s.issue(w, args[0]); !ok {
+		return
+	}
+
+	var comment types.Comment
+	if !decodeBody(w, r, &comment) {
+		return
+	}
+
+	comment.ID = s.newID()
+	comment.Author.Name = Username
+	comment.Author.DisplayName = "Bob"
+	comment.Created = time.Now().Format("2006-01-02T15:04:05.000-0700")
+
+	s.comments[args[0]] = append(s.comments[args[0]], comment)
+
+	reply(w, http.StatusCreated, comment)
+}
+
+func (s *Server) getWorklogs(w http.ResponseWriter, _ *http.Request, args []string) {
+	if _, ok := s.issue(w, args[0]); !ok {
+		return
+	}
+
+	worklogs := s.worklogs[args[0]]
+
+	reply(w, http.StatusOK, map[string]any{
+		"startAt":    0,
+		"maxResults": len(worklogs),
+		"total":      len(worklogs),
+		"worklogs":   nonNil(worklogs),
+	})
+}
+
+func (s *Server) addWorklog(w http.ResponseWriter, r *http.Request, args []string) {
+	if _, ok := s.issue(w, args[0]); !ok {
+		return
+	}
+
+	var worklog types.Worklog
+	if !decodeBody(w, r, &worklog) {
+		return
+	}
+
+	if worklog.TimeSpentSeconds <= 0 {
+		reply(w, http.StatusBadRequest, errorMessages("You must indicate the time spent working"))
+
+		return
+	}
+
+	worklog.ID = s.newID()
+	worklog.Author.Name = Username
+	worklog.Author.DisplayName = "Bob"
+	worklog.TimeSpent = fmt.Sprintf("%dm", worklog.TimeSpentSeconds/60)
+
+	s.worklogs[args[0]] = append(s.worklogs[args[0]], worklog)
+
+	reply(w, http.StatusCreated, worklog)
+}
+
+func (s *Server) deleteWorklog(w http.ResponseWriter, _ *http.Request, args []string) {
+	if _, ok := s.issue(w, args[0]); !ok {
+		return
+	}
+
+	worklogs := s.worklogs[args[0]]
+
+	i := slices.IndexFunc(worklogs, func(wl types.Worklog) bool { return wl.ID == args[1] })
+	if i < 0 {
+		reply(w, http.StatusNotFound, errorMessages("Cannot find worklog with id: "+args[1]))
+
+		return
+	}
+
+	s.worklogs[args[0]] = slices.Delete(worklogs, i, i+1)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) getTransitions(w http.ResponseWriter, _ *http.Request, args []string) {
+	if _, ok := s.issue(w, args[0]); !ok {
+		return
+	}
+
+	resp := []map[string]any{}
+
+	for _, t := range transitions {
+		resp = append(resp, map[string]any{
+			"id":   t.ID,
+			"name": t.Status,
+			"to":   map[string]any{"id": t.ID, "name": t.Status, "statusCategory": map[string]any{"key": t.Category}},
+		})
+	}
+
+	reply(w, http.StatusOK, map[string]any{"transitions": resp})
+}
+
+func (s *Server) transition(w http.ResponseWriter, r *http.Request, args []string) {
+	issue, ok := s.issue(w, args[0])
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Transition struct {
+			ID string `json:"id"`
+		} `json:"transition"`
+	}
+
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	for _, t := range transitions {
+		if t.ID == req.Transition.ID {
+			issue.Fields["status"] = map[string]any{"name": t.Status, "statusCategory": map[string]any{"key": t.Category}}
+
+			w.WriteHeader(http.StatusNoContent)
+
+			return
+		}
+	}
+
+	reply(w, http.StatusBadRequest, errorMessages("Transition id '"+req.Transition.ID+"' is not valid for this issue."))
+}
+
+func (s *Server) getBoards(w http.ResponseWriter, _ *http.Request, _ []string) {
+	views := []types.RapidView{}
+
+	for _, b := range s.boards {
+		views = append(views, types.RapidView{ID: b.ID, Name: b.Name, SprintSupportEnabled: true})
+	}
+
+	reply(w, http.StatusOK, map[string]any{"views": views})
+}
+
+func (s *Server) getSprints(w http.ResponseWriter, r *http.Request, args []string) {
+	board, ok := s.board(w, args[0])
+	if !ok {
+		return
+	}
+
+	states := []string{}
+	if st := r.URL.Query().Get("state"); st != "" {
+		states = strings.Split(strings.ToLower(st), ",")
+	}
+
+	values := []map[string]any{}
+
+	for _, sp := range board.Sprints {
+		if len(states) == 0 || slices.Contains(states, strings.ToLower(sp.State)) {
+			values = append(values, map[string]any{"id": sp.ID, "name": sp.Name, "state": strings.ToLower(sp.State)})
+		}
+	}
+
+	start, end := page(len(values), queryInt(r, "startAt"), queryInt(r, "maxResults"))
+
+	reply(w, http.StatusOK, map[string]any{"isLast": end == len(values), "values": values[start:end]})
+}
+
+func (s *Server) getSprintIssues(w http.ResponseWriter, r *http.Request, args []string) {
+	board, ok := s.board(w, args[0])
+	if !ok {
+		return
+	}
+
+	id, _ := strconv.Atoi(args[1])
+
+	i := slices.IndexFunc(board.Sprints, func(sp Sprint) bool { return sp.ID == id })
+	if i < 0 {
+		reply(w, http.StatusNotFound, errorMessages("Sprint does not exist"))
+
+		return
+	}
+
+	fields := []string{}
+	if f := r.URL.Query().Get("fields"); f != "" {
+		fields = strings.Split(f, ",")
+	}
+
+	issues := []map[string]any{}
+
+	for _, k := range board.Sprints[i].Issues {
+		if issue, ok := s.issues[k]; ok {
+			issues = append(issues, issueJSON(issue, fields))
+		}
+	}
+
+	start, end := page(len(issues), queryInt(r, "startAt"), queryInt(r, "maxResults"))
+
+	reply(w, http.StatusOK, map[string]any{"total": len(issues), "issues": issues[start:end]})
+}
+
+// issue returns the issue, or replies not found.
+func (s *Server) issue(w http.ResponseWriter, key string) (*Issue, bool) {
+	issue, ok := s.issues[strings.ToUpper(key)]
+	if !ok {
+		reply(w, http.StatusNotFound, errorMessages("Issue Does Not Exist"))
+	}
+
+	return issue, ok
+}
+
+// board returns the board, or replies not found.
+func (s *Server) board(w http.ResponseWriter, id string) (Board, bool) {
+	for _, b := range s.boards {
+		if strconv.Itoa(b.ID) == id {
+			return b, true
+		}
+	}
+
+	reply(w, http.StatusNotFound, errorMessages("Board does not exist"))
+
+	return Board{}, false
+}
+
+// issueJSON returns the issue with only the fields asked for, like
+// Jira does, or all fields if none or *all are asked for.
+func issueJSON(issue *Issue, fields []string) map[string]any {
+	selected := map[string]any{}
+
+	for k, v := range issue.Fields {
+		if len(fields) == 0 || slices.Contains(fields, "*all") || slices.Contains(fields, k) {
+			selected[k] = v
+		}
+	}
+
+	return map[string]any{"id": strings.Split(issue.Key, "-")[1], "key": issue.Key, "fields": selected}
+}
+
+// page returns the start and end of the page in a list of n items.
+func page(n, startAt, maxResults int) (int, int) {
+	start := min(max(startAt, 0), n)
+	if maxResults <= 0 {
+		return start, n
+	}
+
+	return start, min(start+maxResults, n)
+}
+
+func queryInt(r *http.Request, name string) int {
+	i, _ := strconv.Atoi(r.URL.Query().Get(name))
+
+	return i
+}
+
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+
+	return items
+}
+
+func decodeBody(w http.ResponseWriter, r *http.Request, v any) bool {
+	body, _ := io.ReadAll(r.Body)
+
+	if err := json.Unmarshal(body, v); err != nil {
+		reply(w, http.StatusBadRequest, errorMessages("Invalid request body: "+err.Error()))
+
+		return false
+	}
+
+	return true
+}
+
+func errorMessages(messages ...string) map[string]any {
+	return map[string]any{"errorMessages": messages, "errors": map[string]string{}}
+}
+
+func reply(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json;charset=UTF-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
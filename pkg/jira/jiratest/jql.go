@@ -0,0 +1,159 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package jiratest
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// The JQL understood by the server is clauses joined by AND, comparing
+// a field with =, !=, in or not in, e.g. project = OSE AND status in
+// ("To Do", "In Progress") order by key. Other JQL is rejected, so a
+// test never passes on a filter the server silently ignored.
+var (
+	orderBy = regexp.MustCompile(`(?i)\s+order\s+by\s+.*$`)
+	and     = regexp.MustCompile(`(?i)\s+and\s+`)
+	clause  = regexp.MustCompile(`(?i)^(\w+)\s*(=|!=|not\s+in|in)\s*(.+)$`)
+)
+
+// aliases are the JQL names of the fields with another id.
+var aliases = map[string]string{
+	"type": "issuetype",
+}
+
+// parseJQL returns a filter matching the issues selected by the JQL.
+func parseJQL(jql string) (func(*Issue) bool, error) {
+	jql = strings.TrimSpace(orderBy.ReplaceAllString(jql, ""))
+	if jql == "" {
+		return func(*Issue) bool { return true }, nil
+	}
+
+	filters := []func(*Issue) bool{}
+
+	for _, c := range and.Split(jql, -1) {
+		m := clause.FindStringSubmatch(strings.TrimSpace(c))
+		if m == nil {
+			return nil, fmt.Errorf("jiratest does not support the JQL %q", c)
+		}
+
+		field := strings.ToLower(m[1])
+		if a, ok := aliases[field]; ok {
+			field = a
+		}
+
+		op := strings.Join(strings.Fields(strings.ToLower(m[2])), " ")
+		values := parseValues(m[3], strings.HasSuffix(op, "in"))
+
+		filters = append(filters, func(i *Issue) bool {
+			matches := slices.ContainsFunc(fieldValues(i, field), func(v string) bool {
+				return slices.ContainsFunc(values, func(want string) bool { return strings.EqualFold(v, want) })
+			})
+
+			return matches == (op == "=" || op == "in")
+		})
+	}
+
+	return func(i *Issue) bool {
+		for _, f := range filters {
+			if !f(i) {
+				return false
+			}
+		}
+
+		return true
+	}, nil
+}
+
+// parseValues returns the value, or the list of values for in,
+// without quotes and with the functions the server knows evaluated.
+func parseValues(s string, list bool) []string {
+	s = strings.TrimSpace(s)
+
+	parts := []string{s}
+	if list {
+		parts = strings.Split(strings.TrimSuffix(strings.TrimPrefix(s, "("), ")"), ",")
+	}
+
+	values := []string{}
+
+	for _, p := range parts {
+		v := strings.Trim(strings.TrimSpace(p), `"'`)
+
+		switch strings.ToLower(v) {
+		case "currentuser()":
+			v = Username
+		case "empty", "null", "unresolved":
+			v = ""
+		}
+
+		values = append(values, v)
+	}
+
+	return values
+}
+
+// fieldValues returns the values of the field on the issue to compare
+// with, the names, keys and values of objects, and "" if it is not set.
+func fieldValues(i *Issue, field string) []string {
+	if field == "key" {
+		return []string{i.Key}
+	}
+
+	var values []string
+
+	var add func(v any)
+
+	add = func(v any) {
+		switch v := v.(type) {
+		case string:
+			values = append(values, v)
+		case map[string]any:
+			for _, k := range []string{"name", "key", "value", "displayName"} {
+				if s, ok := v[k].(string); ok {
+					values = append(values, s)
+				}
+			}
+		case []any:
+			for _, e := range v {
+				add(e)
+			}
+		case []string:
+			for _, e := range v {
+				add(e)
+			}
+		case nil:
+		default:
+			values = append(values, fmt.Sprint(v))
+		}
+	}
+
+	add(i.Fields[field])
+
+	if len(values) == 0 {
+		return []string{""}
+	}
+
+	return values
+}
@@ -0,0 +1,114 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package jira
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"strings"
+
+	"github.com/mhersson/gojira/pkg/types"
+)
+
+// useSession reports if the client should log in once and authenticate
+// with the session cookie instead of sending the password with every
+// request. Jira Cloud has no sessions, and tokens are sent as they are.
+func useSession(config types.Config) bool {
+	return strings.EqualFold(config.AuthMode, "session") && !IsCloud(config) &&
+		config.PasswordType != "pat" && config.PasswordType != "apitoken"
+}
+
+// newCookieJar returns an empty jar for the session cookie,
+// or nil when not using session auth.
+func newCookieJar(session bool) http.CookieJar {
+	if !session {
+		return nil
+	}
+
+	jar, _ := cookiejar.New(nil)
+
+	return jar
+}
+
+// authenticate decrypts the password, and with session auth logs in
+// to get the session cookie before the first request.
+func (c *Client) authenticate(ctx context.Context) error {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+
+	if err := c.cfg.Decrypt(); err != nil {
+		return err
+	}
+
+	if !c.session || c.loggedIn {
+		return nil
+	}
+
+	if err := c.login(ctx); err != nil {
+		return err
+	}
+
+	c.loggedIn = true
+
+	return nil
+}
+
+// login creates a session, the cookie is kept in the jar of the http client.
+func (c *Client) login(ctx context.Context) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	payload, _ := json.Marshal(map[string]string{"username": c.cfg.Username, "password": c.cfg.Password})
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, c.cfg.Server+"/rest/auth/1/session",
+		bytes.NewReader(payload))
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to log in: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to log in: %w", newAPIError(resp, body))
+	}
+
+	return nil
+}
+
+// DecryptPassword decrypts the password before the first request, and
+// returns the error instead of exiting if it fails, e.g. to check it.
+func (c *Client) DecryptPassword() error {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+
+	return c.cfg.Decrypt() //nolint:wrapcheck
+}
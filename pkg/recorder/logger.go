@@ -0,0 +1,150 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package recorder
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Headers never logged, because they hold the credentials.
+var secretHeaders = []string{"Authorization", "Cookie", "Set-Cookie"}
+
+// Logger is a http.RoundTripper writing the method, URL, status and time
+// of every request to the writer, e.g. to debug a request rejected by
+// Jira. With bodies the headers and bodies are written too, redacted as
+// in the traces.
+type Logger struct {
+	next   http.RoundTripper
+	w      io.Writer
+	bodies bool
+	mu     sync.Mutex
+}
+
+func NewLogger(next http.RoundTripper, w io.Writer, bodies bool) *Logger {
+	return &Logger{next: next, w: w, bodies: bodies}
+}
+
+func (l *Logger) RoundTrip(req *http.Request) (*http.Response, error) {
+	secrets := requestSecrets(req)
+	url := req.URL.Redacted()
+
+	out := &strings.Builder{}
+	fmt.Fprintf(out, "--> %s %s\n", req.Method, url)
+
+	if l.bodies {
+		writeHeaders(out, req.Header)
+
+		if req.Body != nil {
+			body, err := io.ReadAll(req.Body)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Body = io.NopCloser(bytes.NewReader(body))
+			writeBody(out, req.Header.Get("Content-Type"), body, secrets)
+		}
+	}
+
+	l.write(out.String())
+
+	start := time.Now()
+
+	resp, err := l.next.RoundTrip(req)
+
+	elapsed := time.Since(start).Round(time.Millisecond)
+
+	if err != nil {
+		l.write(fmt.Sprintf("<-- %s %s failed after %s - %s\n", req.Method, url, elapsed, err.Error()))
+
+		return resp, err
+	}
+
+	out.Reset()
+	fmt.Fprintf(out, "<-- %s %s %s (%s)\n", resp.Status, req.Method, url, elapsed)
+
+	if l.bodies {
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+
+		writeHeaders(out, resp.Header)
+		writeBody(out, resp.Header.Get("Content-Type"), body, append(secrets, cookieValues(resp.Cookies())...))
+	}
+
+	l.write(out.String())
+
+	return resp, nil
+}
+
+// write writes the lines at once, so the lines of concurrent requests are not mixed.
+func (l *Logger) write(lines string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	_, _ = io.WriteString(l.w, lines)
+}
+
+func writeHeaders(out *strings.Builder, header http.Header) {
+	keys := make([]string, 0, len(header))
+	for k := range header {
+		keys = append(keys, k)
+	}
+
+	slices.Sort(keys)
+
+	for _, k := range keys {
+		value := strings.Join(header[k], ", ")
+		if slices.Contains(secretHeaders, k) {
+			value = redacted
+		}
+
+		fmt.Fprintf(out, "    %s: %s\n", k, value)
+	}
+}
+
+// writeBody writes the body if it is text, like the json sent to and
+// from Jira, and only the size of anything else, like attachments.
+func writeBody(out *strings.Builder, contentType string, body []byte, secrets []string) {
+	if len(body) == 0 {
+		return
+	}
+
+	if !strings.Contains(contentType, "json") && !strings.HasPrefix(contentType, "text/") {
+		fmt.Fprintf(out, "    (%d bytes of %s)\n", len(body), contentType)
+
+		return
+	}
+
+	fmt.Fprintf(out, "\n%s\n\n", strings.TrimSpace(Redact(string(body), secrets...)))
+}
@@ -0,0 +1,223 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+// Package recorder records the HTTP traffic between gojira and Jira to
+// a trace file, and replays it, so bugs can be reproduced without access
+// to the Jira server, and traces can be used as regression tests.
+package recorder
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+const redacted = "REDACTED"
+
+// Values of json keys matching this are always redacted.
+var sensitiveKeys = regexp.MustCompile(`(?i)("[a-z_]*(password|token|secret)[a-z_]*"\s*:\s*)"[^"]*"`)
+
+// Exchange is a request and the response to it. The URL is stored
+// without scheme and host, so the trace can be replayed against any server.
+type Exchange struct {
+	Method   string `json:"method"`
+	URL      string `json:"url"`
+	Request  string `json:"request,omitempty"`
+	Status   int    `json:"status"`
+	Response string `json:"response"`
+}
+
+// Recorder is a http.RoundTripper writing all exchanges to the trace file.
+// The file is rewritten after every exchange, so nothing is lost if the
+// command exits early. Credentials are never written.
+type Recorder struct {
+	next      http.RoundTripper
+	file      string
+	mu        sync.Mutex
+	exchanges []Exchange
+}
+
+func New(next http.RoundTripper, file string) (*Recorder, error) {
+	r := &Recorder{next: next, file: file, exchanges: []Exchange{}}
+
+	return r, r.save()
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	secrets := requestSecrets(req)
+
+	e := Exchange{Method: req.Method, URL: req.URL.RequestURI()}
+
+	if req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		e.Request = Redact(string(body), secrets...)
+	}
+
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	e.Status = resp.StatusCode
+	e.Response = Redact(string(body), append(secrets, cookieValues(resp.Cookies())...)...)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.exchanges = append(r.exchanges, e)
+
+	return resp, r.save()
+}
+
+// requestSecrets returns the credentials sent with the request,
+// the password or token and the session cookie.
+func requestSecrets(req *http.Request) []string {
+	secrets := []string{}
+	if _, password, ok := req.BasicAuth(); ok && password != "" {
+		secrets = append(secrets, password)
+	}
+
+	if token, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer "); ok && token != "" {
+		secrets = append(secrets, token)
+	}
+
+	return append(secrets, cookieValues(req.Cookies())...)
+}
+
+// cookieValues returns the values of the cookies, e.g. the session id
+// given when logging in to Jira, which is also in the response body.
+func cookieValues(cookies []*http.Cookie) []string {
+	values := []string{}
+
+	for _, c := range cookies {
+		if c.Value != "" {
+			values = append(values, c.Value)
+		}
+	}
+
+	return values
+}
+
+func (r *Recorder) save() error {
+	data, err := json.MarshalIndent(r.exchanges, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(r.file, data, 0600)
+}
+
+// Redact replaces the secrets, and the values of json keys
+// containing password, token or secret.
+func Redact(text string, secrets ...string) string {
+	for _, s := range secrets {
+		text = strings.ReplaceAll(text, s, redacted)
+	}
+
+	return sensitiveKeys.ReplaceAllString(text, `$1"`+redacted+`"`)
+}
+
+// Replayer is a http.RoundTripper responding with the exchanges from
+// a trace file. Each request gets the first unused exchange with the
+// same method and URL, so repeated requests are replayed in order.
+type Replayer struct {
+	mu        sync.Mutex
+	exchanges []Exchange
+	used      []bool
+}
+
+func Load(file string) (*Replayer, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	exchanges := []Exchange{}
+	if err := json.Unmarshal(data, &exchanges); err != nil {
+		return nil, fmt.Errorf("invalid trace %s - %w", file, err)
+	}
+
+	return &Replayer{exchanges: exchanges, used: make([]bool, len(exchanges))}, nil
+}
+
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	uri := req.URL.RequestURI()
+
+	for i, e := range r.exchanges {
+		if r.used[i] || e.Method != req.Method || e.URL != uri {
+			continue
+		}
+
+		r.used[i] = true
+
+		return &http.Response{
+			Status:     fmt.Sprintf("%d %s", e.Status, http.StatusText(e.Status)),
+			StatusCode: e.Status,
+			Proto:      "HTTP/1.1",
+			ProtoMajor: 1,
+			ProtoMinor: 1,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(e.Response)),
+			Request:    req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("no recorded response for %s %s", req.Method, uri)
+}
+
+// Unused returns the exchanges that were never requested,
+// e.g. to check that a replayed command made all the expected requests.
+func (r *Replayer) Unused() []Exchange {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	unused := []Exchange{}
+
+	for i, e := range r.exchanges {
+		if !r.used[i] {
+			unused = append(unused, e)
+		}
+	}
+
+	return unused
+}
@@ -0,0 +1,172 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package recorder_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mhersson/gojira/pkg/recorder"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedact(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		text     string
+		secrets  []string
+		expected string
+	}{
+		{`{"name": "bob"}`, nil, `{"name": "bob"}`},
+		{`{"password": "hunter2", "name": "bob"}`, nil, `{"password": "REDACTED", "name": "bob"}`},
+		{`{"apiToken":"abc","clientSecret" : "def"}`, nil, `{"apiToken":"REDACTED","clientSecret" : "REDACTED"}`},
+		{`the password is hunter2`, []string{"hunter2"}, `the password is REDACTED`},
+	}
+
+	for _, tc := range tests {
+		assert.Equal(t, tc.expected, recorder.Redact(tc.text, tc.secrets...))
+	}
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"path": "` + r.URL.Path + `", "echo": "` + string(body) + `"}`))
+	}))
+	defer server.Close()
+
+	trace := filepath.Join(t.TempDir(), "trace.json")
+
+	rec, err := recorder.New(http.DefaultTransport, trace)
+	assert.NoError(t, err)
+
+	// Only the trace is redacted, not the response
+	recorded := request(t, rec, server.URL+"/rest/api/2/issue?x=1", "secret-pw")
+	assert.Equal(t, `{"path": "/rest/api/2/issue", "echo": "secret-pw"}`, recorded)
+
+	data, err := os.ReadFile(trace)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "secret-pw")
+
+	rep, err := recorder.Load(trace)
+	assert.NoError(t, err)
+	assert.Len(t, rep.Unused(), 1)
+
+	// The host is not part of the trace
+	replayed := request(t, rep, "http://jira.example.com/rest/api/2/issue?x=1", "other-pw")
+	assert.Equal(t, `{"path": "/rest/api/2/issue", "echo": "REDACTED"}`, replayed)
+	assert.Empty(t, rep.Unused())
+
+	// Every exchange is only replayed once
+	_, err = rep.RoundTrip(newRequest(t, "http://jira.example.com/rest/api/2/issue?x=1", ""))
+	assert.Error(t, err)
+}
+
+func TestRecordSessionCookie(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "JSESSIONID", Value: "6E3487971234567896704A9EB4AE501F"})
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"session": {"name": "JSESSIONID", "value": "6E3487971234567896704A9EB4AE501F"}}`))
+	}))
+	defer server.Close()
+
+	trace := filepath.Join(t.TempDir(), "trace.json")
+
+	rec, err := recorder.New(http.DefaultTransport, trace)
+	assert.NoError(t, err)
+
+	request(t, rec, server.URL+"/rest/auth/1/session", "secret-pw")
+
+	data, err := os.ReadFile(trace)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "6E3487971234567896704A9EB4AE501F")
+}
+
+func TestLogger(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"echo": "` + string(body) + `"}`))
+	}))
+	defer server.Close()
+
+	out := &bytes.Buffer{}
+
+	// Only the log is redacted, not the response
+	logged := request(t, recorder.NewLogger(http.DefaultTransport, out, true), server.URL+"/rest/api/2/issue", "secret-pw")
+	assert.Equal(t, `{"echo": "secret-pw"}`, logged)
+
+	assert.Contains(t, out.String(), "--> POST "+server.URL+"/rest/api/2/issue\n")
+	assert.Contains(t, out.String(), "    Authorization: REDACTED\n")
+	assert.Contains(t, out.String(), `{"echo": "REDACTED"}`)
+	assert.Regexp(t, `<-- 201 Created POST \S+/rest/api/2/issue \(\d+ms\)`, out.String())
+	assert.NotContains(t, out.String(), "secret-pw")
+
+	out.Reset()
+	request(t, recorder.NewLogger(http.DefaultTransport, out, false), server.URL+"/rest/api/2/issue", "secret-pw")
+	assert.Equal(t, 2, strings.Count(out.String(), "\n"), "only the request and response lines without bodies")
+}
+
+func newRequest(t *testing.T, url, password string) *http.Request {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, url, strings.NewReader(password))
+	assert.NoError(t, err)
+
+	req.SetBasicAuth("bob", password)
+
+	return req
+}
+
+func request(t *testing.T, rt http.RoundTripper, url, password string) string {
+	t.Helper()
+
+	resp, err := rt.RoundTrip(newRequest(t, url, password))
+	assert.NoError(t, err)
+
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+
+	return string(body)
+}
@@ -24,54 +24,159 @@ package types
 import (
 	"bytes"
 	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/zalando/go-keyring"
+	"golang.org/x/term"
+
+	"github.com/mhersson/gojira/pkg/util/agent"
+	"github.com/mhersson/gojira/pkg/util/calendar"
 )
 
+// KeyringService is the service the passwords are stored
+// under in the keyring of the operating system.
+const KeyringService = "gojira"
+
 // var cfgFile string.
 type Config struct {
-	JiraURL             string            `yaml:"JiraURL"` //nolint:tagliatelle
-	Username            string            `yaml:"username"`
-	Password            string            `yaml:"password"`
-	PasswordType        string            `yaml:"passwordtype"`
-	UseTimesheetPlugin  bool              `yaml:"useTimesheetPlugin"`
-	CheckForUpdates     bool              `yaml:"checkForUpdates"`
-	NumWorkingDays      int               `yaml:"numberOfWorkingDays"`
-	WorkingHoursPerDay  float64           `yaml:"numberOfWorkingHoursPerDay"`
-	WorkingHoursPerWeek float64           `yaml:"numberOfWorkingHoursPerWeek"`
-	CountryCode         string            `yaml:"countryCode"`
-	Aliases             map[string]string `yaml:"aliases,omitempty"`
-	SprintFilter        string            `yaml:"sprintFilter"`
+	JiraURL             string             `yaml:"JiraURL"` //nolint:tagliatelle
+	Username            string             `yaml:"username"`
+	Password            string             `yaml:"password"`
+	PasswordType        string             `yaml:"passwordtype"`
+	PasswordCache       time.Duration      `yaml:"passwordCache,omitempty"`
+	AuthMode            string             `yaml:"authMode,omitempty"`
+	CACert              string             `yaml:"caCert,omitempty"`
+	ClientCert          string             `yaml:"clientCert,omitempty"`
+	ClientKey           string             `yaml:"clientKey,omitempty"`
+	InsecureSkipVerify  bool               `yaml:"insecureSkipVerify,omitempty"`
+	UseTimesheetPlugin  bool               `yaml:"useTimesheetPlugin"`
+	CheckForUpdates     bool               `yaml:"checkForUpdates"`
+	NumWorkingDays      int                `yaml:"numberOfWorkingDays"`
+	WorkingHoursPerDay  float64            `yaml:"numberOfWorkingHoursPerDay"`
+	WorkingHoursPerWeek float64            `yaml:"numberOfWorkingHoursPerWeek"`
+	CountryCode         string             `yaml:"countryCode"`
+	WeekNumbering       string             `yaml:"weekNumbering,omitempty"`
+	Aliases             map[string]string  `yaml:"aliases,omitempty"`
+	SprintFilter        string             `yaml:"sprintFilter"`
+	ParticipantsField   string             `yaml:"participantsField,omitempty"`
+	TimerMax            time.Duration      `yaml:"timerMax,omitempty"`
+	ExistsCacheTTL      time.Duration      `yaml:"existsCacheTTL,omitempty"`
+	MaxAttempts         int                `yaml:"maxAttempts,omitempty"`
+	Deployment          string             `yaml:"deployment,omitempty"`
+	FullSummary         bool               `yaml:"fullSummary,omitempty"`
+	TruncateSummary     int                `yaml:"truncateSummary,omitempty"`
+	RawMarkup           bool               `yaml:"rawMarkup,omitempty"`
+	EditMarkdown        bool               `yaml:"editMarkdown,omitempty"`
+	ReadOnly            bool               `yaml:"readOnly"`
+	EscalationWatchers  []string           `yaml:"escalationWatchers,omitempty"`
+	AssigneeRules       map[string]string  `yaml:"assigneeRules,omitempty"`
+	TakeStatus          string             `yaml:"takeStatus,omitempty"`
+	DoneStatus          string             `yaml:"doneStatus,omitempty"`
+	DoneResolution      string             `yaml:"doneResolution,omitempty"`
+	InsightFields       []string           `yaml:"insightFields,omitempty"`
+	Mail                MailConfig         `yaml:"mail,omitempty"`
+	Confirm             ConfirmConfig      `yaml:"confirm,omitempty"`
+	ActiveIssue         ActiveIssueConfig  `yaml:"activeIssue,omitempty"`
+	Profiles            map[string]Profile `yaml:"profiles,omitempty"`
+}
+
+// Profile is another Jira server, with the credentials to use for it.
+type Profile struct {
+	JiraURL      string `yaml:"JiraURL"` //nolint:tagliatelle
+	Username     string `yaml:"username"`
+	Password     string `yaml:"password"`
+	PasswordType string `yaml:"passwordtype"`
+	Deployment   string `yaml:"deployment,omitempty"`
+	AuthMode     string `yaml:"authMode,omitempty"`
+}
+
+// MailConfig is how reports are sent, either through an SMTP server
+// or by piping the mail to a sendmail style command.
+type MailConfig struct {
+	From         string `yaml:"from"`
+	SMTPServer   string `yaml:"smtpServer,omitempty"`
+	Username     string `yaml:"username,omitempty"`
+	Password     string `yaml:"password,omitempty"`
+	PasswordType string `yaml:"passwordtype,omitempty"`
+	Sendmail     string `yaml:"sendmail,omitempty"`
+}
+
+// ConfirmConfig is which changes in Jira must be confirmed before
+// they are made. WorklogHours is the length of the worklogs needing
+// a confirmation, 0 means never.
+type ConfirmConfig struct {
+	Transitions  bool    `yaml:"transitions"`
+	Bulk         bool    `yaml:"bulk"`
+	Deletes      bool    `yaml:"deletes"`
+	WorklogHours float64 `yaml:"worklogHours,omitempty"`
+}
+
+// ActiveIssueConfig is when the active issue is stale, and must be
+// checked before it is used. MaxDays is how long it can go unused,
+// 0 means forever. WhenStale is ask or clear.
+type ActiveIssueConfig struct {
+	MaxDays   int    `yaml:"maxDays,omitempty"`
+	Resolved  bool   `yaml:"resolved,omitempty"`
+	WhenStale string `yaml:"whenStale,omitempty"`
 }
 
 type JiraConfig struct {
-	Server       string
-	Username     string
-	Password     string
-	PasswordType string
-	Decrypted    bool
+	Server        string
+	Username      string
+	Password      string
+	PasswordType  string
+	PasswordCache time.Duration
+	Decrypted     bool
 }
 
-func (c *JiraConfig) DecryptPassword() {
+// Decrypt decrypts the password with the program of the password type,
+// or asks for it when there is none. A plain password is used as it is.
+func (c *JiraConfig) Decrypt() error {
 	if c.Decrypted {
-		return
+		return nil
+	}
+
+	if c.Password == "" {
+		pw, err := c.promptPassword()
+		if err != nil {
+			return err
+		}
+
+		c.Password = pw
+		c.Decrypted = true
+
+		return nil
 	}
 
 	switch c.PasswordType {
 	case "pass":
 		pw, err := exec.Command("pass", c.Password).Output() //nolint:gosec
 		if err != nil {
-			fmt.Printf("Failed to run pass: %s\n", err.Error())
-			os.Exit(1)
+			return &Error{Message: "Failed to run pass: " + err.Error()}
 		}
 
 		lines := strings.Split(string(pw), "\n")
 		c.Password = strings.TrimSpace(lines[0])
-		c.Decrypted = true
+	case "op":
+		if !strings.HasPrefix(c.Password, "op://") {
+			return &Error{Message: "The password must be a 1Password secret reference, e.g. op://vault/jira/password"}
+		}
+
+		pw, err := exec.Command("op", "read", "--no-newline", c.Password).Output() //nolint:gosec
+		if err != nil {
+			return &Error{Message: "Failed to run op: " + err.Error()}
+		}
+
+		c.Password = strings.TrimSpace(string(pw))
 	case "gpg":
 		cmd := exec.Command("gpg", "--decrypt")
 		armored, _ := base64.StdEncoding.DecodeString(c.Password)
@@ -79,12 +184,30 @@ func (c *JiraConfig) DecryptPassword() {
 
 		pw, err := cmd.Output()
 		if err != nil {
-			fmt.Printf("Failed to run gpg decrypt: %s\n", err.Error())
-			os.Exit(1)
+			return &Error{Message: "Failed to run gpg decrypt: " + err.Error()}
 		}
 
 		c.Password = strings.TrimSpace(string(pw))
-		c.Decrypted = true
+	case "keyring":
+		pw, err := keyring.Get(KeyringService, c.Password)
+		if errors.Is(err, keyring.ErrNotFound) {
+			return &Error{Message: fmt.Sprintf("The password %s is not in the keyring, add it with gojira auth set",
+				c.Password)}
+		}
+
+		if err != nil {
+			return &Error{Message: "Failed to read the keyring: " + err.Error()}
+		}
+
+		c.Password = pw
+	case "apitoken":
+		if problems := APITokenProblems(c.Username, c.Password); len(problems) > 0 {
+			return &Error{Message: "Invalid API token config: " + strings.Join(problems, ", ")}
+		}
+	case "pat":
+		if strings.TrimSpace(c.Password) == "" {
+			return &Error{Message: "Invalid personal access token config: the password must be the token"}
+		}
 	default:
 		fmt.Println("You should encrypt your password!!")
 		fmt.Println("Start using your gpg key by running the following command")
@@ -92,6 +215,91 @@ func (c *JiraConfig) DecryptPassword() {
 		fmt.Println("Copy the output and paste it into the config.yaml password field, all on one line")
 		fmt.Println("Then set passwordtype = gpg in your config file")
 	}
+
+	c.Decrypted = true
+
+	return nil
+}
+
+// promptPassword asks for the password on the terminal. If the password
+// cache is enabled, the password is kept by the agent until the cache times
+// out, and only asked for when it is not cached.
+func (c *JiraConfig) promptPassword() (string, error) {
+	key := c.Username + "@" + c.Server
+	socket := agent.Socket()
+
+	if c.PasswordCache > 0 {
+		if pw, ok := agent.Get(socket, key); ok {
+			return pw, nil
+		}
+	}
+
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", &Error{Message: "No password is configured, and stdin is not a terminal to ask for it"}
+	}
+
+	fmt.Fprintf(os.Stderr, "Password for %s at %s: ", c.Username, c.Server)
+
+	pw, err := term.ReadPassword(int(os.Stdin.Fd()))
+
+	fmt.Fprintln(os.Stderr)
+
+	if err != nil {
+		return "", &Error{Message: "Failed to read the password: " + err.Error()}
+	}
+
+	password := strings.TrimSpace(string(pw))
+
+	if c.PasswordCache > 0 {
+		if err := cachePassword(socket, key, password, c.PasswordCache); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: the password is not cached - %s\n", err.Error())
+		}
+	}
+
+	return password, nil
+}
+
+// cachePassword gives the password to the agent, and starts the agent
+// with `gojira auth agent` if it is not running.
+func cachePassword(socket, key, password string, ttl time.Duration) error {
+	if !agent.Running(socket) {
+		exe, err := os.Executable()
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		if err := agent.Start(socket, exe, "auth", "agent", "--timeout", ttl.String()); err != nil {
+			return err //nolint:wrapcheck
+		}
+	}
+
+	return agent.Set(socket, key, password) //nolint:wrapcheck
+}
+
+// APITokenProblems returns what is wrong with the credentials for an
+// Atlassian API token, which is sent with the email of the account.
+func APITokenProblems(username, token string) []string {
+	problems := []string{}
+
+	if !strings.Contains(username, "@") {
+		problems = append(problems, "the username must be the email of your Atlassian account")
+	}
+
+	if strings.TrimSpace(token) == "" {
+		problems = append(problems, "the password must be the API token")
+	}
+
+	return problems
+}
+
+// PersonalAccessToken is a token for Jira Data Center. The raw token
+// is only returned when the token is created.
+type PersonalAccessToken struct {
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	CreatedAt  string `json:"createdAt"`
+	ExpiringAt string `json:"expiringAt"`
+	RawToken   string `json:"rawToken"`
 }
 
 type Error struct {
@@ -102,6 +310,12 @@ func (e *Error) Error() string {
 	return e.Message
 }
 
+// Validator is implemented by responses from Jira that are
+// unusable without some essential data, e.g. the key of an issue.
+type Validator interface {
+	Validate() error
+}
+
 // Color type.
 type Color struct {
 	Red     string
@@ -111,10 +325,20 @@ type Color struct {
 	Magenta string
 	Cyan    string
 	Bold    string
+	Italic  string
 	Ul      string
+	Strike  string
 	Nocolor string
 }
 
+func (i *IssueDescription) Validate() error {
+	if i.Key == "" {
+		return &Error{Message: "the issue has no key"}
+	}
+
+	return nil
+}
+
 type IssueDescription struct {
 	ID     string `json:"id"`
 	Key    string `json:"key"`
@@ -132,6 +356,7 @@ type IssueDescription struct {
 		} `json:"priority"`
 		Labels     []string `json:"labels"`
 		IssueLinks []struct {
+			ID   string `json:"id"`
 			Type struct {
 				Name    string `json:"name"`
 				Inward  string `json:"inward"`
@@ -202,7 +427,65 @@ type IssueDescription struct {
 		Comment struct {
 			Comments []Comment `json:"comments"`
 		} `json:"comment"`
+		Subtasks []struct {
+			Key string `json:"key"`
+		} `json:"subtasks"`
+		Attachments []Attachment `json:"attachment"`
 	} `json:"fields"`
+	Changelog Changelog `json:"changelog"`
+}
+
+// ExportedIssue is an issue with the fields copied when
+// migrating it to another Jira server.
+type ExportedIssue struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Summary     string `json:"summary"`
+		Description string `json:"description"`
+		IssueType   struct {
+			Name string `json:"name"`
+		} `json:"issuetype"`
+		Priority struct {
+			Name string `json:"name"`
+		} `json:"priority"`
+		Project struct {
+			Key string `json:"key"`
+		} `json:"project"`
+		Labels      []string     `json:"labels"`
+		DueDate     string       `json:"duedate"`
+		Attachments []Attachment `json:"attachment"`
+	} `json:"fields"`
+}
+
+type Attachment struct {
+	ID       string `json:"id"`
+	Filename string `json:"filename"`
+	Author   struct {
+		Name        string `json:"name"`
+		DisplayName string `json:"displayName"`
+	} `json:"author"`
+	Created  string `json:"created"`
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Content  string `json:"content"`
+}
+
+type Changelog struct {
+	Histories []ChangelogHistory `json:"histories"`
+}
+
+type ChangelogHistory struct {
+	ID     string `json:"id"`
+	Author struct {
+		Name        string `json:"name"`
+		DisplayName string `json:"displayName"`
+	} `json:"author"`
+	Created string `json:"created"`
+	Items   []struct {
+		Field      string `json:"field"`
+		FromString string `json:"fromString"`
+		ToString   string `json:"toString"`
+	} `json:"items"`
 }
 
 type Issue struct {
@@ -222,11 +505,170 @@ type Issue struct {
 		} `json:"priority"`
 		Updated string `json:"updated"`
 		Status  struct {
+			Name           string `json:"name"`
+			StatusCategory struct {
+				Key string `json:"key"`
+			} `json:"statusCategory"`
+		} `json:"status"`
+		Resolution struct {
 			Name string `json:"name"`
+		} `json:"resolution"`
+		DueDate string `json:"duedate,omitempty"`
+	} `json:"fields"`
+}
+
+// IsDone returns true if the issue is in a done status.
+func (i *Issue) IsDone() bool {
+	if i.Fields.Status.StatusCategory.Key != "" {
+		return i.Fields.Status.StatusCategory.Key == "done"
+	}
+
+	return slices.Contains([]string{"Closed", "Resolved", "Verified"}, i.Fields.Status.Name)
+}
+
+// DependencyIssue holds the fields needed to build the blocking
+// graph of the issues in an epic.
+type DependencyIssue struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Summary string `json:"summary"`
+		Status  struct {
+			Name           string `json:"name"`
+			StatusCategory struct {
+				Key string `json:"key"`
+			} `json:"statusCategory"`
 		} `json:"status"`
+		TimeEstimate int `json:"timeestimate"`
+		IssueLinks   []struct {
+			Type struct {
+				Name string `json:"name"`
+			} `json:"type"`
+			OutwardIssue struct {
+				Key string `json:"key"`
+			} `json:"outwardIssue"`
+			InwardIssue struct {
+				Key string `json:"key"`
+			} `json:"inwardIssue"`
+		} `json:"issuelinks"`
+	} `json:"fields"`
+}
+
+// TimeTrackingIssue holds the estimates and time spent on an issue,
+// in seconds, with the epic it belongs to.
+type TimeTrackingIssue struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Summary          string `json:"summary"`
+		Epic             string `json:"customfield_10500"` //nolint:tagliatelle
+		OriginalEstimate int    `json:"timeoriginalestimate"`
+		TimeSpent        int    `json:"timespent"`
+		Remaining        int    `json:"timeestimate"`
+		// The aggregates include the subtasks of the issue
+		AggregateOriginalEstimate int `json:"aggregatetimeoriginalestimate"`
+		AggregateTimeSpent        int `json:"aggregatetimespent"`
+		AggregateRemaining        int `json:"aggregatetimeestimate"`
+	} `json:"fields"`
+}
+
+// ReviewIssue holds the project, epic and lifetime of an issue,
+// used to sum up the work done in a year.
+type ReviewIssue struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Summary string `json:"summary"`
+		Project struct {
+			Key string `json:"key"`
+		} `json:"project"`
+		Epic     string `json:"customfield_10500"` //nolint:tagliatelle
+		Created  string `json:"created"`
+		Resolved string `json:"resolutiondate"`
+	} `json:"fields"`
+}
+
+// LabelIssue holds the labels of an issue and the time spent on it, in seconds.
+type LabelIssue struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Labels    []string `json:"labels"`
+		TimeSpent int      `json:"timespent"`
 	} `json:"fields"`
 }
 
+// WorkloadIssue holds the fields needed to sum up the workload of the assignee.
+type WorkloadIssue struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Assignee struct {
+			Name        string `json:"name"`
+			DisplayName string `json:"displayName"`
+		} `json:"assignee"`
+		TimeEstimate int    `json:"timeestimate"`
+		DueDate      string `json:"duedate"`
+	} `json:"fields"`
+}
+
+type IssueLinkType struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Inward  string `json:"inward"`
+	Outward string `json:"outward"`
+}
+
+type Epic struct {
+	ID      int    `json:"id"`
+	Key     string `json:"key"`
+	Name    string `json:"name"`
+	Summary string `json:"summary"`
+	Done    bool   `json:"done"`
+}
+
+type ServerInfo struct {
+	BaseURL        string `json:"baseUrl"`
+	Version        string `json:"version"`
+	BuildNumber    int    `json:"buildNumber"`
+	DeploymentType string `json:"deploymentType"`
+	ServerTitle    string `json:"serverTitle"`
+}
+
+// RawIssue is an issue where the fields are kept as raw json.
+type RawIssue struct {
+	Key    string                     `json:"key"`
+	Fields map[string]json.RawMessage `json:"fields"`
+}
+
+type Filter struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	JQL   string `json:"jql"`
+	Owner struct {
+		Name        string `json:"name"`
+		DisplayName string `json:"displayName"`
+	} `json:"owner"`
+}
+
+type User struct {
+	Name         string `json:"name"`
+	AccountID    string `json:"accountId,omitempty"`
+	DisplayName  string `json:"displayName"`
+	EmailAddress string `json:"emailAddress,omitempty"`
+}
+
+type Watchers struct {
+	WatchCount int    `json:"watchCount"`
+	IsWatching bool   `json:"isWatching"`
+	Watchers   []User `json:"watchers"`
+}
+
+// RemoteLink is a link from an issue to a web page,
+// e.g. a merge request or a Confluence page.
+type RemoteLink struct {
+	ID     int `json:"id"`
+	Object struct {
+		URL   string `json:"url"`
+		Title string `json:"title"`
+	} `json:"object"`
+}
+
 type Comment struct {
 	ID     string `json:"id"`
 	Author struct {
@@ -241,15 +683,120 @@ type Comment struct {
 }
 
 type Worklog struct {
+	ID     string `json:"id"`
 	Author struct {
 		DisplayName string `json:"displayName"`
 		Name        string `json:"name"`
 	} `json:"author"`
-	Comment          string `json:"comment"`
-	Created          string `json:"created"`
-	Started          string `json:"started"`
-	TimeSpent        string `json:"timeSpent"`
-	TimeSpentSeconds int    `json:"timeSpentSeconds"`
+	Comment          string            `json:"comment"`
+	Created          string            `json:"created"`
+	Started          string            `json:"started"`
+	TimeSpent        string            `json:"timeSpent"`
+	TimeSpentSeconds int               `json:"timeSpentSeconds"`
+	Properties       []WorklogProperty `json:"properties,omitempty"`
+}
+
+// WorklogAttributesKey is the worklog property used to store the work attributes.
+const WorklogAttributesKey = "gojira.attributes"
+
+type WorklogProperty struct {
+	Key   string          `json:"key"`
+	Value json.RawMessage `json:"value"`
+}
+
+// WorklogAttributes are used to separate billable work and overtime,
+// and to book the work on a customer account.
+type WorklogAttributes struct {
+	Billable bool   `json:"billable"`
+	Account  string `json:"account,omitempty"`
+	Overtime bool   `json:"overtime"`
+}
+
+// Attributes returns the work attributes of the worklog,
+// and false if the worklog has none.
+func (w *Worklog) Attributes() (WorklogAttributes, bool) {
+	attrs := WorklogAttributes{}
+
+	for _, p := range w.Properties {
+		if p.Key == WorklogAttributesKey {
+			if err := json.Unmarshal(p.Value, &attrs); err == nil {
+				return attrs, true
+			}
+		}
+	}
+
+	return attrs, false
+}
+
+// InsightObject is an Insight (Assets) object referenced by a custom field.
+type InsightObject struct {
+	ID        int    `json:"id"`
+	ObjectKey string `json:"objectKey"`
+	Label     string `json:"label"`
+}
+
+func (o InsightObject) String() string {
+	switch {
+	case o.Label != "" && o.ObjectKey != "":
+		return o.Label + " (" + o.ObjectKey + ")"
+	case o.Label != "":
+		return o.Label
+	case o.ObjectKey != "":
+		return o.ObjectKey
+	}
+
+	return strconv.Itoa(o.ID)
+}
+
+// ParseInsightObjects returns the objects of an Insight custom field value.
+// Jira Server and Data Center return the objects as "Label (KEY-1)" strings,
+// while Jira Cloud returns objects only holding the workspace and object id.
+func ParseInsightObjects(raw json.RawMessage) []InsightObject {
+	objects := []InsightObject{}
+
+	values := []json.RawMessage{}
+	if err := json.Unmarshal(raw, &values); err != nil {
+		// Single object fields are not wrapped in an array
+		if len(raw) == 0 || string(raw) == "null" {
+			return objects
+		}
+
+		values = append(values, raw)
+	}
+
+	re := regexp.MustCompile(`^(.*) \(([A-Za-z0-9_]+-[0-9]+)\)$`)
+
+	for _, v := range values {
+		var str string
+		if err := json.Unmarshal(v, &str); err == nil {
+			if m := re.FindStringSubmatch(str); m != nil {
+				objects = append(objects, InsightObject{Label: m[1], ObjectKey: m[2]})
+			} else {
+				objects = append(objects, InsightObject{Label: str})
+			}
+
+			continue
+		}
+
+		// The id is a number on Server, and a "workspace:id" string on Cloud
+		obj := struct {
+			ID        json.RawMessage `json:"id"`
+			ObjectID  string          `json:"objectId"`
+			ObjectKey string          `json:"objectKey"`
+			Label     string          `json:"label"`
+		}{}
+
+		if err := json.Unmarshal(v, &obj); err == nil {
+			id, err := strconv.Atoi(string(obj.ID))
+			if err != nil {
+				id, _ = strconv.Atoi(obj.ObjectID)
+			}
+
+			objects = append(objects, InsightObject{ID: id, ObjectKey: obj.ObjectKey, Label: obj.Label})
+		}
+	}
+
+	return objects
 }
 
 type Transition struct {
@@ -260,10 +807,19 @@ type Transition struct {
 		Name           string `json:"name"`
 		ID             string `json:"id"`
 		StatusCategory struct {
+			Key  string `json:"key"`
 			Name string `json:"name"`
 			ID   int    `json:"id"`
 		} `json:"statusCategory"`
 	} `json:"to"`
+	HasScreen bool                       `json:"hasScreen"`
+	Fields    map[string]TransitionField `json:"fields"`
+}
+
+// TransitionField is a field on the transition screen.
+type TransitionField struct {
+	Name     string `json:"name"`
+	Required bool   `json:"required"`
 }
 
 type Project struct {
@@ -272,6 +828,32 @@ type Project struct {
 	Name string `json:"name"`
 }
 
+type Permission struct {
+	ID             string `json:"id"`
+	Key            string `json:"key"`
+	Name           string `json:"name"`
+	HavePermission bool   `json:"havePermission"`
+}
+
+type Component struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Lead        struct {
+		Name        string `json:"name"`
+		DisplayName string `json:"displayName"`
+	} `json:"lead"`
+}
+
+type Version struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Archived    bool   `json:"archived"`
+	Released    bool   `json:"released"`
+	ReleaseDate string `json:"releaseDate"`
+}
+
 type IssueType struct {
 	ID   string `json:"id"`
 	Name string `json:"name"`
@@ -285,13 +867,13 @@ type Priority struct {
 // Struct for representing the time a user
 // has spent on an issue on a given date.
 type TimeSpentUserIssue struct {
-	ID               string
-	Key              string
-	Date             string
-	User             string
-	Summary          string
-	TimeSpent        string
-	TimeSpentSeconds int
+	ID               string `json:"id"`
+	Key              string `json:"key"`
+	Date             string `json:"date"`
+	User             string `json:"user"`
+	Summary          string `json:"summary"`
+	TimeSpent        string `json:"timeSpent"`
+	TimeSpentSeconds int    `json:"timeSpentSeconds"`
 }
 
 // Used by getmyworklog command
@@ -312,13 +894,13 @@ type Timesheet struct {
 // Used by worklog command to be able to
 // sort and edit worklogs.
 type SimplifiedTimesheet struct {
-	ID        int
-	Date      string
-	StartDate string
-	Key       string
-	Summary   string
-	Comment   string
-	TimeSpent int
+	ID        int    `json:"id"`
+	Date      string `json:"date"`
+	StartDate string `json:"startDate"`
+	Key       string `json:"key"`
+	Summary   string `json:"summary"`
+	Comment   string `json:"comment"`
+	TimeSpent int    `json:"timeSpent"`
 }
 
 type RapidView struct {
@@ -334,6 +916,15 @@ type Sprint struct {
 	IssuesIDs []int  `json:"issuesIds"`
 }
 
+// BoardSprint is a sprint from the agile api, with its dates.
+type BoardSprint struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	State     string `json:"state"`
+	StartDate string `json:"startDate"`
+	EndDate   string `json:"endDate"`
+}
+
 func (s *Sprint) MatchesFilter(filter string) bool {
 	if filter == "" {
 		return true
@@ -360,9 +951,16 @@ type SprintIssue struct {
 }
 
 type SprintContent struct {
-	CompletedIssues                   []SprintIssue `json:"completedIssues"`
-	IssuesNotCompletedInCurrentSprint []SprintIssue `json:"issuesNotCompletedInCurrentSprint"`
-	IssuesCompletedInAnotherSprint    []SprintIssue `json:"issuesCompletedInAnotherSprint"`
+	CompletedIssues                   []SprintIssue   `json:"completedIssues"`
+	IssuesNotCompletedInCurrentSprint []SprintIssue   `json:"issuesNotCompletedInCurrentSprint"`
+	IssuesCompletedInAnotherSprint    []SprintIssue   `json:"issuesCompletedInAnotherSprint"`
+	PuntedIssues                      []SprintIssue   `json:"puntedIssues"`
+	IssueKeysAddedDuringSprint        map[string]bool `json:"issueKeysAddedDuringSprint"`
+}
+
+type SprintReport struct {
+	Contents SprintContent `json:"contents"`
+	Sprint   Sprint        `json:"sprint"`
 }
 
 type TimeStat struct {
@@ -378,6 +976,7 @@ type Week struct {
 	EndDate        time.Time
 	PublicHolidays int
 	Worklogs       []SimplifiedTimesheet
+	Rule           calendar.Rule
 }
 
 func inSlice(slice []string, s string) bool {
@@ -391,7 +990,7 @@ func inSlice(slice []string, s string) bool {
 }
 
 func (w *Week) Number() int {
-	_, i := w.StartDate.ISOWeek()
+	_, i := w.Rule.Week(w.StartDate)
 
 	return i
 }
@@ -424,6 +1023,30 @@ func (w *Week) Average() float64 {
 	return w.TotalTime() / float64(w.WorkDays())
 }
 
+// Timer is the state of the work timer.
+type Timer struct {
+	Key     string        `json:"key"`
+	Started time.Time     `json:"started"`
+	Max     time.Duration `json:"max"`
+	Running bool          `json:"running"`
+}
+
+// Elapsed returns the time since the timer was started,
+// but never more than the max duration if one is set.
+func (t *Timer) Elapsed(now time.Time) time.Duration {
+	elapsed := now.Sub(t.Started)
+	if t.Max > 0 && elapsed > t.Max {
+		return t.Max
+	}
+
+	return elapsed
+}
+
+// Expired returns true if the timer has run longer than the max duration.
+func (t *Timer) Expired(now time.Time) bool {
+	return t.Running && t.Max > 0 && now.Sub(t.Started) > t.Max
+}
+
 type PublicHoliday struct {
 	Date        string `json:"date"`
 	Name        string `json:"name"`
@@ -0,0 +1,460 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+// Package adf converts between the Atlassian Document Format used by the
+// Jira Cloud REST API v3 for descriptions, comments and worklogs, and the
+// markdown like text edited in gojira.
+package adf
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Node is a node in a document, either a block like a paragraph,
+// or inline content like text with its marks, e.g. bold.
+type Node struct {
+	Type    string         `json:"type"`
+	Version int            `json:"version,omitempty"`
+	Attrs   map[string]any `json:"attrs,omitempty"`
+	Content []Node         `json:"content,omitempty"`
+	Text    string         `json:"text,omitempty"`
+	Marks   []Mark         `json:"marks,omitempty"`
+}
+
+// Mark is the formatting of text.
+type Mark struct {
+	Type  string         `json:"type"`
+	Attrs map[string]any `json:"attrs,omitempty"`
+}
+
+// IsDoc reports if the value decoded from json is a document.
+func IsDoc(v map[string]any) bool {
+	t, _ := v["type"].(string)
+	_, ok := v["version"]
+
+	return t == "doc" && ok
+}
+
+// ReplaceDocs replaces all documents in the json with their text,
+// so the json can be decoded like a response from the v2 API.
+func ReplaceDocs(data []byte) ([]byte, error) {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+
+	var v any
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(replaceDocs(v))
+}
+
+func replaceDocs(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		if IsDoc(t) {
+			var doc Node
+
+			data, _ := json.Marshal(t)
+			if json.Unmarshal(data, &doc) == nil {
+				return ToText(doc)
+			}
+		}
+
+		for k, e := range t {
+			t[k] = replaceDocs(e)
+		}
+	case []any:
+		for i, e := range t {
+			t[i] = replaceDocs(e)
+		}
+	}
+
+	return v
+}
+
+// ToText renders the document as markdown like text.
+func ToText(doc Node) string {
+	return strings.TrimSpace(blocks(doc.Content, ""))
+}
+
+func blocks(nodes []Node, indent string) string {
+	parts := []string{}
+
+	for _, n := range nodes {
+		if s := block(n, indent); s != "" {
+			parts = append(parts, s)
+		}
+	}
+
+	return strings.Join(parts, "\n\n")
+}
+
+func block(n Node, indent string) string {
+	switch n.Type {
+	case "paragraph":
+		return prefixLines(inline(n.Content), indent, indent)
+	case "heading":
+		return indent + strings.Repeat("#", intAttr(n, "level", 1)) + " " + inline(n.Content)
+	case "bulletList", "orderedList":
+		return list(n, indent)
+	case "codeBlock":
+		lang, _ := n.Attrs["language"].(string)
+
+		return prefixLines("```"+lang+"\n"+inline(n.Content)+"\n```", indent, indent)
+	case "blockquote":
+		return prefixLines(blocks(n.Content, ""), indent+"> ", indent+"> ")
+	case "rule":
+		return indent + "---"
+	case "table":
+		return table(n, indent)
+	case "mediaSingle", "mediaGroup", "media":
+		return indent + "[attachment]"
+	}
+
+	if len(n.Content) > 0 && n.Content[0].Type == "text" {
+		return prefixLines(inline(n.Content), indent, indent)
+	}
+
+	return blocks(n.Content, indent)
+}
+
+func list(n Node, indent string) string {
+	lines := []string{}
+	number := intAttr(n, "order", 1)
+
+	for _, item := range n.Content {
+		marker := "- "
+		if n.Type == "orderedList" {
+			marker = strconv.Itoa(number) + ". "
+			number++
+		}
+
+		// Nested lists are indented, the other blocks of the item
+		// are kept on the lines following the marker
+		text := []string{}
+
+		for _, b := range item.Content {
+			if b.Type == "bulletList" || b.Type == "orderedList" {
+				text = append(text, list(b, indent+"  "))
+			} else {
+				text = append(text, prefixLines(block(b, ""), "", indent+"  "))
+			}
+		}
+
+		lines = append(lines, indent+marker+strings.Join(text, "\n"))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func table(n Node, indent string) string {
+	lines := []string{}
+
+	for i, row := range n.Content {
+		cells := []string{}
+
+		for _, cell := range row.Content {
+			cells = append(cells, strings.ReplaceAll(blocks(cell.Content, ""), "\n", " "))
+		}
+
+		lines = append(lines, indent+"| "+strings.Join(cells, " | ")+" |")
+
+		if i == 0 && len(row.Content) > 0 && row.Content[0].Type == "tableHeader" {
+			lines = append(lines, indent+"|"+strings.Repeat(" --- |", len(cells)))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func inline(nodes []Node) string {
+	var b strings.Builder
+
+	for _, n := range nodes {
+		switch n.Type {
+		case "text":
+			b.WriteString(marked(n.Text, n.Marks))
+		case "hardBreak":
+			b.WriteString("\n")
+		case "mention":
+			b.WriteString(stringAttr(n, "text"))
+		case "emoji":
+			if s := stringAttr(n, "text"); s != "" {
+				b.WriteString(s)
+			} else {
+				b.WriteString(stringAttr(n, "shortName"))
+			}
+		case "inlineCard":
+			b.WriteString(stringAttr(n, "url"))
+		case "status":
+			b.WriteString("[" + stringAttr(n, "text") + "]")
+		case "date":
+			if ms, err := strconv.ParseInt(stringAttr(n, "timestamp"), 10, 64); err == nil {
+				b.WriteString(time.UnixMilli(ms).UTC().Format("2006-01-02"))
+			}
+		default:
+			b.WriteString(inline(n.Content))
+		}
+	}
+
+	return b.String()
+}
+
+func marked(text string, marks []Mark) string {
+	for _, m := range marks {
+		switch m.Type {
+		case "strong":
+			text = "**" + text + "**"
+		case "em":
+			text = "*" + text + "*"
+		case "code":
+			text = "`" + text + "`"
+		case "strike":
+			text = "~~" + text + "~~"
+		case "link":
+			href, _ := m.Attrs["href"].(string)
+			text = "[" + text + "](" + href + ")"
+		}
+	}
+
+	return text
+}
+
+func prefixLines(text, first, rest string) string {
+	lines := strings.Split(text, "\n")
+	for i := range lines {
+		if i == 0 {
+			lines[i] = first + lines[i]
+		} else {
+			lines[i] = rest + lines[i]
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func stringAttr(n Node, name string) string {
+	switch v := n.Attrs[name].(type) {
+	case string:
+		return v
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func intAttr(n Node, name string, def int) int {
+	if i, err := strconv.Atoi(stringAttr(n, name)); err == nil {
+		return i
+	}
+
+	return def
+}
+
+var (
+	headingLine = regexp.MustCompile(`^(#{1,6}) (.*)$`)
+	bulletLine  = regexp.MustCompile(`^[-*] (.*)$`)
+	orderedLine = regexp.MustCompile(`^(\d+)\. (.*)$`)
+	fenceLine   = regexp.MustCompile("^(```|\\{noformat\\}|\\{code(:[^}]*)?\\})(.*)$")
+	inlineToken = regexp.MustCompile("`[^`]+`|\\*\\*[^*]+\\*\\*|\\*[^*\\s][^*]*\\*|~~[^~]+~~|\\[[^\\]]+\\]\\([^)\\s]+\\)")
+	linkToken   = regexp.MustCompile(`^\[([^\]]+)\]\(([^)\s]+)\)$`)
+)
+
+// FromText converts the markdown like text to a document. Paragraphs are
+// separated by blank lines, and the line breaks within them are kept.
+// Code blocks can be fenced by ``` or {noformat} like in the v2 API.
+func FromText(text string) Node {
+	doc := Node{Type: "doc", Version: 1, Content: []Node{}}
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+
+	var para []string
+
+	flush := func() {
+		if len(para) > 0 {
+			doc.Content = append(doc.Content, paragraph(para))
+			para = nil
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+
+		if m := fenceLine.FindStringSubmatch(line); m != nil {
+			flush()
+
+			code := []string{}
+
+			for i++; i < len(lines) && !isFence(lines[i], m[1]); i++ {
+				code = append(code, lines[i])
+			}
+
+			n := Node{Type: "codeBlock", Content: []Node{{Type: "text", Text: strings.Join(code, "\n")}}}
+			if lang := strings.TrimSpace(m[3]); lang != "" {
+				n.Attrs = map[string]any{"language": lang}
+			}
+
+			if len(code) == 0 {
+				n.Content = nil
+			}
+
+			doc.Content = append(doc.Content, n)
+
+			continue
+		}
+
+		switch {
+		case strings.TrimSpace(line) == "":
+			flush()
+		case strings.TrimSpace(line) == "---":
+			flush()
+			doc.Content = append(doc.Content, Node{Type: "rule"})
+		case headingLine.MatchString(line):
+			flush()
+			m := headingLine.FindStringSubmatch(line)
+			doc.Content = append(doc.Content, Node{
+				Type: "heading", Attrs: map[string]any{"level": len(m[1])}, Content: Inline(m[2]),
+			})
+		case bulletLine.MatchString(line), orderedLine.MatchString(line):
+			flush()
+
+			var n Node
+
+			n, i = listFrom(lines, i)
+			doc.Content = append(doc.Content, n)
+		case strings.HasPrefix(line, "> "):
+			flush()
+
+			quote := []string{}
+			for ; i < len(lines) && strings.HasPrefix(lines[i], "> "); i++ {
+				quote = append(quote, strings.TrimPrefix(lines[i], "> "))
+			}
+
+			i--
+
+			doc.Content = append(doc.Content, Node{Type: "blockquote", Content: FromText(strings.Join(quote, "\n")).Content})
+		default:
+			para = append(para, line)
+		}
+	}
+
+	flush()
+
+	if len(doc.Content) == 0 {
+		doc.Content = []Node{{Type: "paragraph"}}
+	}
+
+	return doc
+}
+
+func isFence(line, open string) bool {
+	line = strings.TrimSpace(line)
+
+	if open == "```" {
+		return line == "```"
+	}
+
+	return line == "{noformat}" || line == "{code}"
+}
+
+// listFrom returns the list starting at line i, and the last line of it.
+func listFrom(lines []string, i int) (Node, int) {
+	n := Node{Type: "bulletList"}
+	item := bulletLine
+
+	if m := orderedLine.FindStringSubmatch(lines[i]); m != nil {
+		n.Type, item = "orderedList", orderedLine
+		if start, _ := strconv.Atoi(m[1]); start != 1 {
+			n.Attrs = map[string]any{"order": start}
+		}
+	}
+
+	for ; i < len(lines); i++ {
+		m := item.FindStringSubmatch(lines[i])
+		if m == nil {
+			break
+		}
+
+		n.Content = append(n.Content, Node{Type: "listItem", Content: []Node{paragraph([]string{m[len(m)-1]})}})
+	}
+
+	return n, i - 1
+}
+
+func paragraph(lines []string) Node {
+	p := Node{Type: "paragraph"}
+
+	for i, l := range lines {
+		if i > 0 {
+			p.Content = append(p.Content, Node{Type: "hardBreak"})
+		}
+
+		p.Content = append(p.Content, Inline(l)...)
+	}
+
+	return p
+}
+
+// Inline converts a line of text to text nodes, with marks for
+// `code`, **bold**, *italic*, ~~strike~~ and [links](url).
+func Inline(text string) []Node {
+	nodes := []Node{}
+	last := 0
+
+	for _, loc := range inlineToken.FindAllStringIndex(text, -1) {
+		if loc[0] > last {
+			nodes = append(nodes, Node{Type: "text", Text: text[last:loc[0]]})
+		}
+
+		nodes = append(nodes, token(text[loc[0]:loc[1]]))
+		last = loc[1]
+	}
+
+	if last < len(text) {
+		nodes = append(nodes, Node{Type: "text", Text: text[last:]})
+	}
+
+	return nodes
+}
+
+func token(t string) Node {
+	if m := linkToken.FindStringSubmatch(t); m != nil {
+		return Node{Type: "text", Text: m[1], Marks: []Mark{{Type: "link", Attrs: map[string]any{"href": m[2]}}}}
+	}
+
+	switch {
+	case strings.HasPrefix(t, "`"):
+		return Node{Type: "text", Text: t[1 : len(t)-1], Marks: []Mark{{Type: "code"}}}
+	case strings.HasPrefix(t, "**"):
+		return Node{Type: "text", Text: t[2 : len(t)-2], Marks: []Mark{{Type: "strong"}}}
+	case strings.HasPrefix(t, "~~"):
+		return Node{Type: "text", Text: t[2 : len(t)-2], Marks: []Mark{{Type: "strike"}}}
+	default:
+		return Node{Type: "text", Text: t[1 : len(t)-1], Marks: []Mark{{Type: "em"}}}
+	}
+}
@@ -0,0 +1,160 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package adf_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mhersson/gojira/pkg/util/adf"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToText(t *testing.T) {
+	t.Parallel()
+
+	doc := `{"type":"doc","version":1,"content":[
+		{"type":"heading","attrs":{"level":2},"content":[{"type":"text","text":"Steps"}]},
+		{"type":"paragraph","content":[
+			{"type":"text","text":"Run "},
+			{"type":"text","text":"make","marks":[{"type":"code"}]},
+			{"type":"hardBreak"},
+			{"type":"text","text":"then ask "},
+			{"type":"mention","attrs":{"id":"1","text":"@Alice"}},
+			{"type":"text","text":" or read "},
+			{"type":"text","text":"the docs","marks":[{"type":"link","attrs":{"href":"https://example.com"}}]}
+		]},
+		{"type":"bulletList","content":[
+			{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"one","marks":[{"type":"strong"}]}]}]},
+			{"type":"listItem","content":[
+				{"type":"paragraph","content":[{"type":"text","text":"two"}]},
+				{"type":"orderedList","content":[
+					{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"nested"}]}]}
+				]}
+			]}
+		]},
+		{"type":"codeBlock","attrs":{"language":"go"},"content":[{"type":"text","text":"x := 1"}]},
+		{"type":"blockquote","content":[{"type":"paragraph","content":[{"type":"text","text":"quoted"}]}]},
+		{"type":"rule"},
+		{"type":"table","content":[
+			{"type":"tableRow","content":[
+				{"type":"tableHeader","content":[{"type":"paragraph","content":[{"type":"text","text":"A"}]}]},
+				{"type":"tableHeader","content":[{"type":"paragraph","content":[{"type":"text","text":"B"}]}]}
+			]},
+			{"type":"tableRow","content":[
+				{"type":"tableCell","content":[{"type":"paragraph","content":[{"type":"text","text":"1"}]}]},
+				{"type":"tableCell","content":[{"type":"paragraph","content":[{"type":"text","text":"2"}]}]}
+			]}
+		]}
+	]}`
+
+	var n adf.Node
+	assert.NoError(t, json.Unmarshal([]byte(doc), &n))
+
+	want := "## Steps\n\n" +
+		"Run `make`\nthen ask @Alice or read [the docs](https://example.com)\n\n" +
+		"- **one**\n- two\n  1. nested\n\n" +
+		"```go\nx := 1\n```\n\n" +
+		"> quoted\n\n" +
+		"---\n\n" +
+		"| A | B |\n| --- | --- |\n| 1 | 2 |"
+
+	assert.Equal(t, want, adf.ToText(n))
+}
+
+func TestFromText(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "empty",
+			text: "",
+			want: `{"type":"doc","version":1,"content":[{"type":"paragraph"}]}`,
+		},
+		{
+			name: "paragraphs with line breaks",
+			text: "first\nline\n\nsecond",
+			want: `{"type":"doc","version":1,"content":[` +
+				`{"type":"paragraph","content":[{"type":"text","text":"first"},{"type":"hardBreak"},{"type":"text","text":"line"}]},` +
+				`{"type":"paragraph","content":[{"type":"text","text":"second"}]}]}`,
+		},
+		{
+			name: "marks",
+			text: "a **b** *c* `d` ~~e~~ [f](https://example.com) snake_case",
+			want: `{"type":"doc","version":1,"content":[{"type":"paragraph","content":[` +
+				`{"type":"text","text":"a "},{"type":"text","text":"b","marks":[{"type":"strong"}]},` +
+				`{"type":"text","text":" "},{"type":"text","text":"c","marks":[{"type":"em"}]},` +
+				`{"type":"text","text":" "},{"type":"text","text":"d","marks":[{"type":"code"}]},` +
+				`{"type":"text","text":" "},{"type":"text","text":"e","marks":[{"type":"strike"}]},` +
+				`{"type":"text","text":" "},{"type":"text","text":"f","marks":[{"type":"link","attrs":{"href":"https://example.com"}}]},` +
+				`{"type":"text","text":" snake_case"}]}]}`,
+		},
+		{
+			name: "noformat from the v2 api",
+			text: "{noformat}\nraw *text*\n{noformat}",
+			want: `{"type":"doc","version":1,"content":[{"type":"codeBlock","content":[{"type":"text","text":"raw *text*"}]}]}`,
+		},
+		{
+			name: "ordered list starting at 3",
+			text: "3. c\n4. d",
+			want: `{"type":"doc","version":1,"content":[{"type":"orderedList","attrs":{"order":3},"content":[` +
+				`{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"c"}]}]},` +
+				`{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"d"}]}]}]}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := json.Marshal(adf.FromText(tt.text))
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	text := "# Title\n\nSome **bold** and `code`\non two lines\n\n- a\n- b\n\n1. c\n2. d\n\n" +
+		"```sh\nmake test\n```\n\n> quoted\n\n---\n\nThe end"
+
+	assert.Equal(t, text, adf.ToText(adf.FromText(text)))
+}
+
+func TestReplaceDocs(t *testing.T) {
+	t.Parallel()
+
+	body := `{"key":"A-1","id":10001,"fields":{"summary":"s","description":` +
+		`{"type":"doc","version":1,"content":[{"type":"paragraph","content":[{"type":"text","text":"hello"}]}]},` +
+		`"comment":{"comments":[{"body":{"type":"doc","version":1,"content":[]}}]}}}`
+
+	got, err := adf.ReplaceDocs([]byte(body))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"key":"A-1","id":10001,"fields":{"summary":"s","description":"hello",`+
+		`"comment":{"comments":[{"body":""}]}}}`, string(got))
+}
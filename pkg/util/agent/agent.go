@@ -0,0 +1,219 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+// Package agent caches passwords in the memory of a background
+// process, like ssh-agent, so they are only typed once per session.
+// The process is reached through a unix socket in a directory only
+// the user can access, and exits when the cache times out.
+package agent
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// startTimeout is how long to wait for a started agent to listen.
+const startTimeout = 2 * time.Second
+
+type request struct {
+	Op     string `json:"op"`
+	Key    string `json:"key,omitempty"`
+	Secret string `json:"secret,omitempty"`
+}
+
+type response struct {
+	Secret string `json:"secret,omitempty"`
+	Found  bool   `json:"found"`
+}
+
+// Socket returns the path of the socket of the agent, in the runtime
+// directory of the user if there is one, or else in the temp directory.
+func Socket() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "gojira", "agent.sock")
+	}
+
+	return filepath.Join(os.TempDir(), fmt.Sprintf("gojira-%d", os.Getuid()), "agent.sock")
+}
+
+// Get returns the password cached under the key,
+// and false if it is not cached or there is no agent.
+func Get(socket, key string) (string, bool) {
+	resp, err := send(socket, request{Op: "get", Key: key})
+	if err != nil {
+		return "", false
+	}
+
+	return resp.Secret, resp.Found
+}
+
+// Set caches the password under the key.
+func Set(socket, key, secret string) error {
+	_, err := send(socket, request{Op: "set", Key: key, Secret: secret})
+
+	return err
+}
+
+// Stop makes the agent forget all passwords and exit.
+func Stop(socket string) error {
+	_, err := send(socket, request{Op: "stop"})
+
+	return err
+}
+
+// Running reports whether an agent is listening on the socket.
+func Running(socket string) bool {
+	conn, err := net.Dial("unix", socket)
+	if err != nil {
+		return false
+	}
+
+	conn.Close()
+
+	return true
+}
+
+// Start runs the command, which must serve the socket, in the background
+// and waits until it listens.
+func Start(socket string, name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start the agent: %w", err)
+	}
+
+	_ = cmd.Process.Release()
+
+	for deadline := time.Now().Add(startTimeout); time.Now().Before(deadline); {
+		if Running(socket) {
+			return nil
+		}
+
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	return fmt.Errorf("the agent did not start listening on %s", socket)
+}
+
+// Serve caches the passwords sent to the socket until the ttl has passed
+// or it is stopped.
+func Serve(socket string, ttl time.Duration) error {
+	if err := os.MkdirAll(filepath.Dir(socket), 0o700); err != nil {
+		return fmt.Errorf("failed to create the socket directory: %w", err)
+	}
+
+	if err := os.Chmod(filepath.Dir(socket), 0o700); err != nil {
+		return fmt.Errorf("failed to protect the socket directory: %w", err)
+	}
+
+	if Running(socket) {
+		return fmt.Errorf("an agent is already listening on %s", socket)
+	}
+
+	_ = os.Remove(socket)
+
+	l, err := net.Listen("unix", socket)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", socket, err)
+	}
+
+	var once sync.Once
+
+	stop := func() { once.Do(func() { l.Close() }) }
+
+	timer := time.AfterFunc(ttl, stop)
+	defer timer.Stop()
+
+	secrets := map[string]string{}
+
+	for {
+		conn, err := l.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			return nil
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to accept: %w", err)
+		}
+
+		// One request at a time, so the secrets need no lock
+		if handle(conn, secrets) {
+			stop()
+		}
+	}
+}
+
+// handle answers the request on the connection,
+// and reports whether the agent should stop.
+func handle(conn net.Conn, secrets map[string]string) bool {
+	defer conn.Close()
+
+	_ = conn.SetDeadline(time.Now().Add(time.Second))
+
+	var req request
+	if err := json.NewDecoder(bufio.NewReader(conn)).Decode(&req); err != nil {
+		return false
+	}
+
+	var resp response
+
+	switch req.Op {
+	case "get":
+		resp.Secret, resp.Found = secrets[req.Key]
+	case "set":
+		secrets[req.Key] = req.Secret
+	case "stop":
+		clear(secrets)
+	}
+
+	_ = json.NewEncoder(conn).Encode(resp)
+
+	return req.Op == "stop"
+}
+
+func send(socket string, req request) (response, error) {
+	var resp response
+
+	conn, err := net.DialTimeout("unix", socket, time.Second)
+	if err != nil {
+		return resp, fmt.Errorf("no agent is running: %w", err)
+	}
+	defer conn.Close()
+
+	_ = conn.SetDeadline(time.Now().Add(time.Second))
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return resp, fmt.Errorf("failed to send to the agent: %w", err)
+	}
+
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return resp, fmt.Errorf("failed to read from the agent: %w", err)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,86 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package agent_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mhersson/gojira/pkg/util/agent"
+	"github.com/stretchr/testify/assert"
+)
+
+func serve(t *testing.T, ttl time.Duration) (string, chan error) {
+	t.Helper()
+
+	socket := filepath.Join(t.TempDir(), "agent.sock")
+	done := make(chan error, 1)
+
+	go func() { done <- agent.Serve(socket, ttl) }()
+
+	assert.Eventually(t, func() bool { return agent.Running(socket) }, time.Second, 10*time.Millisecond)
+
+	return socket, done
+}
+
+func TestAgent(t *testing.T) {
+	t.Parallel()
+
+	socket, done := serve(t, time.Minute)
+
+	_, found := agent.Get(socket, "bob@https://jira.example.com")
+	assert.False(t, found)
+
+	assert.NoError(t, agent.Set(socket, "bob@https://jira.example.com", "secret"))
+
+	secret, found := agent.Get(socket, "bob@https://jira.example.com")
+	assert.True(t, found)
+	assert.Equal(t, "secret", secret)
+
+	assert.Error(t, agent.Serve(socket, time.Minute))
+
+	assert.NoError(t, agent.Stop(socket))
+	assert.NoError(t, <-done)
+	assert.False(t, agent.Running(socket))
+
+	_, found = agent.Get(socket, "bob@https://jira.example.com")
+	assert.False(t, found)
+	assert.Error(t, agent.Set(socket, "bob@https://jira.example.com", "secret"))
+}
+
+func TestAgentTimeout(t *testing.T) {
+	t.Parallel()
+
+	socket, done := serve(t, 200*time.Millisecond)
+
+	assert.NoError(t, agent.Set(socket, "bob", "secret"))
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("the agent did not time out")
+	}
+
+	assert.False(t, agent.Running(socket))
+}
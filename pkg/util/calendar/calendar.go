@@ -0,0 +1,139 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package calendar
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Rule is how the weeks are numbered.
+type Rule int
+
+const (
+	// ISO weeks start on Monday, and week 1 is the week with the
+	// first Thursday of the year, so the first days of January can
+	// be in the last week of the year before.
+	ISO Rule = iota
+	// US weeks start on Sunday, and week 1 is the week with January 1,
+	// so the last days of December can be in week 1 of the next year.
+	US
+)
+
+// ParseRule returns the rule with the name, iso or us. An empty
+// name is the ISO rule.
+func ParseRule(name string) (Rule, error) {
+	switch strings.ToLower(name) {
+	case "", "iso":
+		return ISO, nil
+	case "us":
+		return US, nil
+	}
+
+	return ISO, fmt.Errorf("unknown week numbering %s, must be iso or us", name)
+}
+
+func (r Rule) String() string {
+	if r == US {
+		return "us"
+	}
+
+	return "iso"
+}
+
+// FirstDay returns the day the weeks start on.
+func (r Rule) FirstDay() time.Weekday {
+	if r == US {
+		return time.Sunday
+	}
+
+	return time.Monday
+}
+
+// WeekStart returns midnight at the start of the week with t,
+// in the location of t.
+func (r Rule) WeekStart(t time.Time) time.Time {
+	d := Date(t)
+	offset := (int(d.Weekday()) - int(r.FirstDay()) + 7) % 7
+
+	return d.AddDate(0, 0, -offset)
+}
+
+// Week returns the year and number of the week with t. The year
+// is the year the week belongs to, which is not always the year of t.
+func (r Rule) Week(t time.Time) (int, int) {
+	if r == ISO {
+		return t.ISOWeek()
+	}
+
+	start := r.WeekStart(t)
+	year := start.AddDate(0, 0, 6).Year()
+	first := r.WeekStart(time.Date(year, time.January, 1, 0, 0, 0, 0, t.Location()))
+
+	return year, Days(first, start)/7 + 1
+}
+
+// WeekRange returns the first and last day of the week in the year,
+// in UTC.
+func (r Rule) WeekRange(year, week int) (time.Time, time.Time) {
+	// January 4 is always in ISO week 1, and January 1 in US week 1
+	day := 1
+	if r == ISO {
+		day = 4
+	}
+
+	start := r.WeekStart(time.Date(year, time.January, day, 0, 0, 0, 0, time.UTC)).AddDate(0, 0, (week-1)*7)
+
+	return start, start.AddDate(0, 0, 6)
+}
+
+// Weeks returns the start of every week from the week with from,
+// to the week with to.
+func (r Rule) Weeks(from, to time.Time) []time.Time {
+	weeks := []time.Time{}
+
+	for t := r.WeekStart(from); !t.After(to); t = t.AddDate(0, 0, 7) {
+		weeks = append(weeks, t)
+	}
+
+	return weeks
+}
+
+// Date returns midnight at the start of the day of t.
+func Date(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// Days returns the number of whole days from a to b, counted by
+// the calendar, so a day with a daylight saving change is still a day.
+func Days(a, b time.Time) int {
+	a = time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	b = time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+
+	return int(b.Sub(a).Hours() / 24)
+}
+
+// IsWeekend reports if t is on a Saturday or Sunday.
+func IsWeekend(t time.Time) bool {
+	return t.Weekday() == time.Saturday || t.Weekday() == time.Sunday
+}
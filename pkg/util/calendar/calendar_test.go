@@ -0,0 +1,135 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package calendar_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mhersson/gojira/pkg/util/calendar"
+	"github.com/stretchr/testify/assert"
+)
+
+func date(s string) time.Time {
+	t, _ := time.Parse("2006-01-02", s)
+
+	return t
+}
+
+func TestParseRule(t *testing.T) {
+	t.Parallel()
+
+	for name, expected := range map[string]calendar.Rule{"": calendar.ISO, "iso": calendar.ISO, "US": calendar.US} {
+		rule, err := calendar.ParseRule(name)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, rule)
+	}
+
+	_, err := calendar.ParseRule("sunday")
+	assert.Error(t, err)
+}
+
+func TestWeek(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		rule calendar.Rule
+		date string
+		year int
+		week int
+	}{
+		{calendar.ISO, "2024-12-28", 2024, 52},
+		{calendar.ISO, "2024-12-30", 2025, 1},
+		{calendar.ISO, "2021-01-01", 2020, 53},
+		{calendar.ISO, "2023-01-01", 2022, 52},
+		{calendar.ISO, "2023-01-02", 2023, 1},
+		{calendar.US, "2024-12-28", 2024, 52},
+		{calendar.US, "2024-12-29", 2025, 1},
+		{calendar.US, "2024-12-30", 2025, 1},
+		{calendar.US, "2025-01-04", 2025, 1},
+		{calendar.US, "2025-01-05", 2025, 2},
+		{calendar.US, "2021-01-01", 2021, 1},
+		{calendar.US, "2023-01-01", 2023, 1},
+		{calendar.US, "2023-12-31", 2024, 1},
+	}
+
+	for _, test := range tests {
+		year, week := test.rule.Week(date(test.date))
+		assert.Equal(t, test.year, year, "%s %s", test.rule, test.date)
+		assert.Equal(t, test.week, week, "%s %s", test.rule, test.date)
+	}
+}
+
+func TestWeekRange(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		rule  calendar.Rule
+		year  int
+		week  int
+		start string
+		end   string
+	}{
+		{calendar.ISO, 2025, 1, "2024-12-30", "2025-01-05"},
+		{calendar.ISO, 2020, 53, "2020-12-28", "2021-01-03"},
+		{calendar.ISO, 2024, 10, "2024-03-04", "2024-03-10"},
+		{calendar.US, 2025, 1, "2024-12-29", "2025-01-04"},
+		{calendar.US, 2024, 52, "2024-12-22", "2024-12-28"},
+	}
+
+	for _, test := range tests {
+		start, end := test.rule.WeekRange(test.year, test.week)
+		assert.Equal(t, test.start, start.Format("2006-01-02"))
+		assert.Equal(t, test.end, end.Format("2006-01-02"))
+
+		// The numbering goes both ways
+		year, week := test.rule.Week(start)
+		assert.Equal(t, test.year, year)
+		assert.Equal(t, test.week, week)
+	}
+}
+
+func TestWeeks(t *testing.T) {
+	t.Parallel()
+
+	weeks := calendar.ISO.Weeks(date("2024-12-25"), date("2025-01-08"))
+	assert.Equal(t, []time.Time{date("2024-12-23"), date("2024-12-30"), date("2025-01-06")}, weeks)
+
+	weeks = calendar.US.Weeks(date("2024-12-25"), date("2025-01-04"))
+	assert.Equal(t, []time.Time{date("2024-12-22"), date("2024-12-29")}, weeks)
+}
+
+func TestWeekStartDaylightSaving(t *testing.T) {
+	t.Parallel()
+
+	oslo, err := time.LoadLocation("Europe/Oslo")
+	if err != nil {
+		t.Skip("no time zone database")
+	}
+
+	// Summer time starts on Sunday March 31 2024 in Norway
+	sunday := time.Date(2024, time.March, 31, 12, 0, 0, 0, oslo)
+	assert.Equal(t, time.Date(2024, time.March, 25, 0, 0, 0, 0, oslo), calendar.ISO.WeekStart(sunday))
+	assert.Equal(t, time.Date(2024, time.March, 31, 0, 0, 0, 0, oslo), calendar.US.WeekStart(sunday))
+	assert.Equal(t, 7, calendar.Days(time.Date(2024, time.March, 25, 0, 0, 0, 0, oslo),
+		time.Date(2024, time.April, 1, 0, 0, 0, 0, oslo)))
+}
@@ -22,9 +22,12 @@ THE SOFTWARE.
 package convert
 
 import (
+	"encoding/json"
 	"fmt"
 	"regexp"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/mhersson/gojira/pkg/types"
 )
@@ -67,3 +70,126 @@ func SecondsToHoursAndMinutes(seconds int, dropMinutes bool) string {
 
 	return fmt.Sprintf("%dh %dm", hours, minutes)
 }
+
+// DurationToDaysAndHours returns a short human readable duration,
+// e.g. 3d 4h, 5h 30m or 45m.
+func DurationToDaysAndHours(d time.Duration) string {
+	days := int(d.Hours()) / 24
+	hours := int(d.Hours()) % 24
+	minutes := int(d.Minutes()) % 60
+
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
+
+// BytesToHumanSize returns a short human readable size,
+// e.g. 512 B, 1.5 KB or 12.0 MB.
+func BytesToHumanSize(size int) string {
+	const unit = 1024
+
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	value := float64(size) / unit
+	suffixes := []string{"KB", "MB", "GB", "TB"}
+	i := 0
+
+	for value >= unit && i < len(suffixes)-1 {
+		value /= unit
+		i++
+	}
+
+	return fmt.Sprintf("%.1f %s", value, suffixes[i])
+}
+
+// FieldToString returns a readable string for a Jira field value of any kind.
+// Objects are shown by the first of displayName, name, value or key that is
+// set, and lists as a comma separated list of their values.
+func FieldToString(raw json.RawMessage) string {
+	var v interface{}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return ""
+	}
+
+	return valueToString(v)
+}
+
+func valueToString(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case bool:
+		return strconv.FormatBool(val)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case []interface{}:
+		values := []string{}
+		for _, x := range val {
+			values = append(values, valueToString(x))
+		}
+
+		return strings.Join(values, ", ")
+	case map[string]interface{}:
+		for _, k := range []string{"displayName", "name", "value", "key"} {
+			if x, ok := val[k]; ok && x != nil {
+				return valueToString(x)
+			}
+		}
+	}
+
+	return ""
+}
+
+// SinceToTime converts a relative or absolute point in time to a time.
+// Accepted values are today, yesterday, a number of days (3d),
+// a duration (12h, 90m) or a date on the format yyyy-mm-dd.
+func SinceToTime(since string, now time.Time) (time.Time, error) {
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	switch s := strings.ToLower(strings.TrimSpace(since)); {
+	case s == "today":
+		return midnight, nil
+	case s == "yesterday":
+		return midnight.AddDate(0, 0, -1), nil
+	case regexp.MustCompile(`^\d+d$`).MatchString(s):
+		days, _ := strconv.Atoi(strings.TrimSuffix(s, "d"))
+
+		return midnight.AddDate(0, 0, -days), nil
+	default:
+		if d, err := time.ParseDuration(s); err == nil {
+			return now.Add(-d), nil
+		}
+
+		t, err := time.ParseInLocation("2006-01-02", s, now.Location())
+		if err != nil {
+			return time.Time{}, &types.Error{Message: "invalid time " + since}
+		}
+
+		return t, nil
+	}
+}
+
+// UntilToTime converts the end of a period to the time the period ends,
+// accepting the same values as SinceToTime. The days are included, so
+// yesterday ends at midnight today, while a duration ends at that time.
+func UntilToTime(until string, now time.Time) (time.Time, error) {
+	t, err := SinceToTime(until, now)
+	if err != nil {
+		return t, err
+	}
+
+	if _, err := time.ParseDuration(strings.TrimSpace(until)); err == nil {
+		return t, nil
+	}
+
+	return t.AddDate(0, 0, 1), nil
+}
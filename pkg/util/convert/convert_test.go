@@ -23,6 +23,7 @@ package convert_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/mhersson/gojira/pkg/types"
 	"github.com/mhersson/gojira/pkg/util/convert"
@@ -84,3 +85,134 @@ func TestSecondsToHoursAndMinutes(t *testing.T) {
 		}
 	}
 }
+
+func TestDurationToDaysAndHours(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input    time.Duration
+		expected string
+	}{
+		{76 * time.Hour, "3d 4h"},
+		{24 * time.Hour, "1d 0h"},
+		{330 * time.Minute, "5h 30m"},
+		{45 * time.Minute, "45m"},
+		{30 * time.Second, "0m"},
+	}
+
+	for _, v := range tests {
+		ans := convert.DurationToDaysAndHours(v.input)
+		if ans != v.expected {
+			t.Errorf("Input: %s, got: %s, want: %s", v.input, ans, v.expected)
+		}
+	}
+}
+
+func TestBytesToHumanSize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input    int
+		expected string
+	}{
+		{0, "0 B"},
+		{512, "512 B"},
+		{1024, "1.0 KB"},
+		{1536, "1.5 KB"},
+		{12 * 1024 * 1024, "12.0 MB"},
+		{3 * 1024 * 1024 * 1024, "3.0 GB"},
+	}
+
+	for _, v := range tests {
+		ans := convert.BytesToHumanSize(v.input)
+		if ans != v.expected {
+			t.Errorf("Input: %d, got: %s, want: %s", v.input, ans, v.expected)
+		}
+	}
+}
+
+func TestFieldToString(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`null`, ""},
+		{`"2024-06-01"`, "2024-06-01"},
+		{`3.5`, "3.5"},
+		{`true`, "true"},
+		{`{"name": "bob", "displayName": "Bob Builder"}`, "Bob Builder"},
+		{`{"id": "10100", "value": "Team Rocket"}`, "Team Rocket"},
+		{`[{"name": "2.4.0"}, {"name": "2.5.0"}]`, "2.4.0, 2.5.0"},
+		{`["backend", "frontend"]`, "backend, frontend"},
+		{`{"id": "1"}`, ""},
+	}
+
+	for _, tc := range tests {
+		assert.Equal(t, tc.expected, convert.FieldToString([]byte(tc.input)))
+	}
+}
+
+func TestSinceToTime(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 3, 6, 14, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		input    string
+		expected time.Time
+		err      bool
+	}{
+		{"today", time.Date(2024, 3, 6, 0, 0, 0, 0, time.UTC), false},
+		{"Yesterday", time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC), false},
+		{"3d", time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC), false},
+		{"12h", time.Date(2024, 3, 6, 2, 30, 0, 0, time.UTC), false},
+		{"90m", time.Date(2024, 3, 6, 13, 0, 0, 0, time.UTC), false},
+		{"2024-02-29", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), false},
+		{"xd", time.Time{}, true},
+		{"bad", time.Time{}, true},
+		{"last week", time.Time{}, true},
+	}
+
+	for _, v := range tests {
+		ans, err := convert.SinceToTime(v.input, now)
+		if v.err {
+			assert.Error(t, err, v.input)
+		} else {
+			assert.NoError(t, err, v.input)
+		}
+
+		assert.Equal(t, v.expected, ans, v.input)
+	}
+}
+
+func TestUntilToTime(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 3, 6, 14, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		input    string
+		expected time.Time
+		err      bool
+	}{
+		{"today", time.Date(2024, 3, 7, 0, 0, 0, 0, time.UTC), false},
+		{"yesterday", time.Date(2024, 3, 6, 0, 0, 0, 0, time.UTC), false},
+		{"3d", time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC), false},
+		{"12h", time.Date(2024, 3, 6, 2, 30, 0, 0, time.UTC), false},
+		{"2024-02-29", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), false},
+		{"bad", time.Time{}, true},
+	}
+
+	for _, v := range tests {
+		ans, err := convert.UntilToTime(v.input, now)
+		if v.err {
+			assert.Error(t, err, v.input)
+		} else {
+			assert.NoError(t, err, v.input)
+		}
+
+		assert.Equal(t, v.expected, ans, v.input)
+	}
+}
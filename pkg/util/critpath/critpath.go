@@ -0,0 +1,132 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+// Package critpath finds the critical path through a set of tasks
+// blocking each other, i.e. the chain of tasks deciding when all of
+// them can be done at the earliest.
+package critpath
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Task is a piece of work with the keys of the tasks that must be
+// done before it can start. Dependencies not in the set are ignored.
+type Task struct {
+	Key       string
+	Duration  int
+	DependsOn []string
+}
+
+// Result is the earliest finish of each task, relative to when work
+// starts, and the critical path ending with the task finishing last.
+type Result struct {
+	Finish map[string]int
+	Path   []string
+	Total  int
+}
+
+// Analyze computes the earliest finish of the tasks, assuming unlimited
+// hands, and returns an error if the tasks depend on each other in a cycle.
+func Analyze(tasks []Task) (Result, error) {
+	byKey := map[string]Task{}
+	keys := []string{}
+
+	for _, t := range tasks {
+		byKey[t.Key] = t
+		keys = append(keys, t.Key)
+	}
+
+	slices.Sort(keys)
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+
+	state := map[string]int{}
+	finish := map[string]int{}
+	previous := map[string]string{}
+
+	var visit func(key string) error
+
+	visit = func(key string) error {
+		switch state[key] {
+		case visiting:
+			return fmt.Errorf("%s is part of a blocking cycle", key)
+		case visited:
+			return nil
+		}
+
+		state[key] = visiting
+
+		deps := slices.Clone(byKey[key].DependsOn)
+		slices.Sort(deps)
+
+		start := 0
+
+		for _, d := range deps {
+			if _, ok := byKey[d]; !ok {
+				continue
+			}
+
+			if err := visit(d); err != nil {
+				return err
+			}
+
+			if _, ok := previous[key]; !ok || finish[d] > start {
+				start = finish[d]
+				previous[key] = d
+			}
+		}
+
+		finish[key] = start + byKey[key].Duration
+		state[key] = visited
+
+		return nil
+	}
+
+	r := Result{Finish: finish}
+	last := ""
+
+	for _, k := range keys {
+		if err := visit(k); err != nil {
+			return Result{}, err
+		}
+
+		if last == "" || finish[k] > finish[last] {
+			last = k
+		}
+	}
+
+	if last == "" {
+		return r, nil
+	}
+
+	r.Total = finish[last]
+
+	for k := last; k != ""; k = previous[k] {
+		r.Path = append([]string{k}, r.Path...)
+	}
+
+	return r, nil
+}
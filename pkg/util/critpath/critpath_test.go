@@ -0,0 +1,97 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package critpath_test
+
+import (
+	"testing"
+
+	"github.com/mhersson/gojira/pkg/util/critpath"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyze(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		tasks     []critpath.Task
+		wantPath  []string
+		wantTotal int
+	}{
+		{"empty", nil, nil, 0},
+		{
+			name:      "independent",
+			tasks:     []critpath.Task{{Key: "A-1", Duration: 2}, {Key: "A-2", Duration: 5}},
+			wantPath:  []string{"A-2"},
+			wantTotal: 5,
+		},
+		{
+			name: "chain beats the longest task",
+			tasks: []critpath.Task{
+				{Key: "A-1", Duration: 3},
+				{Key: "A-2", Duration: 3, DependsOn: []string{"A-1"}},
+				{Key: "A-3", Duration: 5},
+				{Key: "A-4", Duration: 1, DependsOn: []string{"A-2", "A-3"}},
+			},
+			wantPath:  []string{"A-1", "A-2", "A-4"},
+			wantTotal: 7,
+		},
+		{
+			name: "unknown dependencies are ignored",
+			tasks: []critpath.Task{
+				{Key: "A-1", Duration: 2, DependsOn: []string{"B-1"}},
+			},
+			wantPath:  []string{"A-1"},
+			wantTotal: 2,
+		},
+		{
+			name: "done dependency",
+			tasks: []critpath.Task{
+				{Key: "A-1", Duration: 0},
+				{Key: "A-2", Duration: 4, DependsOn: []string{"A-1"}},
+			},
+			wantPath:  []string{"A-1", "A-2"},
+			wantTotal: 4,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			r, err := critpath.Analyze(tc.tasks)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantPath, r.Path)
+			assert.Equal(t, tc.wantTotal, r.Total)
+		})
+	}
+}
+
+func TestAnalyzeCycle(t *testing.T) {
+	t.Parallel()
+
+	_, err := critpath.Analyze([]critpath.Task{
+		{Key: "A-1", Duration: 1, DependsOn: []string{"A-2"}},
+		{Key: "A-2", Duration: 1, DependsOn: []string{"A-1"}},
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,166 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+// Package emoji replaces emoji shortcodes, e.g. :+1:, and the Jira
+// emoticons, e.g. (y), with the unicode emoji they stand for, so
+// they read well in the terminal.
+package emoji
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// shortcodes are the most used of the common emoji shortcodes.
+var shortcodes = map[string]string{
+	"+1": "👍", "thumbsup": "👍", "-1": "👎", "thumbsdown": "👎",
+	"smile": "😄", "smiley": "😃", "grinning": "😀", "grin": "😁", "joy": "😂", "laughing": "😆",
+	"slightly_smiling_face": "🙂", "wink": "😉", "blush": "😊", "heart_eyes": "😍", "thinking": "🤔",
+	"neutral_face": "😐", "confused": "😕", "worried": "😟", "disappointed": "😞", "cry": "😢", "sob": "😭",
+	"angry": "😠", "rage": "😡", "scream": "😱", "sweat_smile": "😅", "sunglasses": "😎", "stuck_out_tongue": "😛",
+	"facepalm": "🤦", "shrug": "🤷", "pray": "🙏", "clap": "👏", "wave": "👋", "ok_hand": "👌", "muscle": "💪",
+	"raised_hands": "🙌", "eyes": "👀", "point_up": "☝️", "point_right": "👉",
+	"heart": "❤️", "broken_heart": "💔", "fire": "🔥", "star": "⭐", "sparkles": "✨", "zap": "⚡", "boom": "💥",
+	"tada": "🎉", "rocket": "🚀", "100": "💯", "trophy": "🏆", "bulb": "💡", "bug": "🐛", "beetle": "🐞",
+	"warning": "⚠️", "no_entry": "⛔", "x": "❌", "white_check_mark": "✅", "heavy_check_mark": "✔️",
+	"question": "❓", "exclamation": "❗", "information_source": "ℹ️", "lock": "🔒", "unlock": "🔓",
+	"wrench": "🔧", "hammer": "🔨", "gear": "⚙️", "memo": "📝", "pencil": "📝", "calendar": "📅", "clock": "🕒",
+	"hourglass": "⌛", "coffee": "☕", "beer": "🍺", "pizza": "🍕", "cake": "🍰", "see_no_evil": "🙈",
+	"poop": "💩", "skull": "💀", "ghost": "👻", "robot": "🤖", "construction": "🚧", "rotating_light": "🚨",
+	"checkered_flag": "🏁", "triangular_flag_on_post": "🚩", "link": "🔗", "mag": "🔍", "email": "📧",
+}
+
+// emoticons are the emoticons of the Jira wiki markup.
+var emoticons = map[string]string{
+	":)": "🙂", ":(": "🙁", ":P": "😛", ":D": "😀", ";)": "😉",
+	"(y)": "👍", "(n)": "👎", "(i)": "ℹ️", "(/)": "✅", "(x)": "❌", "(!)": "⚠️",
+	"(+)": "➕", "(-)": "➖", "(?)": "❓", "(on)": "💡", "(*)": "⭐", "(flag)": "🚩",
+}
+
+var (
+	shortcodeRegex = regexp.MustCompile(`:[a-z0-9_+\-]+:`)
+	emoticonRegex  = emoticonPattern()
+	inlineCode     = regexp.MustCompile("\\{\\{.*?\\}\\}|`[^`]*`")
+	codeBlock      = regexp.MustCompile("^\\s*(\\{noformat\\}|\\{code(:[^}]*)?\\}|```)")
+)
+
+// emoticonPattern matches the emoticons at the start of the
+// text or after a space, so e.g. f(x) is left alone.
+func emoticonPattern() *regexp.Regexp {
+	quoted := []string{}
+	for e := range emoticons {
+		quoted = append(quoted, regexp.QuoteMeta(e))
+	}
+
+	return regexp.MustCompile(`(^|\s)(` + strings.Join(quoted, "|") + `)`)
+}
+
+// Lookup returns the emoji of the shortcode, with or without the colons.
+func Lookup(shortcode string) (string, bool) {
+	e, ok := shortcodes[strings.ToLower(strings.Trim(shortcode, ":"))]
+
+	return e, ok
+}
+
+// ID returns the code points of the emoji in hex, joined by dashes,
+// which is how Atlassian identifies the standard emoji, e.g. 1f44d.
+func ID(emoji string) string {
+	points := []string{}
+
+	for _, r := range emoji {
+		// The variation selector only asks for the emoji presentation
+		if r != '\ufe0f' {
+			points = append(points, fmt.Sprintf("%x", r))
+		}
+	}
+
+	return strings.Join(points, "-")
+}
+
+// Replace replaces the known shortcodes and emoticons in the text with
+// their emoji. Unknown shortcodes are left as is, and so is code, both
+// inline and in code blocks.
+func Replace(text string) string {
+	lines := strings.Split(text, "\n")
+	inCode := false
+
+	for i, line := range lines {
+		if codeBlock.MatchString(line) {
+			inCode = !inCode
+
+			continue
+		}
+
+		if !inCode {
+			lines[i] = replaceOutsideCode(line)
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func replaceOutsideCode(line string) string {
+	var b strings.Builder
+
+	last := 0
+
+	for _, loc := range inlineCode.FindAllStringIndex(line, -1) {
+		b.WriteString(replaceLine(line[last:loc[0]]))
+		b.WriteString(line[loc[0]:loc[1]])
+		last = loc[1]
+	}
+
+	b.WriteString(replaceLine(line[last:]))
+
+	return b.String()
+}
+
+func replaceLine(line string) string {
+	line = shortcodeRegex.ReplaceAllStringFunc(line, func(s string) string {
+		if e, ok := Lookup(s); ok {
+			return e
+		}
+
+		return s
+	})
+
+	var b strings.Builder
+
+	last := 0
+
+	for _, loc := range emoticonRegex.FindAllStringSubmatchIndex(line, -1) {
+		start, end := loc[4], loc[5]
+
+		// Only whole emoticons, so e.g. :Done is left alone
+		if end < len(line) && !strings.ContainsRune(" \t.,!?;:)", rune(line[end])) {
+			continue
+		}
+
+		b.WriteString(line[last:start])
+		b.WriteString(emoticons[line[start:end]])
+		last = end
+	}
+
+	b.WriteString(line[last:])
+
+	return b.String()
+}
@@ -0,0 +1,80 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package emoji_test
+
+import (
+	"testing"
+
+	"github.com/mhersson/gojira/pkg/util/emoji"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplace(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Looks good :+1:", "Looks good 👍"},
+		{":tada: Released :rocket:", "🎉 Released 🚀"},
+		{"Unknown :not_an_emoji: stays", "Unknown :not_an_emoji: stays"},
+		{"Meet at 10:30:00", "Meet at 10:30:00"},
+		{"Works (y) and (x)", "Works 👍 and ❌"},
+		{"(/) done", "✅ done"},
+		{"f(x) is a function", "f(x) is a function"},
+		{"Thanks :)", "Thanks 🙂"},
+		{"Thanks :), see you", "Thanks 🙂, see you"},
+		{":) :)", "🙂 🙂"},
+		{"Set it to :Done", "Set it to :Done"},
+		{"Run `echo :+1:` and {{(y)}} :+1:", "Run `echo :+1:` and {{(y)}} 👍"},
+		{"{noformat}\n:+1: (y)\n{noformat}\n:+1:", "{noformat}\n:+1: (y)\n{noformat}\n👍"},
+		{"{code:go}\nx := \":smile:\"\n{code}", "{code:go}\nx := \":smile:\"\n{code}"},
+	}
+
+	for _, tc := range tests {
+		assert.Equal(t, tc.expected, emoji.Replace(tc.input), tc.input)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	t.Parallel()
+
+	e, ok := emoji.Lookup(":+1:")
+	assert.True(t, ok)
+	assert.Equal(t, "👍", e)
+
+	e, ok = emoji.Lookup("TADA")
+	assert.True(t, ok)
+	assert.Equal(t, "🎉", e)
+
+	_, ok = emoji.Lookup(":nope:")
+	assert.False(t, ok)
+}
+
+func TestID(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "1f44d", emoji.ID("👍"))
+	assert.Equal(t, "26a0", emoji.ID("⚠️"))
+	assert.Equal(t, "2764", emoji.ID("❤️"))
+}
@@ -38,10 +38,18 @@ var Color = types.Color{
 	Magenta: "\033[35m",
 	Cyan:    "\033[36m",
 	Bold:    "\033[1m",
+	Italic:  "\033[3m",
 	Ul:      "\033[4m",
+	Strike:  "\033[9m",
 	Nocolor: "\033[0m",
 }
 
+// DisableColors makes all the output plain text, e.g. when it is
+// written to a file.
+func DisableColors() {
+	Color = types.Color{}
+}
+
 func Header(project, key, summary string) string {
 	header := fmt.Sprintf("%s%s%s%s / %s - %s%s",
 		Color.Bold, Color.Ul, Color.Blue, project, key, summary, Color.Nocolor)
@@ -63,20 +71,7 @@ func Epic(summary string) string {
 }
 
 func IssueType(issueType string, short bool) string {
-	var col string
-
-	switch issueType {
-	case "Improvement":
-		col = Color.Green
-	case "Task":
-		col = Color.Blue
-	case "Bug":
-		col = Color.Red
-	case "Epic", "Story":
-		col = Color.Magenta
-	case "Setup":
-		col = Color.Cyan
-	}
+	col := IssueTypeColor(issueType)
 
 	if short {
 		return fmt.Sprintf("%s%-12s%s", col, issueType, Color.Nocolor)
@@ -86,20 +81,7 @@ func IssueType(issueType string, short bool) string {
 }
 
 func Status(status string, short bool) string {
-	var col string
-
-	switch status {
-	case "Closed", "Resolved", "Verified":
-		col = Color.Green
-	case "Programmed", "Peer Review", "Ready for Test", "Ready for review":
-		col = Color.Cyan
-	case "To Be Fixed", "In Progress", "Accepted", "Awaiting info":
-		col = Color.Blue
-	case "New", "Open":
-		col = Color.Bold
-	case "Rejected":
-		col = Color.Red
-	}
+	col := StatusColor(status)
 
 	if short {
 		return fmt.Sprintf("%s%-10s%s", col, status, Color.Nocolor)
@@ -109,16 +91,7 @@ func Status(status string, short bool) string {
 }
 
 func Priority(priority string, short bool) string {
-	var col string
-
-	switch priority {
-	case "Low":
-		col = Color.Green
-	case "Normal":
-		col = Color.Blue
-	case "Critical", "High", "Blocker":
-		col = Color.Red
-	}
+	col := PriorityColor(priority)
 
 	if short {
 		return fmt.Sprintf("%s%-10s%s", col, priority, Color.Nocolor)
@@ -222,3 +195,53 @@ func StatsSummary(num float64) string {
 
 	return fmt.Sprintf("%s%.2f%s", Color.Red, num*-1, Color.Nocolor)
 }
+
+// IssueTypeColor returns the color of the issue type.
+func IssueTypeColor(issueType string) string {
+	switch issueType {
+	case "Improvement":
+		return Color.Green
+	case "Task":
+		return Color.Blue
+	case "Bug":
+		return Color.Red
+	case "Epic", "Story":
+		return Color.Magenta
+	case "Setup":
+		return Color.Cyan
+	}
+
+	return ""
+}
+
+// StatusColor returns the color of the status.
+func StatusColor(status string) string {
+	switch status {
+	case "Closed", "Resolved", "Verified":
+		return Color.Green
+	case "Programmed", "Peer Review", "Ready for Test", "Ready for review":
+		return Color.Cyan
+	case "To Be Fixed", "In Progress", "Accepted", "Awaiting info":
+		return Color.Blue
+	case "New", "Open":
+		return Color.Bold
+	case "Rejected":
+		return Color.Red
+	}
+
+	return ""
+}
+
+// PriorityColor returns the color of the priority.
+func PriorityColor(priority string) string {
+	switch priority {
+	case "Low":
+		return Color.Green
+	case "Normal":
+		return Color.Blue
+	case "Critical", "High", "Blocker":
+		return Color.Red
+	}
+
+	return ""
+}
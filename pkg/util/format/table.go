@@ -0,0 +1,159 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package format
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Space between the columns of a table.
+const columnGap = 2
+
+// Column is a column of a Table.
+type Column struct {
+	Header string
+	// Max is the width the values are truncated at, 0 for no limit.
+	Max int
+	// Shrink is the width the column can be truncated to when the table
+	// is wider than the terminal, 0 if it must keep its width.
+	Shrink int
+	// Color returns the color of a value, nil for no color.
+	Color func(value string) string
+}
+
+// Table is a table where the columns are as wide as their longest value,
+// and the shrinkable columns are truncated when the table is wider than
+// the terminal.
+type Table struct {
+	Columns    []Column
+	Rows       [][]string
+	HideHeader bool
+}
+
+// AddRow adds a row with a value for each column.
+func (t *Table) AddRow(values ...string) {
+	t.Rows = append(t.Rows, values)
+}
+
+// Widths returns the width of each column, fitted in width if possible.
+// The widest shrinkable column is truncated one character at a time until
+// the table fits, or all are truncated to their shrink width. A width of
+// 0 fits any table.
+func (t *Table) Widths(width int) []int {
+	widths := make([]int, len(t.Columns))
+	total := 0
+
+	for i, c := range t.Columns {
+		if !t.HideHeader {
+			widths[i] = runes(c.Header)
+		}
+
+		for _, row := range t.Rows {
+			widths[i] = max(widths[i], runes(row[i]))
+		}
+
+		if c.Max > 0 {
+			widths[i] = min(widths[i], max(c.Max, runes(c.Header)))
+		}
+
+		total += widths[i] + columnGap
+	}
+
+	for width > 0 && total-columnGap > width {
+		widest := -1
+
+		for i, c := range t.Columns {
+			if c.Shrink > 0 && widths[i] > c.Shrink && (widest < 0 || widths[i] > widths[widest]) {
+				widest = i
+			}
+		}
+
+		if widest < 0 {
+			break
+		}
+
+		widths[widest]--
+		total--
+	}
+
+	return widths
+}
+
+// Render writes the table to w, fitted in width.
+func (t *Table) Render(w io.Writer, width int) {
+	widths := t.Widths(width)
+
+	if !t.HideHeader {
+		headers := make([]string, len(t.Columns))
+		for i, c := range t.Columns {
+			headers[i] = c.Header
+		}
+
+		fmt.Fprintf(w, "%s%s\n%s%s\n", Color.Ul, Color.Yellow, t.line(headers, widths, false), Color.Nocolor)
+	}
+
+	for _, row := range t.Rows {
+		fmt.Fprintln(w, t.line(row, widths, true))
+	}
+}
+
+// line returns the values padded to the widths, and the last value as is.
+func (t *Table) line(values []string, widths []int, colored bool) string {
+	var b strings.Builder
+
+	for i, value := range values {
+		v := Truncate(value, widths[i])
+		if i < len(values)-1 {
+			v += strings.Repeat(" ", widths[i]+columnGap-runes(v))
+		}
+
+		if colored && t.Columns[i].Color != nil {
+			if col := t.Columns[i].Color(value); col != "" {
+				v = col + v + Color.Nocolor
+			}
+		}
+
+		b.WriteString(v)
+	}
+
+	return b.String()
+}
+
+// Truncate shortens s to width characters, ending it with "..".
+func Truncate(s string, width int) string {
+	r := []rune(s)
+	if len(r) <= width {
+		return s
+	}
+
+	if width <= 2 {
+		return string(r[:width])
+	}
+
+	return string(r[:width-2]) + ".."
+}
+
+func runes(s string) int {
+	return len([]rune(s))
+}
@@ -0,0 +1,110 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package format_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/mhersson/gojira/pkg/util/format"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTable() format.Table {
+	table := format.Table{Columns: []format.Column{
+		{Header: "Key"},
+		{Header: "Summary", Shrink: 10},
+		{Header: "Assignee", Shrink: 8},
+	}}
+
+	table.AddRow("OSE-1", "Fix the flux capacitor", "Emmett Brown")
+	table.AddRow("OSE-12", "Find plutonium", "Marty McFly")
+
+	return table
+}
+
+func TestTableWidths(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		width    int
+		expected []int
+	}{
+		{0, []int{6, 22, 12}},   // no limit
+		{100, []int{6, 22, 12}}, // fits
+		{40, []int{6, 18, 12}},  // the summary is the widest
+		{34, []int{6, 12, 12}},  // until it is as wide as the assignee
+		{30, []int{6, 10, 10}},  // then both are shrunk
+		{28, []int{6, 10, 8}},   // to their shrink width
+		{10, []int{6, 10, 8}},   // and no more
+	}
+
+	for _, v := range tests {
+		table := newTable()
+		assert.Equal(t, v.expected, table.Widths(v.width), "width %d", v.width)
+	}
+}
+
+func TestTableMax(t *testing.T) {
+	t.Parallel()
+
+	table := newTable()
+	table.Columns[1].Max = 5
+
+	// Not narrower than the header
+	assert.Equal(t, []int{6, 7, 12}, table.Widths(0))
+}
+
+func TestTableRender(t *testing.T) {
+	color := format.Color
+	format.DisableColors()
+
+	defer func() { format.Color = color }()
+
+	table := newTable()
+
+	var b bytes.Buffer
+
+	table.Render(&b, 34)
+
+	assert.Equal(t, "\n"+
+		"Key     Summary       Assignee\n"+
+		"OSE-1   Fix the fl..  Emmett Brown\n"+
+		"OSE-12  Find pluto..  Marty McFly\n", b.String())
+
+	b.Reset()
+
+	table.HideHeader = true
+	table.Render(&b, 0)
+
+	assert.Equal(t, ""+
+		"OSE-1   Fix the flux capacitor  Emmett Brown\n"+
+		"OSE-12  Find plutonium          Marty McFly\n", b.String())
+}
+
+func TestTruncate(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "Gojira", format.Truncate("Gojira", 6))
+	assert.Equal(t, "Goj..", format.Truncate("Gojira", 5))
+	assert.Equal(t, "Gø", format.Truncate("Gøjira", 2))
+}
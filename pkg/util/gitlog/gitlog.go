@@ -0,0 +1,200 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package gitlog
+
+import (
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mhersson/gojira/pkg/types"
+)
+
+// ReflogFormat is the format expected by ParseReflog, and must be used
+// together with --date=unix, e.g. git log -g --date=unix --format=<ReflogFormat>.
+const ReflogFormat = "%gd%x09%gs"
+
+// Event is a single entry in the git reflog.
+type Event struct {
+	Time    time.Time
+	From    string // Branch checked out from
+	To      string // Branch checked out to
+	Message string // Commit message
+}
+
+// Suggestion defaults.
+const (
+	DefaultMaxGap   = 2 * time.Hour
+	DefaultLeadTime = 30 * time.Minute
+	roundTo         = 15 * time.Minute
+)
+
+var (
+	reflogTime  = regexp.MustCompile(`@\{([0-9]+)\}`)
+	checkout    = regexp.MustCompile(`^checkout: moving from (\S+) to (\S+)$`)
+	commit      = regexp.MustCompile(`^commit( \([a-z]+\))?: (.*)$`)
+	issueKey    = regexp.MustCompile(`[A-Z]{2,9}-[0-9]{1,5}`)
+	invalidChar = regexp.MustCompile(`[^A-Za-z0-9_\-,\.\s]+`)
+	whitespace  = regexp.MustCompile(`\s+`)
+)
+
+// ParseReflog parses the reflog output and returns
+// the events sorted from oldest to newest.
+func ParseReflog(out string) []Event {
+	events := []Event{}
+
+	for _, line := range strings.Split(out, "\n") {
+		parts := strings.SplitN(line, "\t", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		m := reflogTime.FindStringSubmatch(parts[0])
+		if m == nil {
+			continue
+		}
+
+		sec, _ := strconv.ParseInt(m[1], 10, 64)
+		e := Event{Time: time.Unix(sec, 0)}
+
+		if c := checkout.FindStringSubmatch(parts[1]); c != nil {
+			e.From, e.To = c[1], c[2]
+		} else if c := commit.FindStringSubmatch(parts[1]); c != nil {
+			e.Message = c[2]
+		}
+
+		events = append(events, e)
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Time.Before(events[j].Time)
+	})
+
+	return events
+}
+
+// Suggest estimates the time spent per issue on the given date (yyyy-mm-dd).
+// Every event marks the end of a period of work, which is attributed to the
+// issue key found in the commit message, or else in the name of the branch
+// checked out during that period. The length of a period is the time since
+// the previous event, or the lead time if the gap is longer than maxGap.
+func Suggest(events []Event, date string, maxGap, lead time.Duration) []types.SimplifiedTimesheet {
+	type work struct {
+		start    time.Time
+		duration time.Duration
+		comments []string
+	}
+
+	worked := map[string]*work{}
+	keys := []string{}
+	branch := ""
+
+	var prev time.Time
+
+	for _, e := range events {
+		key := findIssueKey(e.Message)
+		if key == "" {
+			// A checkout ends the work done on the branch we move away from
+			if e.To != "" {
+				key = findIssueKey(e.From)
+			} else {
+				key = findIssueKey(branch)
+			}
+		}
+
+		if e.To != "" {
+			branch = e.To
+		}
+
+		if e.Time.Format("2006-01-02") != date {
+			prev = e.Time
+
+			continue
+		}
+
+		d := e.Time.Sub(prev)
+		if prev.IsZero() || prev.Format("2006-01-02") != date || d > maxGap {
+			d = lead
+		}
+
+		prev = e.Time
+
+		if key == "" {
+			continue
+		}
+
+		w, ok := worked[key]
+		if !ok {
+			w = &work{start: e.Time.Add(-d)}
+			worked[key] = w
+			keys = append(keys, key)
+		}
+
+		w.duration += d
+
+		if c := cleanComment(e.Message); c != "" && !slices.Contains(w.comments, c) {
+			w.comments = append(w.comments, c)
+		}
+	}
+
+	suggestions := []types.SimplifiedTimesheet{}
+
+	for _, key := range keys {
+		w := worked[key]
+
+		comment := strings.Join(w.comments, ", ")
+		if comment == "" {
+			comment = "Development"
+		}
+
+		// Round up to the nearest quarter
+		d := (w.duration + roundTo - 1).Truncate(roundTo)
+
+		suggestions = append(suggestions, types.SimplifiedTimesheet{
+			ID:        666,
+			Date:      date,
+			StartDate: w.start.Format("2006-01-02 15:04"),
+			Key:       key,
+			Comment:   comment,
+			TimeSpent: int(d.Seconds()),
+		})
+	}
+
+	return suggestions
+}
+
+func findIssueKey(s string) string {
+	return issueKey.FindString(strings.ToUpper(s))
+}
+
+// cleanComment removes the issue key and all characters
+// not supported by the worklog editor from the message.
+func cleanComment(msg string) string {
+	msg = issueKey.ReplaceAllString(msg, "")
+	msg = invalidChar.ReplaceAllString(msg, " ")
+	msg = whitespace.ReplaceAllString(msg, " ")
+
+	return strings.Trim(msg, " -,.")
+}
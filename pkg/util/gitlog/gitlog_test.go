@@ -0,0 +1,92 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package gitlog_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mhersson/gojira/pkg/util/gitlog"
+	"github.com/stretchr/testify/assert"
+)
+
+func reflogLine(t time.Time, subject string) string {
+	return fmt.Sprintf("HEAD@{%d}\t%s", t.Unix(), subject)
+}
+
+func TestParseReflog(t *testing.T) {
+	t.Parallel()
+
+	t1 := time.Date(2024, 3, 1, 9, 0, 0, 0, time.Local)
+	t2 := t1.Add(time.Hour)
+
+	// Newest entries first, as printed by git
+	out := reflogLine(t2, "commit: GOJIRA-1 Fix the thing") + "\n" +
+		reflogLine(t1, "checkout: moving from main to feature/gojira-1") + "\n" +
+		"garbage\n"
+
+	events := gitlog.ParseReflog(out)
+
+	assert.Len(t, events, 2)
+	assert.Equal(t, t1, events[0].Time)
+	assert.Equal(t, "main", events[0].From)
+	assert.Equal(t, "feature/gojira-1", events[0].To)
+	assert.Equal(t, "GOJIRA-1 Fix the thing", events[1].Message)
+}
+
+func TestSuggest(t *testing.T) {
+	t.Parallel()
+
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.Local)
+	at := func(h, m int) time.Time { return day.Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute) }
+
+	events := []gitlog.Event{
+		{Time: day.Add(-24 * time.Hour), From: "main", To: "feature/gojira-1"},
+		{Time: at(9, 0), Message: "Add the thing"},
+		{Time: at(10, 10), Message: "GOJIRA-2 Fix typo"},
+		{Time: at(11, 0), From: "feature/gojira-1", To: "gojira-3-other"},
+		{Time: at(15, 0), Message: "Start on the other thing"},
+		{Time: at(15, 40), Message: "Finish the other thing"},
+	}
+
+	suggestions := gitlog.Suggest(events, "2024-03-01", gitlog.DefaultMaxGap, gitlog.DefaultLeadTime)
+
+	assert.Len(t, suggestions, 3)
+
+	// 30m lead time before the first commit and 50m until the checkout, rounded up
+	assert.Equal(t, "GOJIRA-1", suggestions[0].Key)
+	assert.Equal(t, "2024-03-01 08:30", suggestions[0].StartDate)
+	assert.Equal(t, int((90 * time.Minute).Seconds()), suggestions[0].TimeSpent)
+	assert.Equal(t, "Add the thing", suggestions[0].Comment)
+	assert.Equal(t, 666, suggestions[0].ID)
+
+	// 70m rounded up to 75m
+	assert.Equal(t, "GOJIRA-2", suggestions[1].Key)
+	assert.Equal(t, int((75 * time.Minute).Seconds()), suggestions[1].TimeSpent)
+	assert.Equal(t, "Fix typo", suggestions[1].Comment)
+
+	// The gap after the checkout is too long, so lead time + 40m
+	assert.Equal(t, "GOJIRA-3", suggestions[2].Key)
+	assert.Equal(t, int((75 * time.Minute).Seconds()), suggestions[2].TimeSpent)
+	assert.Equal(t, "Start on the other thing, Finish the other thing", suggestions[2].Comment)
+}
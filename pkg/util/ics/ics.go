@@ -0,0 +1,118 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package ics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Event is a calendar event. All day events only use the date of
+// Start and End, and End is the first day after the event.
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	URL         string
+	Start       time.Time
+	End         time.Time
+	AllDay      bool
+}
+
+const (
+	dateFormat     = "20060102"
+	dateTimeFormat = "20060102T150405Z"
+	// Lines longer than this are folded, as required by RFC 5545.
+	maxLineLength = 75
+)
+
+// Write writes the events as an iCalendar file. The time stamp of
+// the events is set to now.
+func Write(w io.Writer, events []Event, now time.Time) error {
+	b := bufio.NewWriter(w)
+
+	writeLine(b, "BEGIN:VCALENDAR")
+	writeLine(b, "VERSION:2.0")
+	writeLine(b, "PRODID:-//gojira//gojira//EN")
+	writeLine(b, "CALSCALE:GREGORIAN")
+
+	for _, e := range events {
+		writeLine(b, "BEGIN:VEVENT")
+		writeLine(b, "UID:"+e.UID)
+		writeLine(b, "DTSTAMP:"+now.UTC().Format(dateTimeFormat))
+
+		if e.AllDay {
+			writeLine(b, "DTSTART;VALUE=DATE:"+e.Start.Format(dateFormat))
+			writeLine(b, "DTEND;VALUE=DATE:"+e.End.Format(dateFormat))
+		} else {
+			writeLine(b, "DTSTART:"+e.Start.UTC().Format(dateTimeFormat))
+			writeLine(b, "DTEND:"+e.End.UTC().Format(dateTimeFormat))
+		}
+
+		writeLine(b, "SUMMARY:"+Escape(e.Summary))
+
+		if e.Description != "" {
+			writeLine(b, "DESCRIPTION:"+Escape(e.Description))
+		}
+
+		if e.URL != "" {
+			writeLine(b, "URL:"+e.URL)
+		}
+
+		writeLine(b, "END:VEVENT")
+	}
+
+	writeLine(b, "END:VCALENDAR")
+
+	return b.Flush()
+}
+
+// Escape escapes the special characters of a text value.
+func Escape(text string) string {
+	r := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+	return r.Replace(text)
+}
+
+// writeLine writes the line ending with CRLF, folded at 75 octets
+// without splitting multi-byte characters.
+func writeLine(w *bufio.Writer, line string) {
+	length := 0
+
+	for _, r := range line {
+		size := len(string(r))
+
+		if length+size > maxLineLength {
+			// The leading space of the continuation line counts as well
+			fmt.Fprint(w, "\r\n ")
+			length = 1
+		}
+
+		_, _ = w.WriteRune(r)
+		length += size
+	}
+
+	fmt.Fprint(w, "\r\n")
+}
@@ -0,0 +1,124 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package ics_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mhersson/gojira/pkg/util/ics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 3, 5, 12, 0, 0, 0, time.UTC)
+	events := []ics.Event{
+		{
+			UID:     "sprint-3@gojira",
+			Summary: "Sprint 12",
+			Start:   time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC),
+			End:     time.Date(2024, 3, 18, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			UID:         "due-OSE-1@gojira",
+			Summary:     "Due: OSE-1 Fix it, now",
+			Description: "Line one\nLine two",
+			URL:         "https://jira/browse/OSE-1",
+			Start:       time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC),
+			End:         time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC),
+			AllDay:      true,
+		},
+	}
+
+	var b bytes.Buffer
+	assert.NoError(t, ics.Write(&b, events, now))
+
+	want := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//gojira//gojira//EN",
+		"CALSCALE:GREGORIAN",
+		"BEGIN:VEVENT",
+		"UID:sprint-3@gojira",
+		"DTSTAMP:20240305T120000Z",
+		"DTSTART:20240304T090000Z",
+		"DTEND:20240318T090000Z",
+		"SUMMARY:Sprint 12",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:due-OSE-1@gojira",
+		"DTSTAMP:20240305T120000Z",
+		"DTSTART;VALUE=DATE:20240310",
+		"DTEND;VALUE=DATE:20240311",
+		`SUMMARY:Due: OSE-1 Fix it\, now`,
+		`DESCRIPTION:Line one\nLine two`,
+		"URL:https://jira/browse/OSE-1",
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n")
+
+	assert.Equal(t, want, b.String())
+}
+
+func TestWriteFoldsLongLines(t *testing.T) {
+	t.Parallel()
+
+	summary := strings.Repeat("æ", 60)
+	events := []ics.Event{{UID: "1", Summary: summary}}
+
+	var b bytes.Buffer
+	assert.NoError(t, ics.Write(&b, events, time.Now()))
+
+	unfolded := strings.ReplaceAll(b.String(), "\r\n ", "")
+	assert.Contains(t, unfolded, "SUMMARY:"+summary+"\r\n")
+
+	for _, line := range strings.Split(b.String(), "\r\n") {
+		assert.LessOrEqual(t, len(line), 75)
+	}
+}
+
+func TestEscape(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		text string
+		want string
+	}{
+		{text: "plain", want: "plain"},
+		{text: "a,b;c", want: `a\,b\;c`},
+		{text: `back\slash`, want: `back\\slash`},
+		{text: "two\r\nlines\nthree", want: `two\nlines\nthree`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, ics.Escape(tt.text))
+		})
+	}
+}
@@ -0,0 +1,253 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package index
+
+import (
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// Matches in the title count more than matches in the body.
+const titleWeight = 3.0
+
+// Fuzzy matches count less than exact matches.
+const (
+	prefixWeight = 0.7
+	typoWeight   = 0.5
+)
+
+var wordRegexp = regexp.MustCompile(`[\p{L}\p{N}]+`)
+
+type Document struct {
+	ID    string
+	Title string
+	Body  string
+}
+
+type Result struct {
+	ID    string
+	Score float64
+	Terms []string // The indexed terms matching the query
+}
+
+// Index is an inverted index mapping each term to
+// the weighted term frequency in the documents.
+type Index struct {
+	docs     []Document
+	postings map[string]map[int]float64
+}
+
+// Tokenize splits the text into lower case words, ignoring
+// punctuation and words shorter than two characters.
+func Tokenize(text string) []string {
+	tokens := []string{}
+
+	for _, w := range wordRegexp.FindAllString(strings.ToLower(text), -1) {
+		if utf8.RuneCountInString(w) > 1 {
+			tokens = append(tokens, w)
+		}
+	}
+
+	return tokens
+}
+
+func New(docs []Document) *Index {
+	idx := &Index{docs: docs, postings: map[string]map[int]float64{}}
+
+	for i, d := range docs {
+		for _, t := range Tokenize(d.Title) {
+			idx.add(t, i, titleWeight)
+		}
+
+		for _, t := range Tokenize(d.Body) {
+			idx.add(t, i, 1)
+		}
+	}
+
+	return idx
+}
+
+func (idx *Index) add(term string, doc int, weight float64) {
+	if idx.postings[term] == nil {
+		idx.postings[term] = map[int]float64{}
+	}
+
+	idx.postings[term][doc] += weight
+}
+
+// Search returns the documents matching any of the words in the query,
+// ranked by tf-idf. Documents matching more of the words rank higher.
+// Words of three or more characters also match terms they are a prefix
+// of, and words of four or more characters match terms with one typo.
+func (idx *Index) Search(query string) []Result {
+	words := Tokenize(query)
+	scores := map[int]float64{}
+	matchedWords := map[int]int{}
+	matchedTerms := map[int][]string{}
+
+	for _, w := range words {
+		best := map[int]float64{}
+
+		for term, weight := range idx.matchingTerms(w) {
+			idf := math.Log(1 + float64(len(idx.docs))/float64(len(idx.postings[term])))
+
+			for doc, tf := range idx.postings[term] {
+				best[doc] = max(best[doc], weight*tf*idf)
+
+				if !slices.Contains(matchedTerms[doc], term) {
+					matchedTerms[doc] = append(matchedTerms[doc], term)
+				}
+			}
+		}
+
+		for doc, score := range best {
+			scores[doc] += score
+			matchedWords[doc]++
+		}
+	}
+
+	results := []Result{}
+
+	for doc, score := range scores {
+		results = append(results, Result{
+			ID:    idx.docs[doc].ID,
+			Score: score * float64(matchedWords[doc]) / float64(len(words)),
+			Terms: matchedTerms[doc],
+		})
+	}
+
+	slices.SortFunc(results, func(a, b Result) int {
+		if a.Score != b.Score {
+			if a.Score > b.Score {
+				return -1
+			}
+
+			return 1
+		}
+
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	return results
+}
+
+// matchingTerms returns the indexed terms matching the word,
+// with the weight of the match.
+func (idx *Index) matchingTerms(word string) map[string]float64 {
+	terms := map[string]float64{}
+	length := utf8.RuneCountInString(word)
+
+	for term := range idx.postings {
+		switch {
+		case term == word:
+			terms[term] = 1
+		case length >= 3 && strings.HasPrefix(term, word):
+			terms[term] = prefixWeight
+		case length >= 4 && withinOneEdit(word, term):
+			terms[term] = typoWeight
+		}
+	}
+
+	return terms
+}
+
+// withinOneEdit returns true if a can be changed to b
+// by inserting, deleting or replacing a single character.
+func withinOneEdit(a, b string) bool {
+	ra, rb := []rune(a), []rune(b)
+
+	if len(ra) > len(rb) {
+		ra, rb = rb, ra
+	}
+
+	if len(rb)-len(ra) > 1 {
+		return false
+	}
+
+	i := 0
+	for i < len(ra) && ra[i] == rb[i] {
+		i++
+	}
+
+	if i == len(ra) {
+		return true
+	}
+
+	if len(ra) == len(rb) {
+		return string(ra[i+1:]) == string(rb[i+1:])
+	}
+
+	return string(ra[i:]) == string(rb[i+1:])
+}
+
+// Snippet returns about width characters of the text around the first
+// word containing one of the terms, with all such words passed to mark.
+func Snippet(text string, terms []string, width int, mark func(string) string) string {
+	words := strings.Fields(text)
+	first := -1
+	matches := make([]bool, len(words))
+
+	for i, w := range words {
+		for _, t := range Tokenize(w) {
+			if slices.Contains(terms, t) {
+				matches[i] = true
+			}
+		}
+
+		if matches[i] && first == -1 {
+			first = i
+		}
+	}
+
+	start := 0
+	if first > 3 {
+		start = first - 3
+	}
+
+	parts := []string{}
+	length := 0
+
+	for i := start; i < len(words) && length < width; i++ {
+		length += utf8.RuneCountInString(words[i]) + 1
+
+		if matches[i] {
+			parts = append(parts, mark(words[i]))
+		} else {
+			parts = append(parts, words[i])
+		}
+	}
+
+	snippet := strings.Join(parts, " ")
+
+	if start > 0 {
+		snippet = ".." + snippet
+	}
+
+	if start+len(parts) < len(words) {
+		snippet += ".."
+	}
+
+	return snippet
+}
@@ -0,0 +1,102 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package index_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mhersson/gojira/pkg/util/index"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"", []string{}},
+		{"Pacemaker timeout, after 30s!", []string{"pacemaker", "timeout", "after", "30s"}},
+		{"a b-cd æøå", []string{"cd", "æøå"}},
+	}
+
+	for _, tc := range tests {
+		assert.Equal(t, tc.expected, index.Tokenize(tc.input))
+	}
+}
+
+func TestSearch(t *testing.T) {
+	t.Parallel()
+
+	idx := index.New([]index.Document{
+		{ID: "OSE-1", Title: "Pacemaker fails to start", Body: "The cluster logs a timeout"},
+		{ID: "OSE-2", Title: "Timeout in pacemaker resource", Body: "Happens after a failover"},
+		{ID: "OSE-3", Title: "Update the docs", Body: "Nothing about clusters"},
+		{ID: "OSE-4", Title: "Network timeouts", Body: ""},
+	})
+
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{"timeout pacemaker", []string{"OSE-2", "OSE-1", "OSE-4"}},
+		{"pacemaker", []string{"OSE-1", "OSE-2"}},
+		{"pacemakr", []string{"OSE-1", "OSE-2"}},
+		{"clust", []string{"OSE-1", "OSE-3"}},
+		{"kubernetes", []string{}},
+		{"", []string{}},
+	}
+
+	for _, tc := range tests {
+		ids := []string{}
+		for _, r := range idx.Search(tc.query) {
+			ids = append(ids, r.ID)
+		}
+
+		assert.Equal(t, tc.expected, ids, tc.query)
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	t.Parallel()
+
+	mark := func(s string) string { return "*" + s + "*" }
+
+	tests := []struct {
+		text     string
+		terms    []string
+		width    int
+		expected string
+	}{
+		{"The cluster hit Timeout: again", []string{"timeout"}, 60, "The cluster hit *Timeout:* again"},
+		{"one two three four five six seven timeout eight nine ten", []string{"timeout"}, 20,
+			"..five six seven *timeout*.."},
+		{"no match here", []string{"timeout"}, 5, "no match.."},
+		{strings.Repeat(" ", 3), []string{"timeout"}, 10, ""},
+	}
+
+	for _, tc := range tests {
+		assert.Equal(t, tc.expected, index.Snippet(tc.text, tc.terms, tc.width, mark))
+	}
+}
@@ -0,0 +1,71 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+// Package mail composes plain text mails, e.g. the reports sent with
+// `gojira report send`.
+package mail
+
+import (
+	"bytes"
+	"mime"
+	"strings"
+	"time"
+)
+
+// Compose returns the mail with headers and CRLF line endings, ready to be
+// sent with SMTP or piped to sendmail.
+func Compose(from string, to []string, subject, body string, date time.Time) []byte {
+	var b bytes.Buffer
+
+	header := func(name, value string) {
+		b.WriteString(name + ": " + value + "\r\n")
+	}
+
+	header("From", from)
+	header("To", strings.Join(to, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", subject))
+	header("Date", date.Format(time.RFC1123Z))
+	header("MIME-Version", "1.0")
+	header("Content-Type", "text/plain; charset=utf-8")
+	header("Content-Transfer-Encoding", "8bit")
+	b.WriteString("\r\n")
+
+	body = strings.ReplaceAll(body, "\r\n", "\n")
+	for _, line := range strings.Split(strings.TrimRight(body, "\n"), "\n") {
+		b.WriteString(line + "\r\n")
+	}
+
+	return b.Bytes()
+}
+
+// SplitSubject returns the subject from a first line starting with
+// "Subject:", and the rest of the text as the body. If there is no such
+// line the subject is empty and the text is returned as is.
+func SplitSubject(text string) (string, string) {
+	first, rest, _ := strings.Cut(text, "\n")
+
+	subject, ok := strings.CutPrefix(first, "Subject:")
+	if !ok {
+		return "", text
+	}
+
+	return strings.TrimSpace(subject), strings.TrimLeft(rest, "\n")
+}
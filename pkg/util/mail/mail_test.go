@@ -0,0 +1,98 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package mail_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mhersson/gojira/pkg/util/mail"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompose(t *testing.T) {
+	t.Parallel()
+
+	date := time.Date(2024, 3, 8, 15, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		subject string
+		body    string
+		want    string
+	}{
+		{
+			name:    "plain",
+			subject: "Status report",
+			body:    "Hi,\n\nAll good.\n",
+			want: "From: me@example.com\r\nTo: boss@example.com, team@example.com\r\n" +
+				"Subject: Status report\r\nDate: Fri, 08 Mar 2024 15:00:00 +0000\r\n" +
+				"MIME-Version: 1.0\r\nContent-Type: text/plain; charset=utf-8\r\n" +
+				"Content-Transfer-Encoding: 8bit\r\n\r\nHi,\r\n\r\nAll good.\r\n",
+		},
+		{
+			name:    "encoded subject and crlf body",
+			subject: "Rapport for uke 10 – ferdig",
+			body:    "Hei\r\nHadet",
+			want: "From: me@example.com\r\nTo: boss@example.com, team@example.com\r\n" +
+				"Subject: =?utf-8?q?Rapport_for_uke_10_=E2=80=93_ferdig?=\r\n" +
+				"Date: Fri, 08 Mar 2024 15:00:00 +0000\r\n" +
+				"MIME-Version: 1.0\r\nContent-Type: text/plain; charset=utf-8\r\n" +
+				"Content-Transfer-Encoding: 8bit\r\n\r\nHei\r\nHadet\r\n",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := mail.Compose("me@example.com", []string{"boss@example.com", "team@example.com"},
+				tc.subject, tc.body, date)
+			assert.Equal(t, tc.want, string(got))
+		})
+	}
+}
+
+func TestSplitSubject(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		text        string
+		wantSubject string
+		wantBody    string
+	}{
+		{"with subject", "Subject: Week 10\n\nHi,\nDone", "Week 10", "Hi,\nDone"},
+		{"without subject", "Hi,\nDone", "", "Hi,\nDone"},
+		{"subject only", "Subject: Week 10", "Week 10", ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			subject, body := mail.SplitSubject(tc.text)
+			assert.Equal(t, tc.wantSubject, subject)
+			assert.Equal(t, tc.wantBody, body)
+		})
+	}
+}
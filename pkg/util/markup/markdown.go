@@ -0,0 +1,331 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package markup
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	mdHeadingRe = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	mdListRe    = regexp.MustCompile(`^(\s*)([-*+]|\d+\.)\s+(.*)$`)
+	mdFenceRe   = regexp.MustCompile("^```\\s*(\\S*)\\s*$")
+	mdRuleRe    = regexp.MustCompile(`^(-{3,}|\*{3,}|_{3,})$`)
+	mdTableSep  = regexp.MustCompile(`^\|?(\s*:?-+:?\s*\|)+\s*:?-*:?\s*$`)
+	mdCodeRe    = regexp.MustCompile("`([^`]+)`")
+	mdImageRe   = regexp.MustCompile(`!\[[^\]]*\]\(([^)\s]+)\)`)
+	mdLinkRe    = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	mdURLRe     = regexp.MustCompile(`<(https?://[^>\s]+)>`)
+	mdBoldRe    = regexp.MustCompile(`(^|[^\w*])\*\*([^\s*](?:[^*]*[^\s*])?)\*\*([^\w*]|$)`)
+	mdBold2Re   = regexp.MustCompile(`(^|[^\w_])__([^\s_](?:[^_]*[^\s_])?)__([^\w_]|$)`)
+	mdItalicRe  = regexp.MustCompile(`(^|[^\w*])\*([^\s*](?:[^*]*[^\s*])?)\*([^\w*]|$)`)
+	mdItalic2Re = regexp.MustCompile(`(^|[^\w_])_([^\s_](?:[^_]*[^\s_])?)_([^\w_]|$)`)
+	mdStrikeRe  = regexp.MustCompile(`~~([^~]+)~~`)
+
+	wikiLinkRe  = regexp.MustCompile(`\[([^\[\]|~^]+)\|([^\[\]]+)\]|\[((?:https?|mailto|file):[^\[\]|]+)\]`)
+	wikiImageRe = regexp.MustCompile(`!([^\s!|]+\.[A-Za-z0-9]+)(?:\|[^!]*)?!`)
+)
+
+// bold marks the bold text while converting, so it is not taken for
+// italic, which is *text* in markdown.
+const bold = "\x00"
+
+// ToMarkdown converts the wiki markup to markdown, e.g. to edit a
+// description in markdown. FromMarkdown converts it back.
+func ToMarkdown(text string) string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	out := []string{}
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], " \t")
+		trimmed := strings.TrimSpace(line)
+
+		switch m := blockRe.FindStringSubmatch(trimmed); {
+		case m != nil:
+			var content []string
+
+			content, i = blockContent(lines, i, m[1], m[2])
+
+			if m[1] == "quote" {
+				for _, l := range content {
+					out = append(out, strings.TrimRight("> "+inlineToMarkdown(l), " "))
+				}
+
+				continue
+			}
+
+			lang := ""
+			if _, l, ok := strings.Cut(strings.SplitN(trimmed, "}", 2)[0], ":"); ok && m[1] == "code" {
+				lang, _, _ = strings.Cut(l, "|")
+			}
+
+			out = append(out, "```"+lang)
+			out = append(out, content...)
+			out = append(out, "```")
+		case strings.HasPrefix(trimmed, "|"):
+			header := strings.HasPrefix(trimmed, "||")
+			cells := wikiCells(trimmed)
+
+			for j := range cells {
+				cells[j] = inlineToMarkdown(cells[j])
+			}
+
+			out = append(out, "| "+strings.Join(cells, " | ")+" |")
+
+			if header {
+				out = append(out, "|"+strings.Repeat(" --- |", len(cells)))
+			}
+		case ruleRe.MatchString(trimmed):
+			out = append(out, "---")
+		case headingRe.MatchString(trimmed):
+			m := headingRe.FindStringSubmatch(trimmed)
+			level, _ := strconv.Atoi(m[1])
+			out = append(out, strings.Repeat("#", level)+" "+inlineToMarkdown(m[2]))
+		case strings.HasPrefix(trimmed, "bq. "):
+			out = append(out, "> "+inlineToMarkdown(strings.TrimPrefix(trimmed, "bq. ")))
+		case listRe.MatchString(trimmed):
+			m := listRe.FindStringSubmatch(trimmed)
+
+			marker := "- "
+			if strings.HasSuffix(m[1], "#") {
+				marker = "1. "
+			}
+
+			out = append(out, strings.Repeat("  ", len(m[1])-1)+marker+inlineToMarkdown(m[2]))
+		default:
+			out = append(out, inlineToMarkdown(line))
+		}
+	}
+
+	return strings.Join(out, "\n")
+}
+
+// FromMarkdown converts the markdown to wiki markup, e.g. after editing
+// a description in markdown. Code blocks with a language are {code}
+// blocks, and those without are {noformat} blocks.
+func FromMarkdown(text string) string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	out := []string{}
+	quote := []string{}
+
+	flushQuote := func() {
+		switch len(quote) {
+		case 0:
+		case 1:
+			out = append(out, "bq. "+quote[0])
+		default:
+			out = append(out, "{quote}")
+			out = append(out, quote...)
+			out = append(out, "{quote}")
+		}
+
+		quote = quote[:0]
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], " \t")
+
+		if rest, ok := strings.CutPrefix(line, ">"); ok {
+			quote = append(quote, inlineFromMarkdown(strings.TrimPrefix(rest, " ")))
+
+			continue
+		}
+
+		flushQuote()
+
+		switch {
+		case mdFenceRe.MatchString(line):
+			tag := "{noformat}"
+			if lang := mdFenceRe.FindStringSubmatch(line)[1]; lang != "" {
+				tag = "{code:" + lang + "}"
+			}
+
+			out = append(out, tag)
+
+			for i++; i < len(lines) && !mdFenceRe.MatchString(strings.TrimRight(lines[i], " \t")); i++ {
+				out = append(out, lines[i])
+			}
+
+			if tag != "{noformat}" {
+				tag = "{code}"
+			}
+
+			out = append(out, tag)
+		case strings.HasPrefix(strings.TrimSpace(line), "|"):
+			cells := markdownCells(line)
+
+			if i+1 < len(lines) && mdTableSep.MatchString(strings.TrimSpace(lines[i+1])) {
+				out = append(out, "||"+strings.Join(cells, "||")+"||")
+				i++
+
+				continue
+			}
+
+			out = append(out, "|"+strings.Join(cells, "|")+"|")
+		case mdRuleRe.MatchString(strings.TrimSpace(line)):
+			out = append(out, "----")
+		case mdHeadingRe.MatchString(line):
+			m := mdHeadingRe.FindStringSubmatch(line)
+			out = append(out, "h"+strconv.Itoa(len(m[1]))+". "+inlineFromMarkdown(m[2]))
+		case mdListRe.MatchString(line):
+			m := mdListRe.FindStringSubmatch(line)
+
+			marker := "*"
+			if strings.HasSuffix(m[2], ".") {
+				marker = "#"
+			}
+
+			depth := len(strings.ReplaceAll(m[1], "\t", "  "))/2 + 1
+			out = append(out, strings.Repeat(marker, depth)+" "+inlineFromMarkdown(m[3]))
+		default:
+			out = append(out, inlineFromMarkdown(line))
+		}
+	}
+
+	flushQuote()
+
+	return strings.Join(out, "\n")
+}
+
+func inlineToMarkdown(text string) string {
+	code := func(c string) string { return "`" + c + "`" }
+
+	return outsideCode(text, monoRe, code, func(s string) string {
+		s = replaceMarks(s, markRes[`\*`], bold, bold)
+		s = replaceMarks(s, markRes[`_`], "*", "*")
+		s = replaceMarks(s, markRes[`-`], "~~", "~~")
+		s = strings.ReplaceAll(s, bold, "**")
+
+		s = wikiLinkRe.ReplaceAllStringFunc(s, func(l string) string {
+			m := wikiLinkRe.FindStringSubmatch(l)
+			if m[3] != "" {
+				return "<" + m[3] + ">"
+			}
+
+			return "[" + m[1] + "](" + m[2] + ")"
+		})
+
+		return wikiImageRe.ReplaceAllString(s, "![]($1)")
+	})
+}
+
+func inlineFromMarkdown(text string) string {
+	code := func(c string) string { return "{{" + c + "}}" }
+
+	return outsideCode(text, mdCodeRe, code, func(s string) string {
+		s = mdImageRe.ReplaceAllString(s, "!$1!")
+		s = mdLinkRe.ReplaceAllString(s, "[$1|$2]")
+		s = mdURLRe.ReplaceAllString(s, "[$1]")
+		s = replaceMarks(s, mdBoldRe, bold, bold)
+		s = replaceMarks(s, mdBold2Re, bold, bold)
+		s = replaceMarks(s, mdItalicRe, "_", "_")
+		s = replaceMarks(s, mdItalic2Re, "_", "_")
+		s = mdStrikeRe.ReplaceAllString(s, "-$1-")
+
+		return strings.ReplaceAll(s, bold, "*")
+	})
+}
+
+// outsideCode converts the text outside the inline code matched by re,
+// and the code in the first group of re with code.
+func outsideCode(text string, re *regexp.Regexp, code, convert func(string) string) string {
+	var b strings.Builder
+
+	last := 0
+
+	for _, m := range re.FindAllStringSubmatchIndex(text, -1) {
+		b.WriteString(convert(text[last:m[0]]))
+		b.WriteString(code(text[m[2]:m[3]]))
+
+		last = m[1]
+	}
+
+	b.WriteString(convert(text[last:]))
+
+	return b.String()
+}
+
+// replaceMarks replaces the marks around the text matched by re, with
+// the text before and after the marks in the first and third group.
+// Adjacent marks share the space between them, so replace until all
+// are found.
+func replaceMarks(text string, re *regexp.Regexp, open, closing string) string {
+	for {
+		replaced := re.ReplaceAllStringFunc(text, func(s string) string {
+			m := re.FindStringSubmatch(s)
+
+			return m[1] + open + m[2] + closing + m[3]
+		})
+
+		if replaced == text {
+			return text
+		}
+
+		text = replaced
+	}
+}
+
+// wikiCells returns the cells of a wiki table row as they are.
+func wikiCells(row string) []string {
+	cells := []string{}
+	depth := 0
+	cell := strings.Builder{}
+
+	row = strings.TrimPrefix(strings.TrimPrefix(row, "||"), "|")
+	row = strings.TrimSuffix(strings.TrimSuffix(row, "||"), "|")
+
+	for i := 0; i < len(row); i++ {
+		switch c := row[i]; {
+		case c == '[' || c == '{':
+			depth++
+		case c == ']' || c == '}':
+			depth = max(depth-1, 0)
+		case c == '|' && depth == 0:
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+
+			if i+1 < len(row) && row[i+1] == '|' {
+				i++
+			}
+
+			continue
+		}
+
+		cell.WriteByte(row[i])
+	}
+
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+// markdownCells returns the cells of a markdown table row as wiki markup.
+func markdownCells(row string) []string {
+	row = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(row), "|"), "|")
+	cells := strings.Split(row, "|")
+
+	for i := range cells {
+		cells[i] = inlineFromMarkdown(strings.TrimSpace(cells[i]))
+	}
+
+	return cells
+}
@@ -0,0 +1,74 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package markup_test
+
+import (
+	"testing"
+
+	"github.com/mhersson/gojira/pkg/util/markup"
+	"github.com/stretchr/testify/assert"
+)
+
+var conversions = []struct {
+	wiki     string
+	markdown string
+}{
+	{"h1. The plan", "# The plan"},
+	{"h3. Steps", "### Steps"},
+	{"Hit it *hard* and _fast_, or -never-", "Hit it **hard** and *fast*, or ~~never~~"},
+	{"*one* _two_ *three*", "**one** *two* **three**"},
+	{"Keep snake_case_name and 2*3*4", "Keep snake_case_name and 2*3*4"},
+	{"Run {{go test *_test.go*}} first", "Run `go test *_test.go*` first"},
+	{"See [the docs|https://example.com] or [https://jira.example.com]",
+		"See [the docs](https://example.com) or <https://jira.example.com>"},
+	{"Ask [~doc] about !flux.png!", "Ask [~doc] about ![](flux.png)"},
+	{"* one\n** nested\n* two", "- one\n  - nested\n- two"},
+	{"# one\n## nested\n# two", "1. one\n  1. nested\n1. two"},
+	{"{code:go}\nfmt.Println(*x*)\n{code}", "```go\nfmt.Println(*x*)\n```"},
+	{"{noformat}\n$ make all\n{noformat}", "```\n$ make all\n```"},
+	{"bq. If you hit it", "> If you hit it"},
+	{"{quote}\nIt *works*\nevery time\n{quote}", "> It **works**\n> every time"},
+	{"----", "---"},
+	{"||Key||Summary||\n|OSE-1|*Fix* it|", "| Key | Summary |\n| --- | --- |\n| OSE-1 | **Fix** it |"},
+}
+
+func TestToMarkdown(t *testing.T) {
+	t.Parallel()
+
+	for _, v := range conversions {
+		assert.Equal(t, v.markdown, markup.ToMarkdown(v.wiki), v.wiki)
+	}
+}
+
+func TestFromMarkdown(t *testing.T) {
+	t.Parallel()
+
+	for _, v := range conversions {
+		assert.Equal(t, v.wiki, markup.FromMarkdown(v.markdown), v.markdown)
+	}
+
+	// The other markdown flavours
+	assert.Equal(t, "*bold* and _italic_", markup.FromMarkdown("__bold__ and _italic_"))
+	assert.Equal(t, "* one\n* two\n# three", markup.FromMarkdown("* one\n+ two\n3. three"))
+	assert.Equal(t, "{code:sh}\nmake\n{code}", markup.FromMarkdown("```sh\nmake\n```"))
+	assert.Equal(t, "|a|b|", markup.FromMarkdown("| a | b |"))
+}
@@ -0,0 +1,305 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+// Package markup renders the Jira wiki markup of descriptions and comments
+// on Jira Server and Data Center as styled text for the terminal.
+package markup
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mhersson/gojira/pkg/util/format"
+)
+
+// Indent of code blocks, nested lists and quotes.
+const indent = "    "
+
+var (
+	headingRe = regexp.MustCompile(`^h([1-6])\.\s+(.*)$`)
+	listRe    = regexp.MustCompile(`^([*#-]+)\s+(.*)$`)
+	ruleRe    = regexp.MustCompile(`^-{4,}$`)
+	blockRe   = regexp.MustCompile(`^\{(code|noformat|quote)(?::[^}]*)?\}(.*)$`)
+
+	monoRe    = regexp.MustCompile(`\{\{(.+?)\}\}`)
+	colorRe   = regexp.MustCompile(`\{color(?::[^}]*)?\}`)
+	linkRe    = regexp.MustCompile(`\[([^\[\]|]+)\|([^\[\]]+)\]|\[([^\[\]]+)\]`)
+	imageRe   = regexp.MustCompile(`!([^\s!|]+\.[A-Za-z0-9]+)(?:\|[^!]*)?!`)
+	boundary  = `(^|[\s(\[{>"'])`
+	endBound  = `($|[\s.,;:!?)\]}"'])`
+	markRes   = map[string]*regexp.Regexp{}
+	markOrder = []string{`\*`, `_`, `\+`, `-`}
+)
+
+func init() {
+	for _, m := range markOrder {
+		markRes[m] = regexp.MustCompile(boundary + m + `([^\s` + m + `](?:[^` + m + `]*[^\s` + m + `])?)` + m + endBound)
+	}
+}
+
+// Render returns the wiki markup as text styled with format.Color,
+// e.g. with headings and *bold* in bold, {code} blocks indented and
+// tables aligned.
+func Render(text string) string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	out := []string{}
+	numbers := []int{}
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], " \t")
+		trimmed := strings.TrimSpace(line)
+
+		// The numbering starts over after each list
+		if !listRe.MatchString(trimmed) {
+			numbers = numbers[:0]
+		}
+
+		switch m := blockRe.FindStringSubmatch(trimmed); {
+		case m != nil:
+			var content []string
+
+			content, i = blockContent(lines, i, m[1], m[2])
+			out = append(out, renderBlock(m[1], content)...)
+		case strings.HasPrefix(trimmed, "|"):
+			rows := []string{}
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), "|"); i++ {
+				rows = append(rows, strings.TrimSpace(lines[i]))
+			}
+
+			i--
+
+			out = append(out, renderTable(rows))
+		case ruleRe.MatchString(trimmed):
+			out = append(out, strings.Repeat("─", 40))
+		case headingRe.MatchString(trimmed):
+			m := headingRe.FindStringSubmatch(trimmed)
+
+			style := format.Color.Bold
+			if m[1] == "1" || m[1] == "2" {
+				style += format.Color.Ul
+			}
+
+			out = append(out, style+plain(m[2])+format.Color.Nocolor)
+		case strings.HasPrefix(trimmed, "bq. "):
+			out = append(out, "│ "+inline(strings.TrimPrefix(trimmed, "bq. ")))
+		case listRe.MatchString(trimmed):
+			m := listRe.FindStringSubmatch(trimmed)
+			depth := len(m[1])
+
+			for len(numbers) < depth {
+				numbers = append(numbers, 0)
+			}
+
+			numbers = numbers[:depth]
+			numbers[depth-1]++
+
+			bullet := "• "
+			if strings.HasSuffix(m[1], "#") {
+				bullet = strconv.Itoa(numbers[depth-1]) + ". "
+			}
+
+			out = append(out, strings.Repeat("  ", depth-1)+bullet+inline(m[2]))
+		default:
+			out = append(out, inline(line))
+		}
+	}
+
+	return strings.Join(out, "\n")
+}
+
+// blockContent returns the lines of the {code}, {noformat} or {quote}
+// block starting on line i, after the opening tag, and the line it ends.
+func blockContent(lines []string, i int, tag, rest string) ([]string, int) {
+	closing := "{" + tag + "}"
+	content := []string{}
+
+	for {
+		if before, _, found := strings.Cut(rest, closing); found {
+			if strings.TrimSpace(before) != "" || len(content) == 0 {
+				content = append(content, before)
+			}
+
+			return trimEmpty(content), i
+		}
+
+		content = append(content, rest)
+
+		if i++; i >= len(lines) {
+			return trimEmpty(content), i
+		}
+
+		rest = strings.TrimRight(lines[i], " \t")
+	}
+}
+
+func renderBlock(tag string, content []string) []string {
+	out := []string{}
+
+	for _, l := range content {
+		switch tag {
+		case "quote":
+			out = append(out, "│ "+inline(l))
+		default:
+			out = append(out, indent+format.Color.Cyan+l+format.Color.Nocolor)
+		}
+	}
+
+	return out
+}
+
+// renderTable aligns the rows of a table, with the first row as the
+// header if it is a ||header|| row.
+func renderTable(rows []string) string {
+	table := format.Table{HideHeader: true}
+	width := 0
+
+	for i, row := range rows {
+		cells := splitCells(row)
+		width = max(width, len(cells))
+
+		if i == 0 && strings.HasPrefix(row, "||") {
+			for _, c := range cells {
+				table.Columns = append(table.Columns, format.Column{Header: c})
+			}
+
+			table.HideHeader = false
+
+			continue
+		}
+
+		table.Rows = append(table.Rows, cells)
+	}
+
+	for len(table.Columns) < width {
+		table.Columns = append(table.Columns, format.Column{})
+	}
+
+	for i, row := range table.Rows {
+		for len(row) < width {
+			row = append(row, "")
+		}
+
+		table.Rows[i] = row
+	}
+
+	var b bytes.Buffer
+
+	table.Render(&b, 0)
+
+	out := strings.TrimRight(b.String(), "\n")
+	if !table.HideHeader {
+		// Without the empty line before the header
+		out = strings.Replace(out, "\n", "", 1)
+	}
+
+	return out
+}
+
+// splitCells returns the cells of a table row, rendered as plain text.
+func splitCells(row string) []string {
+	cells := wikiCells(row)
+	for i := range cells {
+		cells[i] = plain(cells[i])
+	}
+
+	return cells
+}
+
+// inline renders the text effects, links and {{monospaced}} text of a line.
+func inline(line string) string {
+	return render(line, true)
+}
+
+// plain renders the line without styles, e.g. for table cells and
+// headings where the escape codes would be in the way.
+func plain(line string) string {
+	return render(line, false)
+}
+
+func render(line string, styled bool) string {
+	// style returns the start and end of the style, empty when not styled
+	style := func(col string) (string, string) {
+		if !styled || col == "" {
+			return "", ""
+		}
+
+		return col, format.Color.Nocolor
+	}
+
+	line = strings.ReplaceAll(colorRe.ReplaceAllString(line, ""), `\\`, "\n")
+
+	// The monospaced text is kept as is
+	mono := func(code string) string {
+		start, end := style(format.Color.Cyan)
+
+		return start + code + end
+	}
+
+	return outsideCode(line, monoRe, mono, func(text string) string {
+		text = linkRe.ReplaceAllStringFunc(text, func(s string) string {
+			m := linkRe.FindStringSubmatch(s)
+
+			switch {
+			case m[1] != "":
+				start, end := style(format.Color.Ul)
+
+				return start + m[1] + end + " (" + m[2] + ")"
+			case strings.HasPrefix(m[3], "~"):
+				start, end := style(format.Color.Bold)
+
+				return start + "@" + strings.TrimPrefix(m[3], "~") + end
+			case strings.HasPrefix(m[3], "^"):
+				return strings.TrimPrefix(m[3], "^")
+			}
+
+			start, end := style(format.Color.Ul)
+
+			return start + m[3] + end
+		})
+
+		text = imageRe.ReplaceAllString(text, "[image $1]")
+
+		styles := map[string]string{
+			`\*`: format.Color.Bold, `_`: format.Color.Italic, `\+`: format.Color.Ul, `-`: format.Color.Strike,
+		}
+
+		for _, mark := range markOrder {
+			start, end := style(styles[mark])
+			text = replaceMarks(text, markRes[mark], start, end)
+		}
+
+		return text
+	})
+}
+
+func trimEmpty(lines []string) []string {
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
@@ -0,0 +1,77 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package markup_test
+
+import (
+	"testing"
+
+	"github.com/mhersson/gojira/pkg/types"
+	"github.com/mhersson/gojira/pkg/util/format"
+	"github.com/mhersson/gojira/pkg/util/markup"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRender(t *testing.T) {
+	color := format.Color
+	format.DisableColors()
+
+	defer func() { format.Color = color }()
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"h1. The plan", "The plan"},
+		{"Hit it *hard* and _fast_, +now+ or -never-", "Hit it hard and fast, now or never"},
+		{"*one* *two*", "one two"},
+		{"Keep 2024-03-01, snake_case_name and 2*3*4", "Keep 2024-03-01, snake_case_name and 2*3*4"},
+		{"Run {{go test *_test.go*}} first", "Run go test *_test.go* first"},
+		{"See [the docs|https://example.com] or [https://jira.example.com]",
+			"See the docs (https://example.com) or https://jira.example.com"},
+		{"Ask [~doc] about !flux.png|thumbnail!", "Ask @doc about [image flux.png]"},
+		{"{color:red}Red{color} alert", "Red alert"},
+		{"* one\n** nested\n* two", "• one\n  • nested\n• two"},
+		{"# one\n# two\n## nested\n# three\n\n# again", "1. one\n2. two\n  1. nested\n3. three\n\n1. again"},
+		{"{code:go}\nfmt.Println(*x*)\n{code}", "    fmt.Println(*x*)"},
+		{"{noformat}a{noformat}", "    a"},
+		{"{quote}\nIt *works*\n{quote}", "│ It works"},
+		{"bq. If you hit it", "│ If you hit it"},
+		{"----", "────────────────────────────────────────"},
+		{"line\\\\break", "line\nbreak"},
+		{"||Key||Summary||\n|OSE-1|*Fix* [it|https://example.com/a|b]|\n|OSE-22|Charge|",
+			"Key     Summary\nOSE-1   Fix it (https://example.com/a|b)\nOSE-22  Charge"},
+	}
+
+	for _, v := range tests {
+		assert.Equal(t, v.expected, markup.Render(v.input), v.input)
+	}
+}
+
+func TestRenderStyled(t *testing.T) {
+	color := format.Color
+	format.Color = types.Color{Bold: "<b>", Cyan: "<c>", Nocolor: "</>"}
+
+	defer func() { format.Color = color }()
+
+	assert.Equal(t, "Hit it <b>hard</>, run <c>make *all*</>", markup.Render("Hit it *hard*, run {{make *all*}}"))
+	assert.Equal(t, "    <c>x := 1</>", markup.Render("{code}x := 1{code}"))
+}
@@ -0,0 +1,90 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package snapshot
+
+import (
+	"time"
+)
+
+// The kinds of changes between a snapshot and the current issues.
+const (
+	Added         = "New"
+	Removed       = "Removed"
+	StatusChanged = "Status"
+)
+
+// Issue is the state of an issue when the snapshot was saved.
+type Issue struct {
+	Key     string `json:"key"`
+	Summary string `json:"summary"`
+	Status  string `json:"status"`
+}
+
+// Snapshot is the list of issues matching the filter at a point in time.
+type Snapshot struct {
+	Name    string    `json:"name"`
+	Filter  string    `json:"filter"`
+	Created time.Time `json:"created"`
+	Issues  []Issue   `json:"issues"`
+}
+
+// Change is an issue that is new, removed or has changed status since
+// the snapshot was saved. From is the status in the snapshot.
+type Change struct {
+	Kind  string
+	Issue Issue
+	From  string
+}
+
+// Diff returns the issues that are new or have changed status in the
+// current issues, in their current order, followed by the issues
+// removed since the snapshot, in the order of the snapshot.
+func Diff(snapshot, current []Issue) []Change {
+	before := map[string]Issue{}
+	for _, i := range snapshot {
+		before[i.Key] = i
+	}
+
+	changes := []Change{}
+	found := map[string]bool{}
+
+	for _, i := range current {
+		found[i.Key] = true
+
+		old, ok := before[i.Key]
+
+		switch {
+		case !ok:
+			changes = append(changes, Change{Kind: Added, Issue: i})
+		case old.Status != i.Status:
+			changes = append(changes, Change{Kind: StatusChanged, Issue: i, From: old.Status})
+		}
+	}
+
+	for _, i := range snapshot {
+		if !found[i.Key] {
+			changes = append(changes, Change{Kind: Removed, Issue: i})
+		}
+	}
+
+	return changes
+}
@@ -0,0 +1,83 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package snapshot_test
+
+import (
+	"testing"
+
+	"github.com/mhersson/gojira/pkg/util/snapshot"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	open := snapshot.Issue{Key: "OSE-1", Summary: "Open issue", Status: "Open"}
+	done := snapshot.Issue{Key: "OSE-1", Summary: "Open issue", Status: "Done"}
+	kept := snapshot.Issue{Key: "OSE-2", Summary: "Unchanged", Status: "In Progress"}
+	gone := snapshot.Issue{Key: "OSE-3", Summary: "Moved out", Status: "Open"}
+	added := snapshot.Issue{Key: "OSE-4", Summary: "Added", Status: "Open"}
+
+	tests := []struct {
+		name     string
+		snapshot []snapshot.Issue
+		current  []snapshot.Issue
+		want     []snapshot.Change
+	}{
+		{
+			name:     "no changes",
+			snapshot: []snapshot.Issue{open, kept},
+			current:  []snapshot.Issue{kept, open},
+			want:     []snapshot.Change{},
+		},
+		{
+			name:     "new, removed and status changed",
+			snapshot: []snapshot.Issue{open, kept, gone},
+			current:  []snapshot.Issue{added, done, kept},
+			want: []snapshot.Change{
+				{Kind: snapshot.Added, Issue: added},
+				{Kind: snapshot.StatusChanged, Issue: done, From: "Open"},
+				{Kind: snapshot.Removed, Issue: gone},
+			},
+		},
+		{
+			name:     "empty snapshot",
+			snapshot: []snapshot.Issue{},
+			current:  []snapshot.Issue{kept},
+			want:     []snapshot.Change{{Kind: snapshot.Added, Issue: kept}},
+		},
+		{
+			name:     "everything removed",
+			snapshot: []snapshot.Issue{kept},
+			current:  nil,
+			want:     []snapshot.Change{{Kind: snapshot.Removed, Issue: kept}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, snapshot.Diff(tt.snapshot, tt.current))
+		})
+	}
+}
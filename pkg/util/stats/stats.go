@@ -0,0 +1,134 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package stats
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+// RollingAverage returns the average of each value and the window-1
+// values before it. The first values are averaged over the values
+// available, so the result has the same length as the input.
+func RollingAverage(values []float64, window int) []float64 {
+	averages := make([]float64, len(values))
+
+	if window < 1 {
+		window = 1
+	}
+
+	sum := 0.0
+
+	for i, v := range values {
+		sum += v
+		if i >= window {
+			sum -= values[i-window]
+		}
+
+		averages[i] = sum / float64(min(i+1, window))
+	}
+
+	return averages
+}
+
+// LinearTrend returns the slope of the least squares line through
+// the values, i.e. the average change from one value to the next.
+func LinearTrend(values []float64) float64 {
+	n := float64(len(values))
+	if n < 2 {
+		return 0
+	}
+
+	var sumX, sumY, sumXY, sumXX float64
+
+	for i, y := range values {
+		x := float64(i)
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+
+	return (n*sumXY - sumX*sumY) / (n*sumXX - sumX*sumX)
+}
+
+// Percentile returns the p-th percentile (0-100) of the values,
+// interpolating linearly between the closest ranks.
+func Percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
+
+// LongestStreak returns the first and last day and the length of the
+// longest run of consecutive days. Saturdays and Sundays missing from
+// the days do not break a streak, but only the days given are counted.
+// The days are truncated to dates in their own location.
+func LongestStreak(days []time.Time) (time.Time, time.Time, int) {
+	dates := make([]time.Time, 0, len(days))
+
+	for _, d := range days {
+		dates = append(dates, time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, time.UTC))
+	}
+
+	slices.SortFunc(dates, func(a, b time.Time) int { return a.Compare(b) })
+	dates = slices.Compact(dates)
+
+	var first, last, start time.Time
+
+	longest, length := 0, 0
+
+	for i, d := range dates {
+		if i == 0 || !consecutive(dates[i-1], d) {
+			start, length = d, 0
+		}
+
+		length++
+
+		if length > longest {
+			first, last, longest = start, d, length
+		}
+	}
+
+	return first, last, longest
+}
+
+// consecutive reports whether there are only weekend days between the days.
+func consecutive(from, to time.Time) bool {
+	for d := from.AddDate(0, 0, 1); d.Before(to); d = d.AddDate(0, 0, 1) {
+		if d.Weekday() != time.Saturday && d.Weekday() != time.Sunday {
+			return false
+		}
+	}
+
+	return true
+}
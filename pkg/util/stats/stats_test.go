@@ -0,0 +1,130 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package stats_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mhersson/gojira/pkg/util/stats"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRollingAverage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		values   []float64
+		window   int
+		expected []float64
+	}{
+		{[]float64{}, 4, []float64{}},
+		{[]float64{40, 42, 38, 40}, 4, []float64{40, 41, 40, 40}},
+		{[]float64{40, 44, 48, 52, 56}, 2, []float64{40, 42, 46, 50, 54}},
+		{[]float64{10, 20}, 0, []float64{10, 20}},
+	}
+
+	for _, tc := range tests {
+		assert.Equal(t, tc.expected, stats.RollingAverage(tc.values, tc.window))
+	}
+}
+
+func TestLinearTrend(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		values   []float64
+		expected float64
+	}{
+		{[]float64{}, 0},
+		{[]float64{40}, 0},
+		{[]float64{40, 40, 40}, 0},
+		{[]float64{40, 42, 44, 46}, 2},
+		{[]float64{45, 40, 35}, -5},
+	}
+
+	for _, tc := range tests {
+		assert.InDelta(t, tc.expected, stats.LinearTrend(tc.values), 0.0001)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		values   []float64
+		p        float64
+		expected float64
+	}{
+		{[]float64{}, 50, 0},
+		{[]float64{37.5}, 95, 37.5},
+		{[]float64{40, 30, 50}, 50, 40},
+		{[]float64{10, 20, 30, 40}, 50, 25},
+		{[]float64{10, 20, 30, 40}, 100, 40},
+		{[]float64{10, 20, 30, 40}, 0, 10},
+	}
+
+	for _, tc := range tests {
+		assert.InDelta(t, tc.expected, stats.Percentile(tc.values, tc.p), 0.0001)
+	}
+}
+
+func TestLongestStreak(t *testing.T) {
+	t.Parallel()
+
+	day := func(date string) time.Time {
+		d, _ := time.Parse("2006-01-02", date)
+
+		return d
+	}
+
+	tests := []struct {
+		days   []string
+		first  string
+		last   string
+		length int
+	}{
+		{[]string{"2024-03-04", "2024-03-05", "2024-03-07"}, "2024-03-04", "2024-03-05", 2},
+		// Friday to Monday, the weekend does not break the streak
+		{[]string{"2024-03-07", "2024-03-08", "2024-03-11"}, "2024-03-07", "2024-03-11", 3},
+		// Work on a Saturday is counted
+		{[]string{"2024-03-08", "2024-03-09", "2024-03-11"}, "2024-03-08", "2024-03-11", 3},
+		// Duplicates and unsorted days
+		{[]string{"2024-03-13", "2024-03-12", "2024-03-12", "2024-03-01"}, "2024-03-12", "2024-03-13", 2},
+		{[]string{"2024-03-01"}, "2024-03-01", "2024-03-01", 1},
+	}
+
+	for _, tc := range tests {
+		days := []time.Time{}
+		for _, d := range tc.days {
+			days = append(days, day(d))
+		}
+
+		first, last, length := stats.LongestStreak(days)
+		assert.Equal(t, tc.first, first.Format("2006-01-02"))
+		assert.Equal(t, tc.last, last.Format("2006-01-02"))
+		assert.Equal(t, tc.length, length)
+	}
+
+	_, _, length := stats.LongestStreak(nil)
+	assert.Equal(t, 0, length)
+}
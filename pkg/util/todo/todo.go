@@ -0,0 +1,184 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package todo
+
+import (
+	"bufio"
+	"crypto/sha1" //nolint:gosec // Only used to derive stable task ids
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Entry is an issue exported as a todo entry.
+type Entry struct {
+	Key      string
+	Project  string
+	Summary  string
+	Status   string
+	Priority string
+	URL      string
+	Due      time.Time // Zero if the issue has no due date
+	Done     bool      // Marked as done in the plain-text file
+}
+
+// The timestamp format used by Taskwarrior.
+const taskTimeFormat = "20060102T150405Z"
+
+// TaskTag is added to all tasks exported by gojira.
+const TaskTag = "jira"
+
+var (
+	orgHeading = regexp.MustCompile(`^\*+\s+(TODO|DONE)\s`)
+	orgKey     = regexp.MustCompile(`^\s*:JIRA_KEY:\s+(\S+)`)
+)
+
+// priorityClass returns A, B or C, the org-mode priority and the first
+// letter of the Taskwarrior priority, from the name of the Jira priority.
+func priorityClass(priority string) string {
+	switch strings.ToLower(priority) {
+	case "blocker", "critical", "highest", "high":
+		return "A"
+	case "low", "lowest", "minor", "trivial":
+		return "C"
+	}
+
+	return "B"
+}
+
+// WriteOrg writes the entries as org-mode TODO entries, with the due
+// date as deadline and the issue key and status as properties.
+func WriteOrg(w io.Writer, entries []Entry) error {
+	b := bufio.NewWriter(w)
+
+	fmt.Fprintln(b, "# Exported by gojira sync org. Only the TODO state is read back,")
+	fmt.Fprintln(b, "# everything else is overwritten by the next sync.")
+
+	for _, e := range entries {
+		state := "TODO"
+		if e.Done {
+			state = "DONE"
+		}
+
+		fmt.Fprintf(b, "\n* %s [#%s] %s %s\n", state, priorityClass(e.Priority), e.Key, e.Summary)
+
+		if !e.Due.IsZero() {
+			fmt.Fprintf(b, "  DEADLINE: <%s>\n", e.Due.Format("2006-01-02 Mon"))
+		}
+
+		fmt.Fprintln(b, "  :PROPERTIES:")
+		fmt.Fprintf(b, "  :JIRA_KEY: %s\n", e.Key)
+		fmt.Fprintf(b, "  :JIRA_STATUS: %s\n", e.Status)
+
+		if e.URL != "" {
+			fmt.Fprintf(b, "  :JIRA_URL: %s\n", e.URL)
+		}
+
+		fmt.Fprintln(b, "  :END:")
+	}
+
+	return b.Flush()
+}
+
+// ParseOrg returns the keys of the entries marked as DONE.
+func ParseOrg(r io.Reader) ([]string, error) {
+	done := []string{}
+	isDone := false
+
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := s.Text()
+
+		if m := orgHeading.FindStringSubmatch(line); m != nil {
+			isDone = m[1] == "DONE"
+
+			continue
+		}
+
+		if strings.HasPrefix(line, "*") {
+			isDone = false
+
+			continue
+		}
+
+		if m := orgKey.FindStringSubmatch(line); m != nil && isDone {
+			done = append(done, m[1])
+		}
+	}
+
+	return done, s.Err()
+}
+
+// Task is a Taskwarrior task, as read and written by task import and export.
+type Task struct {
+	UUID        string   `json:"uuid"`
+	Description string   `json:"description"`
+	Project     string   `json:"project,omitempty"`
+	Status      string   `json:"status"`
+	Priority    string   `json:"priority,omitempty"`
+	Due         string   `json:"due,omitempty"`
+	End         string   `json:"end,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+}
+
+// TaskUUID returns a stable id for the task of the issue, so the
+// task is updated, and not duplicated, when imported again.
+func TaskUUID(key string) string {
+	sum := sha1.Sum([]byte("gojira:" + key)) //nolint:gosec
+
+	// Set the version to 5 and the variant to RFC 4122, like a name based uuid
+	sum[6] = (sum[6] & 0x0f) | 0x50
+	sum[8] = (sum[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
+
+// TaskTime returns the time in the Taskwarrior timestamp format.
+func TaskTime(t time.Time) string {
+	return t.UTC().Format(taskTimeFormat)
+}
+
+// ToTask returns the entry as a Taskwarrior task. Entries marked as
+// done are completed at the given time.
+func ToTask(e Entry, now time.Time) Task {
+	task := Task{
+		UUID:        TaskUUID(e.Key),
+		Description: e.Key + " " + e.Summary,
+		Project:     e.Project,
+		Status:      "pending",
+		Priority:    map[string]string{"A": "H", "B": "M", "C": "L"}[priorityClass(e.Priority)],
+		Tags:        []string{TaskTag},
+	}
+
+	if !e.Due.IsZero() {
+		task.Due = TaskTime(e.Due)
+	}
+
+	if e.Done {
+		task.Status = "completed"
+		task.End = TaskTime(now)
+	}
+
+	return task
+}
@@ -0,0 +1,176 @@
+/*
+Copyright © 2020-2024 Morten Hersson
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package todo_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mhersson/gojira/pkg/util/todo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteOrg(t *testing.T) {
+	t.Parallel()
+
+	entries := []todo.Entry{
+		{
+			Key: "OSE-1", Summary: "Fix the flux capacitor", Status: "In Progress", Priority: "High",
+			URL: "https://jira/browse/OSE-1", Due: time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC),
+		},
+		{Key: "OSE-2", Summary: "Buy plutonium", Status: "Open", Priority: "Normal", Done: true},
+	}
+
+	var b bytes.Buffer
+	assert.NoError(t, todo.WriteOrg(&b, entries))
+
+	assert.Contains(t, b.String(), `
+* TODO [#A] OSE-1 Fix the flux capacitor
+  DEADLINE: <2024-03-10 Sun>
+  :PROPERTIES:
+  :JIRA_KEY: OSE-1
+  :JIRA_STATUS: In Progress
+  :JIRA_URL: https://jira/browse/OSE-1
+  :END:
+
+* DONE [#B] OSE-2 Buy plutonium
+  :PROPERTIES:
+  :JIRA_KEY: OSE-2
+  :JIRA_STATUS: Open
+  :END:
+`)
+}
+
+func TestParseOrg(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		org  string
+		want []string
+	}{
+		{
+			name: "done and todo",
+			org: `* DONE [#A] OSE-1 Fix the flux capacitor
+  :PROPERTIES:
+  :JIRA_KEY: OSE-1
+  :END:
+* TODO OSE-2 Buy plutonium
+  :PROPERTIES:
+  :JIRA_KEY: OSE-2
+  :END:
+** DONE OSE-3 Nested
+   :PROPERTIES:
+   :JIRA_KEY: OSE-3
+   :END:`,
+			want: []string{"OSE-1", "OSE-3"},
+		},
+		{
+			name: "heading without state resets done",
+			org: `* DONE OSE-1 Done
+* Notes
+  :JIRA_KEY: OSE-9`,
+			want: []string{},
+		},
+		{
+			name: "empty",
+			org:  "",
+			want: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			done, err := todo.ParseOrg(strings.NewReader(tt.org))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, done)
+		})
+	}
+}
+
+func TestOrgRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	entries := []todo.Entry{
+		{Key: "OSE-1", Summary: "One", Done: true},
+		{Key: "OSE-2", Summary: "Two"},
+	}
+
+	var b bytes.Buffer
+	assert.NoError(t, todo.WriteOrg(&b, entries))
+
+	done, err := todo.ParseOrg(&b)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"OSE-1"}, done)
+}
+
+func TestTaskUUID(t *testing.T) {
+	t.Parallel()
+
+	id := todo.TaskUUID("OSE-1")
+
+	assert.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-5[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, id)
+	assert.Equal(t, id, todo.TaskUUID("OSE-1"))
+	assert.NotEqual(t, id, todo.TaskUUID("OSE-2"))
+}
+
+func TestToTask(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 3, 5, 12, 0, 0, 0, time.UTC)
+	due := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		entry todo.Entry
+		want  todo.Task
+	}{
+		{
+			name:  "pending with due date",
+			entry: todo.Entry{Key: "OSE-1", Project: "OSE", Summary: "Fix it", Priority: "Highest", Due: due},
+			want: todo.Task{
+				UUID: todo.TaskUUID("OSE-1"), Description: "OSE-1 Fix it", Project: "OSE",
+				Status: "pending", Priority: "H", Due: "20240310T000000Z", Tags: []string{todo.TaskTag},
+			},
+		},
+		{
+			name:  "completed",
+			entry: todo.Entry{Key: "OSE-2", Summary: "Done", Priority: "Lowest", Done: true},
+			want: todo.Task{
+				UUID: todo.TaskUUID("OSE-2"), Description: "OSE-2 Done",
+				Status: "completed", Priority: "L", End: "20240305T120000Z", Tags: []string{todo.TaskTag},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, todo.ToTask(tt.entry, now))
+		})
+	}
+}
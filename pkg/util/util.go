@@ -30,7 +30,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"html/template"
 	"io"
 	"net/http"
 	"os"
@@ -39,29 +38,23 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"text/template"
 	"time"
 
 	"github.com/mhersson/gojira/pkg/types"
+	"github.com/mhersson/gojira/pkg/util/calendar"
 	"github.com/mhersson/gojira/pkg/util/convert"
 )
 
 //go:embed tpl/*.tmpl
 var tplFS embed.FS
 
-func WeekStartEndDate(year, week int) (string, string) {
-	t := time.Date(year, 7, 1, 0, 0, 0, 0, time.UTC)
+// WeekStartEndDate returns the first and last day of the week with t,
+// numbered by the rule.
+func WeekStartEndDate(rule calendar.Rule, t time.Time) (string, string) {
+	start := rule.WeekStart(t)
 
-	if wd := t.Weekday(); wd == time.Sunday {
-		t = t.AddDate(0, 0, -6)
-	} else {
-		t = t.AddDate(0, 0, -int(wd)+1)
-	}
-
-	_, w := t.ISOWeek()
-	t = t.AddDate(0, 0, (week-w)*7)
-	e := t.AddDate(0, 0, 6)
-
-	return t.Format("2006-01-02"), e.Format("2006-01-02")
+	return start.Format("2006-01-02"), start.AddDate(0, 0, 6).Format("2006-01-02")
 }
 
 func GetCurrentDate() string {
@@ -70,20 +63,15 @@ func GetCurrentDate() string {
 	return now.Format("2006-01-02")
 }
 
-func DateIsToday(date string) bool {
+func DateIsToday(date string) (bool, error) {
 	d, err := time.Parse("2006-01-02", date)
 	if err != nil {
-		fmt.Printf("Failed to parse date: %v\n", err)
-		os.Exit(1)
+		return false, fmt.Errorf("failed to parse date: %w", err)
 	}
 
 	t := time.Now()
 
-	if d.Year() == t.Year() && d.Month() == t.Month() && d.Day() == t.Day() {
-		return true
-	}
-
-	return false
+	return d.Year() == t.Year() && d.Month() == t.Month() && d.Day() == t.Day(), nil
 }
 
 // Returns to today's date on format 2006-01-02.
@@ -93,41 +81,153 @@ func Today() string {
 	return t.Format("2006-01-02")
 }
 
-func GetActiveIssue(path string) string {
+// ParseJiraTime parses timestamps on the format used by Jira,
+// e.g. 2017-12-07T09:23:19.552+0000.
+func ParseJiraTime(t string) (time.Time, error) {
+	return time.Parse("2006-01-02T15:04:05.000-0700", t) //nolint:wrapcheck
+}
+
+// StatusChangedAt returns the time of the last status change,
+// or the time the issue was created if the status never changed.
+func StatusChangedAt(issue types.IssueDescription) (time.Time, error) {
+	changed, err := ParseJiraTime(issue.Fields.Created)
+	if err != nil {
+		return changed, err
+	}
+
+	for _, h := range issue.Changelog.Histories {
+		for _, item := range h.Items {
+			if item.Field != "status" {
+				continue
+			}
+
+			t, err := ParseJiraTime(h.Created)
+			if err == nil && t.After(changed) {
+				changed = t
+			}
+		}
+	}
+
+	return changed, nil
+}
+
+// localIssueName is the name of the file with the issue active in a
+// directory, kept in the git folder when the directory is a repository.
+const localIssueName = "gojira-issue"
+
+// LocalIssueFile returns the file with the issue active in the directory.
+// In a git repository it is kept in the git folder, so it is never
+// committed and every worktree has its own.
+func LocalIssueFile(dir string) string {
+	for d := dir; ; d = filepath.Dir(d) {
+		if gitDir := gitFolder(d); gitDir != "" {
+			return filepath.Join(gitDir, localIssueName)
+		}
+
+		if filepath.Dir(d) == d {
+			return filepath.Join(dir, "."+localIssueName)
+		}
+	}
+}
+
+// ActiveIssueFile returns the file with the issue active in the working
+// directory, searching it and its parents like direnv, or the global file
+// when no issue is set active locally.
+func ActiveIssueFile(global string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return global
+	}
+
+	for d := wd; ; d = filepath.Dir(d) {
+		files := []string{filepath.Join(d, "."+localIssueName)}
+		if gitDir := gitFolder(d); gitDir != "" {
+			files = append(files, filepath.Join(gitDir, localIssueName))
+		}
+
+		for _, f := range files {
+			if _, err := os.Stat(f); err == nil {
+				return f
+			}
+		}
+
+		if filepath.Dir(d) == d {
+			return global
+		}
+	}
+}
+
+// gitFolder returns the git folder of the repository in dir, or an empty
+// string if dir is not the top of a repository. Worktrees and submodules
+// have a .git file pointing to their git folder.
+func gitFolder(dir string) string {
+	git := filepath.Join(dir, ".git")
+
+	info, err := os.Stat(git)
+	if err != nil {
+		return ""
+	}
+
+	if info.IsDir() {
+		return git
+	}
+
+	content, err := os.ReadFile(git)
+	if err != nil || !bytes.HasPrefix(content, []byte("gitdir:")) {
+		return ""
+	}
+
+	gitDir := strings.TrimSpace(string(content[len("gitdir:"):]))
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(dir, gitDir)
+	}
+
+	return gitDir
+}
+
+// NotSetError is returned when there is no active issue or board.
+type NotSetError struct {
+	Message string
+}
+
+func (e *NotSetError) Error() string {
+	return e.Message
+}
+
+// GetActiveIssue returns the issue active in the working directory, or
+// else the issue in the global file.
+func GetActiveIssue(path string) (string, error) {
+	path = ActiveIssueFile(path)
+
 	if _, err := os.Stat(path); os.IsNotExist(err) {
-		fmt.Println("Active issue is not set")
-		os.Exit(1)
+		return "", &NotSetError{Message: "Active issue is not set"}
 	}
 
 	out, err := os.ReadFile(path)
 	if err != nil {
-		fmt.Println("Failed to get active issue")
-		os.Exit(1)
+		return "", fmt.Errorf("failed to get active issue: %w", err)
 	}
 
-	return string(out)
+	return string(out), nil
 }
 
-func GetActiveSprintOrKanban(path, boardType string) string {
+func GetActiveSprintOrKanban(path, boardType string) (string, error) {
 	if _, err := os.Stat(path); os.IsNotExist(err) {
-		fmt.Println("No active board is set")
-		os.Exit(0)
+		return "", &NotSetError{Message: "No active board is set"}
 	}
 
 	out, err := os.ReadFile(path)
 	if err != nil {
-		fmt.Println("Failed to get active board")
-		os.Exit(1)
+		return "", fmt.Errorf("failed to get active board: %w", err)
 	}
 
 	re := regexp.MustCompile(boardType + `=(.*)`)
 	match := re.FindSubmatch(out)
 	if match == nil {
-		fmt.Printf("No active %s is set\n", boardType)
-		os.Exit(0)
+		return "", &NotSetError{Message: fmt.Sprintf("No active %s is set", boardType)}
 	}
 
-	return string(match[1])
+	return string(match[1]), nil
 }
 
 func GetWorklogsSorted(worklogs []types.Timesheet, truncate bool) []types.SimplifiedTimesheet {
@@ -165,38 +265,35 @@ func GetWorklogsSorted(worklogs []types.Timesheet, truncate bool) []types.Simpli
 	return week
 }
 
+// GroupWorklogsByWeek groups the worklogs in the weeks from the week
+// with fromDate to the week with toDate, and counts the public holidays
+// falling on a weekday in each week.
 func GroupWorklogsByWeek(
-	fromDate, toDate string, worklogs []types.SimplifiedTimesheet, holidays []string,
+	rule calendar.Rule, fromDate, toDate string, worklogs []types.SimplifiedTimesheet, holidays []string,
 ) []types.Week {
 	t1, _ := time.Parse("2006-01-02", fromDate)
 	t2, _ := time.Parse("2006-01-02", toDate)
 
 	weeks := []types.Week{}
 
-	for t1.Before(t2) || t1.Equal(t2) {
-		week := types.Week{}
+	for _, start := range rule.Weeks(t1, t2) {
+		week := types.Week{StartDate: start, EndDate: start.AddDate(0, 0, 6), Rule: rule}
 
 		for _, w := range worklogs {
 			d, _ := time.Parse("2006-01-02", w.Date)
-
-			if (t1.Before(d) || t1.Equal(d)) && (t1.Equal(d) || t1.Add(7*24*time.Hour).After(d)) {
+			if !d.Before(week.StartDate) && !d.After(week.EndDate) {
 				week.Worklogs = append(week.Worklogs, w)
-			} else if d.After(t1.Add(6 * 24 * time.Hour)) {
-				break
 			}
 		}
 
 		for _, h := range holidays {
 			d, _ := time.Parse("2006-01-02", h)
-			if (t1.Before(d) || t1.Equal(d)) && (t1.Equal(d) || t1.Add(5*24*time.Hour).After(d)) {
+			if !d.Before(week.StartDate) && !d.After(week.EndDate) && !calendar.IsWeekend(d) {
 				week.PublicHolidays++
 			}
 		}
 
-		week.StartDate = t1
-		week.EndDate = t1.Add(6 * 24 * time.Hour)
 		weeks = append(weeks, week)
-		t1 = t1.Add(7 * 24 * time.Hour)
 	}
 
 	return weeks
@@ -241,12 +338,8 @@ func GetUserInput(prompt string, regRange string) string {
 func MakeStringJSONSafe(str string) string {
 	strText := strings.ReplaceAll(str, "```", "{noformat}")
 	// Convert the string into json to escape whatever
-	// chars json needs to have escaped
-	jsonStr, err := json.Marshal(strText)
-	if err != nil {
-		fmt.Println("Failed to parse comment")
-		os.Exit(1)
-	}
+	// chars json needs to have escaped. Marshaling a string never fails.
+	jsonStr, _ := json.Marshal(strText)
 
 	// Remove the surrounding curly brackets
 	escaped := string(jsonStr[1 : len(jsonStr)-1])
@@ -272,6 +365,24 @@ func ExecuteTemplate(filename string, content interface{}) []byte {
 	return buffer.Bytes()
 }
 
+// ExecuteTemplateText executes a template given as text, e.g. a user
+// defined template. Unlike ExecuteTemplate it returns an error instead
+// of panicking, because the template is not under our control.
+func ExecuteTemplateText(text string, content interface{}) ([]byte, error) {
+	t, err := template.New("text").Funcs(templateFuncMap()).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	var buffer bytes.Buffer
+
+	if err := t.Execute(&buffer, content); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
 func templateFuncMap() template.FuncMap {
 	fns := template.FuncMap{
 		"getTime": func(date string) string {